@@ -183,7 +183,7 @@ func (refOpts *ReferenceOptions) refer(srv common.RPCService, info *ClientInfo)
 		panic(err)
 	}
 	refOpts.urls = urls
-	refOpts.invoker = invoker
+	refOpts.invoker = newInterceptedInvoker(invoker, refOpts.interceptors)
 
 	// create proxy
 	if info == nil && srv != nil {
@@ -364,6 +364,12 @@ func (refOpts *ReferenceOptions) getURLMap() url.Values {
 	urlMap.Set(constant.RegistryRoleKey, strconv.Itoa(common.CONSUMER))
 	urlMap.Set(constant.ProvidedBy, ref.ProvidedBy)
 	urlMap.Set(constant.SerializationKey, ref.Serialization)
+	if ref.Compression != "" {
+		urlMap.Set(constant.CompressionKey, ref.Compression)
+	}
+	if ref.CompressMinBytes > 0 {
+		urlMap.Set(constant.CompressMinBytesKey, strconv.Itoa(ref.CompressMinBytes))
+	}
 	urlMap.Set(constant.TracingConfigKey, ref.TracingKey)
 
 	urlMap.Set(constant.ReleaseKey, "dubbo-golang-"+constant.Version)
@@ -398,12 +404,24 @@ func (refOpts *ReferenceOptions) getURLMap() url.Values {
 	if tracing.Enable != nil && *tracing.Enable {
 		defaultReferenceFilter += fmt.Sprintf(",%s", constant.OTELClientTraceKey)
 	}
+	for _, v := range ref.MethodsConfig {
+		if v.Idempotent {
+			defaultReferenceFilter += fmt.Sprintf(",%s", constant.IdempotentConsumerFilterKey)
+			break
+		}
+	}
+	if needDeprecationWarning(urlMap) {
+		defaultReferenceFilter += fmt.Sprintf(",%s", constant.DeprecationConsumerFilterKey)
+	}
 	urlMap.Set(constant.ReferenceFilterKey, commonCfg.MergeValue(ref.Filter, "", defaultReferenceFilter))
 
 	for _, v := range ref.MethodsConfig {
 		urlMap.Set("methods."+v.Name+"."+constant.LoadbalanceKey, v.LoadBalance)
 		urlMap.Set("methods."+v.Name+"."+constant.RetriesKey, v.Retries)
 		urlMap.Set("methods."+v.Name+"."+constant.StickyKey, strconv.FormatBool(v.Sticky))
+		urlMap.Set("methods."+v.Name+"."+constant.IdempotentKey, strconv.FormatBool(v.Idempotent))
+		urlMap.Set("methods."+v.Name+"."+constant.MaxRequestSizeKey, v.MaxRequestSize)
+		urlMap.Set("methods."+v.Name+"."+constant.MaxResponseSizeKey, v.MaxResponseSize)
 		if len(v.RequestTimeout) != 0 {
 			urlMap.Set("methods."+v.Name+"."+constant.TimeoutKey, v.RequestTimeout)
 		}
@@ -412,6 +430,16 @@ func (refOpts *ReferenceOptions) getURLMap() url.Values {
 	return urlMap
 }
 
+// needDeprecationWarning reports whether the deprecation consumer filter should be appended, that is,
+// the reference doesn't opt out by deprecation.warn=false and filter/deprecation is imported.
+func needDeprecationWarning(urlMap url.Values) bool {
+	if warn, err := strconv.ParseBool(urlMap.Get(constant.DeprecationWarnKey)); err == nil && !warn {
+		return false
+	}
+	_, ok := extension.GetFilter(constant.DeprecationConsumerFilterKey)
+	return ok
+}
+
 // todo: figure this out
 //// GenericLoad ...
 //func (opts *ReferenceOptions) GenericLoad(id string) {
@@ -26,10 +26,11 @@ import (
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/criticality"
+	"dubbo.apache.org/dubbo-go/v3/common/tenant"
 	"dubbo.apache.org/dubbo-go/v3/metadata"
 	"dubbo.apache.org/dubbo-go/v3/protocol/base"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
-	"dubbo.apache.org/dubbo-go/v3/protocol/result"
 )
 
 // ConsumerConfig
@@ -69,7 +70,9 @@ type Connection struct {
 	refOpts *ReferenceOptions
 }
 
-func (conn *Connection) call(ctx context.Context, reqs []any, resp any, methodName, callType string, opts ...CallOption) (result.Result, error) {
+// call invokes the call by the invoker of the reference, which runs the interceptors of the reference.
+// It returns the stream of a streaming call.
+func (conn *Connection) call(ctx context.Context, reqs []any, resp any, methodName, callType string, opts ...CallOption) (any, error) {
 	options := newDefaultCallOptions()
 	for _, opt := range opts {
 		opt(options)
@@ -78,39 +81,39 @@ func (conn *Connection) call(ctx context.Context, reqs []any, resp any, methodNa
 	if err != nil {
 		return nil, err
 	}
-	return conn.refOpts.invoker.Invoke(ctx, inv), nil
+	// the tenant is attached before routing, so that the tenant router sees it
+	if id := tenant.FromContext(ctx); id != "" {
+		inv.SetAttachment(constant.TenantKey, id)
+	}
+	// the criticality of the call, or of the request being handled, is propagated to the provider
+	if options.Criticality != "" {
+		inv.SetAttachment(constant.CriticalityKey, options.Criticality)
+	} else if level := criticality.FromContext(ctx); level != "" {
+		inv.SetAttachment(constant.CriticalityKey, level)
+	}
+
+	res := conn.refOpts.invoker.Invoke(ctx, inv)
+	if callType == constant.CallUnary {
+		return nil, res.Error()
+	}
+	return res.Result(), res.Error()
 }
 
 func (conn *Connection) CallUnary(ctx context.Context, reqs []any, resp any, methodName string, opts ...CallOption) error {
-	res, err := conn.call(ctx, reqs, resp, methodName, constant.CallUnary, opts...)
-	if err != nil {
-		return err
-	}
-	return res.Error()
+	_, err := conn.call(ctx, reqs, resp, methodName, constant.CallUnary, opts...)
+	return err
 }
 
 func (conn *Connection) CallClientStream(ctx context.Context, methodName string, opts ...CallOption) (any, error) {
-	res, err := conn.call(ctx, nil, nil, methodName, constant.CallClientStream, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return res.Result(), res.Error()
+	return conn.call(ctx, nil, nil, methodName, constant.CallClientStream, opts...)
 }
 
 func (conn *Connection) CallServerStream(ctx context.Context, req any, methodName string, opts ...CallOption) (any, error) {
-	res, err := conn.call(ctx, []any{req}, nil, methodName, constant.CallServerStream, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return res.Result(), res.Error()
+	return conn.call(ctx, []any{req}, nil, methodName, constant.CallServerStream, opts...)
 }
 
 func (conn *Connection) CallBidiStream(ctx context.Context, methodName string, opts ...CallOption) (any, error) {
-	res, err := conn.call(ctx, nil, nil, methodName, constant.CallBidiStream, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return res.Result(), res.Error()
+	return conn.call(ctx, nil, nil, methodName, constant.CallBidiStream, opts...)
 }
 
 func (cli *Client) NewService(service any, opts ...ReferenceOption) (*Connection, error) {
@@ -169,6 +172,7 @@ func (cli *Client) dial(interfaceName string, info *ClientInfo, srv any, opts ..
 		setMetrics(cli.cliOpts.Metrics),
 		setOtel(cli.cliOpts.Otel),
 		setTLS(cli.cliOpts.TLS),
+		setInterceptors(cli.cliOpts.interceptors),
 		// this config must be set after Reference initialized
 		setInterfaceName(interfaceName),
 	}
@@ -73,9 +73,22 @@ func compatMethodConfig(c *global.MethodConfig) *config.MethodConfig {
 		TpsLimitInterval:            c.TpsLimitInterval,
 		TpsLimitRate:                c.TpsLimitRate,
 		TpsLimitStrategy:            c.TpsLimitStrategy,
+		TpsLimitBurst:               c.TpsLimitBurst,
+		TpsLimitMaxWait:             c.TpsLimitMaxWait,
+		TpsLimitBy:                  c.TpsLimitBy,
 		ExecuteLimit:                c.ExecuteLimit,
 		ExecuteLimitRejectedHandler: c.ExecuteLimitRejectedHandler,
 		Sticky:                      c.Sticky,
 		RequestTimeout:              c.RequestTimeout,
+		Validation:                  c.Validation,
+		Idempotent:                  c.Idempotent,
+		MaxRequestSize:              c.MaxRequestSize,
+		MaxResponseSize:             c.MaxResponseSize,
+		Deprecated:                  c.Deprecated,
+		Sunset:                      c.Sunset,
+		Replacement:                 c.Replacement,
+		Audit:                       c.Audit,
+		AuditFields:                 c.AuditFields,
+		CacheControl:                c.CacheControl,
 	}
 }
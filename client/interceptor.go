@@ -0,0 +1,170 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"maps"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+)
+
+// Request is a call seen by the interceptors
+type Request struct {
+	// Method is the name of the method called
+	Method string
+	// CallType is the type of the call, like constant.CallUnary and constant.CallBidiStream
+	CallType string
+	// Messages are the request messages, which are the arguments of a unary call, the request of
+	// a server streaming call, or nil for the client and bidirectional streaming calls
+	Messages []any
+	// Header is the attachments sent to the provider, whose values are string or []string
+	Header map[string]any
+}
+
+// Response is the result of a unary call seen by the interceptors
+type Response struct {
+	// Message is the response message, which is filled once the call returns
+	Message any
+	// Header is the attachments returned by the provider
+	Header map[string]any
+}
+
+// UnaryFunc is the signature of a unary call. The Request and Response are passed on to the next
+// interceptor or the invoker, which may replace their messages and header.
+type UnaryFunc func(ctx context.Context, req *Request, resp *Response) error
+
+// StreamFunc is the signature of a streaming call, which returns the stream of the protocol,
+// e.g. *triple_protocol.BidiStreamForClient. Interceptors must return the stream as it is.
+type StreamFunc func(ctx context.Context, req *Request) (any, error)
+
+// Interceptor intercepts the calls of a reference, like a consumer filter, but configured directly by
+// WithInterceptors or WithClientInterceptors, without a registered name. The calls made by the Connection
+// and by the service struct implemented by the proxy are intercepted alike. Interceptors are invoked once
+// per call, before the routing, load balancing and retries, so that they work with any protocol.
+//
+// The returned functions must be safe to call concurrently.
+type Interceptor interface {
+	WrapUnary(UnaryFunc) UnaryFunc
+	WrapStream(StreamFunc) StreamFunc
+}
+
+// UnaryInterceptorFunc is an Interceptor wrapping the unary calls only
+type UnaryInterceptorFunc func(UnaryFunc) UnaryFunc
+
+// WrapUnary implements Interceptor by applying the interceptor function.
+func (f UnaryInterceptorFunc) WrapUnary(next UnaryFunc) UnaryFunc {
+	return f(next)
+}
+
+// WrapStream implements Interceptor with a no-op.
+func (f UnaryInterceptorFunc) WrapStream(next StreamFunc) StreamFunc {
+	return next
+}
+
+// StreamInterceptorFunc is an Interceptor wrapping the streaming calls only
+type StreamInterceptorFunc func(StreamFunc) StreamFunc
+
+// WrapUnary implements Interceptor with a no-op.
+func (f StreamInterceptorFunc) WrapUnary(next UnaryFunc) UnaryFunc {
+	return next
+}
+
+// WrapStream implements Interceptor by applying the interceptor function.
+func (f StreamInterceptorFunc) WrapStream(next StreamFunc) StreamFunc {
+	return f(next)
+}
+
+// wrapUnary composes the interceptors, the first of which is invoked first
+func wrapUnary(interceptors []Interceptor, next UnaryFunc) UnaryFunc {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		if interceptors[i] != nil {
+			next = interceptors[i].WrapUnary(next)
+		}
+	}
+	return next
+}
+
+// wrapStream composes the interceptors, the first of which is invoked first
+func wrapStream(interceptors []Interceptor, next StreamFunc) StreamFunc {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		if interceptors[i] != nil {
+			next = interceptors[i].WrapStream(next)
+		}
+	}
+	return next
+}
+
+// interceptedInvoker runs the interceptors before invoking the cluster invoker of a reference
+type interceptedInvoker struct {
+	base.Invoker
+	interceptors []Interceptor
+}
+
+// newInterceptedInvoker wraps the invoker by the interceptors, it returns the invoker as it is without interceptors
+func newInterceptedInvoker(invoker base.Invoker, interceptors []Interceptor) base.Invoker {
+	if len(interceptors) == 0 {
+		return invoker
+	}
+	return &interceptedInvoker{Invoker: invoker, interceptors: interceptors}
+}
+
+// Invoke passes the invocation to the interceptors as a Request, and invokes the one they pass on
+func (ii *interceptedInvoker) Invoke(ctx context.Context, inv base.Invocation) result.Result {
+	req := &Request{
+		Method:   inv.MethodName(),
+		CallType: constant.CallUnary,
+		Messages: inv.Arguments(),
+		Header:   maps.Clone(inv.Attachments()),
+	}
+	if callType, ok := inv.GetAttribute(constant.CallTypeKey); ok {
+		req.CallType, _ = callType.(string)
+	}
+	if req.Header == nil {
+		req.Header = make(map[string]any)
+	}
+
+	var res result.Result
+	var err error
+	var stream any
+	if req.CallType == constant.CallUnary {
+		invoke := func(ctx context.Context, req *Request, resp *Response) error {
+			res = ii.Invoker.Invoke(ctx, invocation.CopyWithArguments(inv, req.Messages, resp.Message, req.Header))
+			resp.Header = res.Attachments()
+			return res.Error()
+		}
+		err = wrapUnary(ii.interceptors, invoke)(ctx, req, &Response{Message: inv.Reply()})
+	} else {
+		invoke := func(ctx context.Context, req *Request) (any, error) {
+			res = ii.Invoker.Invoke(ctx, invocation.CopyWithArguments(inv, req.Messages, inv.Reply(), req.Header))
+			return res.Result(), res.Error()
+		}
+		stream, err = wrapStream(ii.interceptors, invoke)(ctx, req)
+	}
+	if res == nil {
+		// an interceptor returned without invoking
+		res = &result.RPCResult{Rest: stream}
+	}
+	res.SetError(err)
+	return res
+}
@@ -0,0 +1,95 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+import (
+	_ "dubbo.apache.org/dubbo-go/v3/cluster/cluster/failover"
+	_ "dubbo.apache.org/dubbo-go/v3/cluster/loadbalance/random"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/graceful_shutdown"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	_ "dubbo.apache.org/dubbo-go/v3/protocol/dubbo"
+	"dubbo.apache.org/dubbo-go/v3/proxy/proxy_factory"
+)
+
+// InterceptedGreetProvider greets with the token attached by the consumer
+type InterceptedGreetProvider struct{}
+
+func (*InterceptedGreetProvider) Greet(ctx context.Context, name string) (string, error) {
+	attachments, _ := ctx.Value(constant.AttachmentKey).(map[string]any)
+	return fmt.Sprintf("hello %s with %v", name, attachments["token"]), nil
+}
+
+// InterceptedGreetService is implemented by the proxy
+type InterceptedGreetService struct {
+	Greet func(ctx context.Context, name string) (string, error)
+}
+
+func TestInterceptorsWithProxyOverDubbo(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	const interfaceName = "org.apache.dubbo.InterceptedGreetProvider"
+	url, err := common.NewURL(fmt.Sprintf("dubbo://127.0.0.1:%d/%s?interface=%s&serialization=hessian2",
+		port, interfaceName, interfaceName))
+	require.NoError(t, err)
+	_, err = common.ServiceMap.Register(interfaceName, constant.DubboProtocol, "", "", &InterceptedGreetProvider{})
+	require.NoError(t, err)
+	exporter := extension.GetProtocol(constant.DubboProtocol).Export(&proxy_factory.ProxyInvoker{
+		BaseInvoker: *base.NewBaseInvoker(url),
+	})
+	defer exporter.UnExport()
+
+	var methods []string
+	interceptor := UnaryInterceptorFunc(func(next UnaryFunc) UnaryFunc {
+		return func(ctx context.Context, req *Request, resp *Response) error {
+			methods = append(methods, req.Method)
+			req.Header["token"] = "t-1"
+			req.Messages = []any{"interceptor"}
+			return next(ctx, req, resp)
+		}
+	})
+	cli, err := NewClient(WithClientProtocolDubbo(), WithClientSerialization(constant.Hessian2Serialization))
+	require.NoError(t, err)
+	svc := &InterceptedGreetService{}
+	_, err = cli.DialWithService(interfaceName, svc,
+		WithURL(fmt.Sprintf("dubbo://127.0.0.1:%d", port)),
+		WithInterceptors(interceptor))
+	require.NoError(t, err)
+
+	greeting, err := svc.Greet(context.Background(), "dubbo")
+	require.NoError(t, err)
+	assert.Equal(t, "hello interceptor with t-1", greeting)
+	assert.Equal(t, []string{"Greet"}, methods)
+}
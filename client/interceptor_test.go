@@ -0,0 +1,136 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+)
+
+type greetRequest struct {
+	Name string
+}
+
+type greetResponse struct {
+	Greeting string
+}
+
+// greetInvoker answers the unary calls with a greeting, and the streaming calls with the method name
+type greetInvoker struct {
+	base.BaseInvoker
+	invocation base.Invocation
+}
+
+func (gi *greetInvoker) Invoke(_ context.Context, invocation base.Invocation) result.Result {
+	gi.invocation = invocation
+	res := &result.RPCResult{}
+	if invocation.Reply() == nil {
+		res.SetResult(invocation.MethodName())
+		return res
+	}
+	req := invocation.Arguments()[0].(*greetRequest)
+	invocation.Reply().(*greetResponse).Greeting = "hello " + req.Name
+	res.SetAttachments(map[string]any{"server": "greeter"})
+	return res
+}
+
+func newTestConnection(interceptors ...Interceptor) (*Connection, *greetInvoker) {
+	url, _ := common.NewURL("tri://127.0.0.1:20000/GreetService")
+	invoker := &greetInvoker{BaseInvoker: *base.NewBaseInvoker(url)}
+	return &Connection{refOpts: &ReferenceOptions{invoker: newInterceptedInvoker(invoker, interceptors)}}, invoker
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	var order []string
+	record := func(name string) Interceptor {
+		return UnaryInterceptorFunc(func(next UnaryFunc) UnaryFunc {
+			return func(ctx context.Context, req *Request, resp *Response) error {
+				order = append(order, name)
+				return next(ctx, req, resp)
+			}
+		})
+	}
+	rewrite := UnaryInterceptorFunc(func(next UnaryFunc) UnaryFunc {
+		return func(ctx context.Context, req *Request, resp *Response) error {
+			assert.Equal(t, "Greet", req.Method)
+			assert.Equal(t, constant.CallUnary, req.CallType)
+			req.Messages = []any{&greetRequest{Name: "interceptor"}}
+			req.Header["token"] = "t-1"
+			if err := next(ctx, req, resp); err != nil {
+				return err
+			}
+			assert.Equal(t, "greeter", resp.Header["server"])
+			resp.Message.(*greetResponse).Greeting += "!"
+			return nil
+		}
+	})
+	conn, invoker := newTestConnection(record("first"), rewrite, record("last"), StreamInterceptorFunc(nil))
+
+	resp := &greetResponse{}
+	err := conn.CallUnary(context.Background(), []any{&greetRequest{Name: "dubbo"}}, resp, "Greet", WithCallCriticality("critical"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello interceptor!", resp.Greeting)
+	assert.Equal(t, []string{"first", "last"}, order)
+	token, _ := invoker.invocation.GetAttachment("token")
+	assert.Equal(t, "t-1", token)
+	level, _ := invoker.invocation.GetAttachment(constant.CriticalityKey)
+	assert.Equal(t, "critical", level)
+}
+
+func TestUnaryInterceptorShortCircuit(t *testing.T) {
+	denied := errors.New("denied")
+	conn, invoker := newTestConnection(UnaryInterceptorFunc(func(next UnaryFunc) UnaryFunc {
+		return func(ctx context.Context, req *Request, resp *Response) error {
+			return denied
+		}
+	}))
+	err := conn.CallUnary(context.Background(), []any{&greetRequest{Name: "dubbo"}}, &greetResponse{}, "Greet")
+	assert.Equal(t, denied, err)
+	assert.Nil(t, invoker.invocation)
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	var callTypes []string
+	conn, _ := newTestConnection(StreamInterceptorFunc(func(next StreamFunc) StreamFunc {
+		return func(ctx context.Context, req *Request) (any, error) {
+			callTypes = append(callTypes, req.CallType)
+			return next(ctx, req)
+		}
+	}))
+
+	stream, err := conn.CallBidiStream(context.Background(), "Chat")
+	require.NoError(t, err)
+	assert.Equal(t, "Chat", stream)
+	stream, err = conn.CallServerStream(context.Background(), &greetRequest{}, "Watch")
+	require.NoError(t, err)
+	assert.Equal(t, "Watch", stream)
+	assert.Equal(t, []string{constant.CallBidiStream, constant.CallServerStream}, callTypes)
+}
@@ -53,6 +53,7 @@ type ReferenceOptions struct {
 	urls         []*common.URL
 	metaDataType string
 	info         *ClientInfo
+	interceptors []Interceptor
 
 	methodsCompat     []*config.MethodConfig
 	applicationCompat *config.ApplicationConfig
@@ -165,6 +166,14 @@ func WithFilter(filter string) ReferenceOption {
 	}
 }
 
+// WithInterceptors appends the interceptors of the calls of this reference, which are invoked
+// in order, after the ones set by WithClientInterceptors.
+func WithInterceptors(interceptors ...Interceptor) ReferenceOption {
+	return func(opts *ReferenceOptions) {
+		opts.interceptors = append(opts.interceptors, interceptors...)
+	}
+}
+
 // WithInterface sets the interface name for the service reference.
 //
 // As a functional option, it is passed to a client constructor
@@ -323,6 +332,23 @@ func WithSerialization(serialization string) ReferenceOption {
 	}
 }
 
+// WithCompression sets the algorithm used to compress requests, such as
+// constant.CompressionGzip, constant.CompressionZstd or constant.CompressionSnappy.
+// Over the dubbo protocol only the requests to dubbo-go providers are compressed,
+// the algorithm is still accepted for the responses of the others.
+func WithCompression(compression string) ReferenceOption {
+	return func(opts *ReferenceOptions) {
+		opts.Reference.Compression = compression
+	}
+}
+
+// WithCompressMinBytes sets the size below which requests are sent uncompressed.
+func WithCompressMinBytes(min int) ReferenceOption {
+	return func(opts *ReferenceOptions) {
+		opts.Reference.CompressMinBytes = min
+	}
+}
+
 func WithProvidedBy(providedBy string) ReferenceOption {
 	return func(opts *ReferenceOptions) {
 		opts.Reference.ProvidedBy = providedBy
@@ -427,6 +453,24 @@ func WithParam(k, v string) ReferenceOption {
 	}
 }
 
+// WithMaxRequestSize limits the serialized size of the arguments of every method of this reference,
+// e.g. "512kib". Use config.WithMaxRequestSize to override it per method.
+func WithMaxRequestSize(size string) ReferenceOption {
+	return WithParam(constant.MaxRequestSizeKey, size)
+}
+
+// WithMaxResponseSize limits the serialized size of the result of every method of this reference,
+// e.g. "4mib". Use config.WithMaxResponseSize to override it per method.
+func WithMaxResponseSize(size string) ReferenceOption {
+	return WithParam(constant.MaxResponseSizeKey, size)
+}
+
+// WithoutDeprecationWarning stops warning about and counting the calls to the deprecated methods
+// of this reference, which the deprecation_consumer filter does by default.
+func WithoutDeprecationWarning() ReferenceOption {
+	return WithParam(constant.DeprecationWarnKey, "false")
+}
+
 // ---------- For framework ----------
 // These functions should not be invoked by users
 
@@ -472,6 +516,12 @@ func setOtel(oc *global.OtelConfig) ReferenceOption {
 	}
 }
 
+func setInterceptors(interceptors []Interceptor) ReferenceOption {
+	return func(opts *ReferenceOptions) {
+		opts.interceptors = append(opts.interceptors, interceptors...)
+	}
+}
+
 func setTLS(tls *global.TLSConfig) ReferenceOption {
 	return func(opts *ReferenceOptions) {
 		opts.TLS = tls
@@ -490,6 +540,7 @@ type ClientOptions struct {
 	overallReference  *global.ReferenceConfig
 	applicationCompat *config.ApplicationConfig
 	registriesCompat  map[string]*config.RegistryConfig
+	interceptors      []Interceptor
 }
 
 func defaultClientOptions() *ClientOptions {
@@ -605,6 +656,14 @@ func WithClientFilter(filter string) ClientOption {
 	}
 }
 
+// WithClientInterceptors appends the interceptors of the calls of every reference of this client,
+// which are invoked in order.
+func WithClientInterceptors(interceptors ...Interceptor) ClientOption {
+	return func(opts *ClientOptions) {
+		opts.interceptors = append(opts.interceptors, interceptors...)
+	}
+}
+
 // todo(DMwangnima): think about a more ideal configuration style
 func WithClientRegistryIDs(registryIDs ...string) ClientOption {
 	return func(opts *ClientOptions) {
@@ -941,6 +1000,7 @@ func SetClientTLS(tls *global.TLSConfig) ClientOption {
 type CallOptions struct {
 	RequestTimeout string
 	Retries        string
+	Criticality    string
 }
 
 type CallOption func(*CallOptions)
@@ -962,3 +1022,11 @@ func WithCallRetries(retries int) CallOption {
 		opts.Retries = strconv.Itoa(retries)
 	}
 }
+
+// WithCallCriticality the criticality of one specific call, e.g. criticality.Critical, which overrides the one
+// carried by the context. The providers near their capacity shed the less critical calls first.
+func WithCallCriticality(level string) CallOption {
+	return func(opts *CallOptions) {
+		opts.Criticality = level
+	}
+}
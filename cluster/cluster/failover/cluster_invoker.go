@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 )
 
 import (
@@ -88,6 +89,9 @@ func (invoker *failoverClusterInvoker) Invoke(ctx context.Context, invocation pr
 		res = ivk.Invoke(ctx, invocation)
 		if res.Error() != nil && !isBizError(res.Error()) {
 			providers = append(providers, ivk.GetURL().Key())
+			if i < retries && !waitRetryDelay(ctx, ivk.GetURL(), methodName, res.Error()) {
+				break
+			}
 			continue
 		}
 		return res
@@ -115,6 +119,33 @@ func isBizError(err error) bool {
 	return triple_protocol.IsWireError(err) && triple_protocol.CodeOf(err) == triple_protocol.CodeBizError
 }
 
+// waitRetryDelay waits for the delay suggested by the RetryInfo detail of err, if any.
+// The delay is at most retry.delay.max, 1s by default, and the timeout of the method.
+// It returns false without waiting if ctx would be done before the delay elapses.
+func waitRetryDelay(ctx context.Context, url *common.URL, methodName string, err error) bool {
+	delay, ok := triple_protocol.RetryDelayOf(err)
+	if !ok || delay <= 0 {
+		return true
+	}
+	maxDelay := url.GetParamDuration(constant.RetryDelayMaxKey, constant.DefaultRetryDelayMax)
+	if timeout, parseErr := time.ParseDuration(url.GetMethodParam(methodName, constant.TimeoutKey,
+		url.GetParam(constant.TimeoutKey, ""))); parseErr == nil && timeout > 0 {
+		maxDelay = min(maxDelay, timeout)
+	}
+	delay = min(delay, maxDelay)
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return false
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
 func getRetries(invokers []protocolbase.Invoker, methodName string, invocation protocolbase.Invocation) int {
 	// Todo(finalt) Temporarily solve the problem that the retries is not valid
 	if retries, ok := invocation.GetAttachment(constant.RetriesKey); ok {
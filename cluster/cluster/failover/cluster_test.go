@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"net/url"
 	"testing"
+	"time"
 )
 
 import (
@@ -38,6 +39,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/protocol/base"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
 )
 
 func normalInvoke(successCount int, urlParam url.Values, invocations ...*invocation.RPCInvocation) result.Result {
@@ -110,3 +112,70 @@ func TestFailoverDestroy(t *testing.T) {
 	clusterInvoker.Destroy()
 	assert.Equal(t, false, clusterInvoker.IsAvailable())
 }
+
+type retryInfoInvoker struct {
+	*base.BaseInvoker
+	calls int
+	delay time.Duration
+}
+
+func (ri *retryInfoInvoker) Invoke(ctx context.Context, invocation base.Invocation) result.Result {
+	ri.calls++
+	if ri.calls == 1 {
+		delay := ri.delay
+		if delay == 0 {
+			delay = 50 * time.Millisecond
+		}
+		return &result.RPCResult{Err: triple_protocol.NewRetryableError(triple_protocol.CodeUnavailable, "overloaded", delay)}
+	}
+	return &result.RPCResult{}
+}
+
+func TestFailoverInvokeRetryInfo(t *testing.T) {
+	extension.SetLoadbalance("random", random.NewRandomLoadBalance)
+	u, _ := common.NewURL("dubbo://192.168.1.1:20000/com.ikurento.user.UserProvider")
+	invoker := &retryInfoInvoker{BaseInvoker: base.NewBaseInvoker(u)}
+	clusterInvoker := newFailoverCluster().Join(static.NewDirectory([]base.Invoker{invoker}))
+
+	// the retry waits for the delay suggested by the provider
+	start := time.Now()
+	res := clusterInvoker.Invoke(context.Background(), &invocation.RPCInvocation{})
+	assert.NoError(t, res.Error())
+	assert.Equal(t, 2, invoker.calls)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	// no retry if the deadline is earlier than the suggested delay
+	invoker.calls = 0
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	res = clusterInvoker.Invoke(ctx, &invocation.RPCInvocation{})
+	assert.Error(t, res.Error())
+	assert.Equal(t, 1, invoker.calls)
+	delay, ok := triple_protocol.RetryDelayOf(res.Error())
+	assert.True(t, ok)
+	assert.Equal(t, 50*time.Millisecond, delay)
+}
+
+func TestFailoverInvokeRetryInfoClamped(t *testing.T) {
+	extension.SetLoadbalance("random", random.NewRandomLoadBalance)
+	u, _ := common.NewURL("dubbo://192.168.1.1:20000/com.ikurento.user.UserProvider?retry.delay.max=20ms")
+	invoker := &retryInfoInvoker{BaseInvoker: base.NewBaseInvoker(u), delay: time.Hour}
+	clusterInvoker := newFailoverCluster().Join(static.NewDirectory([]base.Invoker{invoker}))
+
+	// the suggested delay is clamped to retry.delay.max
+	start := time.Now()
+	res := clusterInvoker.Invoke(context.Background(), &invocation.RPCInvocation{})
+	assert.NoError(t, res.Error())
+	assert.Equal(t, 2, invoker.calls)
+	assert.Less(t, time.Since(start), time.Second)
+
+	// and to the timeout of the method
+	u, _ = common.NewURL("dubbo://192.168.1.1:20000/com.ikurento.user.UserProvider?methods.Get.timeout=20ms")
+	invoker = &retryInfoInvoker{BaseInvoker: base.NewBaseInvoker(u), delay: time.Hour}
+	clusterInvoker = newFailoverCluster().Join(static.NewDirectory([]base.Invoker{invoker}))
+	start = time.Now()
+	res = clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocation("Get", nil, nil))
+	assert.NoError(t, res.Error())
+	assert.Equal(t, 2, invoker.calls)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
@@ -0,0 +1,128 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package tenant provides the router pinning tenants to dedicated providers.
+/*
+ The providers dedicated to a tenant are selected by the labels of the tenant, which are matched against
+ the url params of the providers, see common/tenant for the configuration:
+
+ tenants:
+   - id: "acme"
+     labels:
+       pool: "acme"
+
+ The requests of "acme" are routed to the providers with the param pool=acme, and the requests of the
+ other tenants, or without a tenant, are routed to the providers not dedicated to any tenant. If no
+ provider is left, all the providers are kept, so that the requests still have a chance to succeed.
+*/
+package tenant
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/tenant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+)
+
+// PriorityRouter routes the requests of a tenant to its dedicated providers
+type PriorityRouter struct{}
+
+// NewTenantPriorityRouter constructs a new tenant router
+func NewTenantPriorityRouter() *PriorityRouter {
+	return &PriorityRouter{}
+}
+
+// Route Determine the target invokers list.
+func (p *PriorityRouter) Route(invokers []base.Invoker, url *common.URL, invocation base.Invocation) []base.Invoker {
+	if len(invokers) == 0 {
+		return invokers
+	}
+	// the tenants are configured by the application of the providers
+	config := tenant.GetConfig(invokers[0].GetURL().GetParam(constant.ApplicationKey, ""))
+	pinned := config.Pinned()
+	if len(pinned) == 0 {
+		return invokers
+	}
+
+	id := tenant.FromInvocation(invocation)
+	var result []base.Invoker
+	if t := config.Get(id); config.Configured(id) && len(t.Labels) > 0 {
+		result = filterInvokers(invokers, func(invoker base.Invoker) bool {
+			return matchLabels(invoker.GetURL(), t.Labels)
+		})
+	} else {
+		result = filterInvokers(invokers, func(invoker base.Invoker) bool {
+			for _, t := range pinned {
+				if matchLabels(invoker.GetURL(), t.Labels) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+	if len(result) == 0 {
+		logger.Warnf("[tenant router] no provider of %s is left for tenant %q, all the providers are used", url.Service(), id)
+		return invokers
+	}
+	return result
+}
+
+// URL Return URL in router
+func (p *PriorityRouter) URL() *common.URL {
+	return nil
+}
+
+// Priority Return Priority in router
+func (p *PriorityRouter) Priority() int64 {
+	return 0
+}
+
+// Notify starts watching the tenant configuration of the application
+func (p *PriorityRouter) Notify(invokers []base.Invoker) {
+	if len(invokers) == 0 {
+		return
+	}
+	application := invokers[0].GetURL().GetParam(constant.ApplicationKey, "")
+	if application == "" {
+		logger.Warn("url application is empty, tenant router will not be enabled")
+		return
+	}
+	tenant.GetConfig(application)
+}
+
+func matchLabels(url *common.URL, labels map[string]string) bool {
+	for k, v := range labels {
+		if url.GetParam(k, "") != v {
+			return false
+		}
+	}
+	return true
+}
+
+func filterInvokers(invokers []base.Invoker, keep func(base.Invoker) bool) []base.Invoker {
+	result := make([]base.Invoker, 0, len(invokers))
+	for _, invoker := range invokers {
+		if keep(invoker) {
+			result = append(result, invoker)
+		}
+	}
+	return result
+}
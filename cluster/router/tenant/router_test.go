@@ -0,0 +1,103 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tenant
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/tenant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+var providerUrls = []string{
+	"dubbo://127.0.0.1:20000/com.foo.BarService?application=tenant-app",
+	"dubbo://127.0.0.1:20001/com.foo.BarService?application=tenant-app",
+	"dubbo://127.0.0.1:20002/com.foo.BarService?application=tenant-app&pool=acme",
+	"dubbo://127.0.0.1:20003/com.foo.BarService?application=tenant-app&pool=globex&region=hangzhou",
+	"dubbo://127.0.0.1:20004/com.foo.BarService?application=tenant-app&pool=globex",
+}
+
+func newInvokers(t *testing.T) []base.Invoker {
+	invokers := make([]base.Invoker, 0, len(providerUrls))
+	for _, u := range providerUrls {
+		url, err := common.NewURL(u)
+		assert.Nil(t, err)
+		invokers = append(invokers, base.NewBaseInvoker(url))
+	}
+	return invokers
+}
+
+func ports(invokers []base.Invoker) []string {
+	result := make([]string, 0, len(invokers))
+	for _, invoker := range invokers {
+		result = append(result, invoker.GetURL().Port)
+	}
+	return result
+}
+
+func route(r *PriorityRouter, invokers []base.Invoker, id string) []string {
+	attachments := map[string]any{}
+	if id != "" {
+		attachments[constant.TenantKey] = id
+	}
+	consumerURL, _ := common.NewURL("consumer://127.0.0.1/com.foo.BarService")
+	return ports(r.Route(invokers, consumerURL, invocation.NewRPCInvocation("Echo", nil, attachments)))
+}
+
+func TestRoute(t *testing.T) {
+	r := NewTenantPriorityRouter()
+	invokers := newInvokers(t)
+
+	// no tenant is pinned
+	assert.Len(t, route(r, invokers, "acme"), len(providerUrls))
+
+	c, err := tenant.ParseConfig(`
+tenants:
+  - id: "acme"
+    labels: {pool: "acme"}
+  - id: "globex"
+    labels: {pool: "globex", region: "hangzhou"}
+  - id: "initech"
+    labels: {pool: "initech"}
+  - id: "*"
+    rate: 10
+`)
+	assert.Nil(t, err)
+	assert.Nil(t, tenant.SetConfig("tenant-app", c))
+	defer func() {
+		_ = tenant.SetConfig("tenant-app", nil)
+	}()
+
+	assert.Equal(t, []string{"20002"}, route(r, invokers, "acme"))
+	assert.Equal(t, []string{"20003"}, route(r, invokers, "globex"))
+	// the others are kept away from the dedicated providers
+	assert.Equal(t, []string{"20000", "20001", "20004"}, route(r, invokers, "umbrella"))
+	assert.Equal(t, []string{"20000", "20001", "20004"}, route(r, invokers, ""))
+	// all the providers are used if none of the dedicated ones is left
+	assert.Len(t, route(r, invokers, "initech"), len(providerUrls))
+	assert.Empty(t, route(r, nil, "acme"))
+}
@@ -0,0 +1,83 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"context"
+	"strings"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// NewContextWithAttachment returns a copy of ctx whose attachments carry the value of key,
+// the other attachments of ctx are kept.
+func NewContextWithAttachment(ctx context.Context, key, value string) context.Context {
+	attachments := make(map[string]any)
+	switch m := ctx.Value(constant.AttachmentKey).(type) {
+	case map[string]any:
+		for k, v := range m {
+			attachments[k] = v
+		}
+	case map[string]string:
+		for k, v := range m {
+			attachments[k] = v
+		}
+	}
+	attachments[key] = value
+	return context.WithValue(ctx, constant.AttachmentKey, attachments)
+}
+
+// AttachmentFromContext returns the value of key in the attachments of ctx, or "" if there is none
+func AttachmentFromContext(ctx context.Context, key string) string {
+	switch m := ctx.Value(constant.AttachmentKey).(type) {
+	case map[string]any:
+		value, _ := LookupAttachment(m, key)
+		return value
+	case map[string]string:
+		return m[key]
+	}
+	return ""
+}
+
+// LookupAttachment returns the value of key in the attachments. The attachments of triple are taken from
+// the http headers, whose keys may be canonicalized and whose values are wrapped by arrays.
+func LookupAttachment(attachments map[string]any, key string) (string, bool) {
+	value, ok := attachments[key]
+	if !ok {
+		for k, v := range attachments {
+			if strings.EqualFold(k, key) {
+				value, ok = v, true
+				break
+			}
+		}
+	}
+	if !ok {
+		return "", false
+	}
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case []string:
+		if len(v) > 0 {
+			return v[0], true
+		}
+	}
+	return "", false
+}
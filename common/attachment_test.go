@@ -0,0 +1,62 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+func TestContextAttachment(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constant.AttachmentKey, map[string]string{"trace": "t1"})
+	assert.Equal(t, "", AttachmentFromContext(ctx, "tenant-id"))
+
+	ctx = NewContextWithAttachment(ctx, "tenant-id", "acme")
+	assert.Equal(t, "acme", AttachmentFromContext(ctx, "tenant-id"))
+	// the other attachments are kept
+	assert.Equal(t, "t1", AttachmentFromContext(ctx, "trace"))
+	assert.Equal(t, "", AttachmentFromContext(context.Background(), "tenant-id"))
+}
+
+func TestLookupAttachment(t *testing.T) {
+	attachments := map[string]any{
+		"tenant-id":   "acme",
+		"Criticality": []string{"sheddable"},
+		"empty":       []string{},
+		"number":      1,
+	}
+	value, ok := LookupAttachment(attachments, "tenant-id")
+	assert.True(t, ok)
+	assert.Equal(t, "acme", value)
+	// the keys of the http headers are canonicalized
+	value, ok = LookupAttachment(attachments, "criticality")
+	assert.True(t, ok)
+	assert.Equal(t, "sheddable", value)
+	for _, key := range []string{"empty", "number", "missing"} {
+		_, ok = LookupAttachment(attachments, key)
+		assert.False(t, ok, key)
+	}
+}
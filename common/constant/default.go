@@ -30,8 +30,11 @@ const (
 	RouterProtocol   = "router"
 	DubboProtocol    = "dubbo"
 	TriProtocol      = "tri"
+	GRPCProtocol     = "grpc"
 	JSONRPCProtocol  = "jsonrpc"
 	RESTProtocol     = "rest"
+	UnixProtocol     = "unix"   // scheme of the urls addressing unix domain sockets
+	MemoryProtocol   = "memory" // scheme of the urls addressing in-memory listeners
 )
 
 const (
@@ -40,6 +43,7 @@ const (
 	DefaultLoadBalance      = "random"
 	DefaultRetries          = "2"
 	DefaultRetriesInt       = 2
+	DefaultRetryDelayMax    = "1s"
 	DefaultProtocol         = "dubbo"
 	DefaultRegTimeout       = "5s"
 	DefaultRegTTL           = "15m"
@@ -47,6 +47,7 @@ const (
 	TokenKey               = "token"
 	LocalAddr              = "local-addr"
 	RemoteAddr             = "remote-addr"
+	RemoteApplicationKey   = "remote.application" // the application name of the consumer, sent as an attachment
 	DefaultRemotingTimeout = 1000
 	ReleaseKey             = "release"
 	AnyhostKey             = "anyhost"
@@ -99,10 +100,20 @@ const (
 	AccessLogFilterKey                   = "accesslog"
 	ActiveFilterKey                      = "active"
 	AdaptiveServiceProviderFilterKey     = "padasvc"
+	AuditFilterKey                       = "audit"
 	AuthConsumerFilterKey                = "sign"
 	AuthProviderFilterKey                = "auth"
+	CircuitBreakerFilterKey              = "circuit_breaker"
+	DeprecationConsumerFilterKey         = "deprecation_consumer"
+	DeprecationProviderFilterKey         = "deprecation_provider"
 	EchoFilterKey                        = "echo"
+	EncryptionConsumerFilterKey          = "encryption_consumer"
+	EncryptionProviderFilterKey          = "encryption_provider"
 	ExecuteLimitFilterKey                = "execute"
+	FaultInjectionConsumerFilterKey      = "fault_consumer"
+	FaultInjectionProviderFilterKey      = "fault_provider"
+	IdempotentConsumerFilterKey          = "idempotent_consumer"
+	IdempotentProviderFilterKey          = "idempotent_provider"
 	GenericFilterKey                     = "generic"
 	GenericServiceFilterKey              = "generic_service"
 	GracefulShutdownProviderFilterKey    = "pshutdown"
@@ -113,10 +124,13 @@ const (
 	MetricsFilterKey                     = "metrics"
 	SeataFilterKey                       = "seata"
 	SentinelProviderFilterKey            = "sentinel-provider"
+	ServerTimeoutFilterKey               = "server_timeout"
 	SentinelConsumerFilterKey            = "sentinel-consumer"
+	TenantFilterKey                      = "tenant"
 	TokenFilterKey                       = "token"
 	TpsLimitFilterKey                    = "tps"
 	TracingFilterKey                     = "tracing"
+	ValidationFilterKey                  = "validation"
 	XdsCircuitBreakerKey                 = "xds_circuit_reaker"
 	OTELServerTraceKey                   = "otelServerTrace"
 	OTELClientTraceKey                   = "otelClientTrace"
@@ -130,6 +144,7 @@ const (
 	WeightKey                          = "weight"
 	WarmupKey                          = "warmup"
 	RetriesKey                         = "retries"
+	RetryDelayMaxKey                   = "retry.delay.max"
 	StickyKey                          = "sticky"
 	BeanName                           = "bean.name"
 	FailBackTasksKey                   = "failbacktasks"
@@ -143,10 +158,67 @@ const (
 	TPSLimitIntervalKey                = "tps.limit.interval"
 	DefaultTPSLimitInterval            = -1
 	TPSLimitStrategyKey                = "tps.limit.strategy"
+	TPSLimitBurstKey                   = "tps.limit.burst"
+	TPSLimitMaxWaitKey                 = "tps.limit.max.wait"
+	TPSLimitByKey                      = "tps.limit.by"
+	TPSLimitByApplication              = "application"
+	TPSLimitByAttachmentPrefix         = "attachment:"
+	TPSLimitStoreKey                   = "tps.limit.store"
+	TPSLimitStoreAddressKey            = "tps.limit.store.address"
+	TPSLimitStorePasswordKey           = "tps.limit.store.password"
+	TPSLimitStoreTimeoutKey            = "tps.limit.store.timeout"
+	DefaultTPSLimitStoreTimeout        = "100ms"
+	TPSLimitLeaseKey                   = "tps.limit.lease"
+	TPSLimitLocalRateKey               = "tps.limit.local.rate"
+	TPSLimitProvidersKey               = "tps.limit.providers"
+	TPSLimitDegradeKey                 = "tps.limit.degrade"
+	DefaultTPSLimitDegrade             = "5s"
+	AccessLogFormatKey                 = "accesslog.format"
+	AccessLogFieldsKey                 = "accesslog.fields"
+	AccessLogSampleRateKey             = "accesslog.sample.rate"
+	AccessLogRedactKey                 = "accesslog.redact"
+	AccessLogSinkKey                   = "accesslog.sink"
+	AccessLogFileMaxSizeKey            = "accesslog.file.max.size"
+	AccessLogFileMaxBackupsKey         = "accesslog.file.max.backups"
 	ExecuteLimitKey                    = "execute.limit"
 	DefaultExecuteLimit                = "-1"
 	ExecuteRejectedExecutionHandlerKey = "execute.limit.rejected.handler"
+	ValidationKey                      = "validation"
+	FaultInjectedKey                   = "dubbo.fault.injected"
+	IdempotentKey                      = "idempotent"
+	IdempotencyKeyAttachmentKey        = "idempotency-key"
+	IdempotentReplayedKey              = "idempotency-replayed"
+	IdempotentStoreKey                 = "idempotent.store"
+	IdempotentTTLKey                   = "idempotent.ttl"
+	DefaultIdempotentTTL               = "10m"
+	MaxRequestSizeKey                  = "max-request-size"
+	MaxResponseSizeKey                 = "max-response-size"
+	RequestBodyLenKey                  = "request-body-len"
+	TenantKey                          = "tenant-id"
+	DeadlineKey                        = "deadline"
+	ServerTimeoutKey                   = "server.timeout"
+	DeprecatedKey                      = "deprecated"
+	SunsetKey                          = "sunset"
+	ReplacementKey                     = "replacement"
+	SunsetRejectKey                    = "sunset.reject"
+	DeprecationWarnKey                 = "deprecation.warn"
+	CriticalityKey                     = "criticality"
+	CriticalitySheddingKey             = "criticality.shedding"
+	AuditKey                           = "audit"
+	AuditFieldsKey                     = "audit.fields"
+	AuditSinkKey                       = "audit.sink"
+	AuditFileKey                       = "audit.file"
+	AuditSecretEnvKey                  = "audit.secret.env"
+	PrincipalKey                       = "dubbo.principal"
 	SerializationKey                   = "serialization"
+	CompressionKey                     = "compression"
+	CompressMinBytesKey                = "compress-min-bytes"
+	ErrorStatusKey                     = "error-status"
+	CacheControlKey                    = "cache-control"
+	UnixSocketKey                      = "unix-socket"
+	UnixSocketModeKey                  = "unix-socket-mode"
+	InMemoryKey                        = "in-memory"
+	WebSocketKey                       = "websocket"
 	PIDKey                             = "pid"
 	SyncReportKey                      = "sync.report"
 	RetryPeriodKey                     = "retry.period"
@@ -168,6 +240,29 @@ const (
 	TLSConfigKey                       = "tls-config"
 )
 
+// circuit breaker keys, they can be configured at method-level too
+const (
+	CircuitBreakerWindowKey              = "circuit-breaker.window"
+	CircuitBreakerBucketsKey             = "circuit-breaker.buckets"
+	CircuitBreakerMinRequestsKey         = "circuit-breaker.min-requests"
+	CircuitBreakerErrorRatioKey          = "circuit-breaker.error-ratio"
+	CircuitBreakerSlowCallDurationKey    = "circuit-breaker.slow-call-duration"
+	CircuitBreakerSlowCallRatioKey       = "circuit-breaker.slow-call-ratio"
+	CircuitBreakerConsecutiveFailuresKey = "circuit-breaker.consecutive-failures"
+	CircuitBreakerOpenDurationKey        = "circuit-breaker.open-duration"
+	CircuitBreakerHalfOpenRequestsKey    = "circuit-breaker.half-open-requests"
+)
+
+// payload encryption keys
+const (
+	EncryptionRequiredKey            = "encryption.required"
+	EncryptionKeyProviderKey         = "encryption.key-provider"
+	EncryptionKeyFileKey             = "encryption.key-file"
+	EncryptionKeyIDAttachmentKey     = "encryption-key-id"
+	EncryptionRequestIDAttachmentKey = "encryption-request-id"
+	DefaultEncryptionKeyProvider     = "file"
+)
+
 const (
 	DubboGoCtxKey = DubboCtxKey("dubbogo-ctx")
 )
@@ -340,6 +435,9 @@ const (
 	MeshRouteSuffix                   = ".MESHAPPRULE"      // Specify mesh router suffix
 	ForceUseTag                       = "dubbo.force.tag"   // the tag in attachment
 	ForceUseCondition                 = "dubbo.force.condition"
+	FaultInjectionRuleSuffix          = ".fault-injection"
+	CircuitBreakerRuleSuffix          = ".circuit-breaker"
+	TenantRuleSuffix                  = ".tenants"
 	Tagkey                            = "dubbo.tag" // key of tag
 	ConditionKey                      = "dubbo.condition"
 	AttachmentKey                     = DubboCtxKey("attachment") // key in context in invoker
@@ -357,6 +455,7 @@ const (
 	Scope                             = "scope"
 	Wildcard                          = "wildcard"
 	MeshRouterFactoryKey              = "mesh"
+	TenantRouterFactoryKey            = "tenant"
 	DefaultRouteConditionSubSetWeight = 100
 )
 
@@ -427,6 +526,21 @@ const (
 	AdaptiveServiceInflightKey  = "adaptive-service.inflight"
 	AdaptiveServiceEnabledKey   = "adaptive-service.enabled"
 	AdaptiveServiceIsEnabled    = "1"
+
+	// AdaptiveServiceLimiterKey selects the limiter of a service, hill-climbing, gradient2 or vegas.
+	// The limiter is configured by the parameters with the prefix "adaptive-service.limiter.".
+	AdaptiveServiceLimiterKey                = "adaptive-service.limiter"
+	AdaptiveServiceLimiterInitialLimitKey    = "adaptive-service.limiter.initial-limit"
+	AdaptiveServiceLimiterMinLimitKey        = "adaptive-service.limiter.min-limit"
+	AdaptiveServiceLimiterMaxLimitKey        = "adaptive-service.limiter.max-limit"
+	AdaptiveServiceLimiterSmoothingKey       = "adaptive-service.limiter.smoothing"
+	AdaptiveServiceLimiterQueueSizeKey       = "adaptive-service.limiter.queue-size"
+	AdaptiveServiceLimiterRTTToleranceKey    = "adaptive-service.limiter.rtt-tolerance"
+	AdaptiveServiceLimiterWindowKey          = "adaptive-service.limiter.window"
+	AdaptiveServiceLimiterLongWindowKey      = "adaptive-service.limiter.long-window"
+	AdaptiveServiceLimiterAlphaKey           = "adaptive-service.limiter.alpha"
+	AdaptiveServiceLimiterBetaKey            = "adaptive-service.limiter.beta"
+	AdaptiveServiceLimiterProbeMultiplierKey = "adaptive-service.limiter.probe-multiplier"
 )
 
 // reflection service
@@ -19,11 +19,12 @@ package constant
 
 // metrics type
 const (
-	MetricsRegistry     = "dubbo.metrics.registry"
-	MetricsMetadata     = "dubbo.metrics.metadata"
-	MetricsApp          = "dubbo.metrics.app"
-	MetricsConfigCenter = "dubbo.metrics.configCenter"
-	MetricsRpc          = "dubbo.metrics.rpc"
+	MetricsRegistry       = "dubbo.metrics.registry"
+	MetricsMetadata       = "dubbo.metrics.metadata"
+	MetricsApp            = "dubbo.metrics.app"
+	MetricsConfigCenter   = "dubbo.metrics.configCenter"
+	MetricsRpc            = "dubbo.metrics.rpc"
+	MetricsCircuitBreaker = "dubbo.metrics.circuitBreaker"
 )
 
 const (
@@ -41,6 +42,14 @@ const (
 	TagGroup              = "group"
 	TagVersion            = "version"
 	TagErrorCode          = "error"
+	TagFault              = "fault"
+	TagLimiter            = "limiter"
+	TagAddress            = "address"
+	TagState              = "state"
+	TagDirection          = "direction"
+	TagTenant             = "tenant"
+	TagReason             = "reason"
+	TagSunset             = "sunset"
 )
 const (
 	MetricNamespace                     = "dubbo"
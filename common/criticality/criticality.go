@@ -0,0 +1,120 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package criticality tells the importance of a request, so that the provider limiters shed the less critical
+// requests first when they are near their capacity.
+/*
+ The criticality travels with the attachment "criticality". It is set per call by client.WithCallCriticality,
+ or put into the context by criticality.NewContext. A provider handling a request passes its context on to
+ the downstream calls, so that the whole request tree keeps the criticality.
+
+ The shedding is enabled per service, and takes effect on the execute limit and the adaptive service filters:
+
+ services:
+   "UserProvider":
+     interface: "com.ikurento.user.UserProvider"
+     execute.limit: 200
+     params:
+       criticality.shedding: "true"
+
+ Each level may occupy a share of the capacity of the limiters. Once the share is used up, the requests
+ of the level are rejected, while the ones of the more critical levels still get in:
+
+   critical    1.0    the requests the users are waiting for
+   default     0.9    the requests without a criticality
+   sheddable   0.7    the requests which can be retried later, like batch jobs
+
+ Other levels are registered by Register, e.g. Register("background", 0.5).
+*/
+package criticality
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+)
+
+// the built-in levels
+const (
+	Critical  = "critical"
+	Default   = "default"
+	Sheddable = "sheddable"
+)
+
+var (
+	sharesMutex sync.RWMutex
+	shares      = map[string]float64{
+		Critical:  1.0,
+		Default:   0.9,
+		Sheddable: 0.7,
+	}
+)
+
+// Register sets the share of the capacity the requests of the level may occupy, in (0, 1]
+func Register(level string, share float64) error {
+	if share <= 0 || share > 1 {
+		return fmt.Errorf("the share of criticality %s should be in (0, 1], but it is %v", level, share)
+	}
+	sharesMutex.Lock()
+	defer sharesMutex.Unlock()
+	shares[strings.ToLower(level)] = share
+	return nil
+}
+
+// Share returns the share of the capacity of the level, the unknown levels are taken as Default
+func Share(level string) float64 {
+	sharesMutex.RLock()
+	defer sharesMutex.RUnlock()
+	if share, ok := shares[strings.ToLower(level)]; ok {
+		return share
+	}
+	return shares[Default]
+}
+
+// Admit reports whether the invocation is admitted by a limiter with inflight requests, including the
+// invocation itself, under the limit. The invocations are admitted as long as the shedding is disabled.
+func Admit(url *common.URL, invocation base.Invocation, inflight, limit uint64) bool {
+	if !url.GetParamBool(constant.CriticalitySheddingKey, false) {
+		return true
+	}
+	return float64(inflight) <= float64(limit)*Share(FromInvocation(invocation))
+}
+
+// NewContext returns a copy of ctx whose attachments carry the criticality
+func NewContext(ctx context.Context, level string) context.Context {
+	return common.NewContextWithAttachment(ctx, constant.CriticalityKey, level)
+}
+
+// FromContext returns the criticality carried by the attachments of ctx, or "" if there is none
+func FromContext(ctx context.Context) string {
+	return common.AttachmentFromContext(ctx, constant.CriticalityKey)
+}
+
+// FromInvocation returns the criticality attached to the invocation, Default if there is none
+func FromInvocation(invocation base.Invocation) string {
+	if level, ok := common.LookupAttachment(invocation.Attachments(), constant.CriticalityKey); ok && level != "" {
+		return level
+	}
+	return Default
+}
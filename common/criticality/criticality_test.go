@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package criticality
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+func TestShare(t *testing.T) {
+	assert.Equal(t, 1.0, Share(Critical))
+	assert.Equal(t, 0.7, Share("Sheddable"))
+	assert.Equal(t, Share(Default), Share("unknown"))
+
+	assert.Nil(t, Register("background", 0.5))
+	assert.Equal(t, 0.5, Share("background"))
+	assert.NotNil(t, Register("invalid", 0))
+	assert.NotNil(t, Register("invalid", 1.5))
+	assert.Equal(t, Share(Default), Share("invalid"))
+}
+
+func TestAdmit(t *testing.T) {
+	inv := func(level string) *invocation.RPCInvocation {
+		return invocation.NewRPCInvocation("GetUser", nil, map[string]any{constant.CriticalityKey: level})
+	}
+
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider")
+	assert.True(t, Admit(url, inv(Sheddable), 100, 10))
+
+	url.SetParam(constant.CriticalitySheddingKey, "true")
+	assert.False(t, Admit(url, inv(Sheddable), 8, 10))
+	assert.True(t, Admit(url, inv(Sheddable), 7, 10))
+	assert.True(t, Admit(url, inv(Default), 9, 10))
+	assert.False(t, Admit(url, invocation.NewRPCInvocation("GetUser", nil, nil), 10, 10))
+	assert.True(t, Admit(url, inv(Critical), 10, 10))
+}
+
+func TestContext(t *testing.T) {
+	assert.Equal(t, "", FromContext(context.Background()))
+
+	ctx := context.WithValue(context.Background(), constant.AttachmentKey, map[string]any{"k": "v"})
+	ctx = NewContext(ctx, Critical)
+	assert.Equal(t, Critical, FromContext(ctx))
+	assert.Equal(t, "v", ctx.Value(constant.AttachmentKey).(map[string]any)["k"])
+
+	ctx = context.WithValue(context.Background(), constant.AttachmentKey, map[string]any{constant.CriticalityKey: []string{Sheddable}})
+	assert.Equal(t, Sheddable, FromContext(ctx))
+}
@@ -0,0 +1,43 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package extension
+
+import (
+	"errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/filter"
+)
+
+var idempotentStores = make(map[string]func() filter.IdempotentStore)
+
+// SetIdempotentStore sets the IdempotentStore with @name
+func SetIdempotentStore(name string, creator func() filter.IdempotentStore) {
+	idempotentStores[name] = creator
+}
+
+// GetIdempotentStore finds the IdempotentStore with @name
+func GetIdempotentStore(name string) (filter.IdempotentStore, error) {
+	creator, ok := idempotentStores[name]
+	if !ok {
+		return nil, errors.New("IdempotentStore for " + name + " is not existing, make sure you have import the package " +
+			"and you have register it by invoking extension.SetIdempotentStore.")
+	}
+	return creator(), nil
+}
@@ -28,6 +28,7 @@ import (
 var (
 	tpsLimitStrategy = make(map[string]filter.TpsLimitStrategyCreator)
 	tpsLimiter       = make(map[string]func() filter.TpsLimiter)
+	tpsLimitStore    = make(map[string]filter.TpsLimitStoreCreator)
 )
 
 // SetTpsLimiter sets the TpsLimiter with @name
@@ -59,3 +60,18 @@ func GetTpsLimitStrategyCreator(name string) (filter.TpsLimitStrategyCreator, er
 	}
 	return creator, nil
 }
+
+// SetTpsLimitStore sets the TpsLimitStoreCreator with @name
+func SetTpsLimitStore(name string, creator filter.TpsLimitStoreCreator) {
+	tpsLimitStore[name] = creator
+}
+
+// GetTpsLimitStoreCreator finds the TpsLimitStoreCreator with @name
+func GetTpsLimitStoreCreator(name string) (filter.TpsLimitStoreCreator, error) {
+	creator, ok := tpsLimitStore[name]
+	if !ok {
+		return nil, errors.New("TpsLimitStore for " + name + " is not existing, make sure you have import the package " +
+			"and you have register it by invoking extension.SetTpsLimitStore.")
+	}
+	return creator, nil
+}
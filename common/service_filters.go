@@ -0,0 +1,91 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// optionalServiceFilters are the provider filters added to the service when their params are enabled,
+// at the service level, or at the method level as "methods.{method}.{param}" if methods is set.
+// The filters marked first are put ahead of the others, so that they see the calls rejected by them.
+var optionalServiceFilters = []struct {
+	filter  string
+	param   string
+	methods bool
+	first   bool
+}{
+	{filter: constant.ValidationFilterKey, param: constant.ValidationKey, methods: true},
+	{filter: constant.IdempotentProviderFilterKey, param: constant.IdempotentKey, methods: true},
+	{filter: constant.DeprecationProviderFilterKey, param: constant.SunsetRejectKey},
+	// the calls rejected by echo, token, tps or auth etc. are audited as well
+	{filter: constant.AuditFilterKey, param: constant.AuditKey, methods: true, first: true},
+}
+
+// AppendOptionalServiceFilters adds to filters the optional provider filters enabled by params,
+// which are not configured yet.
+func AppendOptionalServiceFilters(filters string, params url.Values) string {
+	configured := make(map[string]struct{})
+	for _, f := range strings.Split(filters, ",") {
+		configured[strings.TrimSpace(f)] = struct{}{}
+	}
+	for _, optional := range optionalServiceFilters {
+		if _, ok := configured[optional.filter]; ok {
+			continue
+		}
+		if !paramEnabled(params, optional.param, optional.methods) {
+			continue
+		}
+		switch {
+		case filters == "":
+			filters = optional.filter
+		case optional.first:
+			filters = optional.filter + "," + filters
+		default:
+			filters += "," + optional.filter
+		}
+	}
+	return filters
+}
+
+func paramEnabled(params url.Values, param string, methods bool) bool {
+	for k, v := range params {
+		if k != param {
+			if !methods || !strings.HasPrefix(k, constant.MethodKeys+".") {
+				continue
+			}
+			// the method names contain no dots
+			method := strings.TrimPrefix(k, constant.MethodKeys+".")
+			if i := strings.Index(method, "."); i < 0 || method[i+1:] != param {
+				continue
+			}
+		}
+		if len(v) > 0 {
+			if enabled, _ := strconv.ParseBool(v[0]); enabled {
+				return true
+			}
+		}
+	}
+	return false
+}
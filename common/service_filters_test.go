@@ -0,0 +1,65 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"net/url"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+func TestAppendOptionalServiceFilters(t *testing.T) {
+	params := url.Values{}
+	params.Set("methods.GetUser."+constant.ValidationKey, "false")
+	assert.Equal(t, "echo", AppendOptionalServiceFilters("echo", params))
+
+	params.Set("methods.SetUser."+constant.ValidationKey, "true")
+	assert.Equal(t, "echo,"+constant.ValidationFilterKey, AppendOptionalServiceFilters("echo", params))
+	// the configured filters are not appended again
+	assert.Equal(t, "echo,"+constant.ValidationFilterKey,
+		AppendOptionalServiceFilters("echo,"+constant.ValidationFilterKey, params))
+
+	params = url.Values{}
+	params.Set(constant.ValidationKey, "true")
+	assert.Equal(t, "echo,"+constant.ValidationFilterKey, AppendOptionalServiceFilters("echo", params))
+
+	params.Set("methods.GetUser."+constant.IdempotentKey, "true")
+	assert.Equal(t, "echo,"+constant.ValidationFilterKey+","+constant.IdempotentProviderFilterKey,
+		AppendOptionalServiceFilters("echo", params))
+
+	// the deprecation filter is only enabled at the service level
+	params = url.Values{}
+	params.Set("methods.GetUser."+constant.SunsetRejectKey, "true")
+	assert.Equal(t, "echo", AppendOptionalServiceFilters("echo", params))
+	params.Set(constant.SunsetRejectKey, "true")
+	assert.Equal(t, "echo,"+constant.DeprecationProviderFilterKey, AppendOptionalServiceFilters("echo", params))
+
+	// the audit filter is put ahead of the others
+	params.Set("methods.GetUser."+constant.AuditKey, "true")
+	assert.Equal(t, constant.AuditFilterKey+",echo,auth,"+constant.DeprecationProviderFilterKey,
+		AppendOptionalServiceFilters("echo,auth", params))
+
+	assert.Equal(t, "echo", AppendOptionalServiceFilters("echo", url.Values{}))
+}
@@ -0,0 +1,71 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"errors"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+// ListenUnixSocket listens on the unix domain socket at path. A stale socket file left by
+// a previous process is removed first, the socket still served by a live process is not.
+// The file permission is set to mode, an octal string like "0660", if it's not empty.
+func ListenUnixSocket(path, mode string) (net.Listener, error) {
+	var perm os.FileMode
+	if mode != "" {
+		parsed, err := strconv.ParseUint(mode, 8, 32)
+		if err != nil || parsed > uint64(fs.ModePerm) {
+			return nil, perrors.Errorf("invalid unix socket mode %q", mode)
+		}
+		perm = os.FileMode(parsed)
+	}
+
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&fs.ModeSocket == 0 {
+			return nil, perrors.Errorf("unix socket %s: file exists and is not a socket", path)
+		}
+		if conn, dialErr := net.Dial("unix", path); dialErr == nil {
+			_ = conn.Close()
+			return nil, perrors.Errorf("unix socket %s: address already in use", path)
+		}
+		if err = os.Remove(path); err != nil {
+			return nil, perrors.WithStack(err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, perrors.WithStack(err)
+	}
+
+	lis, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	if mode != "" {
+		if err = os.Chmod(path, perm); err != nil {
+			_ = lis.Close()
+			return nil, perrors.WithStack(err)
+		}
+	}
+	return lis, nil
+}
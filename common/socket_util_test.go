@@ -0,0 +1,59 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListenUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dubbo.sock")
+
+	lis, err := ListenUnixSocket(path, "0600")
+	assert.NoError(t, err)
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// the socket of a live listener is kept
+	_, err = ListenUnixSocket(path, "")
+	assert.Error(t, err)
+
+	// the stale socket is replaced
+	lis.(*net.UnixListener).SetUnlinkOnClose(false)
+	assert.NoError(t, lis.Close())
+	lis, err = ListenUnixSocket(path, "")
+	assert.NoError(t, err)
+	assert.NoError(t, lis.Close())
+
+	// regular files are never removed
+	file := filepath.Join(t.TempDir(), "file")
+	assert.NoError(t, os.WriteFile(file, nil, 0600))
+	_, err = ListenUnixSocket(file, "")
+	assert.Error(t, err)
+
+	_, err = ListenUnixSocket(path, "999")
+	assert.Error(t, err)
+}
@@ -0,0 +1,171 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tenant
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+import (
+	"gopkg.in/yaml.v2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/config_center/rule"
+)
+
+const (
+	// DefaultTenantID is the id of the tenant whose quotas apply to the tenants not configured
+	DefaultTenantID = "*"
+	// DefaultMaxMetricTenants is the number of tenants labelled by their ids in the metrics
+	DefaultMaxMetricTenants = 100
+)
+
+var configs = rule.NewWatcher("tenant configuration", constant.TenantRuleSuffix, ParseConfig)
+
+// Config is the tenant configuration of an application, which is published to the config center
+// under the key "{application}.tenants", for example:
+/**
+ * max-metric-tenants: 50    # the other tenants are labelled as "other" in the metrics
+ * tenants:
+ *   - id: "acme"
+ *     concurrency: 100      # the max concurrent requests of the tenant on each provider
+ *     rate: 500             # the max requests of the tenant per interval on each provider
+ *     interval: 1s
+ *     burst: 100
+ *     labels:               # the providers dedicated to the tenant
+ *       pool: "acme"
+ *   - id: "*"               # the quotas of the tenants not listed above
+ *     concurrency: 10
+ *     rate: 50
+ */
+type Config struct {
+	MaxMetricTenants int       `yaml:"max-metric-tenants"`
+	Tenants          []*Tenant `yaml:"tenants"`
+
+	tenants map[string]*Tenant
+}
+
+// Tenant describes the quotas of a tenant and the providers it is pinned to
+type Tenant struct {
+	ID string `yaml:"id"`
+	// Concurrency is the max concurrent requests, 0 means unlimited.
+	Concurrency int64 `yaml:"concurrency"`
+	// Rate is the max requests per Interval, 0 means unlimited.
+	Rate     int           `yaml:"rate"`
+	Interval time.Duration `yaml:"interval"`
+	// Burst is the max requests allowed at once, it is Rate by default.
+	Burst int `yaml:"burst"`
+	// Labels are the url params of the providers dedicated to the tenant.
+	Labels map[string]string `yaml:"labels"`
+}
+
+// ParseConfig parses and validates the tenant configuration in yaml
+func ParseConfig(content string) (*Config, error) {
+	c := &Config{}
+	if err := yaml.Unmarshal([]byte(content), c); err != nil {
+		return nil, err
+	}
+	if err := c.init(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Config) init() error {
+	if c.MaxMetricTenants <= 0 {
+		c.MaxMetricTenants = DefaultMaxMetricTenants
+	}
+	c.tenants = make(map[string]*Tenant, len(c.Tenants))
+	for _, t := range c.Tenants {
+		if t == nil || t.ID == "" {
+			return errors.New("the id of a tenant is required")
+		}
+		if _, ok := c.tenants[t.ID]; ok {
+			return fmt.Errorf("tenant %s is configured more than once", t.ID)
+		}
+		if t.Concurrency < 0 || t.Rate < 0 || t.Burst < 0 || t.Interval < 0 {
+			return fmt.Errorf("the quotas of tenant %s should not be negative", t.ID)
+		}
+		if t.Interval == 0 {
+			t.Interval = time.Second
+		}
+		if t.ID == DefaultTenantID && len(t.Labels) > 0 {
+			return errors.New("the default tenant should not be pinned by labels")
+		}
+		c.tenants[t.ID] = t
+	}
+	return nil
+}
+
+// Get returns the configuration of the tenant, falling back to the default tenant "*".
+// It returns nil if neither is configured.
+func (c *Config) Get(id string) *Tenant {
+	if c == nil {
+		return nil
+	}
+	if t, ok := c.tenants[id]; ok {
+		return t
+	}
+	return c.tenants[DefaultTenantID]
+}
+
+// Configured reports whether the tenant is listed explicitly
+func (c *Config) Configured(id string) bool {
+	if c == nil {
+		return false
+	}
+	_, ok := c.tenants[id]
+	return ok
+}
+
+// Pinned returns the tenants pinned to dedicated providers
+func (c *Config) Pinned() []*Tenant {
+	if c == nil {
+		return nil
+	}
+	pinned := make([]*Tenant, 0)
+	for _, t := range c.Tenants {
+		if len(t.Labels) > 0 {
+			pinned = append(pinned, t)
+		}
+	}
+	return pinned
+}
+
+// GetConfig returns the tenant configuration of the application, or nil if there is none.
+// The configuration in the config center is watched since the first call.
+func GetConfig(application string) *Config {
+	return configs.Get(application)
+}
+
+// SetConfig replaces the tenant configuration of the application, a nil config removes it
+func SetConfig(application string, c *Config) error {
+	if c == nil {
+		configs.Delete(application)
+		return nil
+	}
+	if err := c.init(); err != nil {
+		return err
+	}
+	configs.Set(application, c)
+	return nil
+}
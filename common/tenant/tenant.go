@@ -0,0 +1,60 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package tenant propagates the tenant of a request and keeps the tenant configuration of the applications.
+/*
+ The tenant ID travels with the attachment "tenant-id". A consumer puts it into the context of a call:
+
+ ctx = tenant.NewContext(ctx, "acme")
+ resp, err := svc.GetUser(ctx, req)
+
+ A provider reads it from the context of the method, and the context passed on to the downstream calls
+ carries it further:
+
+ id := tenant.FromContext(ctx)
+
+ The configuration is read from the config center with the key "{application}.tenants", and is used by
+ the tenant filter for the quotas and by the tenant router to pin tenants to dedicated providers.
+ It is set by SetConfig as well.
+*/
+package tenant
+
+import (
+	"context"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+)
+
+// NewContext returns a copy of ctx whose attachments carry the tenant id
+func NewContext(ctx context.Context, id string) context.Context {
+	return common.NewContextWithAttachment(ctx, constant.TenantKey, id)
+}
+
+// FromContext returns the tenant id carried by the attachments of ctx, or "" if there is none
+func FromContext(ctx context.Context) string {
+	return common.AttachmentFromContext(ctx, constant.TenantKey)
+}
+
+// FromInvocation returns the tenant id attached to the invocation, or "" if there is none
+func FromInvocation(invocation base.Invocation) string {
+	id, _ := common.LookupAttachment(invocation.Attachments(), constant.TenantKey)
+	return id
+}
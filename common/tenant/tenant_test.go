@@ -0,0 +1,113 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tenant
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, "", FromContext(ctx))
+
+	ctx = context.WithValue(ctx, constant.AttachmentKey, map[string]any{"k": "v"})
+	tenantCtx := NewContext(ctx, "acme")
+	assert.Equal(t, "acme", FromContext(tenantCtx))
+	assert.Equal(t, "v", tenantCtx.Value(constant.AttachmentKey).(map[string]any)["k"])
+	// the attachments of the parent are not changed
+	assert.Equal(t, "", FromContext(ctx))
+
+	// the attachments received by triple
+	ctx = context.WithValue(ctx, constant.AttachmentKey, map[string]any{constant.TenantKey: []string{"acme"}})
+	assert.Equal(t, "acme", FromContext(ctx))
+}
+
+func TestFromInvocation(t *testing.T) {
+	inv := invocation.NewRPCInvocation("GetUser", nil, map[string]any{constant.TenantKey: "acme"})
+	assert.Equal(t, "acme", FromInvocation(inv))
+	inv = invocation.NewRPCInvocation("GetUser", nil, map[string]any{"Tenant-Id": []string{"acme"}})
+	assert.Equal(t, "acme", FromInvocation(inv))
+	inv = invocation.NewRPCInvocation("GetUser", nil, nil)
+	assert.Equal(t, "", FromInvocation(inv))
+}
+
+func TestParseConfig(t *testing.T) {
+	c, err := ParseConfig(`
+tenants:
+  - id: "acme"
+    concurrency: 10
+    rate: 100
+    labels:
+      pool: "acme"
+  - id: "*"
+    rate: 10
+    interval: 100ms
+`)
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultMaxMetricTenants, c.MaxMetricTenants)
+	acme := c.Get("acme")
+	assert.Equal(t, int64(10), acme.Concurrency)
+	assert.Equal(t, time.Second, acme.Interval)
+	assert.True(t, c.Configured("acme"))
+	assert.False(t, c.Configured("other"))
+	assert.Equal(t, 100*time.Millisecond, c.Get("other").Interval)
+	assert.Equal(t, []*Tenant{acme}, c.Pinned())
+
+	_, err = ParseConfig(`tenants: [{id: "acme"}, {id: "acme"}]`)
+	assert.NotNil(t, err)
+	_, err = ParseConfig(`tenants: [{rate: 10}]`)
+	assert.NotNil(t, err)
+	_, err = ParseConfig(`tenants: [{id: "*", labels: {pool: "shared"}}]`)
+	assert.NotNil(t, err)
+
+	var nilConfig *Config
+	assert.Nil(t, nilConfig.Get("acme"))
+	assert.Nil(t, nilConfig.Pinned())
+}
+
+func TestConfigManager(t *testing.T) {
+	key := configs.Key("tenant-app")
+	configs.Process(&config_center.ConfigChangeEvent{Key: key, Value: `tenants: [{id: "acme", rate: 10}]`, ConfigType: remoting.EventTypeAdd})
+	assert.Equal(t, 10, GetConfig("tenant-app").Get("acme").Rate)
+
+	// an invalid configuration is ignored
+	configs.Process(&config_center.ConfigChangeEvent{Key: key, Value: `tenants: [{rate: 20}]`, ConfigType: remoting.EventTypeUpdate})
+	assert.Equal(t, 10, GetConfig("tenant-app").Get("acme").Rate)
+
+	configs.Process(&config_center.ConfigChangeEvent{Key: key, ConfigType: remoting.EventTypeDel})
+	assert.Nil(t, GetConfig("tenant-app"))
+
+	assert.Nil(t, SetConfig("tenant-app", &Config{Tenants: []*Tenant{{ID: "acme", Concurrency: 1}}}))
+	assert.Equal(t, int64(1), GetConfig("tenant-app").Get("acme").Concurrency)
+	assert.NotNil(t, SetConfig("tenant-app", &Config{Tenants: []*Tenant{{ID: "acme", Concurrency: -1}}}))
+	assert.Nil(t, SetConfig("tenant-app", nil))
+	assert.Nil(t, GetConfig("tenant-app"))
+}
@@ -300,6 +300,16 @@ func NewURL(urlString string, opts ...Option) (*URL, error) {
 	s.Password, _ = serviceURL.User.Password()
 	s.Location = serviceURL.Host
 	s.Path = serviceURL.Path
+	switch serviceURL.Scheme {
+	case constant.UnixProtocol:
+		// unix:///var/run/dubbo.sock addresses the unix domain socket of a provider,
+		// the protocol of the url is expected to be set by opts
+		s.params.Set(constant.UnixSocketKey, serviceURL.Path)
+		s.Location, s.Path = "localhost", ""
+	case constant.MemoryProtocol:
+		// memory://name addresses the in-memory listener of a provider
+		s.params.Set(constant.InMemoryKey, serviceURL.Host)
+	}
 	for _, location := range strings.Split(s.Location, ",") {
 		location = strings.Trim(location, " ")
 		if strings.Contains(location, ":") {
@@ -123,6 +123,22 @@ func TestURLWithoutSchema(t *testing.T) {
 		"ZX&pid=1447&revision=0.0.1&side=provider&timeout=3000&timestamp=1556509797245", u.String())
 }
 
+func TestURLWithLocalTransport(t *testing.T) {
+	u, err := NewURL("unix:///var/run/dubbo.sock?serialization=hessian2", WithProtocol("tri"))
+	assert.NoError(t, err)
+	assert.Equal(t, "tri", u.Protocol)
+	assert.Equal(t, "localhost", u.Location)
+	assert.Equal(t, "", u.Path)
+	assert.Equal(t, "/var/run/dubbo.sock", u.GetParam(constant.UnixSocketKey, ""))
+	assert.Equal(t, "hessian2", u.GetParam(constant.SerializationKey, ""))
+
+	u, err = NewURL("memory://greet", WithProtocol("tri"))
+	assert.NoError(t, err)
+	assert.Equal(t, "tri", u.Protocol)
+	assert.Equal(t, "greet", u.Location)
+	assert.Equal(t, "greet", u.GetParam(constant.InMemoryKey, ""))
+}
+
 func TestURLEqual(t *testing.T) {
 	u1, err := NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider&group=&version=2.6.0")
 	assert.NoError(t, err)
@@ -88,6 +88,8 @@ func compatProtocolConfig(c *global.ProtocolConfig) *config.ProtocolConfig {
 		Port:                 c.Port,
 		Params:               c.Params,
 		TripleConfig:         compatTripleConfig(c.TripleConfig),
+		UnixSocket:           c.UnixSocket,
+		UnixSocketMode:       c.UnixSocketMode,
 		MaxServerSendMsgSize: c.MaxServerSendMsgSize,
 		MaxServerRecvMsgSize: c.MaxServerRecvMsgSize,
 	}
@@ -102,9 +104,19 @@ func compatTripleConfig(c *global.TripleConfig) *config.TripleConfig {
 		MaxServerSendMsgSize: c.MaxServerSendMsgSize,
 		MaxServerRecvMsgSize: c.MaxServerRecvMsgSize,
 		Http3:                compatHttp3Config(c.Http3),
+		Cors:                 compatCorsConfig(c.Cors),
+		WebSocket:            compatWebSocketConfig(c.WebSocket),
+
+		MaxConcurrentStreams:    c.MaxConcurrentStreams,
+		InitialStreamWindowSize: c.InitialStreamWindowSize,
+		InitialConnWindowSize:   c.InitialConnWindowSize,
+		MaxHeaderListSize:       c.MaxHeaderListSize,
+		IdleTimeout:             c.IdleTimeout,
 
 		KeepAliveInterval: c.KeepAliveInterval,
 		KeepAliveTimeout:  c.KeepAliveTimeout,
+		DialTimeout:       c.DialTimeout,
+		ConnPool:          compatConnPoolConfig(c.ConnPool),
 	}
 }
 
@@ -114,8 +126,51 @@ func compatHttp3Config(c *global.Http3Config) *config.Http3Config {
 		return nil
 	}
 	return &config.Http3Config{
-		Enable:      c.Enable,
-		Negotiation: c.Negotiation,
+		Enable:                  c.Enable,
+		Negotiation:             c.Negotiation,
+		Allow0RTT:               c.Allow0RTT,
+		EnableDatagrams:         c.EnableDatagrams,
+		DisablePathMTUDiscovery: c.DisablePathMTUDiscovery,
+		InitialPacketSize:       c.InitialPacketSize,
+	}
+}
+
+// just for compat
+func compatCorsConfig(c *global.CorsConfig) *config.CorsConfig {
+	if c == nil {
+		return nil
+	}
+	return &config.CorsConfig{
+		AllowOrigins:     c.AllowOrigins,
+		AllowMethods:     c.AllowMethods,
+		AllowHeaders:     c.AllowHeaders,
+		ExposeHeaders:    c.ExposeHeaders,
+		AllowCredentials: c.AllowCredentials,
+		MaxAge:           c.MaxAge,
+	}
+}
+
+// just for compat
+func compatWebSocketConfig(c *global.WebSocketConfig) *config.WebSocketConfig {
+	if c == nil {
+		return nil
+	}
+	return &config.WebSocketConfig{
+		Enable:       c.Enable,
+		PingInterval: c.PingInterval,
+		PongTimeout:  c.PongTimeout,
+	}
+}
+
+// just for compat
+func compatConnPoolConfig(c *global.ConnPoolConfig) *config.ConnPoolConfig {
+	if c == nil {
+		return nil
+	}
+	return &config.ConnPoolConfig{
+		MaxConnections: c.MaxConnections,
+		OpenThreshold:  c.OpenThreshold,
+		IdleTimeout:    c.IdleTimeout,
 	}
 }
 
@@ -231,6 +286,8 @@ func compatServiceConfig(c *global.ServiceConfig) *config.ServiceConfig {
 		Warmup:                      c.Warmup,
 		Retries:                     c.Retries,
 		Serialization:               c.Serialization,
+		Compression:                 c.Compression,
+		CompressMinBytes:            c.CompressMinBytes,
 		Params:                      c.Params,
 		Token:                       c.Token,
 		AccessLog:                   c.AccessLog,
@@ -239,6 +296,9 @@ func compatServiceConfig(c *global.ServiceConfig) *config.ServiceConfig {
 		TpsLimitRate:                c.TpsLimitRate,
 		TpsLimitStrategy:            c.TpsLimitStrategy,
 		TpsLimitRejectedHandler:     c.TpsLimitRejectedHandler,
+		TpsLimitBurst:               c.TpsLimitBurst,
+		TpsLimitMaxWait:             c.TpsLimitMaxWait,
+		TpsLimitBy:                  c.TpsLimitBy,
 		ExecuteLimit:                c.ExecuteLimit,
 		ExecuteLimitRejectedHandler: c.ExecuteLimitRejectedHandler,
 		Auth:                        c.Auth,
@@ -246,6 +306,7 @@ func compatServiceConfig(c *global.ServiceConfig) *config.ServiceConfig {
 		ParamSign:                   c.ParamSign,
 		Tag:                         c.Tag,
 		TracingKey:                  c.TracingKey,
+		Validation:                  c.Validation,
 		RCProtocolsMap:              protocols,
 		RCRegistriesMap:             registries,
 		ProxyFactoryKey:             c.ProxyFactoryKey,
@@ -266,10 +327,23 @@ func compatMethodConfig(c *global.MethodConfig) *config.MethodConfig {
 		TpsLimitInterval:            c.TpsLimitInterval,
 		TpsLimitRate:                c.TpsLimitRate,
 		TpsLimitStrategy:            c.TpsLimitStrategy,
+		TpsLimitBurst:               c.TpsLimitBurst,
+		TpsLimitMaxWait:             c.TpsLimitMaxWait,
+		TpsLimitBy:                  c.TpsLimitBy,
 		ExecuteLimit:                c.ExecuteLimit,
 		ExecuteLimitRejectedHandler: c.ExecuteLimitRejectedHandler,
 		Sticky:                      c.Sticky,
 		RequestTimeout:              c.RequestTimeout,
+		Validation:                  c.Validation,
+		Idempotent:                  c.Idempotent,
+		MaxRequestSize:              c.MaxRequestSize,
+		MaxResponseSize:             c.MaxResponseSize,
+		Deprecated:                  c.Deprecated,
+		Sunset:                      c.Sunset,
+		Replacement:                 c.Replacement,
+		Audit:                       c.Audit,
+		AuditFields:                 c.AuditFields,
+		CacheControl:                c.CacheControl,
 	}
 }
 
@@ -309,6 +383,8 @@ func compatReferences(c map[string]*global.ReferenceConfig) map[string]*config.R
 			Group:                ref.Group,
 			Version:              ref.Version,
 			Serialization:        ref.Serialization,
+			Compression:          ref.Compression,
+			CompressMinBytes:     ref.CompressMinBytes,
 			ProvidedBy:           ref.ProvidedBy,
 			MethodsConfig:        compatMethod(ref.MethodsConfig),
 			ProtocolClientConfig: compatProtocolClientConfig(ref.ProtocolClientConfig),
@@ -339,10 +415,23 @@ func compatMethod(m []*global.MethodConfig) []*config.MethodConfig {
 			TpsLimitInterval:            method.TpsLimitInterval,
 			TpsLimitRate:                method.TpsLimitRate,
 			TpsLimitStrategy:            method.TpsLimitStrategy,
+			TpsLimitBurst:               method.TpsLimitBurst,
+			TpsLimitMaxWait:             method.TpsLimitMaxWait,
+			TpsLimitBy:                  method.TpsLimitBy,
 			ExecuteLimit:                method.ExecuteLimit,
 			ExecuteLimitRejectedHandler: method.ExecuteLimitRejectedHandler,
 			Sticky:                      method.Sticky,
 			RequestTimeout:              method.RequestTimeout,
+			Validation:                  method.Validation,
+			Idempotent:                  method.Idempotent,
+			MaxRequestSize:              method.MaxRequestSize,
+			MaxResponseSize:             method.MaxResponseSize,
+			Deprecated:                  method.Deprecated,
+			Sunset:                      method.Sunset,
+			Replacement:                 method.Replacement,
+			Audit:                       method.Audit,
+			AuditFields:                 method.AuditFields,
+			CacheControl:                method.CacheControl,
 		})
 	}
 	return methods
@@ -541,6 +630,8 @@ func compatGlobalProtocolConfig(c *config.ProtocolConfig) *global.ProtocolConfig
 		Port:                 c.Port,
 		Params:               c.Params,
 		TripleConfig:         compatGlobalTripleConfig(c.TripleConfig),
+		UnixSocket:           c.UnixSocket,
+		UnixSocketMode:       c.UnixSocketMode,
 		MaxServerSendMsgSize: c.MaxServerSendMsgSize,
 		MaxServerRecvMsgSize: c.MaxServerRecvMsgSize,
 	}
@@ -554,10 +645,20 @@ func compatGlobalTripleConfig(c *config.TripleConfig) *global.TripleConfig {
 	return &global.TripleConfig{
 		KeepAliveInterval: c.KeepAliveInterval,
 		KeepAliveTimeout:  c.KeepAliveTimeout,
+		DialTimeout:       c.DialTimeout,
+		ConnPool:          compatGlobalConnPoolConfig(c.ConnPool),
 		Http3:             compatGlobalHttp3Config(c.Http3),
+		Cors:              compatGlobalCorsConfig(c.Cors),
+		WebSocket:         compatGlobalWebSocketConfig(c.WebSocket),
 
 		MaxServerSendMsgSize: c.MaxServerSendMsgSize,
 		MaxServerRecvMsgSize: c.MaxServerRecvMsgSize,
+
+		MaxConcurrentStreams:    c.MaxConcurrentStreams,
+		InitialStreamWindowSize: c.InitialStreamWindowSize,
+		InitialConnWindowSize:   c.InitialConnWindowSize,
+		MaxHeaderListSize:       c.MaxHeaderListSize,
+		IdleTimeout:             c.IdleTimeout,
 	}
 }
 
@@ -567,8 +668,51 @@ func compatGlobalHttp3Config(c *config.Http3Config) *global.Http3Config {
 		return nil
 	}
 	return &global.Http3Config{
-		Enable:      c.Enable,
-		Negotiation: c.Negotiation,
+		Enable:                  c.Enable,
+		Negotiation:             c.Negotiation,
+		Allow0RTT:               c.Allow0RTT,
+		EnableDatagrams:         c.EnableDatagrams,
+		DisablePathMTUDiscovery: c.DisablePathMTUDiscovery,
+		InitialPacketSize:       c.InitialPacketSize,
+	}
+}
+
+// just for compat
+func compatGlobalCorsConfig(c *config.CorsConfig) *global.CorsConfig {
+	if c == nil {
+		return nil
+	}
+	return &global.CorsConfig{
+		AllowOrigins:     c.AllowOrigins,
+		AllowMethods:     c.AllowMethods,
+		AllowHeaders:     c.AllowHeaders,
+		ExposeHeaders:    c.ExposeHeaders,
+		AllowCredentials: c.AllowCredentials,
+		MaxAge:           c.MaxAge,
+	}
+}
+
+// just for compat
+func compatGlobalWebSocketConfig(c *config.WebSocketConfig) *global.WebSocketConfig {
+	if c == nil {
+		return nil
+	}
+	return &global.WebSocketConfig{
+		Enable:       c.Enable,
+		PingInterval: c.PingInterval,
+		PongTimeout:  c.PongTimeout,
+	}
+}
+
+// just for compat
+func compatGlobalConnPoolConfig(c *config.ConnPoolConfig) *global.ConnPoolConfig {
+	if c == nil {
+		return nil
+	}
+	return &global.ConnPoolConfig{
+		MaxConnections: c.MaxConnections,
+		OpenThreshold:  c.OpenThreshold,
+		IdleTimeout:    c.IdleTimeout,
 	}
 }
 
@@ -703,6 +847,8 @@ func compatGlobalServiceConfig(c *config.ServiceConfig) *global.ServiceConfig {
 		Warmup:                      c.Warmup,
 		Retries:                     c.Retries,
 		Serialization:               c.Serialization,
+		Compression:                 c.Compression,
+		CompressMinBytes:            c.CompressMinBytes,
 		Params:                      c.Params,
 		Token:                       c.Token,
 		AccessLog:                   c.AccessLog,
@@ -711,6 +857,9 @@ func compatGlobalServiceConfig(c *config.ServiceConfig) *global.ServiceConfig {
 		TpsLimitRate:                c.TpsLimitRate,
 		TpsLimitStrategy:            c.TpsLimitStrategy,
 		TpsLimitRejectedHandler:     c.TpsLimitRejectedHandler,
+		TpsLimitBurst:               c.TpsLimitBurst,
+		TpsLimitMaxWait:             c.TpsLimitMaxWait,
+		TpsLimitBy:                  c.TpsLimitBy,
 		ExecuteLimit:                c.ExecuteLimit,
 		ExecuteLimitRejectedHandler: c.ExecuteLimitRejectedHandler,
 		Auth:                        c.Auth,
@@ -718,6 +867,7 @@ func compatGlobalServiceConfig(c *config.ServiceConfig) *global.ServiceConfig {
 		ParamSign:                   c.ParamSign,
 		Tag:                         c.Tag,
 		TracingKey:                  c.TracingKey,
+		Validation:                  c.Validation,
 		RCProtocolsMap:              protocols,
 		RCRegistriesMap:             registries,
 		ProxyFactoryKey:             c.ProxyFactoryKey,
@@ -738,10 +888,23 @@ func compatGlobalMethodConfig(c *config.MethodConfig) *global.MethodConfig {
 		TpsLimitInterval:            c.TpsLimitInterval,
 		TpsLimitRate:                c.TpsLimitRate,
 		TpsLimitStrategy:            c.TpsLimitStrategy,
+		TpsLimitBurst:               c.TpsLimitBurst,
+		TpsLimitMaxWait:             c.TpsLimitMaxWait,
+		TpsLimitBy:                  c.TpsLimitBy,
 		ExecuteLimit:                c.ExecuteLimit,
 		ExecuteLimitRejectedHandler: c.ExecuteLimitRejectedHandler,
 		Sticky:                      c.Sticky,
 		RequestTimeout:              c.RequestTimeout,
+		Validation:                  c.Validation,
+		Idempotent:                  c.Idempotent,
+		MaxRequestSize:              c.MaxRequestSize,
+		MaxResponseSize:             c.MaxResponseSize,
+		Deprecated:                  c.Deprecated,
+		Sunset:                      c.Sunset,
+		Replacement:                 c.Replacement,
+		Audit:                       c.Audit,
+		AuditFields:                 c.AuditFields,
+		CacheControl:                c.CacheControl,
 	}
 }
 
@@ -781,6 +944,8 @@ func compatGlobalReferences(c map[string]*config.ReferenceConfig) map[string]*gl
 			Group:                ref.Group,
 			Version:              ref.Version,
 			Serialization:        ref.Serialization,
+			Compression:          ref.Compression,
+			CompressMinBytes:     ref.CompressMinBytes,
 			ProvidedBy:           ref.ProvidedBy,
 			MethodsConfig:        compatGlobalMethod(ref.MethodsConfig),
 			ProtocolClientConfig: compatGlobalProtocolClientConfig(ref.ProtocolClientConfig),
@@ -811,10 +976,23 @@ func compatGlobalMethod(m []*config.MethodConfig) []*global.MethodConfig {
 			TpsLimitInterval:            method.TpsLimitInterval,
 			TpsLimitRate:                method.TpsLimitRate,
 			TpsLimitStrategy:            method.TpsLimitStrategy,
+			TpsLimitBurst:               method.TpsLimitBurst,
+			TpsLimitMaxWait:             method.TpsLimitMaxWait,
+			TpsLimitBy:                  method.TpsLimitBy,
 			ExecuteLimit:                method.ExecuteLimit,
 			ExecuteLimitRejectedHandler: method.ExecuteLimitRejectedHandler,
 			Sticky:                      method.Sticky,
 			RequestTimeout:              method.RequestTimeout,
+			Validation:                  method.Validation,
+			Idempotent:                  method.Idempotent,
+			MaxRequestSize:              method.MaxRequestSize,
+			MaxResponseSize:             method.MaxResponseSize,
+			Deprecated:                  method.Deprecated,
+			Sunset:                      method.Sunset,
+			Replacement:                 method.Replacement,
+			Audit:                       method.Audit,
+			AuditFields:                 method.AuditFields,
+			CacheControl:                method.CacheControl,
 		})
 	}
 	return methods
@@ -0,0 +1,24 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package config
+
+// ConnPoolConfig represents the pool of the HTTP/2 connections a triple client keeps to each provider
+type ConnPoolConfig struct {
+	MaxConnections int     `yaml:"max-connections" json:"max-connections,omitempty"`
+	OpenThreshold  float64 `yaml:"open-threshold" json:"open-threshold,omitempty"`
+	IdleTimeout    string  `yaml:"idle-timeout" json:"idle-timeout,omitempty"`
+}
@@ -0,0 +1,28 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+// CorsConfig represents the CORS config of the triple protocol
+type CorsConfig struct {
+	AllowOrigins     []string `yaml:"allow-origins" json:"allow-origins,omitempty"`
+	AllowMethods     []string `yaml:"allow-methods" json:"allow-methods,omitempty"`
+	AllowHeaders     []string `yaml:"allow-headers" json:"allow-headers,omitempty"`
+	ExposeHeaders    []string `yaml:"expose-headers" json:"expose-headers,omitempty"`
+	AllowCredentials bool     `yaml:"allow-credentials" json:"allow-credentials,omitempty"`
+	MaxAge           string   `yaml:"max-age" json:"max-age,omitempty"`
+}
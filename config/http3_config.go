@@ -34,5 +34,19 @@ type Http3Config struct {
 	// ref: https://quic-go.net/docs/http3/server/#advertising-http3-via-alt-svc
 	Negotiation bool `yaml:"negotiation" json:"negotiation,omitempty"`
 
-	// TODO: add more params about http3
+	// Whether to accept 0-RTT connection attempts on the server.
+	// The client caches the TLS sessions to resume them, triple calls are POSTs and never sent as 0-RTT data.
+	// The default value is false.
+	Allow0RTT bool `yaml:"allow-0rtt" json:"allow-0rtt,omitempty"`
+
+	// Whether to enable the unreliable datagram extension.
+	// The default value is false.
+	EnableDatagrams bool `yaml:"enable-datagrams" json:"enable-datagrams,omitempty"`
+
+	// Whether to disable the path MTU discovery.
+	// The default value is false.
+	DisablePathMTUDiscovery bool `yaml:"disable-path-mtu-discovery" json:"disable-path-mtu-discovery,omitempty"`
+
+	// The size of the first packets sent, zero means the default of quic-go.
+	InitialPacketSize uint16 `yaml:"initial-packet-size" json:"initial-packet-size,omitempty"`
 }
@@ -42,10 +42,23 @@ type MethodConfig struct {
 	TpsLimitInterval            string `yaml:"tps.limit.interval" json:"tps.limit.interval,omitempty" property:"tps.limit.interval"`
 	TpsLimitRate                string `yaml:"tps.limit.rate" json:"tps.limit.rate,omitempty" property:"tps.limit.rate"`
 	TpsLimitStrategy            string `yaml:"tps.limit.strategy" json:"tps.limit.strategy,omitempty" property:"tps.limit.strategy"`
+	TpsLimitBurst               string `yaml:"tps.limit.burst" json:"tps.limit.burst,omitempty" property:"tps.limit.burst"`
+	TpsLimitMaxWait             string `yaml:"tps.limit.max.wait" json:"tps.limit.max.wait,omitempty" property:"tps.limit.max.wait"`
+	TpsLimitBy                  string `yaml:"tps.limit.by" json:"tps.limit.by,omitempty" property:"tps.limit.by"`
 	ExecuteLimit                string `yaml:"execute.limit" json:"execute.limit,omitempty" property:"execute.limit"`
 	ExecuteLimitRejectedHandler string `yaml:"execute.limit.rejected.handler" json:"execute.limit.rejected.handler,omitempty" property:"execute.limit.rejected.handler"`
 	Sticky                      bool   `yaml:"sticky"   json:"sticky,omitempty" property:"sticky"`
 	RequestTimeout              string `yaml:"timeout"  json:"timeout,omitempty" property:"timeout"`
+	Validation                  string `yaml:"validation" json:"validation,omitempty" property:"validation"`
+	Idempotent                  bool   `yaml:"idempotent" json:"idempotent,omitempty" property:"idempotent"`
+	MaxRequestSize              string `yaml:"max-request-size" json:"max-request-size,omitempty" property:"max-request-size"`
+	MaxResponseSize             string `yaml:"max-response-size" json:"max-response-size,omitempty" property:"max-response-size"`
+	Deprecated                  bool   `yaml:"deprecated" json:"deprecated,omitempty" property:"deprecated"`
+	Sunset                      string `yaml:"sunset" json:"sunset,omitempty" property:"sunset"`
+	Replacement                 string `yaml:"replacement" json:"replacement,omitempty" property:"replacement"`
+	Audit                       bool   `yaml:"audit" json:"audit,omitempty" property:"audit"`
+	AuditFields                 string `yaml:"audit-fields" json:"audit-fields,omitempty" property:"audit-fields"`
+	CacheControl                string `yaml:"cache-control" json:"cache-control,omitempty" property:"cache-control"`
 }
 
 // Prefix builds the configuration key prefix for this method.
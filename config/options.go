@@ -19,6 +19,7 @@ package config
 
 import (
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -82,6 +83,27 @@ func WithTpsLimitStrategy(strategy string) MethodOption {
 	}
 }
 
+// WithTpsLimitBurst sets the max number of requests the tokenBucket strategy allows at once
+func WithTpsLimitBurst(burst int) MethodOption {
+	return func(opts *MethodOptions) {
+		opts.Method.TpsLimitBurst = strconv.Itoa(burst)
+	}
+}
+
+// WithTpsLimitMaxWait sets how long the leakyBucket strategy lets a request wait for its turn
+func WithTpsLimitMaxWait(maxWait time.Duration) MethodOption {
+	return func(opts *MethodOptions) {
+		opts.Method.TpsLimitMaxWait = strconv.FormatInt(maxWait.Milliseconds(), 10)
+	}
+}
+
+// WithTpsLimitBy limits the requests of each caller separately, by is "application" or "attachment:{key}"
+func WithTpsLimitBy(by string) MethodOption {
+	return func(opts *MethodOptions) {
+		opts.Method.TpsLimitBy = by
+	}
+}
+
 func WithExecuteLimit(limit int) MethodOption {
 	return func(opts *MethodOptions) {
 		opts.Method.ExecuteLimit = strconv.Itoa(limit)
@@ -106,6 +128,78 @@ func WithRequestTimeout(timeout time.Duration) MethodOption {
 	}
 }
 
+// WithValidation enables or disables argument validation of this method,
+// overriding the service-level validation setting.
+func WithValidation(enable bool) MethodOption {
+	return func(opts *MethodOptions) {
+		opts.Method.Validation = strconv.FormatBool(enable)
+	}
+}
+
+// WithIdempotent marks the method as needing an idempotency key. The consumer generates
+// one key per invocation and keeps it across cluster retries, so that a provider with the
+// idempotent filter runs the method at most once.
+func WithIdempotent() MethodOption {
+	return func(opts *MethodOptions) {
+		opts.Method.Idempotent = true
+	}
+}
+
+// WithMaxRequestSize limits the encoded size of the requests of this method, e.g. "512kib",
+// overriding the service-level limit.
+func WithMaxRequestSize(size string) MethodOption {
+	return func(opts *MethodOptions) {
+		opts.Method.MaxRequestSize = size
+	}
+}
+
+// WithMaxResponseSize limits the encoded size of the responses of this method, e.g. "4mib",
+// overriding the service-level limit.
+func WithMaxResponseSize(size string) MethodOption {
+	return func(opts *MethodOptions) {
+		opts.Method.MaxResponseSize = size
+	}
+}
+
+// WithDeprecated marks this method as deprecated, the consumers calling it are warned.
+func WithDeprecated() MethodOption {
+	return func(opts *MethodOptions) {
+		opts.Method.Deprecated = true
+	}
+}
+
+// WithSunset marks this method as deprecated, and to be removed at sunset.
+func WithSunset(sunset time.Time) MethodOption {
+	return func(opts *MethodOptions) {
+		opts.Method.Sunset = sunset.UTC().Format(time.RFC3339)
+	}
+}
+
+// WithReplacement tells the consumers of this deprecated method what to call instead, e.g. "GetUserV2".
+func WithReplacement(replacement string) MethodOption {
+	return func(opts *MethodOptions) {
+		opts.Method.Replacement = replacement
+	}
+}
+
+// WithAudit records every invocation of this method by the audit filter, with the argument fields
+// selected by fields, e.g. "0.userId", see filter/audit.
+func WithAudit(fields ...string) MethodOption {
+	return func(opts *MethodOptions) {
+		opts.Method.Audit = true
+		opts.Method.AuditFields = strings.Join(fields, ",")
+	}
+}
+
+// WithCacheControl sets the Cache-Control header of the responses to this method when it is
+// called by triple with HTTP GET, e.g. "public, max-age=60". Only the methods declared with
+// idempotency_level = NO_SIDE_EFFECTS in the proto IDL accept GET requests.
+func WithCacheControl(value string) MethodOption {
+	return func(opts *MethodOptions) {
+		opts.Method.CacheControl = value
+	}
+}
+
 type MethodOptions struct {
 	Method *global.MethodConfig
 }
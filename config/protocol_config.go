@@ -34,6 +34,12 @@ type ProtocolConfig struct {
 
 	TripleConfig *TripleConfig `yaml:"triple" json:"triple,omitempty" property:"triple"`
 
+	// UnixSocket is the path of the unix domain socket served instead of Ip and Port, e.g. "/var/run/dubbo.sock".
+	// The services served on it aren't registered, as the remote consumers can't reach it.
+	UnixSocket string `yaml:"unix-socket" json:"unix-socket,omitempty" property:"unix-socket"`
+	// UnixSocketMode is the file permission of UnixSocket in octal, e.g. "0660".
+	UnixSocketMode string `yaml:"unix-socket-mode" json:"unix-socket-mode,omitempty" property:"unix-socket-mode"`
+
 	// MaxServerSendMsgSize max size of server send message, 1mb=1000kb=1000000b 1mib=1024kb=1048576b.
 	// more detail to see https://pkg.go.dev/github.com/dustin/go-humanize#pkg-constants
 	MaxServerSendMsgSize string `yaml:"max-server-send-msg-size" json:"max-server-send-msg-size,omitempty"`
@@ -85,6 +91,16 @@ func (pcb *ProtocolConfigBuilder) SetParams(params any) *ProtocolConfigBuilder {
 	return pcb
 }
 
+func (pcb *ProtocolConfigBuilder) SetUnixSocket(path string) *ProtocolConfigBuilder {
+	pcb.protocolConfig.UnixSocket = path
+	return pcb
+}
+
+func (pcb *ProtocolConfigBuilder) SetUnixSocketMode(mode string) *ProtocolConfigBuilder {
+	pcb.protocolConfig.UnixSocketMode = mode
+	return pcb
+}
+
 func (pcb *ProtocolConfigBuilder) SetMaxServerSendMsgSize(maxServerSendMsgSize string) *ProtocolConfigBuilder {
 	pcb.protocolConfig.MaxServerSendMsgSize = maxServerSendMsgSize
 	return pcb
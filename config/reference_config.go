@@ -78,6 +78,8 @@ type ReferenceConfig struct {
 	RequestTimeout   string            `yaml:"timeout"  json:"timeout,omitempty" property:"timeout"`
 	ForceTag         bool              `yaml:"force.tag"  json:"force.tag,omitempty" property:"force.tag"`
 	TracingKey       string            `yaml:"tracing-key" json:"tracing-key,omitempty" propertiy:"tracing-key"`
+	Compression      string            `yaml:"compression" json:"compression,omitempty" property:"compression"`
+	CompressMinBytes int               `yaml:"compress-min-bytes" json:"compress-min-bytes,omitempty" property:"compress-min-bytes"`
 	metaDataType     string
 	metricsEnable    bool
 	MeshProviderPort int `yaml:"mesh-provider-port" json:"mesh-provider-port,omitempty" propertiy:"mesh-provider-port"`
@@ -215,6 +217,10 @@ func (rc *ReferenceConfig) Refer(srv any) {
 			if err != nil {
 				panic(fmt.Sprintf("url configuration error,  please check your configuration, user specified URL %v refer error, error message is %v ", urlStr, err))
 			}
+			// unix domain sockets and in-memory listeners are served by rc.Protocol
+			if serviceURL.Protocol == constant.UnixProtocol || serviceURL.Protocol == constant.MemoryProtocol {
+				serviceURL.Protocol = rc.Protocol
+			}
 			if serviceURL.Protocol == constant.RegistryProtocol { // serviceURL in this branch is a registry protocol
 				serviceURL.SubURL = cfgURL
 				rc.urls = append(rc.urls, serviceURL)
@@ -348,6 +354,12 @@ func (rc *ReferenceConfig) getURLMap() url.Values {
 	urlMap.Set(constant.RegistryRoleKey, strconv.Itoa(common.CONSUMER))
 	urlMap.Set(constant.ProvidedBy, rc.ProvidedBy)
 	urlMap.Set(constant.SerializationKey, rc.Serialization)
+	if rc.Compression != "" {
+		urlMap.Set(constant.CompressionKey, rc.Compression)
+	}
+	if rc.CompressMinBytes > 0 {
+		urlMap.Set(constant.CompressMinBytesKey, strconv.Itoa(rc.CompressMinBytes))
+	}
 	urlMap.Set(constant.TracingConfigKey, rc.TracingKey)
 
 	urlMap.Set(constant.ReleaseKey, "dubbo-golang-"+constant.Version)
@@ -377,12 +389,24 @@ func (rc *ReferenceConfig) getURLMap() url.Values {
 	if rc.metricsEnable {
 		defaultReferenceFilter += fmt.Sprintf(",%s", constant.MetricsFilterKey)
 	}
+	for _, v := range rc.MethodsConfig {
+		if v.Idempotent {
+			defaultReferenceFilter += fmt.Sprintf(",%s", constant.IdempotentConsumerFilterKey)
+			break
+		}
+	}
+	if needDeprecationWarning(urlMap) {
+		defaultReferenceFilter += fmt.Sprintf(",%s", constant.DeprecationConsumerFilterKey)
+	}
 	urlMap.Set(constant.ReferenceFilterKey, mergeValue(rc.Filter, "", defaultReferenceFilter))
 
 	for _, v := range rc.MethodsConfig {
 		urlMap.Set("methods."+v.Name+"."+constant.LoadbalanceKey, v.LoadBalance)
 		urlMap.Set("methods."+v.Name+"."+constant.RetriesKey, v.Retries)
 		urlMap.Set("methods."+v.Name+"."+constant.StickyKey, strconv.FormatBool(v.Sticky))
+		urlMap.Set("methods."+v.Name+"."+constant.IdempotentKey, strconv.FormatBool(v.Idempotent))
+		urlMap.Set("methods."+v.Name+"."+constant.MaxRequestSizeKey, v.MaxRequestSize)
+		urlMap.Set("methods."+v.Name+"."+constant.MaxResponseSizeKey, v.MaxResponseSize)
 		if len(v.RequestTimeout) != 0 {
 			urlMap.Set("methods."+v.Name+"."+constant.TimeoutKey, v.RequestTimeout)
 		}
@@ -391,6 +415,16 @@ func (rc *ReferenceConfig) getURLMap() url.Values {
 	return urlMap
 }
 
+// needDeprecationWarning reports whether the deprecation consumer filter should be appended, that is,
+// the reference doesn't opt out by deprecation.warn=false and filter/deprecation is imported.
+func needDeprecationWarning(urlMap url.Values) bool {
+	if warn, err := strconv.ParseBool(urlMap.Get(constant.DeprecationWarnKey)); err == nil && !warn {
+		return false
+	}
+	_, ok := extension.GetFilter(constant.DeprecationConsumerFilterKey)
+	return ok
+}
+
 // GenericLoad ...
 func (rc *ReferenceConfig) GenericLoad(id string) {
 	genericService := generic.NewGenericService(id)
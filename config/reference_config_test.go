@@ -18,6 +18,7 @@
 package config
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -27,6 +28,8 @@ import (
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
 	_ "dubbo.apache.org/dubbo-go/v3/proxy/proxy_factory"
 )
 
@@ -467,3 +470,36 @@ func TestReferenceConfigInitWithoutConsumerConfig(t *testing.T) {
 	err := NewReferenceConfigBuilder().Build().Init(testRootConfig)
 	assert.Nil(t, err)
 }
+
+func TestReferenceConfigDeprecationWarning(t *testing.T) {
+	rc := NewReferenceConfigBuilder().
+		SetInterface("org.apache.dubbo.HelloService").
+		Build()
+	rc.rootConfig = NewRootConfigBuilder().Build()
+	// the consumer filter is appended only once it is registered
+	assert.Equal(t, constant.DefaultReferenceFilters, rc.getURLMap().Get(constant.ReferenceFilterKey))
+
+	extension.SetFilter(constant.DeprecationConsumerFilterKey, func() filter.Filter { return nil })
+	filters := strings.Split(rc.getURLMap().Get(constant.ReferenceFilterKey), ",")
+	assert.Contains(t, filters, constant.DeprecationConsumerFilterKey)
+
+	// unless the reference opts out
+	rc.Params = map[string]string{constant.DeprecationWarnKey: "false"}
+	assert.Equal(t, constant.DefaultReferenceFilters, rc.getURLMap().Get(constant.ReferenceFilterKey))
+}
+
+func TestReferenceConfigIdempotent(t *testing.T) {
+	rc := NewReferenceConfigBuilder().
+		SetInterface("org.apache.dubbo.HelloService").
+		Build()
+	rc.rootConfig = NewRootConfigBuilder().Build()
+	rc.MethodsConfig = []*MethodConfig{{Name: "Get"}}
+	filters := strings.Split(rc.getURLMap().Get(constant.ReferenceFilterKey), ",")
+	assert.NotContains(t, filters, constant.IdempotentConsumerFilterKey)
+
+	rc.MethodsConfig = append(rc.MethodsConfig, &MethodConfig{Name: "Put", Idempotent: true})
+	urlMap := rc.getURLMap()
+	filters = strings.Split(urlMap.Get(constant.ReferenceFilterKey), ",")
+	assert.Contains(t, filters, constant.IdempotentConsumerFilterKey)
+	assert.Equal(t, "true", urlMap.Get("methods.Put."+constant.IdempotentKey))
+}
@@ -61,6 +61,8 @@ type ServiceConfig struct {
 	Warmup                      string            `yaml:"warmup"  json:"warmup,omitempty"  property:"warmup"`
 	Retries                     string            `yaml:"retries"  json:"retries,omitempty" property:"retries"`
 	Serialization               string            `yaml:"serialization" json:"serialization" property:"serialization"`
+	Compression                 string            `yaml:"compression" json:"compression,omitempty" property:"compression"`
+	CompressMinBytes            int               `yaml:"compress-min-bytes" json:"compress-min-bytes,omitempty" property:"compress-min-bytes"`
 	Params                      map[string]string `yaml:"params"  json:"params,omitempty" property:"params"`
 	Token                       string            `yaml:"token" json:"token,omitempty" property:"token"`
 	AccessLog                   string            `yaml:"accesslog" json:"accesslog,omitempty" property:"accesslog"`
@@ -69,6 +71,9 @@ type ServiceConfig struct {
 	TpsLimitRate                string            `yaml:"tps.limit.rate" json:"tps.limit.rate,omitempty" property:"tps.limit.rate"`
 	TpsLimitStrategy            string            `yaml:"tps.limit.strategy" json:"tps.limit.strategy,omitempty" property:"tps.limit.strategy"`
 	TpsLimitRejectedHandler     string            `yaml:"tps.limit.rejected.handler" json:"tps.limit.rejected.handler,omitempty" property:"tps.limit.rejected.handler"`
+	TpsLimitBurst               string            `yaml:"tps.limit.burst" json:"tps.limit.burst,omitempty" property:"tps.limit.burst"`
+	TpsLimitMaxWait             string            `yaml:"tps.limit.max.wait" json:"tps.limit.max.wait,omitempty" property:"tps.limit.max.wait"`
+	TpsLimitBy                  string            `yaml:"tps.limit.by" json:"tps.limit.by,omitempty" property:"tps.limit.by"`
 	ExecuteLimit                string            `yaml:"execute.limit" json:"execute.limit,omitempty" property:"execute.limit"`
 	ExecuteLimitRejectedHandler string            `yaml:"execute.limit.rejected.handler" json:"execute.limit.rejected.handler,omitempty" property:"execute.limit.rejected.handler"`
 	Auth                        string            `yaml:"auth" json:"auth,omitempty" property:"auth"`
@@ -76,6 +81,7 @@ type ServiceConfig struct {
 	ParamSign                   string            `yaml:"param.sign" json:"param.sign,omitempty" property:"param.sign"`
 	Tag                         string            `yaml:"tag" json:"tag,omitempty" property:"tag"`
 	TracingKey                  string            `yaml:"tracing-key" json:"tracing-key,omitempty" propertiy:"tracing-key"`
+	Validation                  string            `yaml:"validation" json:"validation,omitempty" property:"validation"`
 
 	RCProtocolsMap  map[string]*ProtocolConfig
 	RCRegistriesMap map[string]*RegistryConfig
@@ -297,6 +303,14 @@ func (s *ServiceConfig) Export() error {
 			ivkURL.AddParam(constant.Tagkey, s.Tag)
 		}
 
+		// serve on a unix domain socket instead of the TCP address
+		if len(protocolConf.UnixSocket) > 0 {
+			ivkURL.AddParam(constant.UnixSocketKey, protocolConf.UnixSocket)
+		}
+		if len(protocolConf.UnixSocketMode) > 0 {
+			ivkURL.AddParam(constant.UnixSocketModeKey, protocolConf.UnixSocketMode)
+		}
+
 		// post process the URL to be exported
 		s.postProcessConfig(ivkURL)
 		// config post processor may set "export" to false
@@ -413,6 +427,12 @@ func (s *ServiceConfig) getUrlMap() url.Values {
 	urlMap.Set(constant.SideKey, (common.RoleType(common.PROVIDER)).Role())
 	// todo: move
 	urlMap.Set(constant.SerializationKey, s.Serialization)
+	if s.Compression != "" {
+		urlMap.Set(constant.CompressionKey, s.Compression)
+	}
+	if s.CompressMinBytes > 0 {
+		urlMap.Set(constant.CompressMinBytesKey, strconv.Itoa(s.CompressMinBytes))
+	}
 	// application config info
 	ac := GetApplicationConfig()
 	urlMap.Set(constant.ApplicationKey, ac.Name)
@@ -446,12 +466,18 @@ func (s *ServiceConfig) getUrlMap() url.Values {
 	urlMap.Set(constant.TPSLimitRateKey, s.TpsLimitRate)
 	urlMap.Set(constant.TPSLimiterKey, s.TpsLimiter)
 	urlMap.Set(constant.TPSRejectedExecutionHandlerKey, s.TpsLimitRejectedHandler)
+	urlMap.Set(constant.TPSLimitBurstKey, s.TpsLimitBurst)
+	urlMap.Set(constant.TPSLimitMaxWaitKey, s.TpsLimitMaxWait)
+	urlMap.Set(constant.TPSLimitByKey, s.TpsLimitBy)
 	urlMap.Set(constant.TracingConfigKey, s.TracingKey)
 
 	// execute limit filter
 	urlMap.Set(constant.ExecuteLimitKey, s.ExecuteLimit)
 	urlMap.Set(constant.ExecuteRejectedExecutionHandlerKey, s.ExecuteLimitRejectedHandler)
 
+	// validation filter
+	urlMap.Set(constant.ValidationKey, s.Validation)
+
 	// auth filter
 	urlMap.Set(constant.ServiceAuthKey, s.Auth)
 	urlMap.Set(constant.ParameterSignatureEnableKey, s.ParamSign)
@@ -464,15 +490,39 @@ func (s *ServiceConfig) getUrlMap() url.Values {
 		prefix := "methods." + v.Name + "."
 		urlMap.Set(prefix+constant.LoadbalanceKey, v.LoadBalance)
 		urlMap.Set(prefix+constant.RetriesKey, v.Retries)
+		// the timeout of the method on the provider, which is not taken by the consumers as theirs
+		urlMap.Set(prefix+constant.ServerTimeoutKey, v.RequestTimeout)
 		urlMap.Set(prefix+constant.WeightKey, strconv.FormatInt(v.Weight, 10))
 
 		urlMap.Set(prefix+constant.TPSLimitStrategyKey, v.TpsLimitStrategy)
 		urlMap.Set(prefix+constant.TPSLimitIntervalKey, v.TpsLimitInterval)
 		urlMap.Set(prefix+constant.TPSLimitRateKey, v.TpsLimitRate)
+		urlMap.Set(prefix+constant.TPSLimitBurstKey, v.TpsLimitBurst)
+		urlMap.Set(prefix+constant.TPSLimitMaxWaitKey, v.TpsLimitMaxWait)
+		urlMap.Set(prefix+constant.TPSLimitByKey, v.TpsLimitBy)
 
 		urlMap.Set(constant.ExecuteLimitKey, v.ExecuteLimit)
 		urlMap.Set(constant.ExecuteRejectedExecutionHandlerKey, v.ExecuteLimitRejectedHandler)
+
+		urlMap.Set(prefix+constant.ValidationKey, v.Validation)
+		urlMap.Set(prefix+constant.IdempotentKey, strconv.FormatBool(v.Idempotent))
+		urlMap.Set(prefix+constant.MaxRequestSizeKey, v.MaxRequestSize)
+		urlMap.Set(prefix+constant.MaxResponseSizeKey, v.MaxResponseSize)
+		if v.Deprecated || v.Sunset != "" {
+			urlMap.Set(prefix+constant.DeprecatedKey, "true")
+			urlMap.Set(prefix+constant.SunsetKey, v.Sunset)
+			urlMap.Set(prefix+constant.ReplacementKey, v.Replacement)
+		}
+		if v.Audit {
+			urlMap.Set(prefix+constant.AuditKey, "true")
+			urlMap.Set(prefix+constant.AuditFieldsKey, v.AuditFields)
+		}
+		if v.CacheControl != "" {
+			urlMap.Set(prefix+constant.CacheControlKey, v.CacheControl)
+		}
 	}
+	// the filters enabled by the params of the service or its methods
+	urlMap.Set(constant.ServiceFilterKey, common.AppendOptionalServiceFilters(urlMap.Get(constant.ServiceFilterKey), urlMap))
 
 	return urlMap
 }
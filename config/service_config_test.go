@@ -114,6 +114,9 @@ func TestNewServiceConfigBuilder(t *testing.T) {
 		values := serviceConfig.getUrlMap()
 		assert.Equal(t, values.Get("methods.Say.weight"), "0")
 		assert.Equal(t, values.Get("methods.Say.tps.limit.rate"), "")
+		// the methods without a cache-control keep the header of the handler
+		_, ok := values["methods.Say."+constant.CacheControlKey]
+		assert.False(t, ok)
 		assert.Equal(t, values.Get(constant.ServiceFilterKey), "echo,token,accesslog,tps,generic_service,execute,pshutdown")
 	})
 
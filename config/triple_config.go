@@ -26,7 +26,25 @@ type TripleConfig struct {
 	MaxServerRecvMsgSize string `yaml:"max-server-recv-msg-size" json:"max-server-recv-msg-size,omitempty"`
 
 	Http3 *Http3Config `yaml:"http3" json:"http3,omitempty" property:"http3"`
+	Cors  *CorsConfig  `yaml:"cors" json:"cors,omitempty" property:"cors"`
+
+	WebSocket *WebSocketConfig `yaml:"websocket" json:"websocket,omitempty" property:"websocket"`
+
+	// MaxConcurrentStreams limits the streams a peer may open on one connection
+	MaxConcurrentStreams uint32 `yaml:"max-concurrent-streams" json:"max-concurrent-streams,omitempty" property:"max-concurrent-streams"`
+	// InitialStreamWindowSize is the initial flow-control window of a stream,
+	// the windows are ignored by the HTTP/2 client
+	InitialStreamWindowSize string `yaml:"initial-stream-window-size" json:"initial-stream-window-size,omitempty" property:"initial-stream-window-size"`
+	// InitialConnWindowSize is the initial flow-control window of a connection
+	InitialConnWindowSize string `yaml:"initial-conn-window-size" json:"initial-conn-window-size,omitempty" property:"initial-conn-window-size"`
+	// MaxHeaderListSize is the max size of the headers accepted from the peer
+	MaxHeaderListSize string `yaml:"max-header-list-size" json:"max-header-list-size,omitempty" property:"max-header-list-size"`
+	// IdleTimeout closes a connection without any active stream after the duration
+	IdleTimeout string `yaml:"idle-timeout" json:"idle-timeout,omitempty" property:"idle-timeout"`
 
 	KeepAliveInterval string `yaml:"keep-alive-interval" json:"keep-alive-interval,omitempty" property:"keep-alive-interval"`
 	KeepAliveTimeout  string `yaml:"keep-alive-timeout" json:"keep-alive-timeout,omitempty" property:"keep-alive-timeout"`
+	DialTimeout       string `yaml:"dial-timeout" json:"dial-timeout,omitempty" property:"dial-timeout"`
+
+	ConnPool *ConnPoolConfig `yaml:"conn-pool" json:"conn-pool,omitempty" property:"conn-pool"`
 }
@@ -0,0 +1,127 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package rule watches the governance rules of the applications in the config center.
+package rule
+
+import (
+	"strings"
+	"sync"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+import (
+	conf "dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// Watcher keeps the rules of every application and watches the config center for changes.
+// The rules of an application are read from the key "{application}{suffix}" since they are first requested.
+type Watcher[T any] struct {
+	name   string
+	suffix string
+	parse  func(string) (T, error)
+
+	rules   sync.Map // rule key -> T
+	watched sync.Map // rule key -> struct{}
+	mu      sync.Mutex
+}
+
+// NewWatcher creates a watcher of the rules with the key suffix, name is used in the logs
+func NewWatcher[T any](name, suffix string, parse func(string) (T, error)) *Watcher[T] {
+	return &Watcher[T]{name: name, suffix: suffix, parse: parse}
+}
+
+// Key returns the config center key of the rules of the application
+func (w *Watcher[T]) Key(application string) string {
+	return application + w.suffix
+}
+
+// Get returns the rules of the application, or the zero value if there are none
+func (w *Watcher[T]) Get(application string) T {
+	key := w.Key(application)
+	if _, ok := w.watched.Load(key); !ok {
+		w.watch(key)
+	}
+	if v, ok := w.rules.Load(key); ok {
+		return v.(T)
+	}
+	var zero T
+	return zero
+}
+
+// Set replaces the rules of the application until they are changed in the config center
+func (w *Watcher[T]) Set(application string, rules T) {
+	w.rules.Store(w.Key(application), rules)
+}
+
+// Delete removes the rules of the application until they are changed in the config center
+func (w *Watcher[T]) Delete(application string) {
+	w.rules.Delete(w.Key(application))
+}
+
+// watch listens to the key and loads its rules, the key is watched again once the config center starts
+func (w *Watcher[T]) watch(key string) {
+	w.mu.Lock()
+	if _, ok := w.watched.Load(key); ok {
+		w.mu.Unlock()
+		return
+	}
+	dynamicConfiguration := conf.GetEnvInstance().GetDynamicConfiguration()
+	if dynamicConfiguration == nil {
+		w.mu.Unlock()
+		logger.Debugf("Config center does not start, %s will not be loaded", w.name)
+		return
+	}
+	dynamicConfiguration.AddListener(key, w)
+	w.watched.Store(key, struct{}{})
+	w.mu.Unlock()
+	value, err := dynamicConfiguration.GetRule(key)
+	if err != nil {
+		logger.Errorf("query %s fail, key=%s, err=%v", w.name, key, err)
+		return
+	}
+	if value == "" {
+		return
+	}
+	w.Process(&config_center.ConfigChangeEvent{Key: key, Value: value, ConfigType: remoting.EventTypeAdd})
+}
+
+// Process applies the changed rules, the invalid rules are ignored and the original ones are kept
+func (w *Watcher[T]) Process(event *config_center.ConfigChangeEvent) {
+	if event.ConfigType == remoting.EventTypeDel {
+		w.rules.Delete(event.Key)
+		logger.Infof("%s %s removed", w.name, event.Key)
+		return
+	}
+	content, _ := event.Value.(string)
+	if strings.TrimSpace(content) == "" {
+		w.rules.Delete(event.Key)
+		return
+	}
+	rules, err := w.parse(content)
+	if err != nil {
+		logger.Warnf("parse %s %s error, the original ones are kept: %v", w.name, event.Key, err)
+		return
+	}
+	w.rules.Store(event.Key, rules)
+	logger.Infof("%s %s updated", w.name, event.Key)
+}
@@ -0,0 +1,79 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rule
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	conf "dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+func TestWatcher(t *testing.T) {
+	w := NewWatcher("test rules", ".test", func(content string) (int, error) {
+		n, err := strconv.Atoi(content)
+		if err == nil && n < 0 {
+			err = errors.New("negative")
+		}
+		return n, err
+	})
+	assert.Equal(t, "app.test", w.Key("app"))
+	assert.Equal(t, 0, w.Get("app"))
+
+	w.Process(&config_center.ConfigChangeEvent{Key: w.Key("app"), Value: "1", ConfigType: remoting.EventTypeAdd})
+	assert.Equal(t, 1, w.Get("app"))
+
+	// the invalid rules are ignored
+	w.Process(&config_center.ConfigChangeEvent{Key: w.Key("app"), Value: "-1", ConfigType: remoting.EventTypeUpdate})
+	assert.Equal(t, 1, w.Get("app"))
+
+	w.Process(&config_center.ConfigChangeEvent{Key: w.Key("app"), Value: " ", ConfigType: remoting.EventTypeUpdate})
+	assert.Equal(t, 0, w.Get("app"))
+
+	w.Set("app", 2)
+	assert.Equal(t, 2, w.Get("app"))
+	w.Process(&config_center.ConfigChangeEvent{Key: w.Key("app"), ConfigType: remoting.EventTypeDel})
+	assert.Equal(t, 0, w.Get("app"))
+}
+
+func TestWatcherWatchesOnceConfigCenterStarts(t *testing.T) {
+	env := conf.GetEnvInstance()
+	defer env.SetDynamicConfiguration(env.GetDynamicConfiguration())
+	env.SetDynamicConfiguration(nil)
+
+	w := NewWatcher("test rules", ".test", strconv.Atoi)
+	assert.Equal(t, 0, w.Get("app"))
+	_, watched := w.watched.Load(w.Key("app"))
+	assert.False(t, watched)
+
+	dc, err := (&config_center.MockDynamicConfigurationFactory{}).GetDynamicConfiguration(nil)
+	assert.NoError(t, err)
+	env.SetDynamicConfiguration(dc)
+	assert.Equal(t, 0, w.Get("app"))
+	_, watched = w.watched.Load(w.Key("app"))
+	assert.True(t, watched)
+}
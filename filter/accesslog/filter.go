@@ -20,8 +20,10 @@ package accesslog
 
 import (
 	"context"
-	"os"
+	"encoding/json"
+	"math/rand"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -29,14 +31,20 @@ import (
 
 import (
 	"github.com/dubbogo/gost/log/logger"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"google.golang.org/protobuf/proto"
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/filter"
 	"dubbo.apache.org/dubbo-go/v3/protocol/base"
 	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
 )
 
 const (
@@ -50,12 +58,17 @@ const (
 	// LogFileMode is the file permission for access log files.
 	LogFileMode = 0o600
 
-	// those fields are the data collected by this filter
-
 	// Types represents the list of argument types in log.
 	Types = "types"
-	// Arguments represents the arguments string in log.
+	// Arguments represents the arguments in log.
 	Arguments = "arguments"
+	// Fault represents the type of the fault injected into the invocation in log.
+	Fault = "fault"
+
+	// startTimeKey is the attribute of the invocation keeping the time when it starts
+	startTimeKey = "dubbo.accesslog.start"
+	// traceParentKey is the attachment of W3C trace context, which is used when there is no span in the context
+	traceParentKey = "traceparent"
 )
 
 var (
@@ -78,15 +91,27 @@ func init() {
  *   interface : "com.ikurento.user.UserProvider"
  *   ... # other configuration
  *   accesslog: "/your/path/to/store/the/log/", # it should be the path of file.
+ *   params:
+ *     accesslog.format: "json" # optional, "text", "json" or "logfmt", "text" by default
+ *     accesslog.fields: "timestamp,interface,method,status,elapsed" # optional, all the fields by default
+ *     accesslog.sample.rate: 0.1 # optional, the ratio of the successful invocations logged, 1 by default
+ *     accesslog.redact: "password,token" # optional, the names of the sensitive fields and map keys
+ *     accesslog.sink: "file" # optional, the name of the AccessLogSink
  *
  * the value of "accesslog" can be "true" or "default" too.
- * If the value is one of them, the access log will be record in log file which defined in log.yml
+ * If the value is one of them, the access log will be record in log file which defined in log.yml.
+ * If the value is "stdout", the access log will be written to the standard output.
+ *
+ * The record is written when the invocation completes, so it has the duration and the result of the invocation.
+ * The failed invocations are always logged regardless of the sample rate.
+ * The struct fields tagged with `accesslog:"redact"` are always redacted.
  * AccessLogFilter is designed to be singleton
  */
 type Filter struct {
 	logChan      chan Data
-	fileLock     sync.RWMutex // protects fileCache
-	fileCache    map[string]*os.File
+	sinkLock     sync.RWMutex // protects sinks
+	sinks        map[string]filter.AccessLogSink
+	options      sync.Map // the key of the configuration -> *options
 	ctx          context.Context
 	cancel       context.CancelFunc
 	shutdownOnce sync.Once
@@ -97,10 +122,10 @@ func newFilter() filter.Filter {
 		once.Do(func() {
 			ctx, cancel := context.WithCancel(context.Background())
 			accessLogFilter = &Filter{
-				logChan:   make(chan Data, LogMaxBuffer),
-				fileCache: make(map[string]*os.File),
-				ctx:       ctx,
-				cancel:    cancel,
+				logChan: make(chan Data, LogMaxBuffer),
+				sinks:   make(map[string]filter.AccessLogSink),
+				ctx:     ctx,
+				cancel:  cancel,
 			}
 			go accessLogFilter.processLogs()
 		})
@@ -109,18 +134,43 @@ func newFilter() filter.Filter {
 }
 
 // Invoke will check whether user wants to use this filter.
-// If we find the value of key constant.AccessLogFilterKey, we will log the invocation info
+// If we find the value of key constant.AccessLogFilterKey, we will record the start time of the invocation
 func (f *Filter) Invoke(ctx context.Context, invoker base.Invoker, invocation base.Invocation) result.Result {
 	accessLog := invoker.GetURL().GetParam(constant.AccessLogFilterKey, "")
 
-	// the user do not
 	if len(accessLog) > 0 {
-		accessLogData := Data{data: f.buildAccessLogData(invoker, invocation), accessLog: accessLog}
-		f.logIntoChannel(accessLogData)
+		invocation.SetAttribute(startTimeKey, time.Now())
 	}
 	return invoker.Invoke(ctx, invocation)
 }
 
+// OnResponse logs the invocation when it completes
+func (f *Filter) OnResponse(ctx context.Context, res result.Result, invoker base.Invoker, invocation base.Invocation) result.Result {
+	if invoker == nil || invocation == nil {
+		return res
+	}
+	url := invoker.GetURL()
+	accessLog := url.GetParam(constant.AccessLogFilterKey, "")
+	if len(accessLog) == 0 {
+		return res
+	}
+
+	opts := f.getOptions(url, accessLog)
+	failed := res != nil && res.Error() != nil
+	if !failed && opts.sampleRate < 1 && rand.Float64() >= opts.sampleRate {
+		return res
+	}
+
+	sink, err := f.getOrCreateSink(url, opts.sinkName, accessLog)
+	if err != nil {
+		logger.Warnf("Can not create the access log sink %s for %s, %v", opts.sinkName, accessLog, err)
+		return res
+	}
+	data := f.buildAccessLogData(ctx, res, invocation, opts)
+	f.logIntoChannel(Data{accessLog: accessLog, sink: sink, message: data.encode(opts.format, opts.fields)})
+	return res
+}
+
 // logIntoChannel won't block the invocation
 func (f *Filter) logIntoChannel(accessLogData Data) {
 	select {
@@ -132,61 +182,214 @@ func (f *Filter) logIntoChannel(accessLogData Data) {
 	}
 }
 
-// buildAccessLogData builds the access log data
-func (f *Filter) buildAccessLogData(_ base.Invoker, invocation base.Invocation) map[string]string {
-	dataMap := make(map[string]string, 16)
-	attachments := invocation.Attachments()
-	itf := attachments[constant.InterfaceKey]
-	if itf == nil || len(itf.(string)) == 0 {
-		itf = attachments[constant.PathKey]
+// buildAccessLogData builds the access log data of the fields configured
+func (f *Filter) buildAccessLogData(ctx context.Context, res result.Result, invocation base.Invocation, opts *options) record {
+	data := make(record, len(opts.fields))
+	set := func(field string, value func() any) {
+		if _, ok := opts.fieldSet[field]; ok {
+			data[field] = value()
+		}
 	}
-	if itf != nil {
-		dataMap[constant.InterfaceKey] = itf.(string)
+	attachment := func(key string) func() any {
+		return func() any {
+			return invocation.GetAttachmentWithDefaultValue(key, "")
+		}
 	}
-	if v, ok := attachments[constant.MethodKey]; ok && v != nil {
-		dataMap[constant.MethodKey] = v.(string)
+
+	start, ok := invocation.GetAttribute(startTimeKey)
+	if startTime, isTime := start.(time.Time); ok && isTime {
+		set(Timestamp, func() any { return startTime })
+		set(Elapsed, func() any { return float64(time.Since(startTime).Microseconds()) / 1000 })
 	}
-	if v, ok := attachments[constant.VersionKey]; ok && v != nil {
-		dataMap[constant.VersionKey] = v.(string)
+	set(Remote, attachment(constant.RemoteAddr))
+	set(Local, attachment(constant.LocalAddr))
+	set(Caller, attachment(constant.RemoteApplicationKey))
+	set(Interface, func() any {
+		itf := invocation.GetAttachmentWithDefaultValue(constant.InterfaceKey, "")
+		if len(itf) == 0 {
+			itf = invocation.GetAttachmentWithDefaultValue(constant.PathKey, "")
+		}
+		return itf
+	})
+	set(Group, attachment(constant.GroupKey))
+	set(Version, attachment(constant.VersionKey))
+	set(Method, func() any {
+		return invocation.GetAttachmentWithDefaultValue(constant.MethodKey, invocation.MethodName())
+	})
+	if v, ok := invocation.GetAttribute(constant.FaultInjectedKey); ok {
+		set(Fault, func() any { return v })
 	}
-	if v, ok := attachments[constant.GroupKey]; ok && v != nil {
-		dataMap[constant.GroupKey] = v.(string)
+
+	if arguments := invocation.Arguments(); len(arguments) > 0 {
+		set(Types, func() any {
+			types := make([]string, len(arguments))
+			for i, argument := range arguments {
+				if argument == nil {
+					types[i] = "nil"
+				} else {
+					types[i] = reflect.TypeOf(argument).String()
+				}
+			}
+			return strings.Join(types, ",")
+		})
+		set(Arguments, func() any {
+			values := make([]any, len(arguments))
+			for i, argument := range arguments {
+				values[i] = opts.redactor.redact(argument)
+			}
+			return values
+		})
 	}
-	if v, ok := attachments[constant.TimestampKey]; ok && v != nil {
-		dataMap[constant.TimestampKey] = v.(string)
+
+	if res != nil && res.Error() != nil {
+		set(Status, func() any { return statusError })
+		set(Code, func() any { return triple_protocol.CodeOf(res.Error()).String() })
+		set(ErrorMessage, func() any { return res.Error().Error() })
+	} else {
+		set(Status, func() any { return statusOK })
+		if res != nil {
+			set(ResponseSize, func() any { return responseSize(res.Result()) })
+		}
 	}
-	if v, ok := attachments[constant.LocalAddr]; ok && v != nil {
-		dataMap[constant.LocalAddr] = v.(string)
+
+	traceID, spanID := traceIDs(ctx, invocation)
+	if len(traceID) > 0 {
+		set(TraceID, func() any { return traceID })
+		set(SpanID, func() any { return spanID })
 	}
-	if v, ok := attachments[constant.RemoteAddr]; ok && v != nil {
-		dataMap[constant.RemoteAddr] = v.(string)
+	return data
+}
+
+// responseSize returns the size of the response, which is the size of the JSON encoded response if it isn't
+// a protobuf message or bytes
+func responseSize(response any) int {
+	switch value := response.(type) {
+	case nil:
+		return 0
+	case []byte:
+		return len(value)
+	case string:
+		return len(value)
+	case proto.Message:
+		return proto.Size(value)
+	default:
+		b, err := json.Marshal(value)
+		if err != nil {
+			return 0
+		}
+		return len(b)
+	}
+}
+
+// traceIDs returns the trace id and span id of the span in ctx, or the ones in the W3C trace context attachment
+func traceIDs(ctx context.Context, invocation base.Invocation) (string, string) {
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		return spanContext.TraceID().String(), spanContext.SpanID().String()
+	}
+	// the format is {version}-{trace id}-{parent id}-{flags}
+	parts := strings.Split(invocation.GetAttachmentWithDefaultValue(traceParentKey, ""), "-")
+	if len(parts) == 4 {
+		return parts[1], parts[2]
 	}
+	return "", ""
+}
 
-	if len(invocation.Arguments()) > 0 {
-		builder := strings.Builder{}
-		// todo(after the paramTypes were set to the invocation. we should change this implementation)
-		typeBuilder := strings.Builder{}
+// options is the configuration of the access log of a service
+type options struct {
+	format     string
+	fields     []string
+	fieldSet   map[string]struct{}
+	sampleRate float64
+	redactor   *redactor
+	sinkName   string
+}
 
-		builder.WriteString(reflect.ValueOf(invocation.Arguments()[0]).String())
-		typeBuilder.WriteString(reflect.TypeOf(invocation.Arguments()[0]).Name())
-		for idx := 1; idx < len(invocation.Arguments()); idx++ {
-			arg := invocation.Arguments()[idx]
-			builder.WriteString(",")
-			builder.WriteString(reflect.ValueOf(arg).String())
+// getOptions returns the options of url, which are cached as parsing them is expensive
+func (f *Filter) getOptions(url *common.URL, accessLog string) *options {
+	format := strings.ToLower(url.GetParam(constant.AccessLogFormatKey, TextFormat))
+	fields := url.GetParam(constant.AccessLogFieldsKey, "")
+	sampleRate := url.GetParam(constant.AccessLogSampleRateKey, "")
+	redact := url.GetParam(constant.AccessLogRedactKey, "")
+	sinkName := url.GetParam(constant.AccessLogSinkKey, "")
+
+	key := strings.Join([]string{accessLog, format, fields, sampleRate, redact, sinkName}, "|")
+	if opts, ok := f.options.Load(key); ok {
+		return opts.(*options)
+	}
 
-			typeBuilder.WriteString(",")
-			typeBuilder.WriteString(reflect.TypeOf(arg).Name())
+	opts := &options{
+		format:     format,
+		fields:     DefaultFields,
+		fieldSet:   make(map[string]struct{}),
+		sampleRate: 1,
+		redactor:   newRedactor(redact),
+		sinkName:   sinkName,
+	}
+	if len(fields) > 0 {
+		opts.fields = nil
+		for _, field := range strings.Split(fields, ",") {
+			if field = strings.TrimSpace(field); len(field) > 0 {
+				opts.fields = append(opts.fields, field)
+			}
+		}
+	}
+	for _, field := range opts.fields {
+		opts.fieldSet[field] = struct{}{}
+	}
+	if len(sampleRate) > 0 {
+		rate, err := strconv.ParseFloat(sampleRate, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			logger.Warnf("The %s should be a number between 0 and 1, but it is %s", constant.AccessLogSampleRateKey, sampleRate)
+		} else {
+			opts.sampleRate = rate
+		}
+	}
+	if len(opts.sinkName) == 0 {
+		switch {
+		case isDefault(accessLog):
+			opts.sinkName = LoggerSinkKey
+		case strings.EqualFold(accessLog, StdoutSinkKey):
+			opts.sinkName = StdoutSinkKey
+		default:
+			opts.sinkName = FileSinkKey
 		}
-		dataMap[Arguments] = builder.String()
-		dataMap[Types] = typeBuilder.String()
 	}
 
-	return dataMap
+	actual, _ := f.options.LoadOrStore(key, opts)
+	return actual.(*options)
+}
+
+// getOrCreateSink gets or creates the sink with proper caching, the sinks with the same name and
+// "accesslog" are shared
+func (f *Filter) getOrCreateSink(url *common.URL, sinkName string, accessLog string) (filter.AccessLogSink, error) {
+	key := sinkKey(sinkName, accessLog)
+	f.sinkLock.RLock()
+	sink, exists := f.sinks[key]
+	f.sinkLock.RUnlock()
+	if exists {
+		return sink, nil
+	}
+
+	f.sinkLock.Lock()
+	defer f.sinkLock.Unlock()
+
+	// Double-check after acquiring write lock
+	if sink, exists = f.sinks[key]; exists {
+		return sink, nil
+	}
+	creator, err := extension.GetAccessLogSinkCreator(sinkName)
+	if err != nil {
+		return nil, err
+	}
+	if sink, err = creator(url); err != nil {
+		return nil, err
+	}
+	f.sinks[key] = sink
+	return sink, nil
 }
 
-// OnResponse do nothing
-func (f *Filter) OnResponse(_ context.Context, result result.Result, _ base.Invoker, _ base.Invocation) result.Result {
-	return result
+func sinkKey(sinkName string, accessLog string) string {
+	return sinkName + "|" + accessLog
 }
 
 // processLogs runs in a background goroutine to process log data
@@ -204,7 +407,7 @@ func (f *Filter) processLogs() {
 			if !ok {
 				return
 			}
-			f.writeLogToFileWithTimeout(accessLogData, 5*time.Second)
+			f.writeLogWithTimeout(accessLogData, 5*time.Second)
 		case <-f.ctx.Done():
 			return
 		}
@@ -220,7 +423,7 @@ func (f *Filter) drainLogs() {
 			if !ok {
 				return
 			}
-			f.writeLogToFileWithTimeout(accessLogData, 1*time.Second)
+			f.writeLogWithTimeout(accessLogData, 1*time.Second)
 		case <-timeout:
 			logger.Warnf("AccessLog drain timeout, some logs may be lost")
 			return
@@ -230,170 +433,34 @@ func (f *Filter) drainLogs() {
 	}
 }
 
-// writeLogToFileWithTimeout writes log with timeout protection
-func (f *Filter) writeLogToFileWithTimeout(data Data, timeout time.Duration) {
+// writeLogWithTimeout writes log with timeout protection
+func (f *Filter) writeLogWithTimeout(data Data, timeout time.Duration) {
 	done := make(chan struct{})
 	go func() {
 		defer close(done)
-		f.writeLogToFile(data)
+		if err := data.sink.Write(data.message); err != nil {
+			logger.Warnf("Can not write the log into access log %s, %v", data.accessLog, err)
+		}
 	}()
 
 	select {
 	case <-done:
 		logger.Debugf("AccessLog successfully written for: %s", data.accessLog)
 	case <-time.After(timeout):
-		logger.Warnf("AccessLog writeLogToFile timeout for: %s", data.accessLog)
-	}
-}
-
-// writeLogToFile actually write the logs into file
-func (f *Filter) writeLogToFile(data Data) {
-	accessLog := data.accessLog
-	if isDefault(accessLog) {
-		logger.Info(data.toLogMessage())
-		return
-	}
-
-	logFile, err := f.getOrOpenLogFile(accessLog)
-	if err != nil {
-		logger.Warnf("Can not open the access log file: %s, %v", accessLog, err)
-		return
-	}
-	logger.Debugf("Append log to %s", accessLog)
-	message := data.toLogMessage()
-	message = message + "\n"
-	_, err = logFile.WriteString(message)
-	if err != nil {
-		logger.Warnf("Can not write the log into access log file: %s, %v", accessLog, err)
+		logger.Warnf("AccessLog write timeout for: %s", data.accessLog)
 	}
 }
 
-// needLogRotation checks if the log file needs rotation based on date
-func needLogRotation(logFile *os.File) bool {
-	now := time.Now().Format(FileDateFormat)
-	if fileInfo, err := logFile.Stat(); err == nil {
-		last := fileInfo.ModTime().Format(FileDateFormat)
-		return now != last
-	}
-	return true // If we can't stat the file, assume rotation is needed
-}
-
-// getOrOpenLogFile gets or opens the log file with proper caching and handle management
-func (f *Filter) getOrOpenLogFile(accessLog string) (*os.File, error) {
-	f.fileLock.RLock()
-	if logFile, exists := f.fileCache[accessLog]; exists {
-		// Check if we need to rotate the log
-		if !needLogRotation(logFile) {
-			f.fileLock.RUnlock()
-			return logFile, nil
-		}
-	}
-	f.fileLock.RUnlock()
-
-	// Need to open new file or rotate existing one
-	f.fileLock.Lock()
-	defer f.fileLock.Unlock()
-
-	// Double-check after acquiring write lock
-	if logFile, exists := f.fileCache[accessLog]; exists {
-		if !needLogRotation(logFile) {
-			return logFile, nil
-		}
-		// Close the old file before rotation
-		if err := logFile.Close(); err != nil {
-			logger.Warnf("Failed to close old log file %s: %v", accessLog, err)
-		}
-		delete(f.fileCache, accessLog)
-	}
-
-	logFile, err := f.openLogFile(accessLog)
-	if err != nil {
-		return nil, err
-	}
-
-	f.fileCache[accessLog] = logFile
-	return logFile, nil
-}
-
-// openLogFile will open the log file with append mode.
-// If the file is not found, it will create the file.
-// Actually, the accessLog is the filename
-func (f *Filter) openLogFile(accessLog string) (*os.File, error) {
-	logFile, err := os.OpenFile(accessLog, os.O_CREATE|os.O_APPEND|os.O_RDWR, LogFileMode)
-	if err != nil {
-		logger.Warnf("Can not open the access log file: %s, %v", accessLog, err)
-		return nil, err
-	}
-	now := time.Now().Format(FileDateFormat)
-	fileInfo, err := logFile.Stat()
-	if err != nil {
-		logger.Warnf("Can not get the info of access log file: %s, %v", accessLog, err)
-		return nil, err
-	}
-	last := fileInfo.ModTime().Format(FileDateFormat)
-
-	// this is confused.
-	// for example, if the last = '2020-03-04'
-	// and today is '2020-03-05'
-	// we will create one new file to log access data
-	// By this way, we can split the access log based on days.
-	// use 'accessLog' as complete path to avoid log not found.
-	if now != last {
-		err = os.Rename(accessLog, accessLog+"."+now)
-		if err != nil {
-			logger.Warnf("Can not rename access log file: %s, %v", accessLog, err)
-			return nil, err
-		}
-		logFile, err = os.OpenFile(accessLog, os.O_CREATE|os.O_APPEND|os.O_RDWR, LogFileMode)
-	}
-	return logFile, err
-}
-
 // isDefault check whether accessLog == true or accessLog == default
 func isDefault(accessLog string) bool {
 	return strings.EqualFold("true", accessLog) || strings.EqualFold("default", accessLog)
 }
 
-// Data defines the data that will be log into file
+// Data defines the data that will be log into the sink
 type Data struct {
 	accessLog string
-	data      map[string]string
-}
-
-// toLogMessage convert the Data to String
-func (d *Data) toLogMessage() string {
-	builder := strings.Builder{}
-	builder.WriteString("[")
-	builder.WriteString(d.data[constant.TimestampKey])
-	builder.WriteString("] ")
-	builder.WriteString(d.data[constant.RemoteAddr])
-	builder.WriteString(" -> ")
-	builder.WriteString(d.data[constant.LocalAddr])
-	builder.WriteString(" - ")
-	if len(d.data[constant.GroupKey]) > 0 {
-		builder.WriteString(d.data[constant.GroupKey])
-		builder.WriteString("/")
-	}
-
-	builder.WriteString(d.data[constant.InterfaceKey])
-
-	if len(d.data[constant.VersionKey]) > 0 {
-		builder.WriteString(":")
-		builder.WriteString(d.data[constant.VersionKey])
-	}
-
-	builder.WriteString(" ")
-	builder.WriteString(d.data[constant.MethodKey])
-	builder.WriteString("(")
-	if len(d.data[Types]) > 0 {
-		builder.WriteString(d.data[Types])
-	}
-	builder.WriteString(") ")
-
-	if len(d.data[Arguments]) > 0 {
-		builder.WriteString(d.data[Arguments])
-	}
-	return builder.String()
+	sink      filter.AccessLogSink
+	message   []byte
 }
 
 // Shutdown gracefully shuts down the access log filter
@@ -417,14 +484,14 @@ func (f *Filter) shutdown() {
 			close(f.logChan)
 		}
 
-		// Close all cached file handles
-		f.fileLock.Lock()
-		defer f.fileLock.Unlock()
-		for path, file := range f.fileCache {
-			if err := file.Close(); err != nil {
-				logger.Warnf("Error closing access log file %s: %v", path, err)
+		// Close all cached sinks
+		f.sinkLock.Lock()
+		defer f.sinkLock.Unlock()
+		for key, sink := range f.sinks {
+			if err := sink.Close(); err != nil {
+				logger.Warnf("Error closing access log sink %s: %v", key, err)
 			}
-			delete(f.fileCache, path)
+			delete(f.sinks, key)
 		}
 	})
 }
@@ -19,6 +19,9 @@ package accesslog
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
 	"testing"
 )
 
@@ -26,14 +29,18 @@ import (
 	"github.com/golang/mock/gomock"
 
 	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/filter"
 	"dubbo.apache.org/dubbo-go/v3/protocol/base"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
 )
 
 func TestFilter_Invoke_Not_Config(t *testing.T) {
@@ -82,3 +89,150 @@ func TestFilterOnResponse(t *testing.T) {
 	response := filter.OnResponse(context.TODO(), rpcResult, nil, nil)
 	assert.Equal(t, rpcResult, response)
 }
+
+type user struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+	Card     string `accesslog:"redact"`
+	Tags     map[string]string
+	internal string
+}
+
+// newTestFilter returns a filter whose records are kept in the channel
+func newTestFilter() *Filter {
+	return &Filter{
+		logChan: make(chan Data, 10),
+		sinks:   make(map[string]filter.AccessLogSink),
+	}
+}
+
+func newTestURL(params map[string]string) *common.URL {
+	opts := []common.Option{common.WithParamsValue(constant.AccessLogFilterKey, "true")}
+	for k, v := range params {
+		opts = append(opts, common.WithParamsValue(k, v))
+	}
+	return common.NewURLWithOptions(opts...)
+}
+
+func invokeAndRecord(t *testing.T, f *Filter, url *common.URL, ctx context.Context, inv base.Invocation, res result.Result) string {
+	invoker := &MockInvoker{url: url}
+	f.Invoke(ctx, invoker, inv)
+	f.OnResponse(ctx, res, invoker, inv)
+	select {
+	case data := <-f.logChan:
+		return string(data.message)
+	default:
+		t.Fatal("no access log record")
+		return ""
+	}
+}
+
+func TestFilterJSONRecord(t *testing.T) {
+	f := newTestFilter()
+	url := newTestURL(map[string]string{
+		constant.AccessLogFormatKey: JSONFormat,
+		constant.AccessLogRedactKey: "password, secret",
+	})
+	inv := invocation.NewRPCInvocation("GetUser", []any{
+		&user{Name: "alice", Password: "123", Card: "6222", Tags: map[string]string{"secret": "s", "city": "hz"}, internal: "x"},
+		"OK",
+	}, map[string]any{
+		constant.InterfaceKey:         "com.ikurento.user.UserProvider",
+		constant.VersionKey:           "1.0",
+		constant.RemoteApplicationKey: "consumer-app",
+		traceParentKey:                "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	})
+
+	message := invokeAndRecord(t, f, url, context.Background(), inv, &result.RPCResult{Rest: []byte("hello")})
+	record := map[string]any{}
+	assert.Nil(t, json.Unmarshal([]byte(message), &record))
+	assert.Equal(t, "com.ikurento.user.UserProvider", record[Interface])
+	assert.Equal(t, "GetUser", record[Method])
+	assert.Equal(t, "1.0", record[Version])
+	assert.Equal(t, "consumer-app", record[Caller])
+	assert.Equal(t, "*accesslog.user,string", record[Types])
+	assert.Equal(t, statusOK, record[Status])
+	assert.Equal(t, float64(5), record[ResponseSize])
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record[TraceID])
+	assert.Equal(t, "00f067aa0ba902b7", record[SpanID])
+	assert.Contains(t, record, Elapsed)
+	assert.Contains(t, record, Timestamp)
+	assert.NotContains(t, record, ErrorMessage)
+	assert.Equal(t, []any{
+		map[string]any{
+			"name":     "alice",
+			"password": Redacted,
+			"Card":     Redacted,
+			"Tags":     map[string]any{"secret": Redacted, "city": "hz"},
+		},
+		"OK",
+	}, record[Arguments])
+}
+
+func TestFilterLogfmtRecordWithError(t *testing.T) {
+	f := newTestFilter()
+	url := newTestURL(map[string]string{
+		constant.AccessLogFormatKey: LogfmtFormat,
+		constant.AccessLogFieldsKey: "method,status,code,error",
+	})
+	inv := invocation.NewRPCInvocation("GetUser", []any{"OK"}, nil)
+	res := &result.RPCResult{Err: triple_protocol.NewError(triple_protocol.CodeNotFound, errors.New("no such user"))}
+
+	message := invokeAndRecord(t, f, url, context.Background(), inv, res)
+	assert.Equal(t, `method=GetUser status=error code=not_found error="not_found: no such user"`, message)
+}
+
+func TestFilterTextRecord(t *testing.T) {
+	f := newTestFilter()
+	url := newTestURL(nil)
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanContext)
+	inv := invocation.NewRPCInvocation("GetUser", []any{user{Name: "alice"}}, map[string]any{
+		constant.InterfaceKey: "UserProvider",
+		constant.GroupKey:     "MyGroup",
+		constant.RemoteAddr:   "127.0.0.1:1234",
+	})
+
+	message := invokeAndRecord(t, f, url, ctx, inv, &result.RPCResult{})
+	assert.Contains(t, message, "] 127.0.0.1:1234 ->  - MyGroup/UserProvider GetUser(accesslog.user) ")
+	assert.Contains(t, message, `{"Card":"***","Tags":null,"name":"alice","password":""} [ok] `)
+	assert.Contains(t, message, "ms [trace: "+spanContext.TraceID().String()+"/"+spanContext.SpanID().String()+"]")
+}
+
+func TestFilterSampling(t *testing.T) {
+	f := newTestFilter()
+	url := newTestURL(map[string]string{constant.AccessLogSampleRateKey: "0"})
+	invoker := &MockInvoker{url: url}
+	inv := invocation.NewRPCInvocation("GetUser", nil, nil)
+
+	f.OnResponse(context.Background(), &result.RPCResult{}, invoker, inv)
+	assert.Len(t, f.logChan, 0)
+
+	// the failed invocations are always logged
+	f.OnResponse(context.Background(), &result.RPCResult{Err: errors.New("failed")}, invoker, inv)
+	assert.Len(t, f.logChan, 1)
+}
+
+func TestFilterSink(t *testing.T) {
+	f := newTestFilter()
+	inv := invocation.NewRPCInvocation("GetUser", nil, nil)
+
+	file := filepath.Join(t.TempDir(), "access.log")
+	for accessLog, sinkName := range map[string]string{
+		"true":    LoggerSinkKey,
+		"default": LoggerSinkKey,
+		"stdout":  StdoutSinkKey,
+		file:      FileSinkKey,
+	} {
+		url := common.NewURLWithOptions(common.WithParamsValue(constant.AccessLogFilterKey, accessLog))
+		f.OnResponse(context.Background(), &result.RPCResult{}, &MockInvoker{url: url}, inv)
+		assert.Contains(t, f.sinks, sinkKey(sinkName, accessLog))
+	}
+
+	url := newTestURL(map[string]string{constant.AccessLogSinkKey: "unknown"})
+	f.OnResponse(context.Background(), &result.RPCResult{}, &MockInvoker{url: url}, inv)
+	assert.NotContains(t, f.sinks, sinkKey("unknown", "true"))
+}
@@ -95,31 +95,34 @@ func TestAccessLogFilterFileHandleManagement(t *testing.T) {
 	invoker := &MockInvoker{url: url}
 	invocation := &invocation_impl.RPCInvocation{}
 
-	// Invoke multiple times to test file handle caching
+	// Invoke multiple times to test sink caching
 	for i := 0; i < 5; i++ {
-		filter.Invoke(context.Background(), invoker, invocation)
+		res := filter.Invoke(context.Background(), invoker, invocation)
+		filter.OnResponse(context.Background(), res, invoker, invocation)
 	}
 
 	// Wait for logs to be processed
 	time.Sleep(100 * time.Millisecond)
 
-	// Check that file is in cache
-	filter.fileLock.RLock()
-	cachedFile, exists := filter.fileCache[tempFile]
-	filter.fileLock.RUnlock()
+	// Check that file sink is in cache
+	filter.sinkLock.RLock()
+	cachedSink, exists := filter.sinks[sinkKey(FileSinkKey, tempFile)]
+	filter.sinkLock.RUnlock()
 
-	assert.True(t, exists, "File should be cached")
-	assert.NotNil(t, cachedFile, "Cached file should not be nil")
+	assert.True(t, exists, "File sink should be cached")
+	assert.NotNil(t, cachedSink, "Cached file sink should not be nil")
+	assert.NotNil(t, cachedSink.(*fileSink).file, "File should be opened")
 
 	// Shutdown and verify files are closed
 	Shutdown()
 
 	// Check that cache is cleared
-	filter.fileLock.RLock()
-	cacheSize := len(filter.fileCache)
-	filter.fileLock.RUnlock()
+	filter.sinkLock.RLock()
+	cacheSize := len(filter.sinks)
+	filter.sinkLock.RUnlock()
 
-	assert.Equal(t, 0, cacheSize, "File cache should be empty after shutdown")
+	assert.Equal(t, 0, cacheSize, "Sink cache should be empty after shutdown")
+	assert.Nil(t, cachedSink.(*fileSink).file, "File should be closed after shutdown")
 }
 
 // MockInvoker for testing
@@ -0,0 +1,236 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accesslog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// TextFormat is the default format, which is a line readable by human
+	TextFormat = "text"
+	// JSONFormat writes each record as a JSON object
+	JSONFormat = "json"
+	// LogfmtFormat writes each record as the key=value pairs
+	LogfmtFormat = "logfmt"
+
+	// those fields are the data collected by this filter
+
+	// Timestamp represents the time when the invocation starts in log.
+	Timestamp = "timestamp"
+	// Remote represents the address of the consumer in log.
+	Remote = "remote"
+	// Local represents the address of the provider in log.
+	Local = "local"
+	// Caller represents the application of the consumer in log.
+	Caller = "caller"
+	// Interface represents the interface of the service in log.
+	Interface = "interface"
+	// Group represents the group of the service in log.
+	Group = "group"
+	// Version represents the version of the service in log.
+	Version = "version"
+	// Method represents the method name in log.
+	Method = "method"
+	// Status represents whether the invocation succeeds in log, which is "ok" or "error".
+	Status = "status"
+	// Code represents the error code of the invocation in log.
+	Code = "code"
+	// ErrorMessage represents the error message of the invocation in log.
+	ErrorMessage = "error"
+	// Elapsed represents the duration of the invocation in milliseconds in log.
+	Elapsed = "elapsed"
+	// ResponseSize represents the size of the response in bytes in log.
+	ResponseSize = "response_size"
+	// TraceID represents the trace id of the invocation in log.
+	TraceID = "trace_id"
+	// SpanID represents the span id of the invocation in log.
+	SpanID = "span_id"
+
+	statusOK    = "ok"
+	statusError = "error"
+)
+
+// DefaultFields are the fields logged when "accesslog.fields" is not configured
+var DefaultFields = []string{
+	Timestamp, Remote, Local, Caller, Interface, Group, Version, Method, Types, Arguments,
+	Status, Code, ErrorMessage, Elapsed, ResponseSize, TraceID, SpanID, Fault,
+}
+
+// record is the data of an invocation, which is encoded in the format configured
+type record map[string]any
+
+// encode encodes the fields of the record in format
+func (r record) encode(format string, fields []string) []byte {
+	switch format {
+	case JSONFormat:
+		return r.encodeJSON(fields)
+	case LogfmtFormat:
+		return r.encodeLogfmt(fields)
+	default:
+		return r.encodeText()
+	}
+}
+
+func (r record) encodeJSON(fields []string) []byte {
+	buf := bytes.Buffer{}
+	buf.WriteByte('{')
+	for _, field := range fields {
+		v, ok := r[field]
+		if !ok {
+			continue
+		}
+		value, err := json.Marshal(v)
+		if err != nil {
+			value, _ = json.Marshal(fmt.Sprint(v))
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(strconv.Quote(field))
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
+
+func (r record) encodeLogfmt(fields []string) []byte {
+	buf := bytes.Buffer{}
+	for _, field := range fields {
+		v, ok := r[field]
+		if !ok {
+			continue
+		}
+		if buf.Len() > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(field)
+		buf.WriteByte('=')
+		buf.WriteString(logfmtValue(v))
+	}
+	return buf.Bytes()
+}
+
+// logfmtValue formats v, and quotes it if it contains the spaces, quotes or equal signs
+func logfmtValue(v any) string {
+	var s string
+	switch value := v.(type) {
+	case string:
+		s = value
+	case time.Time:
+		s = value.Format(time.RFC3339Nano)
+	case int, int64, float64:
+		s = fmt.Sprint(value)
+	default:
+		b, err := json.Marshal(value)
+		if err != nil {
+			s = fmt.Sprint(value)
+		} else {
+			s = string(b)
+		}
+	}
+	if len(s) == 0 || strings.ContainsAny(s, " =\"\t\r\n") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// encodeText keeps the format of the earlier versions, and appends the result of the invocation
+func (r record) encodeText() []byte {
+	builder := strings.Builder{}
+	builder.WriteString("[")
+	if start, ok := r[Timestamp].(time.Time); ok {
+		builder.WriteString(start.Format(MessageDateLayout))
+	}
+	builder.WriteString("] ")
+	builder.WriteString(r.text(Remote))
+	builder.WriteString(" -> ")
+	builder.WriteString(r.text(Local))
+	builder.WriteString(" - ")
+	if group := r.text(Group); len(group) > 0 {
+		builder.WriteString(group)
+		builder.WriteString("/")
+	}
+
+	builder.WriteString(r.text(Interface))
+
+	if version := r.text(Version); len(version) > 0 {
+		builder.WriteString(":")
+		builder.WriteString(version)
+	}
+
+	builder.WriteString(" ")
+	builder.WriteString(r.text(Method))
+	builder.WriteString("(")
+	builder.WriteString(r.text(Types))
+	builder.WriteString(") ")
+
+	if arguments, ok := r[Arguments].([]any); ok {
+		for i, argument := range arguments {
+			if i > 0 {
+				builder.WriteString(",")
+			}
+			b, err := json.Marshal(argument)
+			if err != nil {
+				builder.WriteString(fmt.Sprint(argument))
+			} else {
+				builder.Write(b)
+			}
+		}
+	}
+	if status := r.text(Status); len(status) > 0 {
+		builder.WriteString(" [")
+		builder.WriteString(status)
+		if msg := r.text(ErrorMessage); len(msg) > 0 {
+			builder.WriteString(" ")
+			builder.WriteString(r.text(Code))
+			builder.WriteString(": ")
+			builder.WriteString(msg)
+		}
+		builder.WriteString("]")
+	}
+	if elapsed, ok := r[Elapsed].(float64); ok {
+		builder.WriteString(" ")
+		builder.WriteString(strconv.FormatFloat(elapsed, 'f', -1, 64))
+		builder.WriteString("ms")
+	}
+	if traceID := r.text(TraceID); len(traceID) > 0 {
+		builder.WriteString(" [trace: ")
+		builder.WriteString(traceID)
+		builder.WriteString("/")
+		builder.WriteString(r.text(SpanID))
+		builder.WriteString("]")
+	}
+	if fault := r.text(Fault); len(fault) > 0 {
+		builder.WriteString(" [fault injected: ")
+		builder.WriteString(fault)
+		builder.WriteString("]")
+	}
+	return []byte(builder.String())
+}
+
+func (r record) text(field string) string {
+	s, _ := r[field].(string)
+	return s
+}
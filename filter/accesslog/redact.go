@@ -0,0 +1,150 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accesslog
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	// RedactTag is the struct tag marking the sensitive fields, such as `accesslog:"redact"`
+	RedactTag = "accesslog"
+	// RedactTagValue is the value of the RedactTag marking the sensitive fields
+	RedactTagValue = "redact"
+	// Redacted replaces the value of the sensitive fields in log
+	Redacted = "***"
+
+	maxRedactDepth = 16
+)
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// redactor converts the arguments to the values encoded as JSON in log,
+// replacing the sensitive struct fields and map entries with Redacted.
+type redactor struct {
+	// names are the lower-case names of the sensitive fields
+	names map[string]struct{}
+}
+
+// newRedactor creates a redactor for the comma separated names of the sensitive fields
+func newRedactor(names string) *redactor {
+	r := &redactor{names: make(map[string]struct{})}
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			r.names[strings.ToLower(name)] = struct{}{}
+		}
+	}
+	return r
+}
+
+func (r *redactor) sensitive(name string) bool {
+	_, ok := r.names[strings.ToLower(name)]
+	return ok
+}
+
+// redact returns a copy of v which can be encoded as JSON without the sensitive data
+func (r *redactor) redact(v any) any {
+	return r.value(reflect.ValueOf(v), 0)
+}
+
+func (r *redactor) value(v reflect.Value, depth int) any {
+	if !v.IsValid() {
+		return nil
+	}
+	if depth > maxRedactDepth {
+		return "..."
+	}
+	if v.Kind() != reflect.Pointer && v.Kind() != reflect.Interface &&
+		(v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType)) {
+		// the types like time.Time know how to encode themselves
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return r.value(v.Elem(), depth+1)
+	case reflect.Struct:
+		return r.structValue(v, depth)
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		m := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			if r.sensitive(key) {
+				m[key] = Redacted
+			} else {
+				m[key] = r.value(iter.Value(), depth+1)
+			}
+		}
+		return m
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Bytes()
+		}
+		fallthrough
+	case reflect.Array:
+		s := make([]any, v.Len())
+		for i := range s {
+			s[i] = r.value(v.Index(i), depth+1)
+		}
+		return s
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return v.Type().String()
+	default:
+		return v.Interface()
+	}
+}
+
+func (r *redactor) structValue(v reflect.Value, depth int) any {
+	t := v.Type()
+	m := make(map[string]any, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag == "-" {
+			continue
+		} else if len(tag) > 0 {
+			name = tag
+		}
+		if field.Tag.Get(RedactTag) == RedactTagValue || r.sensitive(field.Name) || r.sensitive(name) {
+			m[name] = Redacted
+			continue
+		}
+		m[name] = r.value(v.Field(i), depth+1)
+	}
+	return m
+}
@@ -0,0 +1,238 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accesslog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+)
+
+const (
+	// LoggerSinkKey is the name of the sink writing the records by the logger
+	LoggerSinkKey = "logger"
+	// StdoutSinkKey is the name of the sink writing the records to the standard output
+	StdoutSinkKey = "stdout"
+	// FileSinkKey is the name of the sink writing the records to the file, which is rotated every day
+	FileSinkKey = "file"
+)
+
+func init() {
+	extension.SetAccessLogSink(LoggerSinkKey, func(*common.URL) (filter.AccessLogSink, error) {
+		return loggerSink{}, nil
+	})
+	extension.SetAccessLogSink(StdoutSinkKey, func(*common.URL) (filter.AccessLogSink, error) {
+		return &writerSink{writer: os.Stdout}, nil
+	})
+	extension.SetAccessLogSink(FileSinkKey, newFileSink)
+}
+
+// loggerSink writes the records by the logger, so they are in the log file defined in log.yml
+type loggerSink struct{}
+
+func (loggerSink) Write(record []byte) error {
+	logger.Info(string(record))
+	return nil
+}
+
+func (loggerSink) Close() error {
+	return nil
+}
+
+// writerSink writes the records to a writer line by line
+type writerSink struct {
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+func (s *writerSink) Write(record []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.writer.Write(append(record, '\n'))
+	return err
+}
+
+func (s *writerSink) Close() error {
+	return nil
+}
+
+// fileSink writes the records to the file, and rotates the file every day or when it is larger than the max size
+/**
+ * "UserProvider":
+ *   ... # other configuration
+ *   accesslog: "/your/path/to/store/the/log/access.log"
+ *   params:
+ *     accesslog.file.max.size: 100 # optional, the max size of the file in MB, 0 means no limit
+ *     accesslog.file.max.backups: 7 # optional, the max number of the rotated files to keep, 0 means keeping all
+ */
+type fileSink struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	now        func() time.Time
+
+	file *os.File
+	size int64
+	day  string
+}
+
+func newFileSink(url *common.URL) (filter.AccessLogSink, error) {
+	path := url.GetParam(constant.AccessLogFilterKey, "")
+	if len(path) == 0 || isDefault(path) {
+		return nil, fmt.Errorf("the path of the access log file is required, but %s is %q", constant.AccessLogFilterKey, path)
+	}
+	return &fileSink{
+		path:       path,
+		maxSize:    url.GetParamInt(constant.AccessLogFileMaxSizeKey, 0) * 1024 * 1024,
+		maxBackups: int(url.GetParamInt(constant.AccessLogFileMaxBackupsKey, 0)),
+		now:        time.Now,
+	}, nil
+}
+
+func (s *fileSink) Write(record []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	line := append(record, '\n')
+	if s.file != nil && s.needRotation(int64(len(line))) {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	if s.file == nil {
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	return err
+}
+
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// needRotation checks if the file needs rotation based on date and size
+func (s *fileSink) needRotation(size int64) bool {
+	if s.now().Format(FileDateFormat) != s.day {
+		return true
+	}
+	return s.maxSize > 0 && s.size > 0 && s.size+size > s.maxSize
+}
+
+// open will open the log file with append mode, and rotate it if it was written before today
+func (s *fileSink) open() error {
+	if fileInfo, err := os.Stat(s.path); err == nil && fileInfo.Size() > 0 &&
+		fileInfo.ModTime().Format(FileDateFormat) != s.now().Format(FileDateFormat) {
+		// for example, if the file was written on '2020-03-04' and today is '2020-03-05',
+		// the file is renamed, and we will create one new file to log access data.
+		// By this way, we can split the access log based on days.
+		if err = s.backup(fileInfo.ModTime()); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, LogFileMode)
+	if err != nil {
+		return err
+	}
+	fileInfo, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	s.file = file
+	s.size = fileInfo.Size()
+	s.day = s.now().Format(FileDateFormat)
+	return nil
+}
+
+func (s *fileSink) rotate() error {
+	err := s.file.Close()
+	s.file = nil
+	if err != nil {
+		logger.Warnf("Failed to close the access log file %s: %v", s.path, err)
+	}
+	day, _ := time.ParseInLocation(FileDateFormat, s.day, time.Local)
+	return s.backup(day)
+}
+
+// backup renames the file to path.{date}, or path.{date}.{n} if there has been a backup of the date,
+// and removes the oldest backups if there are more than maxBackups
+func (s *fileSink) backup(t time.Time) error {
+	name := s.path + "." + t.Format(FileDateFormat)
+	for i := 1; ; i++ {
+		if _, err := os.Stat(name); os.IsNotExist(err) {
+			break
+		}
+		name = fmt.Sprintf("%s.%s.%d", s.path, t.Format(FileDateFormat), i)
+	}
+	if err := os.Rename(s.path, name); err != nil {
+		return err
+	}
+	if s.maxBackups <= 0 {
+		return nil
+	}
+
+	backups, err := filepath.Glob(s.path + ".*")
+	if err != nil || len(backups) <= s.maxBackups {
+		return err
+	}
+	modTimes := make(map[string]time.Time, len(backups))
+	for _, backup := range backups {
+		if fileInfo, err := os.Stat(backup); err == nil {
+			modTimes[backup] = fileInfo.ModTime()
+		}
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		if modTimes[backups[i]].Equal(modTimes[backups[j]]) {
+			return backups[i] < backups[j]
+		}
+		return modTimes[backups[i]].Before(modTimes[backups[j]])
+	})
+	for _, backup := range backups[:len(backups)-s.maxBackups] {
+		if err = os.Remove(backup); err != nil {
+			logger.Warnf("Failed to remove the access log file %s: %v", backup, err)
+		}
+	}
+	return nil
+}
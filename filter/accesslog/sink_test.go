@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accesslog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+func TestFileSinkRotateDaily(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	now := time.Date(2020, 3, 4, 23, 59, 0, 0, time.Local)
+	sink := &fileSink{path: path, now: func() time.Time { return now }}
+	defer sink.Close()
+
+	assert.Nil(t, sink.Write([]byte("first")))
+	now = now.Add(2 * time.Minute)
+	assert.Nil(t, sink.Write([]byte("second")))
+
+	content, err := os.ReadFile(path + ".2020-03-04")
+	assert.Nil(t, err)
+	assert.Equal(t, "first\n", string(content))
+	content, err = os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "second\n", string(content))
+}
+
+func TestFileSinkRotateBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	now := time.Date(2020, 3, 4, 12, 0, 0, 0, time.Local)
+	sink := &fileSink{path: path, maxSize: 10, maxBackups: 2, now: func() time.Time { return now }}
+	defer sink.Close()
+
+	for _, record := range []string{"11111", "22222", "33333", "44444"} {
+		assert.Nil(t, sink.Write([]byte(record)))
+	}
+
+	backups, err := filepath.Glob(path + ".*")
+	assert.Nil(t, err)
+	assert.Len(t, backups, 2)
+	content, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "44444\n", string(content))
+}
+
+func TestFileSinkReopenOldFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	assert.Nil(t, os.WriteFile(path, []byte("old\n"), LogFileMode))
+	yesterday := time.Now().Add(-24 * time.Hour)
+	assert.Nil(t, os.Chtimes(path, yesterday, yesterday))
+
+	sink := &fileSink{path: path, now: time.Now}
+	assert.Nil(t, sink.Write([]byte("new")))
+	assert.Nil(t, sink.Close())
+
+	content, err := os.ReadFile(path + "." + yesterday.Format(FileDateFormat))
+	assert.Nil(t, err)
+	assert.Equal(t, "old\n", string(content))
+	content, err = os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "new\n", string(content))
+}
+
+func TestNewFileSink(t *testing.T) {
+	_, err := newFileSink(common.NewURLWithOptions(common.WithParamsValue(constant.AccessLogFilterKey, "true")))
+	assert.NotNil(t, err)
+
+	sink, err := newFileSink(common.NewURLWithOptions(
+		common.WithParamsValue(constant.AccessLogFilterKey, "/tmp/access.log"),
+		common.WithParamsValue(constant.AccessLogFileMaxSizeKey, "100"),
+		common.WithParamsValue(constant.AccessLogFileMaxBackupsKey, "7")))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100*1024*1024), sink.(*fileSink).maxSize)
+	assert.Equal(t, 7, sink.(*fileSink).maxBackups)
+}
@@ -16,6 +16,23 @@
  */
 
 // Package adaptivesvc providers AdaptiveService filter.
+/*
+ The limiter of each method is selected per service by the "adaptive-service.limiter" parameter,
+ hill-climbing by default, for example:
+
+ services:
+   "UserProvider":
+     interface: "com.ikurento.user.UserProvider"
+     params:
+       adaptive-service.limiter: "gradient2"       # hill-climbing, gradient2 or vegas
+       adaptive-service.limiter.min-limit: "10"
+       adaptive-service.limiter.max-limit: "1000"
+       adaptive-service.limiter.smoothing: "0.2"
+       adaptive-service.limiter.queue-size: "4"   # gradient2 only, vegas uses alpha and beta
+       criticality.shedding: "true"               # reject the less critical requests first, see common/criticality
+
+ The limitation, inflight requests and rejected requests of each method are exported by the metrics module.
+*/
 package adaptivesvc
 
 import (
@@ -33,6 +50,7 @@ import (
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/criticality"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/filter"
 	"dubbo.apache.org/dubbo-go/v3/filter/adaptivesvc/limiter"
@@ -78,8 +96,12 @@ func (f *adaptiveServiceProviderFilter) Invoke(ctx context.Context, invoker base
 		if errors.Is(err, ErrLimiterNotFoundOnMapper) {
 			// limiter is not found on the mapper, just create
 			// a new limiter
+			limiterType, typeErr := limiterType(invoker.GetURL())
+			if typeErr != nil {
+				return &result.RPCResult{Err: wrapErrAdaptiveSvcInterrupted(typeErr)}
+			}
 			if l, err = limiterMapperSingleton.newAndSetMethodLimiter(invoker.GetURL(),
-				invocation.MethodName(), limiter.HillClimbingLimiter); err != nil {
+				invocation.MethodName(), limiterType); err != nil {
 				return &result.RPCResult{Err: wrapErrAdaptiveSvcInterrupted(err)}
 			}
 		} else {
@@ -88,10 +110,17 @@ func (f *adaptiveServiceProviderFilter) Invoke(ctx context.Context, invoker base
 		}
 	}
 
+	// the less critical invocations are rejected first as the limitation is approached
+	if !criticality.Admit(invoker.GetURL(), invocation, l.Inflight()+1, l.Limitation()) {
+		reportRejected(invoker.GetURL(), invocation.MethodName(), l)
+		return &result.RPCResult{Err: wrapErrAdaptiveSvcInterrupted(limiter.ErrReachLimitation)}
+	}
 	updater, err := l.Acquire()
 	if err != nil {
+		reportRejected(invoker.GetURL(), invocation.MethodName(), l)
 		return &result.RPCResult{Err: wrapErrAdaptiveSvcInterrupted(err)}
 	}
+	reportLimiter(invoker.GetURL(), invocation.MethodName(), l)
 
 	invocation.SetAttribute(constant.AdaptiveServiceUpdaterKey, updater)
 	return invoker.Invoke(ctx, invocation)
@@ -145,6 +174,8 @@ func (f *adaptiveServiceProviderFilter) OnResponse(_ context.Context, res result
 		return &result.RPCResult{Err: err}
 	}
 
+	reportLimiter(invoker.GetURL(), invocation.MethodName(), l)
+
 	// set attachments to inform consumer of provider status
 	res.AddAttachment(constant.AdaptiveServiceRemainingKey, fmt.Sprintf("%d", l.Remaining()))
 	res.AddAttachment(constant.AdaptiveServiceInflightKey, fmt.Sprintf("%d", l.Inflight()))
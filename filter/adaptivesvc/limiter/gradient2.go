@@ -0,0 +1,187 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package limiter
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+import (
+	"go.uber.org/atomic"
+)
+
+var (
+	_ Limiter = (*Gradient2)(nil)
+	_ Updater = (*Gradient2Updater)(nil)
+)
+
+// Gradient2Config is the configuration of the Gradient2 limiter.
+type Gradient2Config struct {
+	InitialLimit uint64
+	MinLimit     uint64
+	MaxLimit     uint64
+	// Smoothing is the weight of the new limitation, 1 means the limitation jumps to the new one at once.
+	Smoothing float64
+	// QueueSize is added to the limitation every update, which lets it grow while the RTT is steady.
+	QueueSize uint64
+	// RTTTolerance is how many times the long RTT the short RTT may be before the limitation shrinks.
+	RTTTolerance float64
+	// Window is the period the samples are aggregated over before updating the limitation, their average RTT is
+	// taken as the short RTT. Otherwise, a burst of responses would grow the limitation before the RTT reacts.
+	Window time.Duration
+	// LongWindow is the number of windows the long RTT is averaged over.
+	LongWindow int
+}
+
+// DefaultGradient2Config returns the default configuration of the Gradient2 limiter.
+func DefaultGradient2Config() Gradient2Config {
+	return Gradient2Config{
+		InitialLimit: initialLimitation,
+		MinLimit:     1,
+		MaxLimit:     maxLimitation,
+		Smoothing:    0.2,
+		QueueSize:    4,
+		RTTTolerance: 1.5,
+		Window:       time.Second,
+		LongWindow:   600,
+	}
+}
+
+// Gradient2 is a limiter using the gradient of the short RTT to the long RTT, it is the same as the Gradient2Limit
+// of Netflix concurrency-limits. The limitation shrinks once the recent RTT deviates from the long term average
+// beyond the tolerance, and grows by the queue size otherwise.
+type Gradient2 struct {
+	cfg Gradient2Config
+	now func() time.Time
+
+	inflight   *atomic.Uint64
+	limitation *atomic.Uint64
+
+	mutex          *sync.Mutex
+	estimatedLimit float64
+	longRTT        *expAvg
+
+	// samples of the current window
+	windowStart    time.Time
+	windowRTTSum   float64
+	windowCount    uint64
+	windowInflight uint64
+}
+
+func NewGradient2(cfg Gradient2Config) Limiter {
+	return newGradient2(cfg, time.Now)
+}
+
+func newGradient2(cfg Gradient2Config, now func() time.Time) *Gradient2 {
+	initial := clamp(float64(cfg.InitialLimit), float64(cfg.MinLimit), float64(cfg.MaxLimit))
+	return &Gradient2{
+		cfg:            cfg,
+		now:            now,
+		inflight:       new(atomic.Uint64),
+		limitation:     atomic.NewUint64(uint64(initial)),
+		mutex:          new(sync.Mutex),
+		estimatedLimit: initial,
+		longRTT:        newExpAvg(cfg.LongWindow, 10),
+	}
+}
+
+func (l *Gradient2) Inflight() uint64 {
+	return l.inflight.Load()
+}
+
+func (l *Gradient2) Limitation() uint64 {
+	return l.limitation.Load()
+}
+
+func (l *Gradient2) Remaining() uint64 {
+	limitation := l.limitation.Load()
+	inflight := l.Inflight()
+	if limitation < inflight {
+		return 0
+	}
+	return limitation - inflight
+}
+
+func (l *Gradient2) Acquire() (Updater, error) {
+	if l.Remaining() == 0 {
+		return nil, ErrReachLimitation
+	}
+	return &Gradient2Updater{
+		startTime: l.now(),
+		inflight:  l.inflight.Add(1),
+		limiter:   l,
+	}, nil
+}
+
+func (l *Gradient2) update(rtt time.Duration, inflight uint64) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	if l.windowStart.IsZero() {
+		l.windowStart = now
+	}
+	l.windowRTTSum += float64(rtt)
+	l.windowCount++
+	if inflight > l.windowInflight {
+		l.windowInflight = inflight
+	}
+	if now.Sub(l.windowStart) < l.cfg.Window {
+		return
+	}
+	shortRTT := math.Max(l.windowRTTSum/float64(l.windowCount), 1)
+	inflight = l.windowInflight
+	l.windowStart, l.windowRTTSum, l.windowCount, l.windowInflight = now, 0, 0, 0
+
+	longRTT := l.longRTT.add(shortRTT)
+	// The long RTT is far behind the short one after a period of overload,
+	// decay it faster so that the limitation recovers in time.
+	if longRTT/shortRTT > 2 {
+		l.longRTT.set(longRTT * 0.95)
+	}
+
+	// Don't grow the limitation if it is not used.
+	if float64(inflight) < l.estimatedLimit/2 {
+		return
+	}
+
+	gradient := clamp(l.cfg.RTTTolerance*longRTT/shortRTT, 0.5, 1)
+	newLimit := l.estimatedLimit*gradient + float64(l.cfg.QueueSize)
+	newLimit = l.estimatedLimit*(1-l.cfg.Smoothing) + newLimit*l.cfg.Smoothing
+	newLimit = clamp(newLimit, float64(l.cfg.MinLimit), float64(l.cfg.MaxLimit))
+
+	VerboseDebugf("[Gradient2] The limitation is updated from %f to %f, shortRTT: %f, longRTT: %f, gradient: %f.",
+		l.estimatedLimit, newLimit, shortRTT, longRTT, gradient)
+	l.estimatedLimit = newLimit
+	l.limitation.Store(uint64(newLimit))
+}
+
+type Gradient2Updater struct {
+	startTime time.Time
+	// inflight when the request arrived
+	inflight uint64
+	limiter  *Gradient2
+}
+
+func (u *Gradient2Updater) DoUpdate() error {
+	defer u.limiter.inflight.Dec()
+	u.limiter.update(u.limiter.now().Sub(u.startTime), u.inflight)
+	return nil
+}
@@ -98,6 +98,10 @@ func (l *HillClimbing) Inflight() uint64 {
 	return l.inflight.Load()
 }
 
+func (l *HillClimbing) Limitation() uint64 {
+	return l.limitation.Load()
+}
+
 func (l *HillClimbing) Remaining() uint64 {
 	limitation := l.limitation.Load()
 	inflight := l.Inflight()
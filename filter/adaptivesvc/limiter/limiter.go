@@ -29,11 +29,22 @@ var (
 
 const (
 	HillClimbingLimiter = iota
+	Gradient2Limiter
+	VegasLimiter
+)
+
+// the names used to select a limiter by the "adaptive-service.limiter" parameter
+const (
+	HillClimbingLimiterName = "hill-climbing"
+	Gradient2LimiterName    = "gradient2"
+	VegasLimiterName        = "vegas"
 )
 
 type Limiter interface {
 	Inflight() uint64
 	Remaining() uint64
+	// Limitation returns the current concurrency limitation.
+	Limitation() uint64
 	// Acquire inspects the current status of the system:
 	// - if reaches the limitation, reject the request immediately.
 	// - if not, grant this request and return an Updater defined below.
@@ -0,0 +1,165 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package limiter
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+// latencyCurve returns the latency of the requests when the service is handling concurrency requests.
+type latencyCurve func(concurrency uint64) time.Duration
+
+// queueingCurve is a service handling capacity requests in parallel, the others wait in a queue.
+func queueingCurve(capacity uint64, base time.Duration) latencyCurve {
+	return func(concurrency uint64) time.Duration {
+		if concurrency <= capacity {
+			return base
+		}
+		return base * time.Duration(concurrency) / time.Duration(capacity)
+	}
+}
+
+// simulation drives a limiter in virtual time. Every round, it offers a batch of requests to the limiter,
+// lets the accepted ones finish after the latency given by the curve and then reports them to the limiter.
+type simulation struct {
+	now time.Time
+}
+
+func newSimulation() *simulation {
+	return &simulation{now: time.Unix(0, 0)}
+}
+
+func (s *simulation) clock() time.Time {
+	return s.now
+}
+
+type simulationResult struct {
+	limitations []uint64
+	rejected    int
+}
+
+// last returns the average limitation of the last n rounds
+func (r *simulationResult) last(n int) float64 {
+	var sum uint64
+	for _, l := range r.limitations[len(r.limitations)-n:] {
+		sum += l
+	}
+	return float64(sum) / float64(n)
+}
+
+func (s *simulation) run(t *testing.T, l Limiter, curve latencyCurve, rounds, offered int) *simulationResult {
+	res := &simulationResult{}
+	for i := 0; i < rounds; i++ {
+		updaters := make([]Updater, 0, offered)
+		for j := 0; j < offered; j++ {
+			u, err := l.Acquire()
+			if err != nil {
+				assert.ErrorIs(t, err, ErrReachLimitation)
+				res.rejected++
+				continue
+			}
+			updaters = append(updaters, u)
+		}
+		assert.LessOrEqual(t, l.Inflight(), l.Limitation()+1)
+		s.now = s.now.Add(curve(uint64(len(updaters))))
+		for _, u := range updaters {
+			assert.Nil(t, u.DoUpdate())
+		}
+		assert.Equal(t, uint64(0), l.Inflight())
+		res.limitations = append(res.limitations, l.Limitation())
+	}
+	return res
+}
+
+func TestSimulation(t *testing.T) {
+	newLimiters := map[string]func(now func() time.Time) Limiter{
+		Gradient2LimiterName: func(now func() time.Time) Limiter {
+			cfg := DefaultGradient2Config()
+			cfg.InitialLimit = 10
+			cfg.MaxLimit = 1000
+			// scale the windows down to the latency of the simulated service
+			cfg.Window = 50 * time.Millisecond
+			cfg.LongWindow = 2000
+			return newGradient2(cfg, now)
+		},
+		VegasLimiterName: func(now func() time.Time) Limiter {
+			cfg := DefaultVegasConfig()
+			cfg.InitialLimit = 10
+			cfg.MaxLimit = 1000
+			return newVegas(cfg, now)
+		},
+	}
+
+	for name, newLimiter := range newLimiters {
+		t.Run(name+"/unloaded", func(t *testing.T) {
+			s := newSimulation()
+			flat := func(uint64) time.Duration { return 10 * time.Millisecond }
+			res := s.run(t, newLimiter(s.clock), flat, 3000, 1000)
+			// the limitation keeps growing as long as the latency is steady
+			assert.Greater(t, res.last(100), 400.0)
+		})
+
+		t.Run(name+"/queueing", func(t *testing.T) {
+			s := newSimulation()
+			res := s.run(t, newLimiter(s.clock), queueingCurve(50, 10*time.Millisecond), 2000, 500)
+			assert.Greater(t, res.rejected, 0)
+			assert.Greater(t, res.last(100), 40.0)
+			assert.Less(t, res.last(100), 125.0)
+		})
+
+		t.Run(name+"/degradation", func(t *testing.T) {
+			s := newSimulation()
+			l := newLimiter(s.clock)
+			healthy := s.run(t, l, queueingCurve(100, 10*time.Millisecond), 1000, 500)
+			degraded := s.run(t, l, queueingCurve(20, 10*time.Millisecond), 1000, 500)
+			assert.Greater(t, healthy.last(100), 80.0)
+			assert.Less(t, degraded.last(100), 50.0)
+		})
+	}
+}
+
+func TestLimiterConfigBounds(t *testing.T) {
+	gradient2Cfg := DefaultGradient2Config()
+	gradient2Cfg.InitialLimit = 1000
+	assert.Equal(t, gradient2Cfg.MaxLimit, NewGradient2(gradient2Cfg).Limitation())
+
+	vegasCfg := DefaultVegasConfig()
+	vegasCfg.InitialLimit = 0
+	vegasCfg.MinLimit = 5
+	l := NewVegas(vegasCfg)
+	assert.Equal(t, uint64(5), l.Limitation())
+
+	updaters := make([]Updater, 0, 5)
+	for i := 0; i < 5; i++ {
+		u, err := l.Acquire()
+		assert.Nil(t, err)
+		updaters = append(updaters, u)
+	}
+	_, err := l.Acquire()
+	assert.ErrorIs(t, err, ErrReachLimitation)
+	assert.Equal(t, uint64(0), l.Remaining())
+	for _, u := range updaters {
+		assert.Nil(t, u.DoUpdate())
+	}
+	assert.Equal(t, uint64(0), l.Inflight())
+}
@@ -18,6 +18,7 @@
 package limiter
 
 import (
+	"math"
 	"time"
 )
 
@@ -38,3 +39,34 @@ func minDuration(lhs, rhs time.Duration) time.Duration {
 	}
 	return rhs
 }
+
+func clamp(v, lower, upper float64) float64 {
+	return math.Max(lower, math.Min(v, upper))
+}
+
+// expAvg is an exponential moving average, which is a simple average of the first warmup samples.
+type expAvg struct {
+	window int
+	warmup int
+	count  int
+	value  float64
+}
+
+func newExpAvg(window, warmup int) *expAvg {
+	return &expAvg{window: window, warmup: warmup}
+}
+
+func (a *expAvg) add(sample float64) float64 {
+	if a.count < a.warmup {
+		a.count++
+		a.value += (sample - a.value) / float64(a.count)
+	} else {
+		factor := 2.0 / float64(a.window+1)
+		a.value = a.value*(1-factor) + sample*factor
+	}
+	return a.value
+}
+
+func (a *expAvg) set(value float64) {
+	a.value = value
+}
@@ -0,0 +1,185 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package limiter
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+import (
+	"go.uber.org/atomic"
+)
+
+var (
+	_ Limiter = (*Vegas)(nil)
+	_ Updater = (*VegasUpdater)(nil)
+)
+
+// VegasConfig is the configuration of the Vegas limiter.
+//
+// The queue size is estimated as limitation * (1 - noLoadRTT / RTT). Alpha and Beta are the queue size thresholds
+// in multiples of log10(limitation): the limitation grows while the queue is shorter than alpha, and shrinks once
+// it is longer than beta.
+type VegasConfig struct {
+	InitialLimit uint64
+	MinLimit     uint64
+	MaxLimit     uint64
+	// Smoothing is the weight of the new limitation, 1 means the limitation jumps to the new one at once.
+	Smoothing float64
+	Alpha     float64
+	Beta      float64
+	// ProbeMultiplier makes the limiter probe the no-load RTT again every ProbeMultiplier * limitation samples,
+	// so that it follows the changes of the service. 0 disables probing.
+	ProbeMultiplier uint64
+}
+
+// DefaultVegasConfig returns the default configuration of the Vegas limiter.
+func DefaultVegasConfig() VegasConfig {
+	return VegasConfig{
+		InitialLimit:    initialLimitation,
+		MinLimit:        1,
+		MaxLimit:        maxLimitation,
+		Smoothing:       1,
+		Alpha:           3,
+		Beta:            6,
+		ProbeMultiplier: 30,
+	}
+}
+
+// Vegas is a limiter using the delay based congestion avoidance of TCP Vegas, it is the same as the VegasLimit
+// of Netflix concurrency-limits. The minimum RTT observed is taken as the no-load RTT, and the queue built up
+// in the service is estimated from how much the RTT exceeds it.
+type Vegas struct {
+	cfg VegasConfig
+	now func() time.Time
+
+	inflight   *atomic.Uint64
+	limitation *atomic.Uint64
+
+	mutex          *sync.Mutex
+	estimatedLimit float64
+	noLoadRTT      float64
+	probeCount     uint64
+}
+
+func NewVegas(cfg VegasConfig) Limiter {
+	return newVegas(cfg, time.Now)
+}
+
+func newVegas(cfg VegasConfig, now func() time.Time) *Vegas {
+	initial := clamp(float64(cfg.InitialLimit), float64(cfg.MinLimit), float64(cfg.MaxLimit))
+	return &Vegas{
+		cfg:            cfg,
+		now:            now,
+		inflight:       new(atomic.Uint64),
+		limitation:     atomic.NewUint64(uint64(initial)),
+		mutex:          new(sync.Mutex),
+		estimatedLimit: initial,
+	}
+}
+
+func (l *Vegas) Inflight() uint64 {
+	return l.inflight.Load()
+}
+
+func (l *Vegas) Limitation() uint64 {
+	return l.limitation.Load()
+}
+
+func (l *Vegas) Remaining() uint64 {
+	limitation := l.limitation.Load()
+	inflight := l.Inflight()
+	if limitation < inflight {
+		return 0
+	}
+	return limitation - inflight
+}
+
+func (l *Vegas) Acquire() (Updater, error) {
+	if l.Remaining() == 0 {
+		return nil, ErrReachLimitation
+	}
+	return &VegasUpdater{
+		startTime: l.now(),
+		inflight:  l.inflight.Add(1),
+		limiter:   l,
+	}, nil
+}
+
+func (l *Vegas) update(rtt time.Duration, inflight uint64) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	sample := math.Max(float64(rtt), 1)
+	l.probeCount++
+	if l.cfg.ProbeMultiplier > 0 && float64(l.probeCount) >= float64(l.cfg.ProbeMultiplier)*l.estimatedLimit {
+		VerboseDebugf("[Vegas] Probe the no-load RTT, the previous one is %f, the new one is %f.", l.noLoadRTT, sample)
+		l.probeCount = 0
+		l.noLoadRTT = sample
+		return
+	}
+	if l.noLoadRTT == 0 || sample < l.noLoadRTT {
+		l.noLoadRTT = sample
+		return
+	}
+
+	// Don't grow the limitation if it is not used.
+	if float64(inflight)*2 < l.estimatedLimit {
+		return
+	}
+
+	limit := l.estimatedLimit
+	queueSize := math.Ceil(limit * (1 - l.noLoadRTT/sample))
+	logLimit := math.Max(1, math.Log10(limit))
+	alpha := l.cfg.Alpha * logLimit
+	beta := l.cfg.Beta * logLimit
+
+	var newLimit float64
+	switch {
+	case queueSize <= logLimit:
+		newLimit = limit + beta
+	case queueSize < alpha:
+		newLimit = limit + logLimit
+	case queueSize > beta:
+		newLimit = limit - logLimit
+	default:
+		return
+	}
+	newLimit = clamp(newLimit, float64(l.cfg.MinLimit), float64(l.cfg.MaxLimit))
+	newLimit = limit*(1-l.cfg.Smoothing) + newLimit*l.cfg.Smoothing
+
+	VerboseDebugf("[Vegas] The limitation is updated from %f to %f, noLoadRTT: %f, rtt: %f, queueSize: %f.",
+		limit, newLimit, l.noLoadRTT, sample, queueSize)
+	l.estimatedLimit = newLimit
+	l.limitation.Store(uint64(newLimit))
+}
+
+type VegasUpdater struct {
+	startTime time.Time
+	// inflight when the request arrived
+	inflight uint64
+	limiter  *Vegas
+}
+
+func (u *VegasUpdater) DoUpdate() error {
+	defer u.limiter.inflight.Dec()
+	u.limiter.update(u.limiter.now().Sub(u.startTime), u.inflight)
+	return nil
+}
@@ -19,11 +19,13 @@ package adaptivesvc
 
 import (
 	"fmt"
+	"strconv"
 	"sync"
 )
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/filter/adaptivesvc/limiter"
 )
 
@@ -34,10 +36,70 @@ var (
 	ErrLimiterTypeNotFound     = fmt.Errorf("limiter type not found")
 )
 
+var limiterTypes = map[string]int{
+	limiter.HillClimbingLimiterName: limiter.HillClimbingLimiter,
+	limiter.Gradient2LimiterName:    limiter.Gradient2Limiter,
+	limiter.VegasLimiterName:        limiter.VegasLimiter,
+}
+
 func init() {
 	limiterMapperSingleton = newLimiterMapper()
 }
 
+// limiterName returns the name of the limiter selected by the url, hill-climbing by default.
+func limiterName(url *common.URL) string {
+	return url.GetParam(constant.AdaptiveServiceLimiterKey, limiter.HillClimbingLimiterName)
+}
+
+func limiterType(url *common.URL) (int, error) {
+	t, ok := limiterTypes[limiterName(url)]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrLimiterTypeNotFound, limiterName(url))
+	}
+	return t, nil
+}
+
+func gradient2Config(url *common.URL) limiter.Gradient2Config {
+	cfg := limiter.DefaultGradient2Config()
+	cfg.InitialLimit = getUint(url, constant.AdaptiveServiceLimiterInitialLimitKey, cfg.InitialLimit)
+	cfg.MinLimit = getUint(url, constant.AdaptiveServiceLimiterMinLimitKey, cfg.MinLimit)
+	cfg.MaxLimit = getUint(url, constant.AdaptiveServiceLimiterMaxLimitKey, cfg.MaxLimit)
+	cfg.Smoothing = getFloat(url, constant.AdaptiveServiceLimiterSmoothingKey, cfg.Smoothing)
+	cfg.QueueSize = getUint(url, constant.AdaptiveServiceLimiterQueueSizeKey, cfg.QueueSize)
+	cfg.RTTTolerance = getFloat(url, constant.AdaptiveServiceLimiterRTTToleranceKey, cfg.RTTTolerance)
+	cfg.Window = url.GetParamDuration(constant.AdaptiveServiceLimiterWindowKey, cfg.Window.String())
+	cfg.LongWindow = int(url.GetParamInt(constant.AdaptiveServiceLimiterLongWindowKey, int64(cfg.LongWindow)))
+	return cfg
+}
+
+func vegasConfig(url *common.URL) limiter.VegasConfig {
+	cfg := limiter.DefaultVegasConfig()
+	cfg.InitialLimit = getUint(url, constant.AdaptiveServiceLimiterInitialLimitKey, cfg.InitialLimit)
+	cfg.MinLimit = getUint(url, constant.AdaptiveServiceLimiterMinLimitKey, cfg.MinLimit)
+	cfg.MaxLimit = getUint(url, constant.AdaptiveServiceLimiterMaxLimitKey, cfg.MaxLimit)
+	cfg.Smoothing = getFloat(url, constant.AdaptiveServiceLimiterSmoothingKey, cfg.Smoothing)
+	cfg.Alpha = getFloat(url, constant.AdaptiveServiceLimiterAlphaKey, cfg.Alpha)
+	cfg.Beta = getFloat(url, constant.AdaptiveServiceLimiterBetaKey, cfg.Beta)
+	cfg.ProbeMultiplier = getUint(url, constant.AdaptiveServiceLimiterProbeMultiplierKey, cfg.ProbeMultiplier)
+	return cfg
+}
+
+func getUint(url *common.URL, key string, def uint64) uint64 {
+	v, err := strconv.ParseUint(url.GetParam(key, ""), 10, 64)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+func getFloat(url *common.URL, key string, def float64) float64 {
+	v, err := strconv.ParseFloat(url.GetParam(key, ""), 64)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
 type limiterMapper struct {
 	rwMutex *sync.RWMutex
 	mapper  map[string]limiter.Limiter
@@ -66,6 +128,10 @@ func (m *limiterMapper) newAndSetMethodLimiter(url *common.URL, methodName strin
 	switch limiterType {
 	case limiter.HillClimbingLimiter:
 		l = limiter.NewHillClimbing()
+	case limiter.Gradient2Limiter:
+		l = limiter.NewGradient2(gradient2Config(url))
+	case limiter.VegasLimiter:
+		l = limiter.NewVegas(vegasConfig(url))
 	default:
 		return nil, ErrLimiterTypeNotFound
 	}
@@ -0,0 +1,85 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adaptivesvc
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/filter/adaptivesvc/limiter"
+)
+
+func newURL(params map[string]string) *common.URL {
+	values := url.Values{}
+	for k, v := range params {
+		values.Set(k, v)
+	}
+	return common.NewURLWithOptions(common.WithPath("com.ikurento.user.UserProvider"), common.WithParams(values))
+}
+
+func TestLimiterType(t *testing.T) {
+	typ, err := limiterType(newURL(nil))
+	assert.Nil(t, err)
+	assert.Equal(t, limiter.HillClimbingLimiter, typ)
+
+	typ, err = limiterType(newURL(map[string]string{constant.AdaptiveServiceLimiterKey: limiter.VegasLimiterName}))
+	assert.Nil(t, err)
+	assert.Equal(t, limiter.VegasLimiter, typ)
+
+	_, err = limiterType(newURL(map[string]string{constant.AdaptiveServiceLimiterKey: "unknown"}))
+	assert.True(t, errors.Is(err, ErrLimiterTypeNotFound))
+}
+
+func TestLimiterConfig(t *testing.T) {
+	u := newURL(map[string]string{
+		constant.AdaptiveServiceLimiterMinLimitKey:  "10",
+		constant.AdaptiveServiceLimiterMaxLimitKey:  "1000",
+		constant.AdaptiveServiceLimiterSmoothingKey: "0.5",
+		constant.AdaptiveServiceLimiterQueueSizeKey: "8",
+		constant.AdaptiveServiceLimiterWindowKey:    "200ms",
+		constant.AdaptiveServiceLimiterAlphaKey:     "2",
+		constant.AdaptiveServiceLimiterBetaKey:      "invalid",
+	})
+
+	gradient2 := gradient2Config(u)
+	assert.Equal(t, uint64(10), gradient2.MinLimit)
+	assert.Equal(t, uint64(1000), gradient2.MaxLimit)
+	assert.Equal(t, 0.5, gradient2.Smoothing)
+	assert.Equal(t, uint64(8), gradient2.QueueSize)
+	assert.Equal(t, 200*time.Millisecond, gradient2.Window)
+	assert.Equal(t, limiter.DefaultGradient2Config().RTTTolerance, gradient2.RTTTolerance)
+
+	vegas := vegasConfig(u)
+	assert.Equal(t, uint64(10), vegas.MinLimit)
+	assert.Equal(t, 2.0, vegas.Alpha)
+	assert.Equal(t, limiter.DefaultVegasConfig().Beta, vegas.Beta)
+
+	l, err := newLimiterMapper().newAndSetMethodLimiter(u, "GetUser", limiter.Gradient2Limiter)
+	assert.Nil(t, err)
+	assert.IsType(t, &limiter.Gradient2{}, l)
+}
@@ -0,0 +1,89 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adaptivesvc
+
+import (
+	"sync/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/filter/adaptivesvc/limiter"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+)
+
+var (
+	limitationKey = metrics.NewMetricKey("dubbo_provider_adaptive_service_limitation",
+		"The Concurrency Limitation of Adaptive Service")
+	inflightKey = metrics.NewMetricKey("dubbo_provider_adaptive_service_inflight",
+		"The Inflight Requests of Adaptive Service")
+	rejectedKey = metrics.NewMetricKey("dubbo_provider_adaptive_service_rejected_total",
+		"The Requests Rejected by Adaptive Service")
+
+	// limiterMetrics is set once the metrics module is initialized
+	limiterMetrics atomic.Pointer[limiterMetricSet]
+)
+
+func init() {
+	metrics.AddCollector("adaptive_service", func(registry metrics.MetricRegistry, _ *common.URL) {
+		limiterMetrics.Store(&limiterMetricSet{
+			limitation: metrics.NewGaugeVec(registry, limitationKey),
+			inflight:   metrics.NewGaugeVec(registry, inflightKey),
+			rejected:   metrics.NewCounterVec(registry, rejectedKey),
+		})
+	})
+}
+
+type limiterMetricSet struct {
+	limitation metrics.GaugeVec
+	inflight   metrics.GaugeVec
+	rejected   metrics.CounterVec
+}
+
+func buildLabels(url *common.URL, methodName string) map[string]string {
+	return map[string]string{
+		constant.TagApplicationName: url.GetParam(constant.ApplicationKey, ""),
+		constant.TagInterface:       url.Service(),
+		constant.TagMethod:          methodName,
+		constant.TagGroup:           url.Group(),
+		constant.TagVersion:         url.GetParam(constant.VersionKey, ""),
+		constant.TagLimiter:         limiterName(url),
+	}
+}
+
+// reportLimiter exports the current limitation and inflight requests of the method limiter
+func reportLimiter(url *common.URL, methodName string, l limiter.Limiter) {
+	m := limiterMetrics.Load()
+	if m == nil {
+		return
+	}
+	labels := buildLabels(url, methodName)
+	m.limitation.Set(labels, float64(l.Limitation()))
+	m.inflight.Set(labels, float64(l.Inflight()))
+}
+
+// reportRejected counts a request rejected by the method limiter
+func reportRejected(url *common.URL, methodName string, l limiter.Limiter) {
+	m := limiterMetrics.Load()
+	if m == nil {
+		return
+	}
+	m.rejected.Inc(buildLabels(url, methodName))
+	reportLimiter(url, methodName, l)
+}
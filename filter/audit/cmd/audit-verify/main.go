@@ -0,0 +1,65 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Command audit-verify checks the hash chains of the audit log files written by the audit filter.
+//
+//	audit-verify [-secret-env AUDIT_SECRET] /var/log/dubbo/audit.log ...
+//
+// It prints the number of the records and the hash of the last record of every file,
+// and exits with 1 if any of them is broken.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/filter/audit"
+)
+
+func main() {
+	secretEnv := flag.String("secret-env", "", "the environment variable of the HMAC secret the records are written with")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-secret-env NAME] FILE...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var secret []byte
+	if *secretEnv != "" {
+		secret = []byte(os.Getenv(*secretEnv))
+	}
+	failed := false
+	for _, path := range flag.Args() {
+		result, err := audit.VerifyFile(path, secret)
+		if err != nil {
+			failed = true
+			fmt.Printf("%s: FAILED, %v\n", path, err)
+			continue
+		}
+		fmt.Printf("%s: OK, %d records, last hash %s\n", path, result.Records, result.LastHash)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
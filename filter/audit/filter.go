@@ -0,0 +1,203 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package audit provides the provider filter recording who invoked the sensitive methods, when, and with
+// what outcome, into a tamper-evident audit log.
+/*
+ The audit is enabled per method, or for every method of a service, and the filter is put ahead of
+ the other filters automatically, so that the calls rejected by them, by auth or tps for example, are
+ audited as well, for example:
+
+ services:
+   "UserProvider":
+     interface: "com.ikurento.user.UserProvider"
+     params:
+       audit.file: "/var/log/dubbo/audit.log"   # the file the records are appended to
+       audit.secret.env: "AUDIT_SECRET"          # optional, the environment variable of the HMAC secret
+       audit.sink: "file"                        # optional, the name of the AuditSink
+     methods:
+       - name: "UpdateUser"
+         audit: true
+         audit-fields: "0.id,0.email"           # optional, the argument fields recorded
+
+ Each record has the principal authenticated by the auth filter, if any, the address and application
+ of the caller, the method, the selected argument fields and the status of the result. An argument
+ field is selected by a path like "1" or "0.address.city", the index of the argument followed by the
+ names of the struct fields, their json names or the map keys. The other arguments are never
+ recorded. The maps keyed by any, like the ones decoded by hessian, are recorded keyed by the strings
+ of their keys, and the values which JSON can't encode are recorded as a placeholder.
+
+ The records are hash-chained: every record has the hash of the previous one, and is written with its
+ own hash, which is a HMAC-SHA256 if a secret is given, SHA-256 otherwise. The services sharing a
+ sink share its chain, so they must name the same secret env, the records of the others are dropped
+ with an error. The chain is continued from the last record of the sink after restarts, so a log file
+ is verified as a whole by Verify or by the command audit-verify. Note that the removal of the latest
+ records can't be told by the chain, keep the last hash elsewhere to detect it.
+*/
+package audit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	triple_protocol "dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+)
+
+const (
+	statusOK    = "ok"
+	statusError = "error"
+
+	// startTimeKey is the attribute of the invocation keeping the time when it starts
+	startTimeKey = "dubbo.audit.start"
+)
+
+var (
+	once        sync.Once
+	auditFilter *Filter
+)
+
+func init() {
+	extension.SetFilter(constant.AuditFilterKey, newFilter)
+}
+
+// Filter appends an audit record for every invocation of the audited methods
+type Filter struct {
+	chainsLock sync.Mutex
+	chains     map[string]*chain // sink name|audit file -> chain
+}
+
+func newFilter() filter.Filter {
+	once.Do(func() {
+		auditFilter = &Filter{chains: make(map[string]*chain)}
+	})
+	return auditFilter
+}
+
+// Invoke records the start time of the audited invocations
+func (f *Filter) Invoke(ctx context.Context, invoker base.Invoker, invocation base.Invocation) result.Result {
+	if audited(invoker.GetURL(), invocation.MethodName()) {
+		invocation.SetAttribute(startTimeKey, time.Now())
+	}
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse appends the audit record of the invocation to its chain. A record failing to be written
+// is logged, while the result is returned as is, since the invocation has been done.
+func (f *Filter) OnResponse(_ context.Context, res result.Result, invoker base.Invoker, invocation base.Invocation) result.Result {
+	url := invoker.GetURL()
+	method := invocation.MethodName()
+	if !audited(url, method) {
+		return res
+	}
+
+	c, err := f.getChain(url)
+	if err != nil {
+		logger.Errorf("[Audit Filter] can not create the audit sink of %s, the invocation of %s is not recorded: %v",
+			url.ServiceKey(), method, err)
+		return res
+	}
+	if err = c.append(newRecord(url, res, invocation)); err != nil {
+		logger.Errorf("[Audit Filter] fail to record the invocation of %s.%s: %v", url.ServiceKey(), method, err)
+	}
+	return res
+}
+
+func audited(url *common.URL, method string) bool {
+	return url.GetMethodParamBool(method, constant.AuditKey, url.GetParamBool(constant.AuditKey, false))
+}
+
+// newRecord builds the audit record of the invocation, whose sequence and previous hash are set by the chain
+func newRecord(url *common.URL, res result.Result, invocation base.Invocation) *Record {
+	method := invocation.MethodName()
+	r := &Record{
+		Time:        time.Now(),
+		Remote:      invocation.GetAttachmentWithDefaultValue(constant.RemoteAddr, ""),
+		Application: invocation.GetAttachmentWithDefaultValue(constant.RemoteApplicationKey, ""),
+		Service:     url.GetParam(constant.InterfaceKey, url.Service()),
+		Group:       url.Group(),
+		Version:     url.Version(),
+		Method:      method,
+		Status:      statusOK,
+	}
+	if start, ok := invocation.GetAttribute(startTimeKey); ok {
+		if t, isTime := start.(time.Time); isTime {
+			r.Time = t
+		}
+	}
+	if principal, ok := invocation.GetAttribute(constant.PrincipalKey); ok {
+		r.Principal, _ = principal.(string)
+	}
+	if fields := url.GetMethodParam(method, constant.AuditFieldsKey, url.GetParam(constant.AuditFieldsKey, "")); fields != "" {
+		r.Arguments = selectFields(invocation.Arguments(), strings.Split(fields, ","))
+	}
+	if res != nil && res.Error() != nil {
+		r.Status = statusError
+		r.Code = triple_protocol.CodeOf(res.Error()).String()
+	}
+	return r
+}
+
+// getChain returns the chain of the sink configured by url, the urls with the same sink share the chain.
+// A chain is hashed with a single secret, so the urls sharing it must name the same secret env.
+func (f *Filter) getChain(url *common.URL) (*chain, error) {
+	sinkName := url.GetParam(constant.AuditSinkKey, FileSinkKey)
+	key := sinkName + "|" + url.GetParam(constant.AuditFileKey, "")
+	env := url.GetParam(constant.AuditSecretEnvKey, "")
+
+	f.chainsLock.Lock()
+	defer f.chainsLock.Unlock()
+	if c, ok := f.chains[key]; ok {
+		if c.secretEnv != env {
+			return nil, fmt.Errorf("the audit sink %s is hashed with the secret env %q, but %s sets %q",
+				key, c.secretEnv, constant.AuditSecretEnvKey, env)
+		}
+		return c, nil
+	}
+	creator, err := extension.GetAuditSinkCreator(sinkName)
+	if err != nil {
+		return nil, err
+	}
+	sink, err := creator(url)
+	if err != nil {
+		return nil, err
+	}
+	var secret []byte
+	if env != "" {
+		if secret = []byte(os.Getenv(env)); len(secret) == 0 {
+			logger.Warnf("[Audit Filter] the audit secret %s is empty, the records of %s are hashed by SHA-256", env, key)
+		}
+	}
+	c := &chain{sink: sink, secret: secret, secretEnv: env}
+	f.chains[key] = c
+	return c, nil
+}
@@ -0,0 +1,264 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+)
+
+type address struct {
+	City string `json:"city"`
+}
+
+type user struct {
+	ID       string `json:"id"`
+	Password string
+	Address  *address
+	Tags     map[string]string
+}
+
+func newInvoker(params map[string]string) base.Invoker {
+	values := url.Values{}
+	for k, v := range params {
+		values.Set(k, v)
+	}
+	return base.NewBaseInvoker(common.NewURLWithOptions(
+		common.WithPath("com.ikurento.user.UserProvider"),
+		common.WithParams(values),
+		common.WithParamsValue(constant.InterfaceKey, "com.ikurento.user.UserProvider")))
+}
+
+func invoke(f *Filter, invoker base.Invoker, method string, err error, arguments ...any) {
+	inv := invocation.NewRPCInvocation(method, arguments, map[string]any{
+		constant.RemoteAddr:           "10.0.0.1:53421",
+		constant.RemoteApplicationKey: "admin-console",
+	})
+	inv.SetAttribute(constant.PrincipalKey, "ak-admin")
+	res := f.Invoke(context.Background(), invoker, inv)
+	if err != nil {
+		res = &result.RPCResult{Err: err}
+	}
+	f.OnResponse(context.Background(), res, invoker, inv)
+}
+
+func readRecords(t *testing.T, path string) []*Record {
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var records []*Record
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		_, r, err := decodeEntry([]byte(line))
+		require.NoError(t, err)
+		records = append(records, r)
+	}
+	return records
+}
+
+func TestFilterRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	invoker := newInvoker(map[string]string{
+		constant.AuditFileKey:                           path,
+		"methods.UpdateUser." + constant.AuditKey:       "true",
+		"methods.UpdateUser." + constant.AuditFieldsKey: "0.id,0.address.city,0.tags.role,1,0.missing",
+	})
+	f := &Filter{chains: make(map[string]*chain)}
+
+	u := &user{ID: "u-1", Password: "secret", Address: &address{City: "Hangzhou"}, Tags: map[string]string{"Role": "admin"}}
+	invoke(f, invoker, "UpdateUser", nil, u, true)
+	invoke(f, invoker, "GetUser", nil, "u-1")
+	invoke(f, invoker, "UpdateUser", triple_protocol.NewError(triple_protocol.CodePermissionDenied, errors.New("denied")), u, false)
+
+	records := readRecords(t, path)
+	require.Len(t, records, 2)
+	r := records[0]
+	assert.Equal(t, uint64(1), r.Seq)
+	assert.Equal(t, GenesisHash, r.PrevHash)
+	assert.Equal(t, "ak-admin", r.Principal)
+	assert.Equal(t, "10.0.0.1:53421", r.Remote)
+	assert.Equal(t, "admin-console", r.Application)
+	assert.Equal(t, "com.ikurento.user.UserProvider", r.Service)
+	assert.Equal(t, "UpdateUser", r.Method)
+	assert.Equal(t, statusOK, r.Status)
+	assert.Equal(t, map[string]any{"0.id": "u-1", "0.address.city": "Hangzhou", "0.tags.role": "admin", "1": true}, r.Arguments)
+
+	assert.Equal(t, uint64(2), records[1].Seq)
+	assert.Equal(t, statusError, records[1].Status)
+	assert.Equal(t, triple_protocol.CodePermissionDenied.String(), records[1].Code)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "secret")
+}
+
+func TestFilterContinueChain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	invoker := newInvoker(map[string]string{
+		constant.AuditFileKey: path,
+		constant.AuditKey:     "true",
+	})
+	invoke(&Filter{chains: make(map[string]*chain)}, invoker, "GetUser", nil)
+	invoke(&Filter{chains: make(map[string]*chain)}, invoker, "GetUser", nil)
+
+	records := readRecords(t, path)
+	require.Len(t, records, 2)
+	assert.Equal(t, uint64(2), records[1].Seq)
+	res, err := VerifyFile(path, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Records)
+
+	// the chain isn't continued from a broken record
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, FileMode)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"record":{"seq":3`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	invoke(&Filter{chains: make(map[string]*chain)}, invoker, "GetUser", nil)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(string(content), `{"record":{"seq":3`))
+}
+
+func TestFilterSecret(t *testing.T) {
+	t.Setenv("AUDIT_TEST_SECRET", "s3cr3t")
+	path := filepath.Join(t.TempDir(), "audit.log")
+	invoker := newInvoker(map[string]string{
+		constant.AuditFileKey:      path,
+		constant.AuditKey:          "true",
+		constant.AuditSecretEnvKey: "AUDIT_TEST_SECRET",
+	})
+	invoke(&Filter{chains: make(map[string]*chain)}, invoker, "GetUser", nil)
+
+	_, err := VerifyFile(path, []byte("s3cr3t"))
+	assert.NoError(t, err)
+	_, err = VerifyFile(path, nil)
+	assert.Error(t, err)
+}
+
+func TestFilterSecretMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	f := &Filter{chains: make(map[string]*chain)}
+	_, err := f.getChain(newInvoker(map[string]string{
+		constant.AuditFileKey:      path,
+		constant.AuditSecretEnvKey: "AUDIT_TEST_SECRET",
+	}).GetURL())
+	require.NoError(t, err)
+
+	// a service sharing the sink with another secret would break the chain
+	_, err = f.getChain(newInvoker(map[string]string{
+		constant.AuditFileKey: path,
+	}).GetURL())
+	assert.Error(t, err)
+}
+
+func TestNewFileSink(t *testing.T) {
+	_, err := newFileSink(common.NewURLWithOptions())
+	assert.Error(t, err)
+}
+
+func TestLastLine(t *testing.T) {
+	long := strings.Repeat("x", chunkSize*2+10)
+	for _, tt := range []struct {
+		content string
+		want    string
+	}{
+		{"", ""},
+		{"\n", ""},
+		{"a", "a"},
+		{"a\nb\n", "b"},
+		{"a\n" + long + "\n\n", long},
+	} {
+		line, err := lastLine(strings.NewReader(tt.content), int64(len(tt.content)))
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, string(line))
+	}
+}
+
+// authInvoker authenticates the callers as the auth filter does, rejecting the ones without an access key
+type authInvoker struct {
+	base.Invoker
+}
+
+func (i *authInvoker) Invoke(_ context.Context, inv base.Invocation) result.Result {
+	ak := inv.GetAttachmentWithDefaultValue(constant.AKKey, "")
+	if ak == "" {
+		return &result.RPCResult{Err: triple_protocol.NewError(triple_protocol.CodeUnauthenticated, errors.New("no access key"))}
+	}
+	inv.SetAttribute(constant.PrincipalKey, ak)
+	return &result.RPCResult{}
+}
+
+func TestFilterRecordPrincipal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	invoker := &authInvoker{Invoker: newInvoker(map[string]string{
+		constant.AuditFileKey: path,
+		constant.AuditKey:     "true",
+	})}
+	f := &Filter{chains: make(map[string]*chain)}
+	for _, ak := range []string{"ak-admin", ""} {
+		inv := invocation.NewRPCInvocation("GetUser", nil, map[string]any{constant.AKKey: ak})
+		f.OnResponse(context.Background(), f.Invoke(context.Background(), invoker, inv), invoker, inv)
+	}
+
+	records := readRecords(t, path)
+	require.Len(t, records, 2)
+	// the principal is the one authenticated by the filters after the audit filter
+	assert.Equal(t, "ak-admin", records[0].Principal)
+	assert.Equal(t, statusOK, records[0].Status)
+	// and the rejected calls are recorded without principal
+	assert.Empty(t, records[1].Principal)
+	assert.Equal(t, triple_protocol.CodeUnauthenticated.String(), records[1].Code)
+}
+
+func TestFilterRecordUnserializable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	invoker := newInvoker(map[string]string{
+		constant.AuditFileKey:   path,
+		constant.AuditKey:       "true",
+		constant.AuditFieldsKey: "0.id,0.meta,1",
+	})
+	// the maps decoded by hessian are keyed by any
+	u := map[any]any{"id": "u-1", "meta": map[any]any{1: "one", "tags": []any{map[any]any{true: "yes"}}}}
+	invoke(&Filter{chains: make(map[string]*chain)}, invoker, "UpdateUser", nil, u, func() {})
+
+	records := readRecords(t, path)
+	require.Len(t, records, 1)
+	assert.Equal(t, map[string]any{
+		"0.id":   "u-1",
+		"0.meta": map[string]any{"1": "one", "tags": []any{map[string]any{"true": "yes"}}},
+		"1":      "<unserializable func()>",
+	}, records[0].Arguments)
+}
@@ -0,0 +1,263 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/filter"
+)
+
+// GenesisHash is the previous hash of the first record of a chain
+var GenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// Record is an audit record
+type Record struct {
+	Seq         uint64         `json:"seq"`
+	Time        time.Time      `json:"time"`
+	Principal   string         `json:"principal,omitempty"`
+	Remote      string         `json:"remote,omitempty"`
+	Application string         `json:"application,omitempty"`
+	Service     string         `json:"service"`
+	Group       string         `json:"group,omitempty"`
+	Version     string         `json:"version,omitempty"`
+	Method      string         `json:"method"`
+	Arguments   map[string]any `json:"arguments,omitempty"`
+	Status      string         `json:"status"`
+	Code        string         `json:"code,omitempty"`
+	PrevHash    string         `json:"prev_hash"`
+}
+
+// entry is a line of the audit log. The hash is computed over the exact bytes of the record,
+// which are kept as they are by json.RawMessage.
+type entry struct {
+	Record json.RawMessage `json:"record"`
+	Hash   string          `json:"hash"`
+}
+
+// decodeEntry decodes a line of the audit log
+func decodeEntry(line []byte) (*entry, *Record, error) {
+	e := &entry{}
+	if err := json.Unmarshal(line, e); err != nil {
+		return nil, nil, err
+	}
+	r := &Record{}
+	if err := json.Unmarshal(e.Record, r); err != nil {
+		return nil, nil, err
+	}
+	return e, r, nil
+}
+
+// sum returns the hash of the record, which is a HMAC-SHA256 if there is a secret
+func sum(secret, record []byte) string {
+	if len(secret) > 0 {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(record)
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+	digest := sha256.Sum256(record)
+	return hex.EncodeToString(digest[:])
+}
+
+// chain appends the records to a sink, each of them linked to the previous one
+type chain struct {
+	mu     sync.Mutex
+	sink   filter.AuditSink
+	secret []byte
+	// secretEnv is the environment variable the secret is read from
+	secretEnv string
+
+	loaded bool
+	seq    uint64
+	hash   string
+}
+
+func (c *chain) append(r *Record) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.loaded {
+		if err := c.load(); err != nil {
+			return err
+		}
+	}
+	r.Seq = c.seq + 1
+	r.PrevHash = c.hash
+	record, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	hash := sum(c.secret, record)
+	line, err := json.Marshal(&entry{Record: record, Hash: hash})
+	if err != nil {
+		return err
+	}
+	if err = c.sink.Write(line); err != nil {
+		return err
+	}
+	c.seq, c.hash = r.Seq, hash
+	return nil
+}
+
+// load continues the chain from the last record of the sink. The sink whose last record is broken
+// is not written any more, so that the log can be inspected before the chain is continued.
+func (c *chain) load() error {
+	last, err := c.sink.Last()
+	if err != nil {
+		return err
+	}
+	c.seq, c.hash = 0, GenesisHash
+	if len(last) > 0 {
+		e, r, err := decodeEntry(last)
+		if err != nil {
+			return fmt.Errorf("the last audit record is broken, %w", err)
+		}
+		c.seq, c.hash = r.Seq, e.Hash
+	}
+	c.loaded = true
+	return nil
+}
+
+// selectFields returns the values of the argument fields selected by the paths
+func selectFields(arguments []any, paths []string) map[string]any {
+	values := make(map[string]any, len(paths))
+	for _, path := range paths {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		if value, ok := lookup(arguments, path); ok {
+			values[path] = jsonSafe(value)
+		}
+	}
+	return values
+}
+
+// jsonSafe returns value if it can be marshaled to json. Otherwise the maps nested in value, like the
+// ones keyed by any decoded by hessian, are converted to maps keyed by the strings of their keys, and
+// the value still failing is replaced by a placeholder naming its type, so that the record is kept.
+func jsonSafe(value any) any {
+	if _, err := json.Marshal(value); err == nil {
+		return value
+	}
+	converted := convertMaps(reflect.ValueOf(value))
+	if _, err := json.Marshal(converted); err == nil {
+		return converted
+	}
+	return fmt.Sprintf("<unserializable %T>", value)
+}
+
+// convertMaps converts the maps in value to map[string]any, and the slices holding them to []any
+func convertMaps(value reflect.Value) any {
+	if value = indirect(value); !value.IsValid() || !value.CanInterface() {
+		return nil
+	}
+	switch value.Kind() {
+	case reflect.Map:
+		converted := make(map[string]any, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			converted[fmt.Sprint(iter.Key().Interface())] = convertMaps(iter.Value())
+		}
+		return converted
+	case reflect.Slice, reflect.Array:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			return value.Interface()
+		}
+		converted := make([]any, value.Len())
+		for i := range converted {
+			converted[i] = convertMaps(value.Index(i))
+		}
+		return converted
+	}
+	return value.Interface()
+}
+
+// lookup finds the value of path, which is the index of the argument followed by the names of the fields
+func lookup(arguments []any, path string) (any, bool) {
+	names := strings.Split(path, ".")
+	index, err := strconv.Atoi(names[0])
+	if err != nil || index < 0 || index >= len(arguments) {
+		return nil, false
+	}
+	value := reflect.ValueOf(arguments[index])
+	for _, name := range names[1:] {
+		if value = field(indirect(value), name); !value.IsValid() {
+			return nil, false
+		}
+	}
+	if value = indirect(value); !value.IsValid() {
+		return nil, true
+	}
+	if !value.CanInterface() {
+		return nil, false
+	}
+	return value.Interface(), true
+}
+
+func indirect(value reflect.Value) reflect.Value {
+	for value.IsValid() && (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+// field finds the field of a struct by its name or json name, the value of a map by its key,
+// or the element of a slice by its index, case-insensitively
+func field(value reflect.Value, name string) reflect.Value {
+	switch value.Kind() {
+	case reflect.Struct:
+		typ := value.Type()
+		for i := 0; i < typ.NumField(); i++ {
+			f := typ.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			jsonName, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if strings.EqualFold(f.Name, name) || (jsonName != "" && strings.EqualFold(jsonName, name)) {
+				return value.Field(i)
+			}
+		}
+	case reflect.Map:
+		// the maps decoded by hessian are keyed by any
+		iter := value.MapRange()
+		for iter.Next() {
+			if key := indirect(iter.Key()); key.Kind() == reflect.String && strings.EqualFold(key.String(), name) {
+				return iter.Value()
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if i, err := strconv.Atoi(name); err == nil && i >= 0 && i < value.Len() {
+			return value.Index(i)
+		}
+	}
+	return reflect.Value{}
+}
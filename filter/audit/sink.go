@@ -0,0 +1,126 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+)
+
+const (
+	// FileSinkKey is the name of the sink appending the records to the file
+	FileSinkKey = "file"
+	// FileMode is the file permission of the audit log files
+	FileMode = 0o600
+
+	// chunkSize is the size of the chunks the last record is read backwards by
+	chunkSize = 4096
+)
+
+func init() {
+	extension.SetAuditSink(FileSinkKey, newFileSink)
+}
+
+// fileSink appends the records to the file line by line. The file is never rotated, since the chain
+// is verified per file, and every record is synced to the disk before the next one is written.
+type fileSink struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+func newFileSink(url *common.URL) (filter.AuditSink, error) {
+	path := url.GetParam(constant.AuditFileKey, "")
+	if len(path) == 0 {
+		return nil, fmt.Errorf("the path of the audit log file is required, but %s is not set", constant.AuditFileKey)
+	}
+	return &fileSink{path: path}, nil
+}
+
+func (s *fileSink) Write(record []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, FileMode)
+		if err != nil {
+			return err
+		}
+		s.file = file
+	}
+	if _, err := s.file.Write(append(record, '\n')); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+func (s *fileSink) Last() ([]byte, error) {
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return lastLine(file, info.Size())
+}
+
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// lastLine reads the last non-empty line of r backwards chunk by chunk
+func lastLine(r io.ReaderAt, size int64) ([]byte, error) {
+	var tail []byte
+	for end := size; end > 0; {
+		start := max(end-chunkSize, 0)
+		chunk := make([]byte, end-start)
+		if _, err := r.ReadAt(chunk, start); err != nil && err != io.EOF {
+			return nil, err
+		}
+		tail = append(chunk, tail...)
+		trimmed := bytes.TrimRight(tail, "\n")
+		if i := bytes.LastIndexByte(trimmed, '\n'); i >= 0 {
+			return trimmed[i+1:], nil
+		}
+		end = start
+	}
+	return bytes.TrimRight(tail, "\n"), nil
+}
@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"fmt"
+	"io"
+	"os"
+)
+
+// VerifyResult is the summary of a verified audit log
+type VerifyResult struct {
+	// Records is the number of the records
+	Records int
+	// LastSeq is the sequence of the last record
+	LastSeq uint64
+	// LastHash is the hash of the last record, which is compared with the one kept elsewhere
+	// to tell whether the latest records are removed
+	LastHash string
+}
+
+// VerifyError tells the first record of the audit log breaking the chain
+type VerifyError struct {
+	// Line is the line number of the record, starting from 1
+	Line   int
+	Reason string
+}
+
+func (e *VerifyError) Error() string {
+	return fmt.Sprintf("audit log is broken at line %d: %s", e.Line, e.Reason)
+}
+
+// Verify checks the chain of the audit log read from r with the secret the records are written with,
+// which is nil for the logs without a secret. It returns a *VerifyError if the log has been tampered with.
+func Verify(r io.Reader, secret []byte) (*VerifyResult, error) {
+	result := &VerifyResult{LastHash: GenesisHash}
+	reader := bufio.NewReader(r)
+	for lineNo := 1; ; lineNo++ {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if line = bytes.TrimRight(line, "\n"); len(line) > 0 {
+			if reason := verifyLine(line, secret, result); reason != "" {
+				return result, &VerifyError{Line: lineNo, Reason: reason}
+			}
+		}
+		if err == io.EOF {
+			return result, nil
+		}
+	}
+}
+
+// VerifyFile checks the chain of the audit log file, see Verify
+func VerifyFile(path string, secret []byte) (*VerifyResult, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Verify(file, secret)
+}
+
+// verifyLine checks a record against the previous one, and returns the reason if it is broken
+func verifyLine(line, secret []byte, result *VerifyResult) string {
+	e, r, err := decodeEntry(line)
+	if err != nil {
+		return "malformed record: " + err.Error()
+	}
+	if !hmac.Equal([]byte(sum(secret, e.Record)), []byte(e.Hash)) {
+		return "the hash does not match the record"
+	}
+	if r.Seq != result.LastSeq+1 {
+		return fmt.Sprintf("the sequence %d does not follow %d", r.Seq, result.LastSeq)
+	}
+	if r.PrevHash != result.LastHash {
+		return "the previous hash does not match the previous record"
+	}
+	result.Records++
+	result.LastSeq = r.Seq
+	result.LastHash = e.Hash
+	return ""
+}
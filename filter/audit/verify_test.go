@@ -0,0 +1,134 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySink keeps the records in memory
+type memorySink struct {
+	lines [][]byte
+}
+
+func (s *memorySink) Write(record []byte) error {
+	s.lines = append(s.lines, record)
+	return nil
+}
+
+func (s *memorySink) Last() ([]byte, error) {
+	if len(s.lines) == 0 {
+		return nil, nil
+	}
+	return s.lines[len(s.lines)-1], nil
+}
+
+func (s *memorySink) Close() error {
+	return nil
+}
+
+func (s *memorySink) String() string {
+	return string(bytes.Join(s.lines, []byte("\n"))) + "\n"
+}
+
+func newLog(t *testing.T, n int) *memorySink {
+	sink := &memorySink{}
+	c := &chain{sink: sink}
+	for i := 0; i < n; i++ {
+		require.NoError(t, c.append(&Record{Time: time.Unix(int64(i), 0).UTC(), Service: "UserProvider", Method: "UpdateUser", Status: statusOK}))
+	}
+	return sink
+}
+
+func TestVerify(t *testing.T) {
+	sink := newLog(t, 3)
+	res, err := Verify(strings.NewReader(sink.String()), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, res.Records)
+	assert.Equal(t, uint64(3), res.LastSeq)
+	e, _, _ := decodeEntry(sink.lines[2])
+	assert.Equal(t, e.Hash, res.LastHash)
+
+	res, err = Verify(strings.NewReader(""), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, res.Records)
+}
+
+func TestVerifyTampered(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(lines []string) []string
+		line   int
+	}{
+		{
+			name: "modified",
+			tamper: func(lines []string) []string {
+				lines[1] = strings.Replace(lines[1], "UpdateUser", "GetUser", 1)
+				return lines
+			},
+			line: 2,
+		},
+		{
+			name: "removed",
+			tamper: func(lines []string) []string {
+				return append(lines[:1], lines[2:]...)
+			},
+			line: 2,
+		},
+		{
+			name: "reordered",
+			tamper: func(lines []string) []string {
+				lines[1], lines[2] = lines[2], lines[1]
+				return lines
+			},
+			line: 2,
+		},
+		{
+			name: "head removed",
+			tamper: func(lines []string) []string {
+				return lines[1:]
+			},
+			line: 1,
+		},
+		{
+			name: "malformed",
+			tamper: func(lines []string) []string {
+				lines[2] = lines[2][:10]
+				return lines
+			},
+			line: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := strings.Split(strings.TrimSpace(newLog(t, 3).String()), "\n")
+			_, err := Verify(strings.NewReader(strings.Join(tt.tamper(lines), "\n")), nil)
+			var verifyErr *VerifyError
+			require.ErrorAs(t, err, &verifyErr)
+			assert.Equal(t, tt.line, verifyErr.Line)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+)
+
+// AuditSink is where the audit filter appends the hash-chained audit records to.
+//
+// please register your implementation by invoking SetAuditSink
+type AuditSink interface {
+	// Write appends an encoded audit record, which doesn't end with a line break.
+	// It is invoked by one goroutine at a time.
+	Write(record []byte) error
+	// Last returns the last record written, even by a previous process, so that the chain continues
+	// after restarts. It returns nil if nothing has been written.
+	Last() ([]byte, error)
+	// Close releases the resources of the sink.
+	Close() error
+}
+
+// AuditSinkCreator creates the AuditSink configured by the url. The audit filter creates one sink,
+// and so one chain, for each pair of the sink name and the "audit.file" configuration.
+type AuditSinkCreator func(url *common.URL) (AuditSink, error)
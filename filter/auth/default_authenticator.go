@@ -119,6 +119,8 @@ func (authenticator *defaultAuthenticator) Authenticate(inv base.Invocation, url
 	if success := computeSignature == originSignature; !success {
 		return errors.New("failed to authenticate, signature is not correct")
 	}
+	// the access key is the principal of the request, which is recorded by the audit filter
+	inv.SetAttribute(constant.PrincipalKey, accessKeyId)
 	return nil
 }
 
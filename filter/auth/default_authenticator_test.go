@@ -60,6 +60,8 @@ func TestDefaultAuthenticator_Authenticate(t *testing.T) {
 	})
 	err := authenticator.Authenticate(rpcInvocation, testUrl)
 	assert.Nil(t, err)
+	principal, _ := rpcInvocation.GetAttribute(constant.PrincipalKey)
+	assert.Equal(t, access, principal)
 	// modify the params
 	rpcInvocation = invocation.NewRPCInvocation("test", params[:1], map[string]any{
 		constant.RequestSignatureKey: signature,
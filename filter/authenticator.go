@@ -29,6 +29,7 @@ type Authenticator interface {
 	// Sign adds signature to the invocation
 	Sign(base.Invocation, *common.URL) error
 
-	// Authenticate verifies the signature of the request
+	// Authenticate verifies the signature of the request, and sets the authenticated principal
+	// as the attribute constant.PrincipalKey of the invocation
 	Authenticate(base.Invocation, *common.URL) error
 }
@@ -0,0 +1,204 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package circuitbreaker
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/metrics/util/aggregate"
+)
+
+// State is the state of a circuit breaker
+type State int32
+
+const (
+	// StateClosed permits all the invocations, and counts their results
+	StateClosed State = iota
+	// StateOpen rejects all the invocations until the open duration elapses
+	StateOpen
+	// StateHalfOpen permits a few invocations to probe whether the provider has recovered
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// breaker is the circuit breaker of a method of an invoker.
+//
+// It is concurrent-safe.
+// The invocations are counted in sliding windows while it is closed, and it trips when the rule is met.
+// After the open duration, it permits HalfOpenRequests invocations, and closes if all of them succeed,
+// or opens again if any of them fails.
+type breaker struct {
+	rule          *Rule
+	fromURL       bool
+	now           func() time.Time
+	onStateChange func(from, to State)
+	// lastUsed is the unix nano time of the last invocation, the idle breakers are removed by the filter
+	lastUsed atomic.Int64
+
+	mu    sync.Mutex
+	state State
+	// generation is increased on every transition, so the results of the invocations permitted
+	// in the earlier states are ignored
+	generation          uint64
+	calls               *aggregate.TimeWindowCounter
+	failures            *aggregate.TimeWindowCounter
+	slowCalls           *aggregate.TimeWindowCounter
+	consecutiveFailures int
+	openedAt            time.Time
+	probes              int
+	probeSuccesses      int
+}
+
+func newBreaker(rule *Rule, now func() time.Time, onStateChange func(from, to State)) *breaker {
+	b := &breaker{
+		rule:          rule,
+		now:           now,
+		onStateChange: onStateChange,
+	}
+	b.resetCounters()
+	return b
+}
+
+func (b *breaker) resetCounters() {
+	b.calls = aggregate.NewTimeWindowCounterWithClock(b.rule.Buckets, b.rule.Window, b.now)
+	b.failures = aggregate.NewTimeWindowCounterWithClock(b.rule.Buckets, b.rule.Window, b.now)
+	b.slowCalls = aggregate.NewTimeWindowCounterWithClock(b.rule.Buckets, b.rule.Window, b.now)
+	b.consecutiveFailures = 0
+}
+
+// appliesTo reports whether the breaker is created for the rule of the config center, or for the rule
+// of the url if rule is nil
+func (b *breaker) appliesTo(rule *Rule) bool {
+	return b.rule == rule || (rule == nil && b.fromURL)
+}
+
+// currentState returns the state of the breaker
+func (b *breaker) currentState() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// allow reports whether the invocation is permitted, and returns the generation of the state permitting it
+func (b *breaker) allow() (uint64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if b.now().Sub(b.openedAt) < b.rule.OpenDuration {
+			return 0, false
+		}
+		b.transition(StateHalfOpen)
+		fallthrough
+	case StateHalfOpen:
+		if b.probes >= b.rule.HalfOpenRequests {
+			return 0, false
+		}
+		b.probes++
+	}
+	return b.generation, true
+}
+
+// onResult records the result of an invocation permitted in the generation
+func (b *breaker) onResult(generation uint64, failed bool, elapsed time.Duration) {
+	slow := b.rule.SlowCallDuration > 0 && elapsed >= b.rule.SlowCallDuration
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if generation != b.generation {
+		return
+	}
+
+	switch b.state {
+	case StateHalfOpen:
+		if failed || slow {
+			b.transition(StateOpen)
+			return
+		}
+		b.probeSuccesses++
+		if b.probeSuccesses >= b.rule.HalfOpenRequests {
+			b.transition(StateClosed)
+		}
+	case StateClosed:
+		b.calls.Inc()
+		if failed {
+			b.failures.Inc()
+			b.consecutiveFailures++
+		} else {
+			b.consecutiveFailures = 0
+		}
+		if slow {
+			b.slowCalls.Inc()
+		}
+		// the ratios only grow by failed or slow invocations
+		if (failed || slow) && b.shouldTrip() {
+			b.transition(StateOpen)
+		}
+	}
+}
+
+func (b *breaker) shouldTrip() bool {
+	r := b.rule
+	if r.ConsecutiveFailures > 0 && b.consecutiveFailures >= r.ConsecutiveFailures {
+		return true
+	}
+	calls := b.calls.Count()
+	if calls < float64(r.MinRequests) {
+		return false
+	}
+	if r.ErrorRatio > 0 && b.failures.Count()/calls >= r.ErrorRatio {
+		return true
+	}
+	return r.SlowCallRatio > 0 && b.slowCalls.Count()/calls >= r.SlowCallRatio
+}
+
+// transition changes the state, it must be invoked with the lock held
+func (b *breaker) transition(to State) {
+	from := b.state
+	b.state = to
+	b.generation++
+	switch to {
+	case StateOpen:
+		b.openedAt = b.now()
+	case StateHalfOpen:
+		b.probes = 0
+		b.probeSuccesses = 0
+	case StateClosed:
+		b.resetCounters()
+	}
+	if b.onStateChange != nil {
+		b.onStateChange(from, to)
+	}
+}
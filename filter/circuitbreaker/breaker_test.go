@@ -0,0 +1,208 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package circuitbreaker
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	current time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.current
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.current = c.current.Add(d)
+}
+
+type transitionRecorder struct {
+	transitions []State
+}
+
+func (r *transitionRecorder) record(_, to State) {
+	r.transitions = append(r.transitions, to)
+}
+
+func newTestBreaker(rule *Rule) (*breaker, *fakeClock, *transitionRecorder) {
+	rule.setDefaults()
+	clock := &fakeClock{current: time.Unix(1000, 0)}
+	recorder := &transitionRecorder{}
+	return newBreaker(rule, clock.now, recorder.record), clock, recorder
+}
+
+func call(b *breaker, failed bool, elapsed time.Duration) bool {
+	generation, ok := b.allow()
+	if ok {
+		b.onResult(generation, failed, elapsed)
+	}
+	return ok
+}
+
+func TestBreakerErrorRatio(t *testing.T) {
+	b, _, recorder := newTestBreaker(&Rule{ErrorRatio: 0.5, MinRequests: 10})
+
+	for i := 0; i < 4; i++ {
+		call(b, false, 0)
+		call(b, true, 0)
+	}
+	// the ratios are not evaluated until there are enough invocations
+	assert.Equal(t, StateClosed, b.currentState())
+
+	call(b, false, 0)
+	call(b, true, 0)
+	assert.Equal(t, StateOpen, b.currentState())
+	assert.Equal(t, []State{StateOpen}, recorder.transitions)
+	assert.False(t, call(b, false, 0))
+}
+
+func TestBreakerWindowSlidesByClock(t *testing.T) {
+	b, clock, _ := newTestBreaker(&Rule{ErrorRatio: 0.5, MinRequests: 4, Window: 10 * time.Second})
+
+	call(b, true, 0)
+	call(b, true, 0)
+	call(b, true, 0)
+	// the failures slide out of the window by the injected clock
+	clock.advance(11 * time.Second)
+	call(b, false, 0)
+	call(b, false, 0)
+	call(b, false, 0)
+	call(b, true, 0)
+	assert.Equal(t, StateClosed, b.currentState())
+}
+
+func TestBreakerBucketsBoundedByWindow(t *testing.T) {
+	// a bucket of the window spans a millisecond at least
+	b, clock, _ := newTestBreaker(&Rule{ErrorRatio: 0.5, MinRequests: 2, Window: time.Second, Buckets: 2000})
+	assert.Equal(t, 1000, b.rule.Buckets)
+
+	call(b, true, 0)
+	clock.advance(1500 * time.Millisecond)
+	call(b, true, 0)
+	call(b, false, 0)
+	call(b, false, 0)
+	assert.Equal(t, StateClosed, b.currentState())
+}
+
+// the window isn't truncated to seconds
+func TestBreakerWindowInMilliseconds(t *testing.T) {
+	b, clock, _ := newTestBreaker(&Rule{ErrorRatio: 0.5, MinRequests: 2, Window: 1500 * time.Millisecond})
+
+	call(b, true, 0)
+	clock.advance(1200 * time.Millisecond)
+	call(b, true, 0)
+	assert.Equal(t, StateOpen, b.currentState())
+}
+
+func TestBreakerSlowCallRatio(t *testing.T) {
+	b, _, _ := newTestBreaker(&Rule{SlowCallDuration: time.Second, SlowCallRatio: 0.3, MinRequests: 10})
+
+	for i := 0; i < 7; i++ {
+		call(b, false, time.Millisecond)
+	}
+	call(b, false, 2*time.Second)
+	call(b, false, 2*time.Second)
+	assert.Equal(t, StateClosed, b.currentState())
+	call(b, false, time.Second)
+	assert.Equal(t, StateOpen, b.currentState())
+}
+
+func TestBreakerConsecutiveFailures(t *testing.T) {
+	b, _, _ := newTestBreaker(&Rule{ConsecutiveFailures: 3})
+
+	call(b, true, 0)
+	call(b, true, 0)
+	call(b, false, 0)
+	call(b, true, 0)
+	call(b, true, 0)
+	assert.Equal(t, StateClosed, b.currentState())
+	call(b, true, 0)
+	assert.Equal(t, StateOpen, b.currentState())
+}
+
+func TestBreakerHalfOpen(t *testing.T) {
+	b, clock, recorder := newTestBreaker(&Rule{ConsecutiveFailures: 1, OpenDuration: 5 * time.Second, HalfOpenRequests: 2})
+
+	call(b, true, 0)
+	assert.False(t, call(b, false, 0))
+	clock.advance(5 * time.Second)
+
+	// only 2 probes are permitted
+	g1, ok := b.allow()
+	assert.True(t, ok)
+	assert.Equal(t, StateHalfOpen, b.currentState())
+	g2, ok := b.allow()
+	assert.True(t, ok)
+	_, ok = b.allow()
+	assert.False(t, ok)
+
+	b.onResult(g1, false, 0)
+	assert.Equal(t, StateHalfOpen, b.currentState())
+	b.onResult(g2, false, 0)
+	assert.Equal(t, StateClosed, b.currentState())
+	assert.Equal(t, []State{StateOpen, StateHalfOpen, StateClosed}, recorder.transitions)
+
+	// the counters are reset after closing
+	assert.True(t, call(b, false, 0))
+	assert.Equal(t, StateClosed, b.currentState())
+}
+
+func TestBreakerHalfOpenFailure(t *testing.T) {
+	b, clock, recorder := newTestBreaker(&Rule{ConsecutiveFailures: 1, OpenDuration: 5 * time.Second})
+
+	call(b, true, 0)
+	clock.advance(5 * time.Second)
+	assert.True(t, call(b, true, 0))
+	assert.Equal(t, StateOpen, b.currentState())
+	assert.Equal(t, []State{StateOpen, StateHalfOpen, StateOpen}, recorder.transitions)
+
+	// it waits for another open duration
+	clock.advance(4 * time.Second)
+	assert.False(t, call(b, false, 0))
+}
+
+func TestBreakerIgnoresStaleResults(t *testing.T) {
+	b, clock, _ := newTestBreaker(&Rule{ConsecutiveFailures: 1, HalfOpenRequests: 1})
+
+	stale, ok := b.allow()
+	assert.True(t, ok)
+	call(b, true, 0)
+	clock.advance(time.Minute)
+	probe, ok := b.allow()
+	assert.True(t, ok)
+
+	// the result of the invocation permitted while closed doesn't affect the half-open breaker
+	b.onResult(stale, true, 0)
+	assert.Equal(t, StateHalfOpen, b.currentState())
+	b.onResult(probe, false, 0)
+	assert.Equal(t, StateClosed, b.currentState())
+}
+
+func TestStateString(t *testing.T) {
+	assert.Equal(t, "closed", StateClosed.String())
+	assert.Equal(t, "open", StateOpen.String())
+	assert.Equal(t, "half-open", StateHalfOpen.String())
+	assert.Equal(t, "unknown", State(10).String())
+}
@@ -0,0 +1,202 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package circuitbreaker provides a consumer filter breaking the circuit of the failing providers.
+/*
+ Each method of each provider has its own breaker, which is closed, open or half-open. The closed breaker counts
+ the invocations in a sliding window, and opens when the ratio of the failed or slow invocations, or the number
+ of the consecutive failures reaches the threshold. The open breaker rejects the invocations with the unavailable
+ error, so the cluster retries them on the other providers. After the open duration, the breaker is half-open and
+ permits a few invocations to probe the provider, it closes if all of them succeed. The breakers without
+ invocations for 10 minutes, like the ones of the providers gone offline, are removed.
+
+ The rules are configured by the parameters of the reference, which can be method-level too:
+
+ references:
+   UserProvider:
+     interface: com.ikurento.user.UserProvider
+     filter: circuit_breaker
+     params:
+       circuit-breaker.error-ratio: 0.5         # 0.5 by default, 0 disables it
+       circuit-breaker.slow-call-duration: 1s
+       circuit-breaker.slow-call-ratio: 0.8     # 0 by default, which disables it
+       circuit-breaker.consecutive-failures: 10 # 0 by default, which disables it
+       circuit-breaker.min-requests: 20
+       circuit-breaker.window: 10s
+       circuit-breaker.open-duration: 5s
+       circuit-breaker.half-open-requests: 3
+
+ The rules read from the config center with the key "{application}.circuit-breaker" take precedence,
+ and take effect as soon as they are changed, for example:
+
+ enabled: true
+ rules:
+   - service: "com.ikurento.user.UserProvider"
+     methods: ["GetUser"]
+     error-ratio: 0.3
+     min-requests: 50
+     open-duration: 10s
+
+ The state transitions are published to the metrics event bus as StateChangeEvent.
+*/
+package circuitbreaker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/config_center/rule"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+)
+
+const (
+	errBreakerOpen = "circuit breaker of %s#%s on %s is open"
+
+	// breakerIdleTimeout is how long a breaker is kept without invocations, so that the breakers of
+	// the departed providers are removed. The idle breakers are swept at most once in the timeout.
+	breakerIdleTimeout = 10 * time.Minute
+)
+
+var (
+	once          sync.Once
+	breakerFilter *Filter
+	rules         = rule.NewWatcher("circuit breaker rules", constant.CircuitBreakerRuleSuffix, parseRuleSet)
+)
+
+func init() {
+	extension.SetFilter(constant.CircuitBreakerFilterKey, newFilter)
+}
+
+// Filter rejects the invocations to the providers whose circuit breakers are open
+type Filter struct {
+	breakers sync.Map // address/service key#method -> *breaker
+	now      func() time.Time
+	// lastSweep is the unix nano time of the last sweep of the idle breakers
+	lastSweep atomic.Int64
+}
+
+func newFilter() filter.Filter {
+	once.Do(func() {
+		breakerFilter = &Filter{now: time.Now}
+	})
+	return breakerFilter
+}
+
+// Invoke rejects the invocation if the breaker is open, or records the result of the invocation otherwise
+func (f *Filter) Invoke(ctx context.Context, invoker base.Invoker, invocation base.Invocation) result.Result {
+	url := invoker.GetURL()
+	method := invocation.MethodName()
+	b := f.getBreaker(url, method)
+	if b == nil {
+		return invoker.Invoke(ctx, invocation)
+	}
+
+	generation, ok := b.allow()
+	if !ok {
+		reportRejected(url, method)
+		return &result.RPCResult{Err: triple_protocol.NewError(triple_protocol.CodeUnavailable,
+			fmt.Errorf(errBreakerOpen, url.Service(), method, url.Location))}
+	}
+	start := f.now()
+	res := invoker.Invoke(ctx, invocation)
+	b.onResult(generation, res.Error() != nil, f.now().Sub(start))
+	return res
+}
+
+// OnResponse does nothing
+func (f *Filter) OnResponse(_ context.Context, res result.Result, _ base.Invoker, _ base.Invocation) result.Result {
+	return res
+}
+
+// getBreaker returns the breaker of the method of the invoker, which is replaced when its rule changes.
+// It returns nil if the circuit breaker is disabled by the config center.
+func (f *Filter) getBreaker(url *common.URL, method string) *breaker {
+	var rule *Rule
+	if rs := rules.Get(url.GetParam(constant.ApplicationKey, "")); rs != nil {
+		if !rs.enabled() {
+			return nil
+		}
+		rule = rs.match(url.Service(), method)
+	}
+
+	now := f.now()
+	f.sweepIdle(now)
+	key := url.Location + "/" + url.ServiceKey() + "#" + method
+	for {
+		current, loaded := f.breakers.Load(key)
+		if loaded && current.(*breaker).appliesTo(rule) {
+			current.(*breaker).lastUsed.Store(now.UnixNano())
+			return current.(*breaker)
+		}
+		b := f.newBreaker(url, method, rule)
+		b.lastUsed.Store(now.UnixNano())
+		if !loaded {
+			if _, raced := f.breakers.LoadOrStore(key, b); !raced {
+				return b
+			}
+		} else if f.breakers.CompareAndSwap(key, current, b) {
+			return b
+		}
+		// another invocation has replaced the breaker, which is checked again
+	}
+}
+
+// sweepIdle removes the breakers without invocations for breakerIdleTimeout, such as the ones of
+// the providers gone offline, once in the timeout.
+func (f *Filter) sweepIdle(now time.Time) {
+	last := f.lastSweep.Load()
+	if now.UnixNano()-last < int64(breakerIdleTimeout) || !f.lastSweep.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+	f.breakers.Range(func(key, value any) bool {
+		if now.UnixNano()-value.(*breaker).lastUsed.Load() >= int64(breakerIdleTimeout) {
+			f.breakers.CompareAndDelete(key, value)
+		}
+		return true
+	})
+}
+
+// newBreaker creates the breaker of the method for the rule, or for the rule of the url if rule is nil
+func (f *Filter) newBreaker(url *common.URL, method string, rule *Rule) *breaker {
+	fromURL := rule == nil
+	if fromURL {
+		rule = ruleFromURL(url, method)
+	}
+	b := newBreaker(rule, f.now, func(from, to State) {
+		logger.Infof("[Circuit Breaker] the circuit breaker of %s#%s on %s changes from %s to %s",
+			url.Service(), method, url.Location, from, to)
+		metrics.Publish(newStateChangeEvent(url, method, from, to))
+	})
+	b.fromURL = fromURL
+	return b
+}
@@ -0,0 +1,190 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+type testInvoker struct {
+	base.BaseInvoker
+	err   error
+	calls int
+}
+
+func (i *testInvoker) Invoke(context.Context, base.Invocation) result.Result {
+	i.calls++
+	return &result.RPCResult{Err: i.err}
+}
+
+func newTestInvoker(application, port string, params ...common.Option) *testInvoker {
+	opts := append([]common.Option{
+		common.WithIp("127.0.0.1"),
+		common.WithPort(port),
+		common.WithParamsValue(constant.ApplicationKey, application),
+		common.WithParamsValue(constant.InterfaceKey, "com.ikurento.user.UserProvider"),
+		common.WithPath("com.ikurento.user.UserProvider"),
+	}, params...)
+	return &testInvoker{BaseInvoker: *base.NewBaseInvoker(common.NewURLWithOptions(opts...))}
+}
+
+func TestFilterOpensAndRecovers(t *testing.T) {
+	clock := &fakeClock{current: time.Unix(1000, 0)}
+	f := &Filter{now: clock.now}
+	events := make(chan metrics.MetricsEvent, 10)
+	metrics.Subscribe(constant.MetricsCircuitBreaker, events)
+	defer metrics.Unsubscribe(constant.MetricsCircuitBreaker)
+
+	invoker := newTestInvoker(uuid.NewString(), "20000",
+		common.WithParamsValue(constant.CircuitBreakerConsecutiveFailuresKey, "2"),
+		common.WithParamsValue(constant.CircuitBreakerHalfOpenRequestsKey, "1"))
+	other := newTestInvoker(invoker.GetURL().GetParam(constant.ApplicationKey, ""), "20001")
+	inv := invocation.NewRPCInvocation("GetUser", nil, nil)
+
+	invoker.err = errors.New("connection refused")
+	f.Invoke(context.Background(), invoker, inv)
+	f.Invoke(context.Background(), invoker, inv)
+	assert.Equal(t, 2, invoker.calls)
+
+	res := f.Invoke(context.Background(), invoker, inv)
+	assert.Equal(t, 2, invoker.calls)
+	assert.Equal(t, triple_protocol.CodeUnavailable, triple_protocol.CodeOf(res.Error()))
+
+	// the breakers of the other providers and methods are not affected
+	assert.Nil(t, f.Invoke(context.Background(), other, inv).Error())
+	invoker.err = nil
+	assert.Nil(t, f.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("UpdateUser", nil, nil)).Error())
+
+	clock.advance(defaultOpenDuration)
+	assert.Nil(t, f.Invoke(context.Background(), invoker, inv).Error())
+	assert.Equal(t, 4, invoker.calls)
+
+	for _, want := range []State{StateOpen, StateHalfOpen, StateClosed} {
+		select {
+		case e := <-events:
+			event := e.(*StateChangeEvent)
+			assert.Equal(t, want, event.To)
+			assert.Equal(t, "com.ikurento.user.UserProvider", event.Interface)
+			assert.Equal(t, "GetUser", event.Method)
+			assert.Equal(t, "127.0.0.1:20000", event.Address)
+		default:
+			t.Fatalf("no event of %s", want)
+		}
+	}
+}
+
+func TestFilterConfigCenterRules(t *testing.T) {
+	clock := &fakeClock{current: time.Unix(1000, 0)}
+	f := &Filter{now: clock.now}
+	application := uuid.NewString()
+	invoker := newTestInvoker(application, "20000",
+		common.WithParamsValue(constant.CircuitBreakerConsecutiveFailuresKey, "1"))
+	invoker.err = errors.New("connection refused")
+	inv := invocation.NewRPCInvocation("GetUser", nil, nil)
+
+	rules.Process(&config_center.ConfigChangeEvent{Key: rules.Key(application), ConfigType: remoting.EventTypeAdd, Value: `
+rules:
+  - methods: ["GetUser"]
+    consecutive-failures: 3
+`})
+	defer rules.Delete(application)
+
+	// the rule in the config center takes precedence
+	for i := 0; i < 3; i++ {
+		f.Invoke(context.Background(), invoker, inv)
+	}
+	require.Equal(t, 3, invoker.calls)
+	f.Invoke(context.Background(), invoker, inv)
+	assert.Equal(t, 3, invoker.calls)
+
+	// the breakers are disabled
+	rules.Process(&config_center.ConfigChangeEvent{Key: rules.Key(application), ConfigType: remoting.EventTypeUpdate,
+		Value: "enabled: false"})
+	f.Invoke(context.Background(), invoker, inv)
+	assert.Equal(t, 4, invoker.calls)
+
+	// the rule in the url is used after the rules are removed
+	rules.Process(&config_center.ConfigChangeEvent{Key: rules.Key(application), ConfigType: remoting.EventTypeDel})
+	f.Invoke(context.Background(), invoker, inv)
+	f.Invoke(context.Background(), invoker, inv)
+	assert.Equal(t, 5, invoker.calls)
+}
+
+func TestGetBreakerConcurrently(t *testing.T) {
+	f := &Filter{now: time.Now}
+	url := newTestInvoker(uuid.NewString(), "20000").GetURL()
+	breakers := make([]*breaker, 16)
+	var wg sync.WaitGroup
+	for i := range breakers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			breakers[i] = f.getBreaker(url, "GetUser")
+		}(i)
+	}
+	wg.Wait()
+	for _, b := range breakers {
+		assert.Same(t, breakers[0], b)
+	}
+}
+
+func TestFilterRemovesIdleBreakers(t *testing.T) {
+	clock := &fakeClock{current: time.Unix(1000, 0)}
+	f := &Filter{now: clock.now}
+	application := uuid.NewString()
+	departed := newTestInvoker(application, "20000").GetURL()
+	active := newTestInvoker(application, "20001").GetURL()
+	idle := f.getBreaker(departed, "GetUser")
+	f.getBreaker(active, "GetUser")
+
+	clock.advance(breakerIdleTimeout / 2)
+	f.getBreaker(active, "GetUser")
+	clock.advance(breakerIdleTimeout / 2)
+	f.getBreaker(active, "GetUser")
+
+	count := 0
+	f.breakers.Range(func(_, _ any) bool {
+		count++
+		return true
+	})
+	assert.Equal(t, 1, count)
+	assert.NotSame(t, idle, f.getBreaker(departed, "GetUser"))
+}
@@ -0,0 +1,134 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package circuitbreaker
+
+import (
+	"sync/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+)
+
+var (
+	stateKey = metrics.NewMetricKey("dubbo_consumer_circuit_breaker_state",
+		"The State of Circuit Breaker, 0 is closed, 1 is open and 2 is half-open")
+	transitionsKey = metrics.NewMetricKey("dubbo_consumer_circuit_breaker_transitions_total",
+		"The State Transitions of Circuit Breaker")
+	rejectedKey = metrics.NewMetricKey("dubbo_consumer_circuit_breaker_rejected_total",
+		"The Requests Rejected by Circuit Breaker")
+
+	// rejectedCounter is set once the metrics module is initialized
+	rejectedCounter atomic.Pointer[metrics.CounterVec]
+)
+
+func init() {
+	metrics.AddCollector("circuit_breaker", func(registry metrics.MetricRegistry, _ *common.URL) {
+		c := &collector{
+			state:       metrics.NewGaugeVec(registry, stateKey),
+			transitions: metrics.NewCounterVec(registry, transitionsKey),
+		}
+		rejected := metrics.NewCounterVec(registry, rejectedKey)
+		rejectedCounter.Store(&rejected)
+		c.start()
+	})
+}
+
+// StateChangeEvent is published to the metrics event bus when a circuit breaker changes its state
+type StateChangeEvent struct {
+	Application string
+	Interface   string
+	Method      string
+	Group       string
+	Version     string
+	// Address is the address of the provider
+	Address string
+	From    State
+	To      State
+}
+
+// Type returns constant.MetricsCircuitBreaker
+func (*StateChangeEvent) Type() string {
+	return constant.MetricsCircuitBreaker
+}
+
+func newStateChangeEvent(url *common.URL, method string, from, to State) *StateChangeEvent {
+	return &StateChangeEvent{
+		Application: url.GetParam(constant.ApplicationKey, ""),
+		Interface:   url.Service(),
+		Method:      method,
+		Group:       url.Group(),
+		Version:     url.GetParam(constant.VersionKey, ""),
+		Address:     url.Location,
+		From:        from,
+		To:          to,
+	}
+}
+
+func (e *StateChangeEvent) labels() map[string]string {
+	return map[string]string{
+		constant.TagApplicationName: e.Application,
+		constant.TagInterface:       e.Interface,
+		constant.TagMethod:          e.Method,
+		constant.TagGroup:           e.Group,
+		constant.TagVersion:         e.Version,
+		constant.TagAddress:         e.Address,
+	}
+}
+
+type collector struct {
+	state       metrics.GaugeVec
+	transitions metrics.CounterVec
+}
+
+func (c *collector) start() {
+	ch := make(chan metrics.MetricsEvent, 64)
+	metrics.Subscribe(constant.MetricsCircuitBreaker, ch)
+	go func() {
+		for e := range ch {
+			if event, ok := e.(*StateChangeEvent); ok {
+				c.handleStateChange(event)
+			}
+		}
+	}()
+}
+
+func (c *collector) handleStateChange(event *StateChangeEvent) {
+	labels := event.labels()
+	c.state.Set(labels, float64(event.To))
+	labels[constant.TagState] = event.To.String()
+	c.transitions.Inc(labels)
+}
+
+// reportRejected counts an invocation rejected by the open circuit breaker
+func reportRejected(url *common.URL, method string) {
+	rejected := rejectedCounter.Load()
+	if rejected == nil {
+		return
+	}
+	(*rejected).Inc(map[string]string{
+		constant.TagApplicationName: url.GetParam(constant.ApplicationKey, ""),
+		constant.TagInterface:       url.Service(),
+		constant.TagMethod:          method,
+		constant.TagGroup:           url.Group(),
+		constant.TagVersion:         url.GetParam(constant.VersionKey, ""),
+		constant.TagAddress:         url.Location,
+	})
+}
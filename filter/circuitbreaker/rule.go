@@ -0,0 +1,177 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package circuitbreaker
+
+import (
+	"strconv"
+	"time"
+)
+
+import (
+	"gopkg.in/yaml.v2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+const (
+	defaultWindow           = 10 * time.Second
+	defaultBuckets          = 10
+	defaultMinRequests      = 20
+	defaultErrorRatio       = 0.5
+	defaultOpenDuration     = 5 * time.Second
+	defaultHalfOpenRequests = 3
+)
+
+// RuleSet is the circuit breaker configuration of an application, which is published
+// to the config center under the key "{application}.circuit-breaker".
+type RuleSet struct {
+	Enabled *bool   `yaml:"enabled"`
+	Rules   []*Rule `yaml:"rules"`
+}
+
+// Rule describes when the breakers of the matched methods trip. The conditions with zero values are disabled.
+type Rule struct {
+	// Service is the interface name, empty or "*" matches any service.
+	Service string `yaml:"service"`
+	// Methods are the method names, empty or "*" matches any method.
+	Methods []string `yaml:"methods"`
+
+	// Window is the length of the sliding window, in seconds at least. It is 10s by default.
+	Window time.Duration `yaml:"window"`
+	// Buckets is the number of the buckets of the sliding window, at most one per millisecond of the
+	// window. It is 10 by default.
+	Buckets int `yaml:"buckets"`
+	// MinRequests is the number of the invocations in the window required to evaluate the ratios.
+	// It is 20 by default.
+	MinRequests int `yaml:"min-requests"`
+	// ErrorRatio trips the breaker when the ratio of the failed invocations in the window reaches it.
+	ErrorRatio float64 `yaml:"error-ratio"`
+	// SlowCallDuration is the duration from which an invocation is slow.
+	SlowCallDuration time.Duration `yaml:"slow-call-duration"`
+	// SlowCallRatio trips the breaker when the ratio of the slow invocations in the window reaches it.
+	SlowCallRatio float64 `yaml:"slow-call-ratio"`
+	// ConsecutiveFailures trips the breaker when so many invocations fail in a row.
+	ConsecutiveFailures int `yaml:"consecutive-failures"`
+
+	// OpenDuration is how long the breaker rejects the invocations before probing. It is 5s by default.
+	OpenDuration time.Duration `yaml:"open-duration"`
+	// HalfOpenRequests is the number of the probing invocations, the breaker closes if all of them succeed.
+	// It is 3 by default.
+	HalfOpenRequests int `yaml:"half-open-requests"`
+}
+
+func parseRuleSet(content string) (*RuleSet, error) {
+	rs := &RuleSet{}
+	if err := yaml.Unmarshal([]byte(content), rs); err != nil {
+		return nil, err
+	}
+	for _, r := range rs.Rules {
+		r.setDefaults()
+	}
+	return rs, nil
+}
+
+func (rs *RuleSet) enabled() bool {
+	return rs != nil && (rs.Enabled == nil || *rs.Enabled)
+}
+
+// match returns the first rule matching the method of the service.
+func (rs *RuleSet) match(service, method string) *Rule {
+	for _, r := range rs.Rules {
+		if r.matches(service, method) {
+			return r
+		}
+	}
+	return nil
+}
+
+func (r *Rule) matches(service, method string) bool {
+	if r.Service != "" && r.Service != "*" && r.Service != service {
+		return false
+	}
+	if len(r.Methods) == 0 {
+		return true
+	}
+	for _, m := range r.Methods {
+		if m == "*" || m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) setDefaults() {
+	if r.Window < time.Second {
+		r.Window = defaultWindow
+	}
+	if r.Buckets <= 0 {
+		r.Buckets = defaultBuckets
+	}
+	// a bucket spans a millisecond at least
+	if maxBuckets := int(r.Window.Milliseconds()); r.Buckets > maxBuckets {
+		r.Buckets = maxBuckets
+	}
+	if r.MinRequests <= 0 {
+		r.MinRequests = defaultMinRequests
+	}
+	if r.OpenDuration <= 0 {
+		r.OpenDuration = defaultOpenDuration
+	}
+	if r.HalfOpenRequests <= 0 {
+		r.HalfOpenRequests = defaultHalfOpenRequests
+	}
+}
+
+// ruleFromURL reads the method-level or service-level rule from the url, the error ratio is 0.5 by default
+func ruleFromURL(url *common.URL, method string) *Rule {
+	param := func(key string) string {
+		return url.GetParam("methods."+method+"."+key, url.GetParam(key, ""))
+	}
+	duration := func(key string) time.Duration {
+		d, _ := time.ParseDuration(param(key))
+		return d
+	}
+	integer := func(key string) int {
+		i, _ := strconv.Atoi(param(key))
+		return i
+	}
+	ratio := func(key string, defaultValue float64) float64 {
+		f, err := strconv.ParseFloat(param(key), 64)
+		if err != nil {
+			return defaultValue
+		}
+		return f
+	}
+
+	r := &Rule{
+		Window:              duration(constant.CircuitBreakerWindowKey),
+		Buckets:             integer(constant.CircuitBreakerBucketsKey),
+		MinRequests:         integer(constant.CircuitBreakerMinRequestsKey),
+		ErrorRatio:          ratio(constant.CircuitBreakerErrorRatioKey, defaultErrorRatio),
+		SlowCallDuration:    duration(constant.CircuitBreakerSlowCallDurationKey),
+		SlowCallRatio:       ratio(constant.CircuitBreakerSlowCallRatioKey, 0),
+		ConsecutiveFailures: integer(constant.CircuitBreakerConsecutiveFailuresKey),
+		OpenDuration:        duration(constant.CircuitBreakerOpenDurationKey),
+		HalfOpenRequests:    integer(constant.CircuitBreakerHalfOpenRequestsKey),
+	}
+	r.setDefaults()
+	return r
+}
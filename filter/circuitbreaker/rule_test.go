@@ -0,0 +1,92 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package circuitbreaker
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+func TestParseRuleSet(t *testing.T) {
+	rs, err := parseRuleSet(`
+rules:
+  - service: "com.ikurento.user.UserProvider"
+    methods: ["GetUser"]
+    error-ratio: 0.3
+    slow-call-duration: 500ms
+    open-duration: 10s
+  - service: "*"
+    consecutive-failures: 5
+`)
+	require.Nil(t, err)
+	assert.True(t, rs.enabled())
+	require.Len(t, rs.Rules, 2)
+
+	r := rs.match("com.ikurento.user.UserProvider", "GetUser")
+	assert.Equal(t, 0.3, r.ErrorRatio)
+	assert.Equal(t, 500*time.Millisecond, r.SlowCallDuration)
+	assert.Equal(t, 10*time.Second, r.OpenDuration)
+	// the defaults
+	assert.Equal(t, defaultWindow, r.Window)
+	assert.Equal(t, defaultBuckets, r.Buckets)
+	assert.Equal(t, defaultMinRequests, r.MinRequests)
+	assert.Equal(t, defaultHalfOpenRequests, r.HalfOpenRequests)
+
+	r = rs.match("com.ikurento.user.UserProvider", "UpdateUser")
+	assert.Equal(t, 5, r.ConsecutiveFailures)
+	assert.Zero(t, r.ErrorRatio)
+
+	_, err = parseRuleSet("rules: {")
+	assert.NotNil(t, err)
+
+	rs, err = parseRuleSet("enabled: false")
+	require.Nil(t, err)
+	assert.False(t, rs.enabled())
+}
+
+func TestRuleFromURL(t *testing.T) {
+	url := common.NewURLWithOptions(
+		common.WithParamsValue(constant.CircuitBreakerSlowCallDurationKey, "1s"),
+		common.WithParamsValue(constant.CircuitBreakerSlowCallRatioKey, "0.8"),
+		common.WithParamsValue(constant.CircuitBreakerWindowKey, "30s"),
+		common.WithParamsValue("methods.GetUser."+constant.CircuitBreakerErrorRatioKey, "0"),
+		common.WithParamsValue("methods.GetUser."+constant.CircuitBreakerConsecutiveFailuresKey, "3"))
+
+	r := ruleFromURL(url, "UpdateUser")
+	assert.Equal(t, defaultErrorRatio, r.ErrorRatio)
+	assert.Equal(t, time.Second, r.SlowCallDuration)
+	assert.Equal(t, 0.8, r.SlowCallRatio)
+	assert.Equal(t, 30*time.Second, r.Window)
+	assert.Equal(t, defaultOpenDuration, r.OpenDuration)
+	assert.Zero(t, r.ConsecutiveFailures)
+
+	r = ruleFromURL(url, "GetUser")
+	assert.Zero(t, r.ErrorRatio)
+	assert.Equal(t, 3, r.ConsecutiveFailures)
+	assert.Equal(t, 30*time.Second, r.Window)
+}
@@ -0,0 +1,203 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package deprecation provides the filters signalling the deprecated services and methods to their consumers.
+/*
+ A provider marks a service or its methods as deprecated, optionally with the sunset, after which they
+ are to be removed, and the replacement to call instead:
+
+ services:
+   "UserProvider":
+     interface: "com.ikurento.user.UserProvider"
+     params:
+       deprecated: "true"
+       sunset: "2025-06-30"              # a date or an RFC 3339 time
+       replacement: "com.ikurento.user.UserProviderV2"
+       sunset.reject: "true"             # reject the calls after the sunset
+     methods:
+       - name: "GetUser"
+         deprecated: true
+         sunset: "2025-03-31T00:00:00Z"
+         replacement: "GetUserV2"
+
+ or by server.WithDeprecated, server.WithSunset and config.WithDeprecated etc. The deprecation is
+ published with the urls and the metadata of the service. The deprecation_consumer filter, which is
+ appended to the references once filter/deprecation is imported, unless they opt out by
+ client.WithoutDeprecationWarning or the param deprecation.warn set to false, logs a warning at most
+ once a minute per method of the deprecated ones being called, and counts the calls by
+ dubbo_consumer_deprecated_requests_total labelled by the calling application. Once the sunset has
+ passed and sunset.reject is set, the calls are rejected with an unimplemented error, by the
+ consumer filter and by the deprecation_provider filter, which is appended automatically, so the
+ consumers without the filter are rejected as well.
+*/
+package deprecation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	triple_protocol "dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+)
+
+var (
+	consumerOnce   sync.Once
+	consumerFilter *Filter
+	providerOnce   sync.Once
+	providerFilter *Filter
+
+	// warnInterval is the min interval between the warnings of a method, it is a variable for testing
+	warnInterval = time.Minute
+	// now is a variable for testing
+	now = time.Now
+)
+
+func init() {
+	extension.SetFilter(constant.DeprecationConsumerFilterKey, newConsumerFilter)
+	extension.SetFilter(constant.DeprecationProviderFilterKey, newProviderFilter)
+}
+
+// Filter warns about or rejects the calls to the deprecated methods
+type Filter struct {
+	side     string
+	lastWarn sync.Map // service#method -> time.Time
+}
+
+func newConsumerFilter() filter.Filter {
+	consumerOnce.Do(func() {
+		consumerFilter = &Filter{side: constant.SideConsumer}
+	})
+	return consumerFilter
+}
+
+func newProviderFilter() filter.Filter {
+	providerOnce.Do(func() {
+		providerFilter = &Filter{side: constant.SideProvider}
+	})
+	return providerFilter
+}
+
+// Invoke rejects the call if the sunset of the method has passed and the rejection is enabled, otherwise
+// the consumer filter warns about the call to a deprecated method
+func (f *Filter) Invoke(ctx context.Context, invoker base.Invoker, invocation base.Invocation) result.Result {
+	url := invoker.GetURL()
+	method := invocation.MethodName()
+	d := getDeprecation(url, method)
+	if d == nil {
+		return invoker.Invoke(ctx, invocation)
+	}
+
+	if d.reject && d.sunsetPassed() {
+		return &result.RPCResult{Err: triple_protocol.NewError(triple_protocol.CodeUnimplemented, d.error(url, method))}
+	}
+	if f.side == constant.SideConsumer {
+		reportDeprecated(url, method, d.sunset)
+		f.warn(url, method, d)
+	}
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *Filter) OnResponse(_ context.Context, res result.Result, _ base.Invoker, _ base.Invocation) result.Result {
+	return res
+}
+
+// warn logs a warning unless there has been one for the method within warnInterval
+func (f *Filter) warn(url *common.URL, method string, d *deprecation) {
+	key := url.Service() + "#" + method
+	current := now()
+	if last, ok := f.lastWarn.Load(key); ok && current.Sub(last.(time.Time)) < warnInterval {
+		return
+	}
+	f.lastWarn.Store(key, current)
+	logger.Warnf("[Deprecation] %s", d.message(url, method))
+}
+
+// deprecation describes a deprecated method
+type deprecation struct {
+	sunset      string
+	sunsetTime  time.Time
+	replacement string
+	reject      bool
+}
+
+// getDeprecation returns the deprecation of the method, or nil if neither the method nor the service is deprecated
+func getDeprecation(url *common.URL, method string) *deprecation {
+	sunset := url.GetMethodParam(method, constant.SunsetKey, url.GetParam(constant.SunsetKey, ""))
+	if sunset == "" && !url.GetMethodParamBool(method, constant.DeprecatedKey, url.GetParamBool(constant.DeprecatedKey, false)) {
+		return nil
+	}
+	d := &deprecation{
+		sunset:      sunset,
+		replacement: url.GetMethodParam(method, constant.ReplacementKey, url.GetParam(constant.ReplacementKey, "")),
+		reject:      url.GetParamBool(constant.SunsetRejectKey, false),
+	}
+	if sunset != "" {
+		t, err := parseSunset(sunset)
+		if err != nil {
+			logger.Warnf("[Deprecation] invalid sunset %q of %s#%s, it is ignored: %v", sunset, url.Service(), method, err)
+		}
+		d.sunsetTime = t
+	}
+	return d
+}
+
+// parseSunset parses the sunset as an RFC 3339 time or a date in UTC
+func parseSunset(sunset string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, sunset); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, sunset)
+}
+
+func (d *deprecation) sunsetPassed() bool {
+	return !d.sunsetTime.IsZero() && !now().Before(d.sunsetTime)
+}
+
+func (d *deprecation) message(url *common.URL, method string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "method %s of service %s is deprecated", method, url.Service())
+	if d.sunset != "" {
+		fmt.Fprintf(&sb, " and will be removed at %s", d.sunset)
+	}
+	if d.replacement != "" {
+		fmt.Fprintf(&sb, ", use %s instead", d.replacement)
+	}
+	return sb.String()
+}
+
+func (d *deprecation) error(url *common.URL, method string) error {
+	msg := fmt.Sprintf("method %s of service %s has been removed since %s", method, url.Service(), d.sunset)
+	if d.replacement != "" {
+		msg += fmt.Sprintf(", use %s instead", d.replacement)
+	}
+	return fmt.Errorf("%s", msg)
+}
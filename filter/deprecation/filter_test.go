@@ -0,0 +1,138 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deprecation
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+)
+
+func newInvoker(params map[string]string) base.Invoker {
+	values := url.Values{}
+	for k, v := range params {
+		values.Set(k, v)
+	}
+	return base.NewBaseInvoker(common.NewURLWithOptions(
+		common.WithPath("com.ikurento.user.UserProvider"),
+		common.WithParams(values),
+		common.WithParamsValue(constant.InterfaceKey, "com.ikurento.user.UserProvider")))
+}
+
+func setNow(t *testing.T, current time.Time) {
+	original := now
+	now = func() time.Time {
+		return current
+	}
+	t.Cleanup(func() {
+		now = original
+	})
+}
+
+func TestGetDeprecation(t *testing.T) {
+	invoker := newInvoker(map[string]string{
+		"methods.GetUser." + constant.DeprecatedKey:  "true",
+		"methods.GetUser." + constant.ReplacementKey: "GetUserV2",
+		"methods.ListUsers." + constant.SunsetKey:    "2025-03-31",
+	})
+	assert.Nil(t, getDeprecation(invoker.GetURL(), "SaveUser"))
+
+	d := getDeprecation(invoker.GetURL(), "GetUser")
+	assert.NotNil(t, d)
+	assert.Equal(t, "GetUserV2", d.replacement)
+	assert.False(t, d.sunsetPassed())
+	assert.Equal(t, "method GetUser of service com.ikurento.user.UserProvider is deprecated, use GetUserV2 instead",
+		d.message(invoker.GetURL(), "GetUser"))
+
+	// a sunset implies the deprecation
+	d = getDeprecation(invoker.GetURL(), "ListUsers")
+	assert.NotNil(t, d)
+	assert.True(t, d.sunsetTime.Equal(time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)))
+
+	// the service-level deprecation applies to every method
+	invoker = newInvoker(map[string]string{
+		constant.SunsetKey:                      "2025-06-30T12:00:00+08:00",
+		constant.ReplacementKey:                 "com.ikurento.user.UserProviderV2",
+		"methods.GetUser." + constant.SunsetKey: "2025-03-31",
+	})
+	d = getDeprecation(invoker.GetURL(), "SaveUser")
+	assert.True(t, d.sunsetTime.Equal(time.Date(2025, 6, 30, 4, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "com.ikurento.user.UserProviderV2", d.replacement)
+	assert.Equal(t, "2025-03-31", getDeprecation(invoker.GetURL(), "GetUser").sunset)
+}
+
+func TestFilterReject(t *testing.T) {
+	invoker := newInvoker(map[string]string{
+		constant.SunsetKey:       "2025-03-31",
+		constant.ReplacementKey:  "com.ikurento.user.UserProviderV2",
+		constant.SunsetRejectKey: "true",
+	})
+	inv := invocation.NewRPCInvocation("GetUser", nil, nil)
+
+	setNow(t, time.Date(2025, 3, 30, 0, 0, 0, 0, time.UTC))
+	for _, f := range []*Filter{{side: constant.SideConsumer}, {side: constant.SideProvider}} {
+		assert.Nil(t, f.Invoke(context.Background(), invoker, inv).Error())
+	}
+
+	setNow(t, time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC))
+	for _, f := range []*Filter{{side: constant.SideConsumer}, {side: constant.SideProvider}} {
+		err := f.Invoke(context.Background(), invoker, inv).Error()
+		assert.Equal(t, triple_protocol.CodeUnimplemented, triple_protocol.CodeOf(err))
+		assert.Contains(t, err.Error(), "has been removed since 2025-03-31, use com.ikurento.user.UserProviderV2 instead")
+	}
+
+	// the calls are only warned about without sunset.reject
+	invoker = newInvoker(map[string]string{constant.SunsetKey: "2025-03-31"})
+	assert.Nil(t, (&Filter{side: constant.SideConsumer}).Invoke(context.Background(), invoker, inv).Error())
+}
+
+func TestFilterWarn(t *testing.T) {
+	f := &Filter{side: constant.SideConsumer}
+	invoker := newInvoker(map[string]string{constant.DeprecatedKey: "true"})
+	start := time.Date(2025, 3, 30, 0, 0, 0, 0, time.UTC)
+
+	setNow(t, start)
+	assert.Nil(t, f.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", nil, nil)).Error())
+	last, ok := f.lastWarn.Load("com.ikurento.user.UserProvider#GetUser")
+	assert.True(t, ok)
+	assert.Equal(t, start, last)
+
+	// the warnings are rate limited
+	setNow(t, start.Add(warnInterval/2))
+	f.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", nil, nil))
+	last, _ = f.lastWarn.Load("com.ikurento.user.UserProvider#GetUser")
+	assert.Equal(t, start, last)
+
+	setNow(t, start.Add(warnInterval))
+	f.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", nil, nil))
+	last, _ = f.lastWarn.Load("com.ikurento.user.UserProvider#GetUser")
+	assert.Equal(t, start.Add(warnInterval), last)
+}
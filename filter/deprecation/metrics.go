@@ -0,0 +1,60 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deprecation
+
+import (
+	"sync/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+)
+
+var (
+	deprecatedKey = metrics.NewMetricKey("dubbo_consumer_deprecated_requests_total",
+		"The Requests to Deprecated Methods Sent by Consumer")
+
+	// the counter is set once the metrics module is initialized
+	deprecated atomic.Pointer[metrics.CounterVec]
+)
+
+func init() {
+	metrics.AddCollector("deprecation", func(registry metrics.MetricRegistry, _ *common.URL) {
+		c := metrics.NewCounterVec(registry, deprecatedKey)
+		deprecated.Store(&c)
+	})
+}
+
+// reportDeprecated counts a call to a deprecated method, labelled by the application of the consumer,
+// as the application of the url is the one of the provider
+func reportDeprecated(url *common.URL, method, sunset string) {
+	c := deprecated.Load()
+	if c == nil {
+		return
+	}
+	(*c).Inc(map[string]string{
+		constant.TagApplicationName: metrics.GetApplicationLevel().ApplicationName,
+		constant.TagInterface:       url.Service(),
+		constant.TagMethod:          method,
+		constant.TagGroup:           url.Group(),
+		constant.TagVersion:         url.GetParam(constant.VersionKey, ""),
+		constant.TagSunset:          sunset,
+	})
+}
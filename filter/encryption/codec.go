@@ -0,0 +1,345 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+const (
+	directionRequest  = "request"
+	directionResponse = "response"
+)
+
+// payloadCodec serializes the arguments and results before they are encrypted, and carries the
+// encrypted payloads in the body of the invocations in place of the original values.
+type payloadCodec interface {
+	// marshal serializes the values in order
+	marshal(values []any) ([]byte, error)
+	// unmarshal deserializes the values marshaled by marshal. The values are the arguments received
+	// in place of the original ones, the deserialized values are returned.
+	unmarshal(data []byte, values []any) ([]any, error)
+	// unmarshalResult deserializes the result marshaled by marshal into the reply, and returns the result
+	unmarshalResult(data []byte, reply any) (any, error)
+	// wrap returns the arguments sent in place of values, which carry the sealed payload
+	wrap(sealed []byte, values []any) ([]any, error)
+	// unwrap returns the sealed payload carried by the arguments returned by wrap, or nil if there is none
+	unwrap(values []any) []byte
+	// wrapResult returns the result sent in place of value, which carries the sealed result
+	wrapResult(sealed []byte, value any) any
+	// newReply returns the reply receiving the result returned by wrapResult in place of reply
+	newReply(reply any) any
+	// unwrapResult returns the sealed result carried by the reply returned by newReply, or nil if there is none
+	unwrapResult(reply any) []byte
+}
+
+// getPayloadCodec returns the codec of the serialization of url, protobuf is the default one of triple,
+// and hessian2 is the default one of the others.
+func getPayloadCodec(url *common.URL) (payloadCodec, error) {
+	serialization := url.GetParam(constant.SerializationKey, "")
+	if serialization == "" {
+		serialization = constant.Hessian2Serialization
+		if url.Protocol == constant.TriProtocol {
+			serialization = constant.ProtobufSerialization
+		}
+	}
+	switch serialization {
+	case constant.ProtobufSerialization:
+		return protobufCodec{}, nil
+	case constant.Hessian2Serialization:
+		// the arguments of triple are decoded into the parameters of the method, which can't take the payload
+		if url.Protocol == constant.TriProtocol {
+			return nil, errors.New("the payload encryption over triple requires the protobuf serialization")
+		}
+		return hessianCodec{}, nil
+	default:
+		return nil, fmt.Errorf("the payload encryption does not support the serialization %s", serialization)
+	}
+}
+
+// payloadField is the number of the unknown field carrying the sealed payload in the protobuf messages
+const payloadField = protowire.MaxValidNumber
+
+// protobufCodec marshals every message as a length-delimited field, the sealed payload is carried
+// by the empty messages sent in place of the original ones.
+type protobufCodec struct{}
+
+func (protobufCodec) marshal(values []any) ([]byte, error) {
+	var data []byte
+	for _, value := range values {
+		msg, ok := value.(proto.Message)
+		if !ok {
+			return nil, fmt.Errorf("%T is not a protobuf message", value)
+		}
+		b, err := proto.Marshal(msg)
+		if err != nil {
+			return nil, err
+		}
+		data = protowire.AppendBytes(data, b)
+	}
+	return data, nil
+}
+
+func (protobufCodec) unmarshal(data []byte, values []any) ([]any, error) {
+	for _, value := range values {
+		msg, ok := value.(proto.Message)
+		if !ok {
+			return nil, fmt.Errorf("%T is not a protobuf message", value)
+		}
+		b, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		if err := proto.Unmarshal(b, msg); err != nil {
+			return nil, err
+		}
+		data = data[n:]
+	}
+	if len(data) > 0 {
+		return nil, errors.New("the number of the encrypted values does not match")
+	}
+	return values, nil
+}
+
+func (c protobufCodec) unmarshalResult(data []byte, reply any) (any, error) {
+	if _, err := c.unmarshal(data, []any{reply}); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (protobufCodec) wrap(sealed []byte, values []any) ([]any, error) {
+	if len(values) == 0 {
+		return nil, errors.New("there is no protobuf message to carry the encrypted arguments")
+	}
+	wrapped := make([]any, len(values))
+	for i, value := range values {
+		msg, ok := value.(proto.Message)
+		if !ok {
+			return nil, fmt.Errorf("%T is not a protobuf message", value)
+		}
+		wrapped[i] = msg.ProtoReflect().New().Interface()
+	}
+	appendPayload(wrapped[0].(proto.Message), sealed)
+	return wrapped, nil
+}
+
+func (protobufCodec) unwrap(values []any) []byte {
+	if len(values) == 0 {
+		return nil
+	}
+	return payloadOf(values[0])
+}
+
+func (protobufCodec) wrapResult(sealed []byte, value any) any {
+	msg := value.(proto.Message).ProtoReflect().New().Interface()
+	appendPayload(msg, sealed)
+	return msg
+}
+
+func (protobufCodec) newReply(reply any) any {
+	if msg, ok := reply.(proto.Message); ok {
+		return msg.ProtoReflect().New().Interface()
+	}
+	return reply
+}
+
+func (protobufCodec) unwrapResult(reply any) []byte {
+	return payloadOf(reply)
+}
+
+func appendPayload(msg proto.Message, sealed []byte) {
+	m := msg.ProtoReflect()
+	m.SetUnknown(protowire.AppendBytes(protowire.AppendTag(m.GetUnknown(), payloadField, protowire.BytesType), sealed))
+}
+
+// payloadOf returns the sealed payload in the unknown fields of value
+func payloadOf(value any) []byte {
+	msg, ok := value.(proto.Message)
+	if !ok || !msg.ProtoReflect().IsValid() {
+		return nil
+	}
+	var payload []byte
+	b := msg.ProtoReflect().GetUnknown()
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil
+		}
+		b = b[n:]
+		if num == payloadField && typ == protowire.BytesType {
+			payload, n = protowire.ConsumeBytes(b)
+		} else {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return nil
+		}
+		b = b[n:]
+	}
+	return payload
+}
+
+// hessianCodec encodes the number of the values followed by the values, the sealed payload
+// is sent as the only argument and as the result, which are in the body of the dubbo protocol.
+type hessianCodec struct{}
+
+func (hessianCodec) marshal(values []any) ([]byte, error) {
+	encoder := hessian.NewEncoder()
+	if err := encoder.Encode(int32(len(values))); err != nil {
+		return nil, err
+	}
+	for _, value := range values {
+		if err := encoder.Encode(value); err != nil {
+			return nil, err
+		}
+	}
+	return encoder.Buffer(), nil
+}
+
+func (hessianCodec) unmarshal(data []byte, _ []any) ([]any, error) {
+	decoder := hessian.NewDecoder(data)
+	count, err := decoder.Decode()
+	if err != nil {
+		return nil, err
+	}
+	n, ok := count.(int32)
+	if !ok || n < 0 {
+		return nil, fmt.Errorf("invalid number of the encrypted values %v", count)
+	}
+	decoded := make([]any, n)
+	for i := range decoded {
+		if decoded[i], err = decoder.Decode(); err != nil {
+			return nil, err
+		}
+	}
+	return decoded, nil
+}
+
+func (c hessianCodec) unmarshalResult(data []byte, reply any) (any, error) {
+	values, err := c.unmarshal(data, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 1 {
+		return nil, errors.New("the number of the encrypted values does not match")
+	}
+	if reply == nil {
+		return values[0], nil
+	}
+	// a nil result leaves the reply untouched, like the dubbo protocol does
+	if values[0] != nil {
+		if err = hessian.ReflectResponse(values[0], reply); err != nil {
+			return nil, err
+		}
+	}
+	return reply, nil
+}
+
+func (hessianCodec) wrap(sealed []byte, _ []any) ([]any, error) {
+	return []any{sealed}, nil
+}
+
+func (hessianCodec) unwrap(values []any) []byte {
+	if len(values) != 1 {
+		return nil
+	}
+	sealed, _ := values[0].([]byte)
+	return sealed
+}
+
+func (hessianCodec) wrapResult(sealed []byte, _ any) any {
+	return sealed
+}
+
+func (hessianCodec) newReply(_ any) any {
+	return new([]byte)
+}
+
+func (hessianCodec) unwrapResult(reply any) []byte {
+	switch r := reply.(type) {
+	case *[]byte:
+		return *r
+	case []byte:
+		return r
+	}
+	return nil
+}
+
+// envelope identifies the payload of an invocation, it is bound to the payload as the additional data
+// of AES-GCM, so the payload can't be replayed for another service, method, invocation or direction.
+type envelope struct {
+	service   string
+	method    string
+	requestID string
+	direction string
+}
+
+func (e envelope) additionalData() []byte {
+	return []byte(e.service + "#" + e.method + "#" + e.requestID + "#" + e.direction)
+}
+
+// seal encrypts the plaintext with AES-GCM, the random nonce is prepended to the result
+func seal(key, plaintext []byte, env envelope) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, env.additionalData()), nil
+}
+
+// open decrypts the payload encrypted by seal
+func open(key, sealed []byte, env envelope) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("the encrypted payload is too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, env.additionalData())
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
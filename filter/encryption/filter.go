@@ -0,0 +1,309 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package encryption provides the consumer and provider filters encrypting the payloads end to end.
+/*
+ The consumer filter serializes the arguments, encrypts them with AES-GCM and sends the ciphertext in
+ place of the arguments, the id of the key and a random request id are sent in the attachments.
+ The provider filter decrypts the arguments before the service is invoked, and encrypts the result with
+ the same key, so the payloads stay confidential even if TLS ends at a proxy in the middle, for example:
+
+ references:
+   "UserProvider":
+     interface: "com.ikurento.user.UserProvider"
+     filter: "encryption_consumer"
+     params:
+       encryption.key-provider: "file"   # the name of the EncryptionKeyProvider, "file" by default
+       encryption.key-file: "/etc/dubbo/payload-keys.yaml"
+
+ services:
+   "UserProvider":
+     interface: "com.ikurento.user.UserProvider"
+     filter: "encryption_provider"
+     params:
+       encryption.required: "true"   # reject the unencrypted invocations, "true" by default
+       encryption.key-file: "/etc/dubbo/payload-keys.yaml"
+
+ The payloads are serialized by protobuf for triple, or by hessian2 for dubbo, according to the serialization
+ of the service. The ciphertext travels in the body: protobuf carries it in a reserved unknown field of the empty
+ messages sent in place of the request and the response, and hessian2 sends it as the only argument and as
+ the result. The service, the method, the request id and the direction are bound to the ciphertext, so it can't
+ be replayed for another invocation. Only unary invocations are encrypted, and the errors are not.
+ Set encryption.required to "false" while the consumers are migrating. Register another key provider,
+ e.g. backed by a KMS, by extension.SetEncryptionKeyProvider.
+*/
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+import (
+	"github.com/google/uuid"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	triple_protocol "dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+)
+
+// encryptedReplyAttribute is the attribute of the reply receiving the encrypted result
+const encryptedReplyAttribute = "encryption-reply"
+
+var (
+	consumerOnce   sync.Once
+	consumerFilter *encryptionConsumerFilter
+	providerOnce   sync.Once
+	providerFilter *encryptionProviderFilter
+)
+
+func init() {
+	extension.SetFilter(constant.EncryptionConsumerFilterKey, newConsumerFilter)
+	extension.SetFilter(constant.EncryptionProviderFilterKey, newProviderFilter)
+}
+
+type encryptionConsumerFilter struct{}
+
+func newConsumerFilter() filter.Filter {
+	consumerOnce.Do(func() {
+		consumerFilter = &encryptionConsumerFilter{}
+	})
+	return consumerFilter
+}
+
+// Invoke encrypts the arguments with the current key, and sends them in place of the arguments
+func (f *encryptionConsumerFilter) Invoke(ctx context.Context, invoker base.Invoker, inv base.Invocation) result.Result {
+	if isStreaming(inv) {
+		return invoker.Invoke(ctx, inv)
+	}
+	url := invoker.GetURL()
+	codec, err := getPayloadCodec(url)
+	if err != nil {
+		return &result.RPCResult{Err: err}
+	}
+	provider, err := getKeyProvider(url)
+	if err != nil {
+		return &result.RPCResult{Err: err}
+	}
+	keyID, key, err := provider.CurrentKey()
+	if err != nil {
+		return &result.RPCResult{Err: fmt.Errorf("get the current encryption key error: %w", err)}
+	}
+
+	args := inv.Arguments()
+	plaintext, err := codec.marshal(args)
+	if err != nil {
+		return &result.RPCResult{Err: fmt.Errorf("serialize the arguments of %s error: %w", inv.MethodName(), err)}
+	}
+	requestID := uuid.NewString()
+	sealed, err := seal(key, plaintext, newEnvelope(url, inv.MethodName(), requestID, directionRequest))
+	if err != nil {
+		return &result.RPCResult{Err: fmt.Errorf("encrypt the arguments of %s error: %w", inv.MethodName(), err)}
+	}
+	wrapped, err := codec.wrap(sealed, args)
+	if err != nil {
+		return &result.RPCResult{Err: fmt.Errorf("encrypt the arguments of %s error: %w", inv.MethodName(), err)}
+	}
+
+	attachments := copyAttachments(inv.Attachments())
+	attachments[constant.EncryptionKeyIDAttachmentKey] = keyID
+	attachments[constant.EncryptionRequestIDAttachmentKey] = requestID
+	// the encrypted result is received by another reply, which is decrypted into the original one
+	reply := codec.newReply(inv.Reply())
+	inv.SetAttribute(constant.EncryptionKeyIDAttachmentKey, keyID)
+	inv.SetAttribute(constant.EncryptionRequestIDAttachmentKey, requestID)
+	inv.SetAttribute(encryptedReplyAttribute, reply)
+	return invoker.Invoke(ctx, invocation.CopyWithArguments(inv, wrapped, reply, attachments))
+}
+
+// OnResponse decrypts the result into the reply
+func (f *encryptionConsumerFilter) OnResponse(_ context.Context, res result.Result, invoker base.Invoker, inv base.Invocation) result.Result {
+	keyID, ok := inv.GetAttribute(constant.EncryptionKeyIDAttachmentKey)
+	if !ok || res.Error() != nil {
+		return res
+	}
+	url := invoker.GetURL()
+	codec, err := getPayloadCodec(url)
+	if err != nil {
+		res.SetError(err)
+		return res
+	}
+	reply, _ := inv.GetAttribute(encryptedReplyAttribute)
+	sealed := codec.unwrapResult(reply)
+	if sealed == nil {
+		// the result is not decoded into the reply by some protocols
+		sealed = codec.unwrapResult(res.Result())
+	}
+	if sealed == nil {
+		res.SetError(fmt.Errorf("the result of %s is not encrypted by the provider", inv.MethodName()))
+		return res
+	}
+	requestID, _ := inv.GetAttribute(constant.EncryptionRequestIDAttachmentKey)
+	plaintext, err := decrypt(url, keyID.(string), sealed,
+		newEnvelope(url, inv.MethodName(), requestID.(string), directionResponse))
+	if err != nil {
+		res.SetError(fmt.Errorf("decrypt the result of %s error: %w", inv.MethodName(), err))
+		return res
+	}
+	value, err := codec.unmarshalResult(plaintext, inv.Reply())
+	if err != nil {
+		res.SetError(fmt.Errorf("deserialize the result of %s error: %w", inv.MethodName(), err))
+		return res
+	}
+	res.SetResult(value)
+	return res
+}
+
+type encryptionProviderFilter struct{}
+
+func newProviderFilter() filter.Filter {
+	providerOnce.Do(func() {
+		providerFilter = &encryptionProviderFilter{}
+	})
+	return providerFilter
+}
+
+// Invoke decrypts the arguments, and encrypts the result with the key of the invocation.
+// The unencrypted invocations are rejected if the service requires encryption.
+func (f *encryptionProviderFilter) Invoke(ctx context.Context, invoker base.Invoker, inv base.Invocation) result.Result {
+	url := invoker.GetURL()
+	attachments := inv.Attachments()
+	keyID, _ := common.LookupAttachment(attachments, constant.EncryptionKeyIDAttachmentKey)
+	if keyID == "" {
+		if url.GetParamBool(constant.EncryptionRequiredKey, true) {
+			return &result.RPCResult{Err: triple_protocol.NewError(triple_protocol.CodePermissionDenied,
+				fmt.Errorf("the service %s requires encryption, the unencrypted invocation of %s is rejected",
+					url.Service(), inv.MethodName()))}
+		}
+		return invoker.Invoke(ctx, inv)
+	}
+
+	codec, err := getPayloadCodec(url)
+	if err != nil {
+		return &result.RPCResult{Err: triple_protocol.NewError(triple_protocol.CodeInternal, err)}
+	}
+	sealed := codec.unwrap(inv.Arguments())
+	if sealed == nil {
+		return &result.RPCResult{Err: triple_protocol.NewError(triple_protocol.CodeInvalidArgument,
+			fmt.Errorf("the encrypted arguments of %s are missing", inv.MethodName()))}
+	}
+	requestID, _ := common.LookupAttachment(attachments, constant.EncryptionRequestIDAttachmentKey)
+	plaintext, err := decrypt(url, keyID, sealed, newEnvelope(url, inv.MethodName(), requestID, directionRequest))
+	if err != nil {
+		return &result.RPCResult{Err: triple_protocol.NewError(triple_protocol.CodeUnauthenticated,
+			fmt.Errorf("decrypt the arguments of %s error: %w", inv.MethodName(), err))}
+	}
+	args, err := codec.unmarshal(plaintext, inv.Arguments())
+	if err != nil {
+		return &result.RPCResult{Err: triple_protocol.NewError(triple_protocol.CodeInvalidArgument,
+			fmt.Errorf("deserialize the arguments of %s error: %w", inv.MethodName(), err))}
+	}
+
+	res := invoker.Invoke(ctx, invocation.CopyWithArguments(inv, args, inv.Reply(), copyAttachments(attachments)))
+	if res.Error() != nil {
+		return res
+	}
+	env := newEnvelope(url, inv.MethodName(), requestID, directionResponse)
+	if err = encryptResult(url, res, codec, keyID, env); err != nil {
+		return &result.RPCResult{Err: triple_protocol.NewError(triple_protocol.CodeInternal,
+			fmt.Errorf("encrypt the result of %s error: %w", inv.MethodName(), err))}
+	}
+	return res
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *encryptionProviderFilter) OnResponse(_ context.Context, res result.Result, _ base.Invoker, _ base.Invocation) result.Result {
+	return res
+}
+
+// encryptResult replaces the result with the one carrying the encrypted result
+func encryptResult(url *common.URL, res result.Result, codec payloadCodec, keyID string, env envelope) error {
+	value := res.Result()
+	// please refer to the MethodFunc of the code generated by new triple
+	triResp, isTriResp := value.(*triple_protocol.Response)
+	if isTriResp {
+		value = triResp.Msg
+	}
+	plaintext, err := codec.marshal([]any{value})
+	if err != nil {
+		return err
+	}
+	key, err := getKey(url, keyID)
+	if err != nil {
+		return err
+	}
+	sealed, err := seal(key, plaintext, env)
+	if err != nil {
+		return err
+	}
+	if isTriResp {
+		triResp.Msg = codec.wrapResult(sealed, value)
+	} else {
+		res.SetResult(codec.wrapResult(sealed, value))
+	}
+	return nil
+}
+
+func getKeyProvider(url *common.URL) (filter.EncryptionKeyProvider, error) {
+	creator, err := extension.GetEncryptionKeyProviderCreator(
+		url.GetParam(constant.EncryptionKeyProviderKey, constant.DefaultEncryptionKeyProvider))
+	if err != nil {
+		return nil, err
+	}
+	return creator(url)
+}
+
+func getKey(url *common.URL, keyID string) ([]byte, error) {
+	provider, err := getKeyProvider(url)
+	if err != nil {
+		return nil, err
+	}
+	return provider.GetKey(keyID)
+}
+
+func decrypt(url *common.URL, keyID string, sealed []byte, env envelope) ([]byte, error) {
+	key, err := getKey(url, keyID)
+	if err != nil {
+		return nil, err
+	}
+	return open(key, sealed, env)
+}
+
+func newEnvelope(url *common.URL, method, requestID, direction string) envelope {
+	return envelope{service: url.Service(), method: method, requestID: requestID, direction: direction}
+}
+
+func isStreaming(inv base.Invocation) bool {
+	callType, ok := inv.GetAttribute(constant.CallTypeKey)
+	return ok && callType != constant.CallUnary
+}
+
+func copyAttachments(attachments map[string]any) map[string]any {
+	copied := make(map[string]any, len(attachments)+2)
+	for k, v := range attachments {
+		copied[k] = v
+	}
+	return copied
+}
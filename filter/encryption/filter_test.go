@@ -0,0 +1,396 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encryption
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+
+	"github.com/google/uuid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+)
+
+type staticKeyProvider struct {
+	current string
+	keys    map[string][]byte
+}
+
+func (p *staticKeyProvider) CurrentKey() (string, []byte, error) {
+	return p.current, p.keys[p.current], nil
+}
+
+func (p *staticKeyProvider) GetKey(id string) ([]byte, error) {
+	key, ok := p.keys[id]
+	if !ok {
+		return nil, errors.New("unknown key " + id)
+	}
+	return key, nil
+}
+
+// newStaticKeyProvider registers a key provider with the keys k1 and k2, k1 is the current one
+func newStaticKeyProvider() (string, *staticKeyProvider) {
+	p := &staticKeyProvider{
+		current: "k1",
+		keys: map[string][]byte{
+			"k1": []byte("0123456789abcdef"),
+			"k2": []byte("0123456789abcdef0123456789abcdef"),
+		},
+	}
+	name := uuid.NewString()
+	extension.SetEncryptionKeyProvider(name, func(*common.URL) (filter.EncryptionKeyProvider, error) {
+		return p, nil
+	})
+	return name, p
+}
+
+func newURL(protocol, keyProvider string, params ...common.Option) *common.URL {
+	opts := append([]common.Option{
+		common.WithProtocol(protocol),
+		common.WithPath("com.ikurento.user.UserProvider"),
+		common.WithParamsValue(constant.InterfaceKey, "com.ikurento.user.UserProvider"),
+		common.WithParamsValue(constant.EncryptionKeyProviderKey, keyProvider),
+	}, params...)
+	return common.NewURLWithOptions(opts...)
+}
+
+// serviceInvoker invokes the service with the arguments
+type serviceInvoker struct {
+	base.BaseInvoker
+	service func(args []any) any
+	args    []any
+}
+
+func (i *serviceInvoker) Invoke(_ context.Context, inv base.Invocation) result.Result {
+	i.args = inv.Arguments()
+	res := &result.RPCResult{}
+	res.SetResult(i.service(inv.Arguments()))
+	return res
+}
+
+// wireInvoker delivers the arguments and the attachments received by the consumer to the provider filter,
+// and decodes the result into the reply, like the network and the protocols do.
+type wireInvoker struct {
+	base.BaseInvoker
+	provider *serviceInvoker
+	sent     base.Invocation
+}
+
+func (i *wireInvoker) Invoke(ctx context.Context, inv base.Invocation) result.Result {
+	i.sent = inv
+	attachments := make(map[string]any, len(inv.Attachments()))
+	for k, v := range inv.Attachments() {
+		attachments[k] = v
+	}
+	args := make([]any, len(inv.Arguments()))
+	for j, arg := range inv.Arguments() {
+		args[j] = arg
+		if msg, ok := arg.(proto.Message); ok {
+			args[j] = proto.Clone(msg)
+		}
+	}
+	res := newProviderFilter().Invoke(ctx, i.provider, invocation.NewRPCInvocation(inv.MethodName(), args, attachments))
+	received := &result.RPCResult{Err: res.Error(), Attrs: res.Attachments()}
+	if res.Error() == nil {
+		received.SetResult(decodeReply(res.Result(), inv.Reply()))
+	}
+	return received
+}
+
+func decodeReply(value, reply any) any {
+	if resp, ok := value.(*triple_protocol.Response); ok {
+		value = resp.Msg
+	}
+	if msg, ok := reply.(proto.Message); ok {
+		b, _ := proto.Marshal(value.(proto.Message))
+		_ = proto.Unmarshal(b, msg)
+		return reply
+	}
+	_ = hessian.ReflectResponse(value, reply)
+	return reply
+}
+
+func invoke(consumerURL *common.URL, wire *wireInvoker, inv base.Invocation) result.Result {
+	wire.BaseInvoker = *base.NewBaseInvoker(consumerURL)
+	f := newConsumerFilter()
+	res := f.Invoke(context.Background(), wire, inv)
+	return f.OnResponse(context.Background(), res, wire, inv)
+}
+
+func TestFilterProtobuf(t *testing.T) {
+	keyProvider, _ := newStaticKeyProvider()
+	provider := &serviceInvoker{
+		BaseInvoker: *base.NewBaseInvoker(newURL(constant.TriProtocol, keyProvider)),
+		service: func(args []any) any {
+			// the code generated by new triple wraps the result in a response
+			return triple_protocol.NewResponse(wrapperspb.String("hello " + args[0].(*wrapperspb.StringValue).Value))
+		},
+	}
+	wire := &wireInvoker{provider: provider}
+	reply := &wrapperspb.StringValue{}
+	req := wrapperspb.String("dubbo")
+	inv := invocation.NewRPCInvocationWithOptions(
+		invocation.WithMethodName("Greet"),
+		invocation.WithArguments([]any{req}),
+		invocation.WithReply(reply),
+		invocation.WithParameterRawValues([]any{req, reply}),
+	)
+	inv.SetAttribute(constant.CallTypeKey, constant.CallUnary)
+
+	res := invoke(newURL(constant.TriProtocol, keyProvider), wire, inv)
+	require.NoError(t, res.Error())
+	assert.Equal(t, "hello dubbo", reply.Value)
+	assert.Same(t, reply, res.Result())
+
+	// only the empty messages carrying the ciphertext in their unknown fields are sent
+	sent := wire.sent.Arguments()[0].(*wrapperspb.StringValue)
+	assert.Empty(t, sent.Value)
+	assert.NotEmpty(t, payloadOf(sent))
+	assert.Equal(t, wire.sent.Arguments()[0], wire.sent.ParameterRawValues()[0])
+	assert.NotSame(t, reply, wire.sent.ParameterRawValues()[1])
+	assert.IsType(t, reply, wire.sent.ParameterRawValues()[1])
+	assert.Equal(t, "k1", wire.sent.GetAttachmentWithDefaultValue(constant.EncryptionKeyIDAttachmentKey, ""))
+	assert.NotEmpty(t, wire.sent.GetAttachmentWithDefaultValue(constant.EncryptionRequestIDAttachmentKey, ""))
+	assert.Equal(t, "dubbo", provider.args[0].(*wrapperspb.StringValue).Value)
+	assert.Equal(t, "dubbo", req.Value)
+}
+
+type user struct {
+	ID   string
+	Name string
+}
+
+func (u *user) JavaClassName() string {
+	return "org.apache.dubbo.User"
+}
+
+func init() {
+	hessian.RegisterPOJO(&user{})
+}
+
+func TestFilterHessian(t *testing.T) {
+	keyProvider, keys := newStaticKeyProvider()
+	provider := &serviceInvoker{
+		BaseInvoker: *base.NewBaseInvoker(newURL(constant.DubboProtocol, keyProvider)),
+		service: func(args []any) any {
+			return &user{ID: args[0].(string), Name: "Alex"}
+		},
+	}
+	wire := &wireInvoker{provider: provider}
+	consumerURL := newURL(constant.DubboProtocol, keyProvider)
+
+	reply := &user{}
+	res := invoke(consumerURL, wire, invocation.NewRPCInvocationWithOptions(
+		invocation.WithMethodName("GetUser"),
+		invocation.WithArguments([]any{"A001", int32(18)}),
+		invocation.WithReply(reply),
+	))
+	require.NoError(t, res.Error())
+	assert.Equal(t, &user{ID: "A001", Name: "Alex"}, reply)
+	require.Len(t, wire.sent.Arguments(), 1)
+	assert.IsType(t, []byte{}, wire.sent.Arguments()[0])
+	assert.Equal(t, []any{"A001", int32(18)}, provider.args)
+
+	// the provider decrypts the invocations encrypted with the previous key after the rotation
+	keys.current = "k2"
+	reply = &user{}
+	res = invoke(consumerURL, wire, invocation.NewRPCInvocationWithOptions(
+		invocation.WithMethodName("GetUser"),
+		invocation.WithArguments([]any{"A002", int32(20)}),
+		invocation.WithReply(reply),
+	))
+	require.NoError(t, res.Error())
+	assert.Equal(t, "A002", reply.ID)
+	assert.Equal(t, "k2", wire.sent.GetAttachmentWithDefaultValue(constant.EncryptionKeyIDAttachmentKey, ""))
+}
+
+func TestProviderFilterRejectsUnencryptedInvocations(t *testing.T) {
+	keyProvider, _ := newStaticKeyProvider()
+	provider := &serviceInvoker{
+		BaseInvoker: *base.NewBaseInvoker(newURL(constant.DubboProtocol, keyProvider)),
+		service: func(args []any) any {
+			return args[0]
+		},
+	}
+	inv := invocation.NewRPCInvocation("Echo", []any{"hello"}, map[string]any{})
+
+	res := newProviderFilter().Invoke(context.Background(), provider, inv)
+	assert.Equal(t, triple_protocol.CodePermissionDenied, triple_protocol.CodeOf(res.Error()))
+	assert.Nil(t, provider.args)
+
+	provider.BaseInvoker = *base.NewBaseInvoker(newURL(constant.DubboProtocol, keyProvider,
+		common.WithParamsValue(constant.EncryptionRequiredKey, "false")))
+	res = newProviderFilter().Invoke(context.Background(), provider, inv)
+	require.NoError(t, res.Error())
+	assert.Equal(t, "hello", res.Result())
+}
+
+func TestProviderFilterRejectsTamperedPayloads(t *testing.T) {
+	keyProvider, keys := newStaticKeyProvider()
+	url := newURL(constant.DubboProtocol, keyProvider)
+	provider := &serviceInvoker{
+		BaseInvoker: *base.NewBaseInvoker(url),
+		service: func(args []any) any {
+			return args[0]
+		},
+	}
+	plaintext, err := hessianCodec{}.marshal([]any{"hello"})
+	require.NoError(t, err)
+	env := newEnvelope(url, "Echo", "r1", directionRequest)
+	sealed := mustSeal(t, keys.keys["k1"], plaintext, env)
+	otherMethod, otherRequest, response := env, env, env
+	otherMethod.method = "Delete"
+	otherRequest.requestID = "r2"
+	response.direction = directionResponse
+
+	tests := []struct {
+		name   string
+		keyID  string
+		sealed []byte
+	}{
+		{name: "unknown key", keyID: "k3", sealed: sealed},
+		{name: "wrong key", keyID: "k2", sealed: sealed},
+		{name: "another method", keyID: "k1", sealed: mustSeal(t, keys.keys["k1"], plaintext, otherMethod)},
+		{name: "another request", keyID: "k1", sealed: mustSeal(t, keys.keys["k1"], plaintext, otherRequest)},
+		{name: "replayed response", keyID: "k1", sealed: mustSeal(t, keys.keys["k1"], plaintext, response)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := newProviderFilter().Invoke(context.Background(), provider,
+				invocation.NewRPCInvocation("Echo", []any{tt.sealed}, map[string]any{
+					constant.EncryptionKeyIDAttachmentKey:     tt.keyID,
+					constant.EncryptionRequestIDAttachmentKey: "r1",
+				}))
+			assert.Equal(t, triple_protocol.CodeUnauthenticated, triple_protocol.CodeOf(res.Error()))
+			assert.Nil(t, provider.args)
+		})
+	}
+
+	res := newProviderFilter().Invoke(context.Background(), provider,
+		invocation.NewRPCInvocation("Echo", []any{"hello"}, map[string]any{constant.EncryptionKeyIDAttachmentKey: "k1"}))
+	assert.Equal(t, triple_protocol.CodeInvalidArgument, triple_protocol.CodeOf(res.Error()))
+	assert.Nil(t, provider.args)
+
+	res = newProviderFilter().Invoke(context.Background(), provider,
+		invocation.NewRPCInvocation("Echo", []any{sealed}, map[string]any{
+			constant.EncryptionKeyIDAttachmentKey:     "k1",
+			constant.EncryptionRequestIDAttachmentKey: "r1",
+		}))
+	require.NoError(t, res.Error())
+	assert.Equal(t, []any{"hello"}, provider.args)
+}
+
+func TestConsumerFilterRejectsUnencryptedResults(t *testing.T) {
+	keyProvider, _ := newStaticKeyProvider()
+	// the provider filter is not configured, so the result is not encrypted
+	plain := &serviceInvoker{
+		BaseInvoker: *base.NewBaseInvoker(newURL(constant.DubboProtocol, keyProvider)),
+		service: func(args []any) any {
+			return "hello"
+		},
+	}
+	f := newConsumerFilter()
+	var reply string
+	inv := invocation.NewRPCInvocationWithOptions(
+		invocation.WithMethodName("Echo"),
+		invocation.WithArguments([]any{"hello"}),
+		invocation.WithReply(&reply),
+	)
+	res := f.OnResponse(context.Background(), f.Invoke(context.Background(), plain, inv), plain, inv)
+	assert.Error(t, res.Error())
+	assert.Equal(t, "", reply)
+}
+
+// resultInvoker returns the result regardless of the invocation
+type resultInvoker struct {
+	base.BaseInvoker
+	result any
+}
+
+func (i *resultInvoker) Invoke(_ context.Context, _ base.Invocation) result.Result {
+	res := &result.RPCResult{}
+	res.SetResult(i.result)
+	return res
+}
+
+func TestConsumerFilterRejectsResultsOfOtherRequests(t *testing.T) {
+	keyProvider, keys := newStaticKeyProvider()
+	url := newURL(constant.DubboProtocol, keyProvider)
+	plaintext, err := hessianCodec{}.marshal([]any{"hello"})
+	require.NoError(t, err)
+	// the result of another invocation of the same method is replayed
+	invoker := &resultInvoker{
+		BaseInvoker: *base.NewBaseInvoker(url),
+		result:      mustSeal(t, keys.keys["k1"], plaintext, newEnvelope(url, "Echo", "r1", directionResponse)),
+	}
+	f := newConsumerFilter()
+	var reply string
+	inv := invocation.NewRPCInvocationWithOptions(
+		invocation.WithMethodName("Echo"),
+		invocation.WithArguments([]any{"hello"}),
+		invocation.WithReply(&reply),
+	)
+	res := f.OnResponse(context.Background(), f.Invoke(context.Background(), invoker, inv), invoker, inv)
+	assert.ErrorContains(t, res.Error(), "decrypt the result of Echo")
+	assert.Equal(t, "", reply)
+}
+
+func TestGetPayloadCodec(t *testing.T) {
+	codec, err := getPayloadCodec(common.NewURLWithOptions(common.WithProtocol(constant.TriProtocol)))
+	require.NoError(t, err)
+	assert.IsType(t, protobufCodec{}, codec)
+
+	// the arguments of triple can't carry the ciphertext of hessian2
+	_, err = getPayloadCodec(common.NewURLWithOptions(common.WithProtocol(constant.TriProtocol),
+		common.WithParamsValue(constant.SerializationKey, constant.Hessian2Serialization)))
+	assert.Error(t, err)
+
+	codec, err = getPayloadCodec(common.NewURLWithOptions(common.WithProtocol(constant.DubboProtocol)))
+	require.NoError(t, err)
+	assert.IsType(t, hessianCodec{}, codec)
+
+	_, err = getPayloadCodec(common.NewURLWithOptions(common.WithProtocol(constant.TriProtocol),
+		common.WithParamsValue(constant.SerializationKey, constant.JSONSerialization)))
+	assert.Error(t, err)
+}
+
+func mustSeal(t *testing.T, key, plaintext []byte, env envelope) []byte {
+	sealed, err := seal(key, plaintext, env)
+	require.NoError(t, err)
+	return sealed
+}
@@ -0,0 +1,194 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encryption
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+
+	"gopkg.in/yaml.v2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+)
+
+const (
+	// FileKey is the name of the FileKeyProvider
+	FileKey = constant.DefaultEncryptionKeyProvider
+
+	// keyFileCheckInterval is how often the key file is checked for modification
+	keyFileCheckInterval = 10 * time.Second
+)
+
+var (
+	fileProvidersMutex sync.Mutex
+	fileProviders      = make(map[string]*FileKeyProvider)
+)
+
+func init() {
+	extension.SetEncryptionKeyProvider(FileKey, newFileKeyProvider)
+}
+
+// keyFile is the content of the key file
+type keyFile struct {
+	Current string            `yaml:"current"`
+	Keys    map[string]string `yaml:"keys"`
+}
+
+// FileKeyProvider reads the base64 encoded AES keys from a yaml file, and reloads the file once it is modified,
+// so the keys can be rotated by updating the file. The key file looks like:
+/**
+ * current: "2024-10"
+ * keys:
+ *   "2024-09": "k6Nf0o1A2m4...=" # kept to decrypt the payloads encrypted before the rotation
+ *   "2024-10": "Zq3tB8nV5x0...="
+ */
+type FileKeyProvider struct {
+	path string
+
+	mutex     sync.RWMutex
+	current   string
+	keys      map[string][]byte
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+// newFileKeyProvider returns the FileKeyProvider shared by the urls with the same key file
+func newFileKeyProvider(url *common.URL) (filter.EncryptionKeyProvider, error) {
+	path := url.GetParam(constant.EncryptionKeyFileKey, "")
+	if len(path) == 0 {
+		return nil, fmt.Errorf("the %s of the file encryption key provider is required", constant.EncryptionKeyFileKey)
+	}
+
+	fileProvidersMutex.Lock()
+	defer fileProvidersMutex.Unlock()
+	if p, ok := fileProviders[path]; ok {
+		return p, nil
+	}
+	p, err := NewFileKeyProvider(path)
+	if err != nil {
+		return nil, err
+	}
+	fileProviders[path] = p
+	return p, nil
+}
+
+// NewFileKeyProvider creates a FileKeyProvider and loads the key file
+func NewFileKeyProvider(path string) (*FileKeyProvider, error) {
+	p := &FileKeyProvider{path: path}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if err = p.load(info.ModTime()); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// CurrentKey returns the current key of the key file
+func (p *FileKeyProvider) CurrentKey() (string, []byte, error) {
+	p.refresh()
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.current, p.keys[p.current], nil
+}
+
+// GetKey returns the key with id
+func (p *FileKeyProvider) GetKey(id string) ([]byte, error) {
+	p.refresh()
+	p.mutex.RLock()
+	key, ok := p.keys[id]
+	p.mutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("the encryption key %s is not found in %s", id, p.path)
+	}
+	return key, nil
+}
+
+// refresh reloads the key file if it has been modified, the loaded keys are kept if the file is broken
+func (p *FileKeyProvider) refresh() {
+	p.mutex.RLock()
+	checked := time.Since(p.checkedAt) < keyFileCheckInterval
+	p.mutex.RUnlock()
+	if checked {
+		return
+	}
+
+	p.mutex.Lock()
+	p.checkedAt = time.Now()
+	modTime := p.modTime
+	p.mutex.Unlock()
+	info, err := os.Stat(p.path)
+	if err != nil {
+		logger.Warnf("[Encryption Filter] stat the key file %s error, the loaded keys are kept: %v", p.path, err)
+		return
+	}
+	if info.ModTime().Equal(modTime) {
+		return
+	}
+	if err = p.load(info.ModTime()); err != nil {
+		logger.Warnf("[Encryption Filter] load the key file %s error, the loaded keys are kept: %v", p.path, err)
+		return
+	}
+	logger.Infof("[Encryption Filter] the key file %s is reloaded", p.path)
+}
+
+func (p *FileKeyProvider) load(modTime time.Time) error {
+	content, err := os.ReadFile(p.path)
+	if err != nil {
+		return err
+	}
+	var file keyFile
+	if err = yaml.Unmarshal(content, &file); err != nil {
+		return err
+	}
+	keys := make(map[string][]byte, len(file.Keys))
+	for id, encoded := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return fmt.Errorf("the encryption key %s is not base64 encoded: %w", id, err)
+		}
+		if l := len(key); l != 16 && l != 24 && l != 32 {
+			return fmt.Errorf("the encryption key %s should be 16, 24 or 32 bytes, but got %d", id, l)
+		}
+		keys[id] = key
+	}
+	if _, ok := keys[file.Current]; !ok {
+		return fmt.Errorf("the current encryption key %q is not found", file.Current)
+	}
+
+	p.mutex.Lock()
+	p.current = file.Current
+	p.keys = keys
+	p.modTime = modTime
+	p.checkedAt = time.Now()
+	p.mutex.Unlock()
+	return nil
+}
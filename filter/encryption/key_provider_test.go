@@ -0,0 +1,113 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encryption
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+const (
+	key1 = "MDEyMzQ1Njc4OWFiY2RlZg=="                     // 0123456789abcdef
+	key2 = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=" // 0123456789abcdef0123456789abcdef
+)
+
+func writeKeyFile(t *testing.T, path, content string, modTime time.Time) {
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestFileKeyProviderReloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	modTime := time.Now().Add(-time.Hour)
+	writeKeyFile(t, path, "current: k1\nkeys:\n  k1: "+key1+"\n", modTime)
+
+	p, err := NewFileKeyProvider(path)
+	require.NoError(t, err)
+	id, key, err := p.CurrentKey()
+	require.NoError(t, err)
+	assert.Equal(t, "k1", id)
+	assert.Equal(t, []byte("0123456789abcdef"), key)
+	_, err = p.GetKey("k2")
+	assert.Error(t, err)
+
+	// rotate to k2
+	writeKeyFile(t, path, "current: k2\nkeys:\n  k1: "+key1+"\n  k2: "+key2+"\n", modTime.Add(time.Minute))
+	id, _, _ = p.CurrentKey()
+	assert.Equal(t, "k1", id, "the file is not checked again within the interval")
+	p.checkedAt = time.Time{}
+	id, key, err = p.CurrentKey()
+	require.NoError(t, err)
+	assert.Equal(t, "k2", id)
+	assert.Equal(t, []byte("0123456789abcdef0123456789abcdef"), key)
+	key, err = p.GetKey("k1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("0123456789abcdef"), key)
+
+	// the loaded keys are kept if the file is broken
+	writeKeyFile(t, path, "current: k3\nkeys:\n  k2: "+key2+"\n", modTime.Add(2*time.Minute))
+	p.checkedAt = time.Time{}
+	id, _, err = p.CurrentKey()
+	require.NoError(t, err)
+	assert.Equal(t, "k2", id)
+}
+
+func TestFileKeyProviderInvalidKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "current key missing", content: "current: k2\nkeys:\n  k1: " + key1 + "\n"},
+		{name: "not base64", content: "current: k1\nkeys:\n  k1: '!!'\n"},
+		{name: "wrong length", content: "current: k1\nkeys:\n  k1: MDEyMzQ1Njc=\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "keys.yaml")
+			writeKeyFile(t, path, tt.content, time.Now())
+			_, err := NewFileKeyProvider(path)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewFileKeyProviderSharesByPath(t *testing.T) {
+	_, err := newFileKeyProvider(common.NewURLWithOptions())
+	assert.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	writeKeyFile(t, path, "current: k1\nkeys:\n  k1: "+key1+"\n", time.Now())
+	url := common.NewURLWithOptions(common.WithParamsValue(constant.EncryptionKeyFileKey, path))
+	p1, err := newFileKeyProvider(url)
+	require.NoError(t, err)
+	p2, err := newFileKeyProvider(url.Clone())
+	require.NoError(t, err)
+	assert.Same(t, p1, p2)
+}
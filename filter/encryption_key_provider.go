@@ -0,0 +1,39 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+)
+
+// EncryptionKeyProvider provides the AES keys of the payload encryption filters. Every key is identified
+// by an id, which is carried along with the encrypted payload, so the keys can be rotated without downtime:
+// distribute the new key to both sides first, then make it the current one, and retire the old key after
+// no payload encrypted with it is in flight.
+//
+// please register your implementation by invoking SetEncryptionKeyProvider
+type EncryptionKeyProvider interface {
+	// CurrentKey returns the id and the key to encrypt the new payloads with.
+	CurrentKey() (string, []byte, error)
+	// GetKey returns the key with id to decrypt a payload.
+	GetKey(id string) ([]byte, error)
+}
+
+// EncryptionKeyProviderCreator creates the EncryptionKeyProvider configured by the url, the implementation
+// may share the provider among the urls with the same configuration.
+type EncryptionKeyProviderCreator func(url *common.URL) (EncryptionKeyProvider, error)
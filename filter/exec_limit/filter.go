@@ -33,6 +33,8 @@
     - name: "DeleteUser"
       execute.limit.rejected.handle: "customHandler" # Using the custom handler to do something when the request was rejected.
     - name: "AddUser"
+   params:
+     criticality.shedding: "true" # reject the less critical requests first, see common/criticality
  From the example, the configuration in service-level is 200, and the configuration of method GetUser is 20.
  it means that, the GetUser will be counted separately.
  The configuration of method UpdateUser is -1, so the invocation for it will not be counted.
@@ -57,6 +59,7 @@ import (
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/criticality"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/filter"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/handler"
@@ -126,7 +129,8 @@ func (f *executeLimitFilter) Invoke(ctx context.Context, invoker base.Invoker, i
 
 	concurrentCount := state.(*ExecuteState).increase()
 	defer state.(*ExecuteState).decrease()
-	if concurrentCount > limitRate {
+	// the less critical invocations are rejected first as the limitation is approached
+	if concurrentCount > limitRate || !criticality.Admit(ivkURL, invocation, uint64(concurrentCount), uint64(limitRate)) {
 		logger.Errorf("The invocation was rejected due to over the execute limitation, url: %s ", ivkURL.String())
 		rejectedHandlerConfig := ivkURL.GetParam(methodConfigPrefix+constant.ExecuteRejectedExecutionHandlerKey,
 			ivkURL.GetParam(constant.ExecuteRejectedExecutionHandlerKey, constant.DefaultKey))
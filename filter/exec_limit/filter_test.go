@@ -19,19 +19,27 @@ package exec_limit
 
 import (
 	"context"
+	"errors"
 	"net/url"
 	"testing"
 )
 
 import (
+	"github.com/golang/mock/gomock"
+
 	"github.com/stretchr/testify/assert"
 )
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/criticality"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/filter/handler"
 	"dubbo.apache.org/dubbo-go/v3/protocol/base"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
 )
 
 func TestFilterInvokeIgnored(t *testing.T) {
@@ -83,3 +91,32 @@ func TestFilterInvoke(t *testing.T) {
 	assert.NotNil(t, result)
 	assert.Nil(t, result.Error())
 }
+
+func TestFilterInvokeShedding(t *testing.T) {
+	methodName := "hello2"
+	invokeUrl := common.NewURLWithOptions(
+		common.WithParams(url.Values{}),
+		common.WithParamsValue(constant.InterfaceKey, methodName),
+		common.WithParamsValue(constant.ExecuteLimitKey, "10"),
+		common.WithParamsValue(constant.CriticalitySheddingKey, "true"),
+		common.WithParamsValue(constant.ExecuteRejectedExecutionHandlerKey, "shedding-test"),
+	)
+	ctrl := gomock.NewController(t)
+	rejectedHandler := handler.NewMockRejectedExecutionHandler(ctrl)
+	rejectedHandler.EXPECT().RejectedExecution(gomock.Any(), gomock.Any()).
+		Return(&result.RPCResult{Err: errors.New("rejected")}).Times(1)
+	extension.SetRejectedExecutionHandler("shedding-test", func() filter.RejectedExecutionHandler {
+		return rejectedHandler
+	})
+	limitFilter := newFilter().(*executeLimitFilter)
+	// 8 invocations are in progress
+	limitFilter.executeState.Store(invokeUrl.ServiceKey(), &ExecuteState{concurrentCount: 8})
+
+	invoke := func(level string) error {
+		invoc := invocation.NewRPCInvocation(methodName, []any{"OK"}, map[string]any{constant.CriticalityKey: level})
+		return limitFilter.Invoke(context.Background(), base.NewBaseInvoker(invokeUrl), invoc).Error()
+	}
+	assert.NotNil(t, invoke(criticality.Sheddable))
+	assert.Nil(t, invoke(criticality.Default))
+	assert.Nil(t, invoke(criticality.Critical))
+}
@@ -0,0 +1,134 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package fault provides consumer and provider filters injecting delays and errors for chaos testing.
+/*
+ The rules are read from the config center with the key "{application}.fault-injection"
+ and take effect as soon as they are changed, for example:
+
+ enabled: true
+ faults:
+   - service: "com.ikurento.user.UserProvider"
+     methods: ["GetUser"]
+     side: "provider"          # consumer, provider or empty for both
+     attachments:
+       x-game-day: "2024-q3"   # only requests carrying this attachment are affected
+     delay:
+       percentage: 30          # delay 30% of the matched requests
+       fixed: 200ms
+     abort:
+       percentage: 5           # fail 5% of the matched requests
+       code: 14                # triple code, 14 is unavailable
+       message: "game day"
+
+ Instead of a fixed delay, "percentiles" can describe a latency distribution to sample from:
+
+     delay:
+       percentage: 100
+       percentiles:
+         - {percentile: 50, delay: 20ms}
+         - {percentile: 99, delay: 1s}
+
+ Affected invocations carry the constant.FaultInjectedKey attribute. They are counted by the rpc metrics as usual,
+ and by the fault injected counter labelled with the fault type, so injected faults can be told apart from real ones.
+ It is recorded by the access log as well.
+*/
+package fault
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/config_center/rule"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+)
+
+var (
+	consumerOnce   sync.Once
+	consumerFilter *Filter
+	providerOnce   sync.Once
+	providerFilter *Filter
+
+	rules = rule.NewWatcher("fault injection rules", constant.FaultInjectionRuleSuffix, parseRuleSet)
+)
+
+func init() {
+	extension.SetFilter(constant.FaultInjectionConsumerFilterKey, newConsumerFilter)
+	extension.SetFilter(constant.FaultInjectionProviderFilterKey, newProviderFilter)
+}
+
+// Filter injects the faults configured for its side
+type Filter struct {
+	side string
+}
+
+func newConsumerFilter() filter.Filter {
+	consumerOnce.Do(func() {
+		consumerFilter = &Filter{side: constant.SideConsumer}
+	})
+	return consumerFilter
+}
+
+func newProviderFilter() filter.Filter {
+	providerOnce.Do(func() {
+		providerFilter = &Filter{side: constant.SideProvider}
+	})
+	return providerFilter
+}
+
+// Invoke delays or aborts the invocation if it matches a fault rule
+func (f *Filter) Invoke(ctx context.Context, invoker base.Invoker, invocation base.Invocation) result.Result {
+	url := invoker.GetURL()
+	rs := rules.Get(url.GetParam(constant.ApplicationKey, ""))
+	rule := rs.match(f.side, url.Service(), invocation)
+	if rule == nil {
+		return invoker.Invoke(ctx, invocation)
+	}
+
+	if d := rule.Delay.delay(); d > 0 {
+		invocation.SetAttribute(constant.FaultInjectedKey, TypeDelay)
+		timer := time.NewTimer(d)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return &result.RPCResult{Err: ctx.Err()}
+		}
+	}
+	if err := rule.Abort.abort(); err != nil {
+		invocation.SetAttribute(constant.FaultInjectedKey, TypeAbort)
+		res := &result.RPCResult{Err: err}
+		res.AddAttachment(constant.FaultInjectedKey, TypeAbort)
+		return res
+	}
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse marks the result of a delayed invocation
+func (f *Filter) OnResponse(_ context.Context, res result.Result, _ base.Invoker, invocation base.Invocation) result.Result {
+	if typ, ok := invocation.GetAttribute(constant.FaultInjectedKey); ok && typ == TypeDelay {
+		res.AddAttachment(constant.FaultInjectedKey, TypeDelay)
+	}
+	return res
+}
@@ -0,0 +1,129 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fault
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+const testRules = `
+faults:
+  - service: "com.ikurento.user.UserProvider"
+    methods: ["GetUser"]
+    side: "provider"
+    attachments:
+      x-game-day: "on"
+    delay:
+      percentage: 100
+      fixed: 20ms
+    abort:
+      percentage: 100
+      code: 14
+      message: "game day"
+  - service: "com.ikurento.user.UserProvider"
+    methods: ["ListUsers"]
+    delay:
+      percentage: 100
+      fixed: 20ms
+`
+
+func newInvoker(application string) base.Invoker {
+	return base.NewBaseInvoker(common.NewURLWithOptions(
+		common.WithPath("com.ikurento.user.UserProvider"),
+		common.WithParams(url.Values{}),
+		common.WithParamsValue(constant.InterfaceKey, "com.ikurento.user.UserProvider"),
+		common.WithParamsValue(constant.ApplicationKey, application)))
+}
+
+func publishRules(t *testing.T, application, content string) {
+	rules.Process(&config_center.ConfigChangeEvent{Key: rules.Key(application), Value: content, ConfigType: remoting.EventTypeAdd})
+	t.Cleanup(func() {
+		rules.Process(&config_center.ConfigChangeEvent{Key: rules.Key(application), ConfigType: remoting.EventTypeDel})
+	})
+}
+
+func TestFilterAbort(t *testing.T) {
+	publishRules(t, "abort-app", testRules)
+	provider := newProviderFilter()
+	invoker := newInvoker("abort-app")
+
+	inv := invocation.NewRPCInvocation("GetUser", nil, map[string]any{"x-game-day": []string{"on"}})
+	start := time.Now()
+	res := provider.Invoke(context.Background(), invoker, inv)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	assert.Equal(t, triple_protocol.CodeUnavailable, triple_protocol.CodeOf(res.Error()))
+	assert.Equal(t, TypeAbort, res.Attachment(constant.FaultInjectedKey, ""))
+	fault, _ := inv.GetAttribute(constant.FaultInjectedKey)
+	assert.Equal(t, TypeAbort, fault)
+
+	// attachments do not match
+	inv = invocation.NewRPCInvocation("GetUser", nil, map[string]any{"x-game-day": "off"})
+	res = provider.Invoke(context.Background(), invoker, inv)
+	assert.Nil(t, res.Error())
+	_, ok := inv.GetAttribute(constant.FaultInjectedKey)
+	assert.False(t, ok)
+
+	// the first rule is for provider only
+	inv = invocation.NewRPCInvocation("GetUser", nil, map[string]any{"x-game-day": "on"})
+	res = newConsumerFilter().Invoke(context.Background(), invoker, inv)
+	assert.Nil(t, res.Error())
+}
+
+func TestFilterDelay(t *testing.T) {
+	publishRules(t, "delay-app", testRules)
+	consumer := newConsumerFilter()
+	invoker := newInvoker("delay-app")
+
+	inv := invocation.NewRPCInvocation("ListUsers", nil, nil)
+	start := time.Now()
+	res := consumer.Invoke(context.Background(), invoker, inv)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	assert.Nil(t, res.Error())
+	res = consumer.OnResponse(context.Background(), res, invoker, inv)
+	assert.Equal(t, TypeDelay, res.Attachment(constant.FaultInjectedKey, ""))
+
+	// the delay is interrupted once the caller gives up
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	res = consumer.Invoke(ctx, invoker, invocation.NewRPCInvocation("ListUsers", nil, nil))
+	assert.ErrorIs(t, res.Error(), context.DeadlineExceeded)
+}
+
+func TestFilterDisabledRules(t *testing.T) {
+	publishRules(t, "disabled-app", "enabled: false\n"+testRules)
+	res := newConsumerFilter().Invoke(context.Background(), newInvoker("disabled-app"), invocation.NewRPCInvocation("ListUsers", nil, nil))
+	assert.Nil(t, res.Error())
+	assert.Empty(t, res.Attachment(constant.FaultInjectedKey, ""))
+}
@@ -0,0 +1,216 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fault
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+import (
+	"gopkg.in/yaml.v2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+)
+
+const (
+	// TypeDelay marks an invocation which has been delayed by the fault filter.
+	TypeDelay = "delay"
+	// TypeAbort marks an invocation which has been aborted by the fault filter.
+	TypeAbort = "abort"
+)
+
+// randFloat returns a pseudo-random number in [0.0, 100.0), it is a variable for testing.
+var randFloat = func() float64 {
+	return rand.Float64() * 100
+}
+
+// RuleSet is the fault injection configuration of an application, which is published
+// to the config center under the key "{application}.fault-injection".
+type RuleSet struct {
+	Enabled *bool   `yaml:"enabled"`
+	Faults  []*Rule `yaml:"faults"`
+}
+
+// Rule describes which invocations are affected and which faults are injected.
+type Rule struct {
+	// Service is the interface name, empty or "*" matches any service.
+	Service string `yaml:"service"`
+	// Methods are the method names, empty or "*" matches any method.
+	Methods []string `yaml:"methods"`
+	// Side is "consumer" or "provider", empty matches both sides.
+	Side string `yaml:"side"`
+	// Attachments must all be present in the invocation with exactly the same values.
+	Attachments map[string]string `yaml:"attachments"`
+	Delay       *Delay            `yaml:"delay"`
+	Abort       *Abort            `yaml:"abort"`
+}
+
+// Delay delays the matched invocations before they are performed.
+type Delay struct {
+	// Percentage of the matched invocations to delay, in [0, 100].
+	Percentage float64 `yaml:"percentage"`
+	// Fixed is the delay applied when Percentiles is empty.
+	Fixed time.Duration `yaml:"fixed"`
+	// Percentiles describe a latency distribution the delay is sampled from,
+	// e.g. p50=20ms and p99=1s. Delays between two points are linearly interpolated.
+	Percentiles []*Percentile `yaml:"percentiles"`
+}
+
+// Percentile is a point of a latency distribution.
+type Percentile struct {
+	Percentile float64       `yaml:"percentile"`
+	Delay      time.Duration `yaml:"delay"`
+}
+
+// Abort fails the matched invocations with the configured error code instead of performing them.
+type Abort struct {
+	// Percentage of the matched invocations to abort, in [0, 100].
+	Percentage float64 `yaml:"percentage"`
+	// Code is a triple error code, e.g. 14 for unavailable. CodeInternal is used if it is unset.
+	Code uint32 `yaml:"code"`
+	// Message is the error message returned to the caller.
+	Message string `yaml:"message"`
+}
+
+func parseRuleSet(content string) (*RuleSet, error) {
+	rs := &RuleSet{}
+	if err := yaml.Unmarshal([]byte(content), rs); err != nil {
+		return nil, err
+	}
+	for _, r := range rs.Faults {
+		if r.Delay != nil {
+			sort.Slice(r.Delay.Percentiles, func(i, j int) bool {
+				return r.Delay.Percentiles[i].Percentile < r.Delay.Percentiles[j].Percentile
+			})
+		}
+	}
+	return rs, nil
+}
+
+func (rs *RuleSet) enabled() bool {
+	return rs != nil && (rs.Enabled == nil || *rs.Enabled)
+}
+
+// match returns the first rule matching the invocation on the given side.
+func (rs *RuleSet) match(side, service string, invocation base.Invocation) *Rule {
+	if !rs.enabled() {
+		return nil
+	}
+	for _, r := range rs.Faults {
+		if r.matches(side, service, invocation) {
+			return r
+		}
+	}
+	return nil
+}
+
+func (r *Rule) matches(side, service string, invocation base.Invocation) bool {
+	if r.Side != "" && !strings.EqualFold(r.Side, side) {
+		return false
+	}
+	if r.Service != "" && r.Service != "*" && r.Service != service {
+		return false
+	}
+	if len(r.Methods) > 0 && !matchMethod(r.Methods, invocation.MethodName()) {
+		return false
+	}
+	for k, want := range r.Attachments {
+		if !matchAttachment(invocation.GetAttachmentInterface(k), want) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == "*" || m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAttachment compares an attachment value, which is a string for dubbo
+// protocol and a []string for triple protocol, with the wanted value.
+func matchAttachment(value any, want string) bool {
+	switch v := value.(type) {
+	case string:
+		return v == want
+	case []string:
+		for _, s := range v {
+			if s == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// delay returns how long to delay the invocation, zero means no delay.
+func (d *Delay) delay() time.Duration {
+	if d == nil || !hit(d.Percentage) {
+		return 0
+	}
+	if len(d.Percentiles) == 0 {
+		return d.Fixed
+	}
+	return sampleDelay(d.Percentiles, randFloat())
+}
+
+// sampleDelay returns the delay of the given percentile p of the distribution described by points.
+func sampleDelay(points []*Percentile, p float64) time.Duration {
+	if p <= points[0].Percentile {
+		return points[0].Delay
+	}
+	for i := 1; i < len(points); i++ {
+		lo, hi := points[i-1], points[i]
+		if p <= hi.Percentile {
+			ratio := (p - lo.Percentile) / (hi.Percentile - lo.Percentile)
+			return lo.Delay + time.Duration(ratio*float64(hi.Delay-lo.Delay))
+		}
+	}
+	return points[len(points)-1].Delay
+}
+
+// abort returns the error to fail the invocation with, nil means no abort.
+func (a *Abort) abort() error {
+	if a == nil || !hit(a.Percentage) {
+		return nil
+	}
+	code := triple_protocol.Code(a.Code)
+	if code == 0 {
+		code = triple_protocol.CodeInternal
+	}
+	msg := a.Message
+	if msg == "" {
+		msg = "fault injected"
+	}
+	return triple_protocol.NewError(code, errors.New(msg))
+}
+
+func hit(percentage float64) bool {
+	return percentage > 0 && randFloat() < percentage
+}
@@ -0,0 +1,76 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fault
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRuleSet(t *testing.T) {
+	rs, err := parseRuleSet(`
+faults:
+  - methods: ["*"]
+    delay:
+      percentage: 50
+      percentiles:
+        - {percentile: 99, delay: 1s}
+        - {percentile: 50, delay: 20ms}
+`)
+	require.Nil(t, err)
+	assert.True(t, rs.enabled())
+	require.Len(t, rs.Faults, 1)
+	delay := rs.Faults[0].Delay
+	assert.Equal(t, 50.0, delay.Percentage)
+	// percentiles are sorted
+	assert.Equal(t, 20*time.Millisecond, delay.Percentiles[0].Delay)
+	assert.Equal(t, time.Second, delay.Percentiles[1].Delay)
+
+	_, err = parseRuleSet("faults: {")
+	assert.NotNil(t, err)
+}
+
+func TestSampleDelay(t *testing.T) {
+	points := []*Percentile{
+		{Percentile: 50, Delay: 100 * time.Millisecond},
+		{Percentile: 90, Delay: 500 * time.Millisecond},
+	}
+	assert.Equal(t, 100*time.Millisecond, sampleDelay(points, 10))
+	assert.Equal(t, 300*time.Millisecond, sampleDelay(points, 70))
+	assert.Equal(t, 500*time.Millisecond, sampleDelay(points, 99))
+}
+
+func TestPercentage(t *testing.T) {
+	defer func(origin func() float64) { randFloat = origin }(randFloat)
+	randFloat = func() float64 { return 40 }
+
+	d := &Delay{Percentage: 30, Fixed: time.Second}
+	assert.Zero(t, d.delay())
+	d.Percentage = 50
+	assert.Equal(t, time.Second, d.delay())
+
+	a := &Abort{Percentage: 30}
+	assert.Nil(t, a.abort())
+	a.Percentage = 100
+	assert.NotNil(t, a.abort())
+}
@@ -0,0 +1,185 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package idempotent provides the filters deduplicating the invocations retried by the cluster.
+/*
+ The consumer filter attaches a generated idempotency key to the invocations of the methods marked as
+ idempotent, and keeps the key when the cluster retries the invocation on another provider. The provider
+ filter runs the first invocation with a key and replays its result to the later ones, for example:
+
+ references:
+   "UserProvider":
+     interface: "com.ikurento.user.UserProvider"
+     cluster: "failover"
+     methods:
+       - name: "CreateUser"
+         idempotent: true   # the idempotent_consumer filter is appended automatically
+
+ services:
+   "UserProvider":
+     interface: "com.ikurento.user.UserProvider"
+     idempotent.store: "memory"   # the name of the IdempotentStore, "memory" by default
+     idempotent.ttl: "10m"        # how long a result is kept for replaying
+     methods:
+       - name: "CreateUser"
+         idempotent: true   # the idempotent_provider filter is appended automatically
+
+ Only successful results are kept; a failed invocation releases its key, so that a retry runs the method
+ again. A duplicate arriving while the first invocation is still running is rejected with an aborted error.
+ The in-memory store only deduplicates the retries reaching the same provider instance. Register a shared
+ implementation by extension.SetIdempotentStore to deduplicate across instances.
+*/
+package idempotent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+
+	"github.com/google/uuid"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/idempotent/store"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	triple_protocol "dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+)
+
+var (
+	consumerOnce   sync.Once
+	consumerFilter *idempotentConsumerFilter
+	providerOnce   sync.Once
+	providerFilter *idempotentProviderFilter
+)
+
+func init() {
+	extension.SetFilter(constant.IdempotentConsumerFilterKey, newConsumerFilter)
+	extension.SetFilter(constant.IdempotentProviderFilterKey, newProviderFilter)
+}
+
+type idempotentConsumerFilter struct{}
+
+func newConsumerFilter() filter.Filter {
+	consumerOnce.Do(func() {
+		consumerFilter = &idempotentConsumerFilter{}
+	})
+	return consumerFilter
+}
+
+// Invoke attaches an idempotency key to the invocation of an idempotent method.
+// The invocation is shared by the retries of the cluster, so the key is only generated once.
+func (f *idempotentConsumerFilter) Invoke(ctx context.Context, invoker base.Invoker, invocation base.Invocation) result.Result {
+	url := invoker.GetURL()
+	method := invocation.MethodName()
+	if !url.GetMethodParamBool(method, constant.IdempotentKey, url.GetParamBool(constant.IdempotentKey, false)) {
+		return invoker.Invoke(ctx, invocation)
+	}
+	if key, ok := invocation.GetAttachment(constant.IdempotencyKeyAttachmentKey); !ok || key == "" {
+		invocation.SetAttachment(constant.IdempotencyKeyAttachmentKey, uuid.NewString())
+	}
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *idempotentConsumerFilter) OnResponse(_ context.Context, res result.Result, _ base.Invoker, _ base.Invocation) result.Result {
+	return res
+}
+
+type idempotentProviderFilter struct{}
+
+func newProviderFilter() filter.Filter {
+	providerOnce.Do(func() {
+		providerFilter = &idempotentProviderFilter{}
+	})
+	return providerFilter
+}
+
+// Invoke runs the first invocation with an idempotency key and replays its result to the duplicates
+func (f *idempotentProviderFilter) Invoke(ctx context.Context, invoker base.Invoker, invocation base.Invocation) result.Result {
+	key, ok := invocation.GetAttachment(constant.IdempotencyKeyAttachmentKey)
+	if !ok || key == "" {
+		return invoker.Invoke(ctx, invocation)
+	}
+
+	url := invoker.GetURL()
+	storeName := url.GetParam(constant.IdempotentStoreKey, constant.DefaultKey)
+	store, err := extension.GetIdempotentStore(storeName)
+	if err != nil {
+		logger.Errorf("[Idempotent filter] Can not get the IdempotentStore %s, the invocation is not deduplicated: %v",
+			storeName, err)
+		return invoker.Invoke(ctx, invocation)
+	}
+	ttl := url.GetParamDuration(constant.IdempotentTTLKey, constant.DefaultIdempotentTTL)
+	storeKey := url.ServiceKey() + "#" + invocation.MethodName() + "#" + key
+
+	existing, reserved := store.Reserve(storeKey, ttl)
+	if !reserved {
+		return replay(key, existing)
+	}
+
+	completed := false
+	defer func() {
+		// release the key if the invoker panics, so that the retry can run the method
+		if !completed {
+			store.Release(storeKey)
+		}
+	}()
+	res := invoker.Invoke(ctx, invocation)
+	if res.Error() != nil {
+		store.Release(storeKey)
+	} else {
+		store.Complete(storeKey, &filter.IdempotentRecord{
+			Result:      res.Result(),
+			Attachments: copyAttachments(res.Attachments()),
+		}, ttl)
+	}
+	completed = true
+	return res
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *idempotentProviderFilter) OnResponse(_ context.Context, res result.Result, _ base.Invoker, _ base.Invocation) result.Result {
+	return res
+}
+
+func replay(key string, record *filter.IdempotentRecord) result.Result {
+	if record == nil || record.InProgress {
+		return &result.RPCResult{Err: triple_protocol.NewError(triple_protocol.CodeAborted,
+			fmt.Errorf("the invocation with idempotency key %s is in progress", key))}
+	}
+	res := &result.RPCResult{}
+	res.SetResult(record.Result)
+	res.SetAttachments(copyAttachments(record.Attachments))
+	res.AddAttachment(constant.IdempotentReplayedKey, "true")
+	return res
+}
+
+func copyAttachments(attachments map[string]any) map[string]any {
+	copied := make(map[string]any, len(attachments))
+	for k, v := range attachments {
+		copied[k] = v
+	}
+	return copied
+}
@@ -0,0 +1,162 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package idempotent
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync/atomic"
+	"testing"
+)
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+)
+
+type countingInvoker struct {
+	*base.BaseInvoker
+	calls atomic.Int32
+	err   error
+	// started is signaled when an invocation blocks on block
+	started chan struct{}
+	block   chan struct{}
+}
+
+func newCountingInvoker(params url.Values) *countingInvoker {
+	return &countingInvoker{BaseInvoker: base.NewBaseInvoker(common.NewURLWithOptions(
+		common.WithPath("com.ikurento.user.UserProvider"),
+		common.WithParams(params),
+		common.WithParamsValue(constant.InterfaceKey, "com.ikurento.user.UserProvider")))}
+}
+
+func (i *countingInvoker) Invoke(_ context.Context, _ base.Invocation) result.Result {
+	n := i.calls.Add(1)
+	if i.block != nil {
+		i.started <- struct{}{}
+		<-i.block
+	}
+	if i.err != nil {
+		return &result.RPCResult{Err: i.err}
+	}
+	res := &result.RPCResult{}
+	res.SetResult(n)
+	res.AddAttachment("call", n)
+	return res
+}
+
+func TestConsumerFilterKeepsKeyAcrossRetries(t *testing.T) {
+	params := url.Values{}
+	params.Set("methods.CreateUser."+constant.IdempotentKey, "true")
+	invoker := newCountingInvoker(params)
+	consumer := newConsumerFilter()
+
+	inv := invocation.NewRPCInvocation("CreateUser", nil, nil)
+	consumer.Invoke(context.Background(), invoker, inv)
+	key, ok := inv.GetAttachment(constant.IdempotencyKeyAttachmentKey)
+	assert.True(t, ok)
+	assert.NotEmpty(t, key)
+
+	// the cluster retries with the same invocation
+	consumer.Invoke(context.Background(), invoker, inv)
+	retryKey, _ := inv.GetAttachment(constant.IdempotencyKeyAttachmentKey)
+	assert.Equal(t, key, retryKey)
+
+	other := invocation.NewRPCInvocation("GetUser", nil, nil)
+	consumer.Invoke(context.Background(), invoker, other)
+	_, ok = other.GetAttachment(constant.IdempotencyKeyAttachmentKey)
+	assert.False(t, ok)
+}
+
+func TestProviderFilterReplay(t *testing.T) {
+	invoker := newCountingInvoker(url.Values{})
+	provider := newProviderFilter()
+
+	newInv := func(key string) base.Invocation {
+		return invocation.NewRPCInvocation("CreateUser", nil, map[string]any{
+			constant.IdempotencyKeyAttachmentKey: []string{key},
+		})
+	}
+
+	key := uuid.NewString()
+	first := provider.Invoke(context.Background(), invoker, newInv(key))
+	assert.Nil(t, first.Error())
+	assert.Equal(t, int32(1), first.Result())
+
+	second := provider.Invoke(context.Background(), invoker, newInv(key))
+	assert.Nil(t, second.Error())
+	assert.Equal(t, int32(1), second.Result())
+	assert.Equal(t, int32(1), second.Attachment("call", nil))
+	assert.Equal(t, "true", second.Attachment(constant.IdempotentReplayedKey, ""))
+	assert.Equal(t, int32(1), invoker.calls.Load())
+
+	third := provider.Invoke(context.Background(), invoker, newInv(uuid.NewString()))
+	assert.Equal(t, int32(2), third.Result())
+
+	// invocations without a key are not deduplicated
+	provider.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("CreateUser", nil, nil))
+	provider.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("CreateUser", nil, nil))
+	assert.Equal(t, int32(4), invoker.calls.Load())
+}
+
+func TestProviderFilterReleasesFailure(t *testing.T) {
+	invoker := newCountingInvoker(url.Values{})
+	invoker.err = errors.New("db unavailable")
+	provider := newProviderFilter()
+	attachments := map[string]any{constant.IdempotencyKeyAttachmentKey: uuid.NewString()}
+
+	res := provider.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("CreateUser", nil, attachments))
+	assert.NotNil(t, res.Error())
+
+	invoker.err = nil
+	res = provider.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("CreateUser", nil, attachments))
+	assert.Nil(t, res.Error())
+	assert.Equal(t, int32(2), invoker.calls.Load())
+}
+
+func TestProviderFilterInProgress(t *testing.T) {
+	invoker := newCountingInvoker(url.Values{})
+	invoker.started = make(chan struct{}, 1)
+	invoker.block = make(chan struct{})
+	provider := newProviderFilter()
+	attachments := map[string]any{constant.IdempotencyKeyAttachmentKey: uuid.NewString()}
+
+	done := make(chan result.Result)
+	go func() {
+		done <- provider.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("CreateUser", nil, attachments))
+	}()
+	<-invoker.started
+
+	res := provider.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("CreateUser", nil, attachments))
+	assert.Equal(t, triple_protocol.CodeAborted, triple_protocol.CodeOf(res.Error()))
+
+	close(invoker.block)
+	assert.Nil(t, (<-done).Error())
+	assert.Equal(t, int32(1), invoker.calls.Load())
+}
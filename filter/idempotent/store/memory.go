@@ -0,0 +1,115 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package store provides the built-in IdempotentStore implementations.
+package store
+
+import (
+	"sync"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+)
+
+const (
+	memoryName = "memory"
+	// sweepInterval is the number of reservations between two sweeps of the expired records.
+	sweepInterval = 1024
+)
+
+var (
+	memoryOnce  sync.Once
+	memoryStore *MemoryStore
+)
+
+func init() {
+	extension.SetIdempotentStore(constant.DefaultKey, GetMemoryStore)
+	extension.SetIdempotentStore(memoryName, GetMemoryStore)
+}
+
+// MemoryStore is an IdempotentStore in the memory of the current process.
+// It only deduplicates the retries which reach the same provider instance.
+type MemoryStore struct {
+	mu       sync.Mutex
+	records  map[string]*memoryRecord
+	reserved int
+}
+
+type memoryRecord struct {
+	*filter.IdempotentRecord
+	expireAt time.Time
+}
+
+// GetMemoryStore returns the singleton MemoryStore
+func GetMemoryStore() filter.IdempotentStore {
+	memoryOnce.Do(func() {
+		memoryStore = NewMemoryStore()
+	})
+	return memoryStore
+}
+
+// NewMemoryStore creates an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]*memoryRecord)}
+}
+
+// Reserve records an in-progress marker for key if it is absent or expired
+func (s *MemoryStore) Reserve(key string, ttl time.Duration) (*filter.IdempotentRecord, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.reserved++
+	if s.reserved%sweepInterval == 0 {
+		s.sweep(now)
+	}
+	if existing, ok := s.records[key]; ok && now.Before(existing.expireAt) {
+		return existing.IdempotentRecord, false
+	}
+	s.records[key] = &memoryRecord{
+		IdempotentRecord: &filter.IdempotentRecord{InProgress: true},
+		expireAt:         now.Add(ttl),
+	}
+	return nil, true
+}
+
+// Complete stores the final record of key
+func (s *MemoryStore) Complete(key string, record *filter.IdempotentRecord, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[key] = &memoryRecord{IdempotentRecord: record, expireAt: time.Now().Add(ttl)}
+}
+
+// Release removes key
+func (s *MemoryStore) Release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+}
+
+// sweep removes the expired records, the caller must hold the lock
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, record := range s.records {
+		if !now.Before(record.expireAt) {
+			delete(s.records, key)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/filter"
+)
+
+func TestMemoryStore(t *testing.T) {
+	s := NewMemoryStore()
+
+	existing, ok := s.Reserve("k", time.Minute)
+	assert.True(t, ok)
+	assert.Nil(t, existing)
+
+	existing, ok = s.Reserve("k", time.Minute)
+	assert.False(t, ok)
+	assert.True(t, existing.InProgress)
+
+	s.Complete("k", &filter.IdempotentRecord{Result: "done"}, time.Minute)
+	existing, ok = s.Reserve("k", time.Minute)
+	assert.False(t, ok)
+	assert.Equal(t, "done", existing.Result)
+
+	s.Release("k")
+	_, ok = s.Reserve("k", time.Minute)
+	assert.True(t, ok)
+}
+
+func TestMemoryStoreExpire(t *testing.T) {
+	s := NewMemoryStore()
+	s.Complete("k", &filter.IdempotentRecord{Result: "done"}, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	_, ok := s.Reserve("k", time.Minute)
+	assert.True(t, ok)
+
+	s.Complete("expired", &filter.IdempotentRecord{}, -time.Second)
+	for i := 0; i < sweepInterval; i++ {
+		s.Reserve("other", time.Minute)
+	}
+	assert.NotContains(t, s.records, "expired")
+}
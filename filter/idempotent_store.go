@@ -0,0 +1,45 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter
+
+import (
+	"time"
+)
+
+// IdempotentStore keeps the outcome of invocations carrying an idempotency key, so that
+// invocations duplicated by cluster retries can be answered without running the handler again.
+//
+// Implementations shared by several provider instances must be able to serialize
+// IdempotentRecord.Result, which is the reply of the service method.
+type IdempotentStore interface {
+	// Reserve records an in-progress marker for key if it is absent and returns true.
+	// Otherwise, it returns false together with the existing record.
+	Reserve(key string, ttl time.Duration) (*IdempotentRecord, bool)
+	// Complete replaces the in-progress marker of key with the final record.
+	Complete(key string, record *IdempotentRecord, ttl time.Duration)
+	// Release removes key, so that the next invocation with it will be performed.
+	Release(key string)
+}
+
+// IdempotentRecord is the outcome of an invocation stored in an IdempotentStore.
+type IdempotentRecord struct {
+	// InProgress is true while the first invocation with the key is still running.
+	InProgress  bool
+	Result      any
+	Attachments map[string]any
+}
@@ -0,0 +1,202 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package servertimeout provides the provider filter giving up the invocations beyond their deadlines.
+/*
+ The deadline of an invocation is the earliest of
+   - the timeout attached by the caller, which is the "timeout" of the reference or the call,
+   - the absolute deadline attached by the caller as "deadline", in unix milliseconds,
+   - the "server.timeout" of the method or service on the provider, the "timeout" of a method
+     of the service is its "server.timeout",
+   - the deadline of the context, e.g. the one carried by the headers of triple.
+
+ services:
+   "UserProvider":
+     interface: "com.ikurento.user.UserProvider"
+     filter: "server_timeout"
+     params:
+       server.timeout: "3s"
+     methods:
+       - name: "GetUser"
+         timeout: "500ms"
+
+ The timeouts of the provider are not taken by the consumers as theirs, unlike the "timeout" of the service.
+
+ The invocation is run with a context cancelled at the deadline, which is cancelled as well once the
+ caller cancels the call, like a triple consumer resetting the stream. A handler still running then is
+ abandoned, and the caller gets a deadline-exceeded or canceled error right away. The abandoned handlers
+ are counted by dubbo_provider_abandoned_requests_total, and the ones still running are counted by
+ dubbo_provider_abandoned_requests_running, so the handlers ignoring their contexts can be told.
+ The streaming methods are not limited.
+*/
+package servertimeout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	triple_protocol "dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+)
+
+var (
+	once          sync.Once
+	timeoutFilter *Filter
+)
+
+func init() {
+	extension.SetFilter(constant.ServerTimeoutFilterKey, newFilter)
+}
+
+// Filter runs the invocations with their deadlines, and gives them up once the deadlines are exceeded
+type Filter struct{}
+
+func newFilter() filter.Filter {
+	once.Do(func() {
+		timeoutFilter = &Filter{}
+	})
+	return timeoutFilter
+}
+
+// Invoke runs the invocation with a context cancelled at its deadline, and returns an error once the context
+// is done, without waiting for the handler
+func (f *Filter) Invoke(ctx context.Context, invoker base.Invoker, invocation base.Invocation) result.Result {
+	if isStreaming(invocation) {
+		return invoker.Invoke(ctx, invocation)
+	}
+	url := invoker.GetURL()
+	if deadline, ok := getDeadline(url, invocation); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+	if ctx.Done() == nil {
+		return invoker.Invoke(ctx, invocation)
+	}
+	if ctx.Err() != nil {
+		reportAbandoned(url, invocation.MethodName(), reason(ctx))
+		return &result.RPCResult{Err: abandonedError(ctx, invocation)}
+	}
+
+	done := make(chan result.Result, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Errorf("[Server Timeout Filter] invoke %s#%s panic: %v", url.Service(), invocation.MethodName(), r)
+				done <- &result.RPCResult{Err: fmt.Errorf("invoke %s panic: %v", invocation.MethodName(), r)}
+			}
+		}()
+		done <- invoker.Invoke(ctx, invocation)
+	}()
+
+	select {
+	case res := <-done:
+		return res
+	case <-ctx.Done():
+		method := invocation.MethodName()
+		reportAbandoned(url, method, reason(ctx))
+		reportRunning(url, method, 1)
+		go func() {
+			<-done
+			reportRunning(url, method, -1)
+		}()
+		return &result.RPCResult{Err: abandonedError(ctx, invocation)}
+	}
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *Filter) OnResponse(_ context.Context, res result.Result, _ base.Invoker, _ base.Invocation) result.Result {
+	return res
+}
+
+// getDeadline returns the earliest deadline set by the caller and the provider
+func getDeadline(url *common.URL, invocation base.Invocation) (time.Time, bool) {
+	var deadline time.Time
+	earlier := func(d time.Time) {
+		if deadline.IsZero() || d.Before(deadline) {
+			deadline = d
+		}
+	}
+
+	now := time.Now()
+	if v, ok := invocation.GetAttachment(constant.TimeoutKey); ok {
+		if timeout := parseTimeout(v); timeout > 0 {
+			earlier(now.Add(timeout))
+		}
+	}
+	if v, ok := invocation.GetAttachment(constant.DeadlineKey); ok {
+		if millis, err := strconv.ParseInt(v, 10, 64); err == nil && millis > 0 {
+			earlier(time.UnixMilli(millis))
+		}
+	}
+	method := invocation.MethodName()
+	if timeout := parseTimeout(url.GetMethodParam(method, constant.ServerTimeoutKey, url.GetParam(constant.ServerTimeoutKey, ""))); timeout > 0 {
+		earlier(now.Add(timeout))
+	}
+	return deadline, !deadline.IsZero()
+}
+
+// parseTimeout parses the timeout in milliseconds, as attached by the dubbo consumers, or as a duration
+func parseTimeout(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if millis, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Duration(millis) * time.Millisecond
+	}
+	timeout, err := time.ParseDuration(v)
+	if err != nil {
+		return 0
+	}
+	return timeout
+}
+
+func reason(ctx context.Context) string {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return reasonCanceled
+	}
+	return reasonDeadline
+}
+
+func abandonedError(ctx context.Context, invocation base.Invocation) error {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return triple_protocol.NewError(triple_protocol.CodeCanceled,
+			fmt.Errorf("the invocation of %s is canceled by the caller", invocation.MethodName()))
+	}
+	return triple_protocol.NewError(triple_protocol.CodeDeadlineExceeded,
+		fmt.Errorf("the invocation of %s exceeds its deadline", invocation.MethodName()))
+}
+
+func isStreaming(invocation base.Invocation) bool {
+	callType, ok := invocation.GetAttribute(constant.CallTypeKey)
+	return ok && callType != constant.CallUnary
+}
@@ -0,0 +1,155 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package servertimeout
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+)
+
+// slowInvoker ignores its context and returns after the delay, reporting whether the context is done by then
+type slowInvoker struct {
+	*base.BaseInvoker
+	delay    time.Duration
+	finished chan bool
+}
+
+func newSlowInvoker(delay time.Duration, params ...common.Option) *slowInvoker {
+	opts := append([]common.Option{
+		common.WithPath("com.ikurento.user.UserProvider"),
+		common.WithParams(url.Values{}),
+		common.WithParamsValue(constant.InterfaceKey, "com.ikurento.user.UserProvider"),
+	}, params...)
+	return &slowInvoker{
+		BaseInvoker: base.NewBaseInvoker(common.NewURLWithOptions(opts...)),
+		delay:       delay,
+		finished:    make(chan bool, 1),
+	}
+}
+
+func (i *slowInvoker) Invoke(ctx context.Context, _ base.Invocation) result.Result {
+	time.Sleep(i.delay)
+	i.finished <- ctx.Err() != nil
+	return &result.RPCResult{Rest: "done"}
+}
+
+func TestFilterCallerTimeout(t *testing.T) {
+	f := newFilter()
+	invoker := newSlowInvoker(200 * time.Millisecond)
+	inv := invocation.NewRPCInvocation("GetUser", nil, map[string]any{constant.TimeoutKey: "20"})
+
+	start := time.Now()
+	res := f.Invoke(context.Background(), invoker, inv)
+	assert.Less(t, time.Since(start), 150*time.Millisecond)
+	assert.Equal(t, triple_protocol.CodeDeadlineExceeded, triple_protocol.CodeOf(res.Error()))
+	// the abandoned handler sees its context done
+	assert.True(t, <-invoker.finished)
+
+	// the handler finishing in time
+	invoker = newSlowInvoker(0)
+	res = f.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", nil, map[string]any{constant.TimeoutKey: "1s"}))
+	assert.Nil(t, res.Error())
+	assert.Equal(t, "done", res.Result())
+	assert.False(t, <-invoker.finished)
+}
+
+func TestFilterProviderTimeout(t *testing.T) {
+	f := newFilter()
+	invoker := newSlowInvoker(200*time.Millisecond, common.WithParamsValue("methods.GetUser."+constant.ServerTimeoutKey, "20ms"),
+		common.WithParamsValue(constant.ServerTimeoutKey, "50ms"))
+
+	// the method timeout is earlier than the one of the caller
+	inv := invocation.NewRPCInvocation("GetUser", nil, map[string]any{constant.TimeoutKey: []string{"3s"}})
+	res := f.Invoke(context.Background(), invoker, inv)
+	assert.Equal(t, triple_protocol.CodeDeadlineExceeded, triple_protocol.CodeOf(res.Error()))
+	<-invoker.finished
+
+	// the service timeout applies to the other methods
+	start := time.Now()
+	res = f.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("ListUsers", nil, nil))
+	assert.Equal(t, triple_protocol.CodeDeadlineExceeded, triple_protocol.CodeOf(res.Error()))
+	assert.Less(t, time.Since(start), 150*time.Millisecond)
+	<-invoker.finished
+
+	// the timeout shared with the consumers is not the one of the provider
+	invoker = newSlowInvoker(50*time.Millisecond, common.WithParamsValue("methods.GetUser."+constant.TimeoutKey, "10ms"))
+	res = f.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", nil, nil))
+	assert.Nil(t, res.Error())
+	<-invoker.finished
+}
+
+func TestFilterCanceled(t *testing.T) {
+	f := newFilter()
+	invoker := newSlowInvoker(200 * time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	res := f.Invoke(ctx, invoker, invocation.NewRPCInvocation("GetUser", nil, nil))
+	assert.Equal(t, triple_protocol.CodeCanceled, triple_protocol.CodeOf(res.Error()))
+	assert.True(t, <-invoker.finished)
+
+	// the handler is not run once the context is done
+	res = f.Invoke(ctx, invoker, invocation.NewRPCInvocation("GetUser", nil, nil))
+	assert.Equal(t, triple_protocol.CodeCanceled, triple_protocol.CodeOf(res.Error()))
+	assert.Empty(t, invoker.finished)
+}
+
+func TestFilterStreaming(t *testing.T) {
+	f := newFilter()
+	invoker := newSlowInvoker(50 * time.Millisecond)
+	inv := invocation.NewRPCInvocation("Watch", nil, map[string]any{constant.TimeoutKey: "10"})
+	inv.SetAttribute(constant.CallTypeKey, constant.CallServerStream)
+	res := f.Invoke(context.Background(), invoker, inv)
+	assert.Nil(t, res.Error())
+	assert.False(t, <-invoker.finished)
+}
+
+func TestGetDeadline(t *testing.T) {
+	u := common.NewURLWithOptions(common.WithParams(url.Values{}))
+	_, ok := getDeadline(u, invocation.NewRPCInvocation("GetUser", nil, nil))
+	assert.False(t, ok)
+
+	deadline := time.Now().Add(time.Minute).Truncate(time.Millisecond)
+	inv := invocation.NewRPCInvocation("GetUser", nil, map[string]any{
+		constant.TimeoutKey:  "1h",
+		constant.DeadlineKey: strconv.FormatInt(deadline.UnixMilli(), 10),
+	})
+	d, ok := getDeadline(u, inv)
+	assert.True(t, ok)
+	assert.True(t, d.Equal(deadline))
+
+	assert.Equal(t, 1500*time.Millisecond, parseTimeout("1500"))
+	assert.Equal(t, 2*time.Second, parseTimeout("2s"))
+	assert.Equal(t, time.Duration(0), parseTimeout("invalid"))
+}
@@ -0,0 +1,79 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package servertimeout
+
+import (
+	"sync/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+)
+
+const (
+	reasonDeadline = "deadline"
+	reasonCanceled = "canceled"
+)
+
+var (
+	abandonedKey = metrics.NewMetricKey("dubbo_provider_abandoned_requests_total",
+		"The Requests Given Up by Provider for Exceeding Deadlines or Being Canceled")
+	runningKey = metrics.NewMetricKey("dubbo_provider_abandoned_requests_running",
+		"The Abandoned Requests Whose Handlers Are Still Running in Provider")
+
+	// the metrics are set once the metrics module is initialized
+	abandoned atomic.Pointer[metrics.CounterVec]
+	running   atomic.Pointer[metrics.GaugeVec]
+)
+
+func init() {
+	metrics.AddCollector("server_timeout", func(registry metrics.MetricRegistry, _ *common.URL) {
+		a := metrics.NewCounterVec(registry, abandonedKey)
+		abandoned.Store(&a)
+		r := metrics.NewGaugeVec(registry, runningKey)
+		running.Store(&r)
+	})
+}
+
+func methodTags(url *common.URL, method string) map[string]string {
+	return map[string]string{
+		constant.TagApplicationName: url.GetParam(constant.ApplicationKey, ""),
+		constant.TagInterface:       url.Service(),
+		constant.TagMethod:          method,
+		constant.TagGroup:           url.Group(),
+		constant.TagVersion:         url.GetParam(constant.VersionKey, ""),
+	}
+}
+
+// reportAbandoned counts an abandoned invocation
+func reportAbandoned(url *common.URL, method, reason string) {
+	if c := abandoned.Load(); c != nil {
+		tags := methodTags(url, method)
+		tags[constant.TagReason] = reason
+		(*c).Inc(tags)
+	}
+}
+
+// reportRunning counts the handler of an abandoned invocation in while it is running, and out once it returns
+func reportRunning(url *common.URL, method string, delta float64) {
+	if g := running.Load(); g != nil {
+		(*g).Add(methodTags(url, method), delta)
+	}
+}
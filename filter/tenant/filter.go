@@ -0,0 +1,110 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package tenant provides the provider filter enforcing the per-tenant quotas.
+/*
+ The tenant of a request is attached by the consumer with tenant.NewContext, and the quotas of the tenants
+ are configured in the config center with the key "{application}.tenants", see common/tenant for the format:
+
+ services:
+   "UserProvider":
+     interface: "com.ikurento.user.UserProvider"
+     filter: "tenant"
+
+ The quotas are counted on each provider. A request beyond the quotas of its tenant fails with a
+ resource-exhausted error, and the requests without a tenant are not limited. Each tenant falling back to
+ the default tenant "*" has a quota of its own, of which the 10000 most recently used are kept per
+ application, the others start over once they come back. The requests and the rejections are counted by
+ dubbo_provider_tenant_requests_total and dubbo_provider_tenant_rejected_total, labelled by the tenant.
+ Only the first max-metric-tenants tenants seen are labelled by their ids, the others are labelled as
+ "other" to keep the cardinality of the metrics bounded.
+*/
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/tenant"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	triple_protocol "dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+)
+
+var (
+	once         sync.Once
+	tenantFilter *Filter
+)
+
+func init() {
+	extension.SetFilter(constant.TenantFilterKey, newFilter)
+}
+
+// Filter enforces the quotas of the tenants
+type Filter struct {
+	quotas quotaManager
+	guard  labelGuard
+}
+
+func newFilter() filter.Filter {
+	once.Do(func() {
+		tenantFilter = &Filter{}
+	})
+	return tenantFilter
+}
+
+// Invoke rejects the invocation if its tenant runs out of the rate or concurrency quota
+func (f *Filter) Invoke(ctx context.Context, invoker base.Invoker, invocation base.Invocation) result.Result {
+	id := tenant.FromInvocation(invocation)
+	if id == "" {
+		return invoker.Invoke(ctx, invocation)
+	}
+
+	url := invoker.GetURL()
+	application := url.GetParam(constant.ApplicationKey, "")
+	config := tenant.GetConfig(application)
+	label := f.guard.label(application, id, config)
+	reportRequest(url, invocation.MethodName(), label)
+
+	q := f.quotas.get(application, config, id)
+	if q == nil {
+		return invoker.Invoke(ctx, invocation)
+	}
+	if !q.allowRate() {
+		reportRejected(url, invocation.MethodName(), label, reasonRate)
+		return &result.RPCResult{Err: triple_protocol.NewError(triple_protocol.CodeResourceExhausted,
+			fmt.Errorf("the rate quota of tenant %s is exhausted", id))}
+	}
+	if !q.acquire() {
+		reportRejected(url, invocation.MethodName(), label, reasonConcurrency)
+		return &result.RPCResult{Err: triple_protocol.NewError(triple_protocol.CodeResourceExhausted,
+			fmt.Errorf("the concurrency quota of tenant %s is exhausted", id))}
+	}
+	defer q.release()
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *Filter) OnResponse(_ context.Context, res result.Result, _ base.Invoker, _ base.Invocation) result.Result {
+	return res
+}
@@ -0,0 +1,164 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/tenant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+)
+
+// blockingInvoker holds the invocations until release is closed
+type blockingInvoker struct {
+	*base.BaseInvoker
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingInvoker(application string) *blockingInvoker {
+	return &blockingInvoker{
+		BaseInvoker: base.NewBaseInvoker(common.NewURLWithOptions(
+			common.WithPath("com.ikurento.user.UserProvider"),
+			common.WithParams(url.Values{}),
+			common.WithParamsValue(constant.InterfaceKey, "com.ikurento.user.UserProvider"),
+			common.WithParamsValue(constant.ApplicationKey, application))),
+		started: make(chan struct{}, 10),
+		release: make(chan struct{}),
+	}
+}
+
+func (i *blockingInvoker) Invoke(_ context.Context, _ base.Invocation) result.Result {
+	i.started <- struct{}{}
+	<-i.release
+	return &result.RPCResult{}
+}
+
+func setConfig(t *testing.T, application, content string) {
+	c, err := tenant.ParseConfig(content)
+	assert.Nil(t, err)
+	assert.Nil(t, tenant.SetConfig(application, c))
+	t.Cleanup(func() {
+		_ = tenant.SetConfig(application, nil)
+	})
+}
+
+func newInvocation(id string) base.Invocation {
+	return invocation.NewRPCInvocation("GetUser", nil, map[string]any{constant.TenantKey: []string{id}})
+}
+
+func TestFilterConcurrency(t *testing.T) {
+	setConfig(t, "concurrency-app", `tenants: [{id: "acme", concurrency: 1}]`)
+	f := &Filter{}
+	invoker := newBlockingInvoker("concurrency-app")
+	close(invoker.release)
+
+	blocking := newBlockingInvoker("concurrency-app")
+	done := make(chan result.Result)
+	go func() {
+		done <- f.Invoke(context.Background(), blocking, newInvocation("acme"))
+	}()
+	<-blocking.started
+
+	res := f.Invoke(context.Background(), invoker, newInvocation("acme"))
+	assert.Equal(t, triple_protocol.CodeResourceExhausted, triple_protocol.CodeOf(res.Error()))
+	// the other tenants and the requests without a tenant are not limited
+	assert.Nil(t, f.Invoke(context.Background(), invoker, newInvocation("other")).Error())
+	assert.Nil(t, f.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", nil, nil)).Error())
+
+	close(blocking.release)
+	assert.Nil(t, (<-done).Error())
+	assert.Nil(t, f.Invoke(context.Background(), invoker, newInvocation("acme")).Error())
+}
+
+func TestFilterRate(t *testing.T) {
+	setConfig(t, "rate-app", `
+tenants:
+  - id: "acme"
+    rate: 2
+    interval: 1h
+  - id: "*"
+    rate: 1
+    interval: 1h
+`)
+	f := &Filter{}
+	invoker := newBlockingInvoker("rate-app")
+	close(invoker.release)
+
+	for i := 0; i < 2; i++ {
+		assert.Nil(t, f.Invoke(context.Background(), invoker, newInvocation("acme")).Error())
+	}
+	res := f.Invoke(context.Background(), invoker, newInvocation("acme"))
+	assert.Equal(t, triple_protocol.CodeResourceExhausted, triple_protocol.CodeOf(res.Error()))
+
+	// the default quota applies to each unlisted tenant separately
+	assert.Nil(t, f.Invoke(context.Background(), invoker, newInvocation("foo")).Error())
+	assert.NotNil(t, f.Invoke(context.Background(), invoker, newInvocation("foo")).Error())
+	assert.Nil(t, f.Invoke(context.Background(), invoker, newInvocation("bar")).Error())
+
+	// the quotas are reset once the configuration changes
+	setConfig(t, "rate-app", `tenants: [{id: "acme", rate: 1, interval: 1h}]`)
+	assert.Nil(t, f.Invoke(context.Background(), invoker, newInvocation("acme")).Error())
+	assert.Nil(t, f.Invoke(context.Background(), invoker, newInvocation("foo")).Error())
+	assert.Nil(t, f.Invoke(context.Background(), invoker, newInvocation("foo")).Error())
+}
+
+func TestQuotaManagerBoundsDefaultTenants(t *testing.T) {
+	config, err := tenant.ParseConfig(`tenants: [{id: "acme", concurrency: 1}, {id: "*", concurrency: 1}]`)
+	assert.Nil(t, err)
+	m := &quotaManager{}
+	acme := m.get("bound-app", config, "acme")
+	first := m.get("bound-app", config, "tenant-0")
+	assert.Same(t, first, m.get("bound-app", config, "tenant-0"))
+
+	// the tenant ids falling back to the default tenant are bounded
+	for i := 1; i < maxDefaultTenantQuotas+100; i++ {
+		m.get("bound-app", config, fmt.Sprintf("tenant-%d", i))
+	}
+	set := m.set("bound-app", config)
+	assert.Equal(t, maxDefaultTenantQuotas, set.defaults.Len())
+	assert.NotSame(t, first, m.get("bound-app", config, "tenant-0"))
+	// while the configured tenants are kept
+	assert.Same(t, acme, m.get("bound-app", config, "acme"))
+}
+
+func TestLabelGuard(t *testing.T) {
+	setConfig(t, "label-app", `max-metric-tenants: 2`)
+	config := tenant.GetConfig("label-app")
+	g := &labelGuard{}
+	assert.Equal(t, "a", g.label("label-app", "a", config))
+	assert.Equal(t, "b", g.label("label-app", "b", config))
+	assert.Equal(t, otherTenants, g.label("label-app", "c", config))
+	assert.Equal(t, "a", g.label("label-app", "a", config))
+	// the labels are guarded per application
+	assert.Equal(t, "c", g.label("another-app", "c", nil))
+}
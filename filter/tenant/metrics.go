@@ -0,0 +1,114 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tenant
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/tenant"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+)
+
+const (
+	reasonRate        = "rate"
+	reasonConcurrency = "concurrency"
+
+	// otherTenants labels the tenants beyond the max-metric-tenants
+	otherTenants = "other"
+)
+
+var (
+	requestsKey = metrics.NewMetricKey("dubbo_provider_tenant_requests_total",
+		"The Requests Received by Provider per Tenant")
+	rejectedKey = metrics.NewMetricKey("dubbo_provider_tenant_rejected_total",
+		"The Requests Rejected by Provider for Exceeding Tenant Quotas")
+
+	// the counters are set once the metrics module is initialized
+	requests atomic.Pointer[metrics.CounterVec]
+	rejected atomic.Pointer[metrics.CounterVec]
+)
+
+func init() {
+	metrics.AddCollector("tenant", func(registry metrics.MetricRegistry, _ *common.URL) {
+		r := metrics.NewCounterVec(registry, requestsKey)
+		requests.Store(&r)
+		j := metrics.NewCounterVec(registry, rejectedKey)
+		rejected.Store(&j)
+	})
+}
+
+// labelGuard bounds the distinct tenant labels of every application
+type labelGuard struct {
+	applications sync.Map // application -> *tenantLabels
+}
+
+type tenantLabels struct {
+	seen  sync.Map // tenant id -> struct{}
+	count atomic.Int64
+}
+
+// label returns the tenant id if it is seen before or there is room for a new one, otherwise "other"
+func (g *labelGuard) label(application, id string, config *tenant.Config) string {
+	value, _ := g.applications.LoadOrStore(application, &tenantLabels{})
+	labels := value.(*tenantLabels)
+	if _, ok := labels.seen.Load(id); ok {
+		return id
+	}
+	limit := int64(tenant.DefaultMaxMetricTenants)
+	if config != nil {
+		limit = int64(config.MaxMetricTenants)
+	}
+	if labels.count.Add(1) > limit {
+		labels.count.Add(-1)
+		return otherTenants
+	}
+	if _, loaded := labels.seen.LoadOrStore(id, struct{}{}); loaded {
+		labels.count.Add(-1)
+	}
+	return id
+}
+
+func tenantTags(url *common.URL, method, label string) map[string]string {
+	return map[string]string{
+		constant.TagApplicationName: url.GetParam(constant.ApplicationKey, ""),
+		constant.TagInterface:       url.Service(),
+		constant.TagMethod:          method,
+		constant.TagGroup:           url.Group(),
+		constant.TagVersion:         url.GetParam(constant.VersionKey, ""),
+		constant.TagTenant:          label,
+	}
+}
+
+func reportRequest(url *common.URL, method, label string) {
+	if c := requests.Load(); c != nil {
+		(*c).Inc(tenantTags(url, method, label))
+	}
+}
+
+func reportRejected(url *common.URL, method, label, reason string) {
+	if c := rejected.Load(); c != nil {
+		tags := tenantTags(url, method, label)
+		tags[constant.TagReason] = reason
+		(*c).Inc(tags)
+	}
+}
@@ -0,0 +1,149 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tenant
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/tenant"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/filter/tps/strategy"
+)
+
+// maxDefaultTenantQuotas bounds the quotas of the tenants falling back to the default tenant "*" per
+// application, the least recently used ones are evicted, as the tenant ids are sent by anyone
+const maxDefaultTenantQuotas = 10000
+
+// quota counts the requests of a tenant against its configured quotas
+type quota struct {
+	concurrency int64
+	active      atomic.Int64
+	limiter     filter.TpsLimitStrategy
+}
+
+func newQuota(t *tenant.Tenant) *quota {
+	q := &quota{concurrency: t.Concurrency}
+	if t.Rate > 0 {
+		creator, err := extension.GetTpsLimitStrategyCreator(strategy.TokenBucketKey)
+		if err != nil {
+			logger.Errorf("[Tenant Filter] the rate quota of tenant %s is ignored: %v", t.ID, err)
+			return q
+		}
+		config := &filter.TpsLimitStrategyConfig{Rate: t.Rate, Interval: int(t.Interval.Milliseconds()), Burst: t.Burst}
+		if c, ok := creator.(filter.ConfigurableTpsLimitStrategyCreator); ok {
+			q.limiter = c.CreateWithConfig(config)
+		} else {
+			q.limiter = creator.Create(config.Rate, config.Interval)
+		}
+	}
+	return q
+}
+
+func (q *quota) allowRate() bool {
+	return q.limiter == nil || q.limiter.IsAllowable()
+}
+
+func (q *quota) acquire() bool {
+	if q.concurrency <= 0 {
+		return true
+	}
+	if q.active.Add(1) > q.concurrency {
+		q.active.Add(-1)
+		return false
+	}
+	return true
+}
+
+func (q *quota) release() {
+	if q.concurrency > 0 {
+		q.active.Add(-1)
+	}
+}
+
+// quotaSet holds the quotas of the tenants of an application under one configuration
+type quotaSet struct {
+	config *tenant.Config
+	quotas sync.Map // tenant id -> *quota of the configured tenants
+	// defaults are the quotas of the tenants falling back to the default tenant
+	defaults *lru.Cache
+}
+
+func newQuotaSet(config *tenant.Config) *quotaSet {
+	defaults, _ := lru.New(maxDefaultTenantQuotas)
+	return &quotaSet{config: config, defaults: defaults}
+}
+
+// quotaManager keeps the quotas of every application, they are reset once the configuration changes.
+// The requests in flight keep releasing the quotas they acquired.
+type quotaManager struct {
+	sets sync.Map // application -> *quotaSet
+}
+
+// get returns the quota of the tenant, or nil if the tenant is not limited
+func (m *quotaManager) get(application string, config *tenant.Config, id string) *quota {
+	t := config.Get(id)
+	if t == nil || (t.Concurrency <= 0 && t.Rate <= 0) {
+		return nil
+	}
+	set := m.set(application, config)
+	if !config.Configured(id) {
+		// a tenant evicted starts over with a new quota, while its requests in flight release the old one
+		if q, ok := set.defaults.Get(id); ok {
+			return q.(*quota)
+		}
+		q := newQuota(t)
+		if previous, ok, _ := set.defaults.PeekOrAdd(id, q); ok {
+			return previous.(*quota)
+		}
+		return q
+	}
+	if q, ok := set.quotas.Load(id); ok {
+		return q.(*quota)
+	}
+	q, _ := set.quotas.LoadOrStore(id, newQuota(t))
+	return q.(*quota)
+}
+
+func (m *quotaManager) set(application string, config *tenant.Config) *quotaSet {
+	for {
+		value, ok := m.sets.Load(application)
+		if !ok {
+			value, ok = m.sets.LoadOrStore(application, newQuotaSet(config))
+			if !ok {
+				return value.(*quotaSet)
+			}
+		}
+		set := value.(*quotaSet)
+		if set.config == config {
+			return set
+		}
+		if m.sets.CompareAndSwap(application, set, newQuotaSet(config)) {
+			logger.Infof("[Tenant Filter] the tenant quotas of %s are reset as the configuration changes", application)
+		}
+	}
+}
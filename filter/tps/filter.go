@@ -82,7 +82,12 @@ func (t *tpsLimitFilter) Invoke(ctx context.Context, invoker base.Invoker, invoc
 			logger.Warn(err)
 			return invoker.Invoke(ctx, invocation)
 		}
-		allow := limiter.IsAllowable(invoker.GetURL(), invocation)
+		var allow bool
+		if contextual, ok := limiter.(filter.ContextualTpsLimiter); ok {
+			allow = contextual.IsAllowableWithContext(ctx, url, invocation)
+		} else {
+			allow = limiter.IsAllowable(url, invocation)
+		}
 		if allow {
 			return invoker.Invoke(ctx, invocation)
 		}
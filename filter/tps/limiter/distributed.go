@@ -0,0 +1,278 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package limiter
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/filter/tps/store"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+)
+
+const (
+	// DistributedKey is the name of the DistributedTpsLimiter
+	DistributedKey = "distributed"
+
+	distributedStoreKeyPrefix = "dubbo:tps:"
+)
+
+func init() {
+	extension.SetTpsLimiter(DistributedKey, GetDistributedTpsLimiter)
+}
+
+// DistributedTpsLimiter limits the TPS of all the providers of a service together, by counting the invocations
+// of each fixed window in a TpsLimitStore shared by the providers, so the quota doesn't drift when scaling providers.
+/**
+ * "UserProvider":
+ *   ... # other configuration
+ *   tps.limiter: "distributed"
+ *   tps.limit.interval: 1000
+ *   tps.limit.rate: 1000 # the max value in the interval of all the providers
+ *   tps.limit.strategy: "fixedWindow" # optional, the strategy of the local limit
+ *   params:
+ *     tps.limit.store: "redis" # the name of the TpsLimitStore, "redis" by default
+ *     tps.limit.store.address: "127.0.0.1:6379"
+ *     tps.limit.lease: 20 # optional, lease 20 tokens from the store at once, so there are fewer round trips
+ *     tps.limit.degrade: "5s" # optional, how long to use the local limit after failing to access the store
+ *     tps.limit.providers: 4 # the expected number of the providers, the local limit is tps.limit.rate / 4
+ *     tps.limit.local.rate: 300 # or the rate of the local limit, overriding tps.limit.providers
+ *
+ * One of tps.limit.providers and tps.limit.local.rate is required, so the providers limited locally while the
+ * store is unreachable don't allow the whole rate each.
+ * The method-level configuration and tps.limit.by work the same as the MethodServiceTpsLimiter.
+ * The leased tokens not used by the end of the window are wasted, so a large lease makes the limit
+ * less accurate when there are many providers.
+ */
+type DistributedTpsLimiter struct {
+	tpsState *limitStates
+	now      func() time.Time
+}
+
+// IsAllowable leases the tokens from the store, or asks the local limit when the store is unreachable
+func (limiter *DistributedTpsLimiter) IsAllowable(url *common.URL, invocation base.Invocation) bool {
+	return limiter.IsAllowableWithContext(context.Background(), url, invocation)
+}
+
+// IsAllowableWithContext is like IsAllowable, and the local limit letting the requests wait respects the deadline of ctx
+func (limiter *DistributedTpsLimiter) IsAllowableWithContext(ctx context.Context, url *common.URL, invocation base.Invocation) bool {
+	limitTarget, byCaller := getLimitTarget(url, invocation)
+
+	limitState, found := limiter.tpsState.load(limitTarget, byCaller)
+	if !found {
+		state := limiter.newState(limitTarget, url, invocation)
+		if state == nil {
+			return true
+		}
+		limitState = limiter.tpsState.loadOrStore(limitTarget, byCaller, state)
+	}
+	return limitState.(*distributedTpsLimitState).isAllowable(ctx)
+}
+
+func (limiter *DistributedTpsLimiter) newState(limitTarget string, url *common.URL, invocation base.Invocation) *distributedTpsLimitState {
+	methodConfigPrefix := "methods." + invocation.MethodName() + "."
+
+	limitRate := getLimitConfig(url.GetParam(methodConfigPrefix+constant.TPSLimitRateKey, ""), url, invocation,
+		constant.TPSLimitRateKey,
+		constant.DefaultTPSLimitRate)
+	if limitRate < 0 {
+		logger.Errorf("Found error configuration value of tps.limit.rate for the invocation %s, ignores TPS Limiter", url.ServiceKey()+"#"+invocation.MethodName())
+		return nil
+	}
+	limitInterval := getLimitConfig(url.GetParam(methodConfigPrefix+constant.TPSLimitIntervalKey, ""), url, invocation,
+		constant.TPSLimitIntervalKey,
+		constant.DefaultTPSLimitInterval)
+	if limitInterval <= 0 {
+		logger.Errorf("Found error configuration value of tps.limit.interval for the invocation %s, ignores TPS Limiter", url.ServiceKey()+"#"+invocation.MethodName())
+		return nil
+	}
+
+	localRate := getOptionalLimitConfig(url, invocation, constant.TPSLimitLocalRateKey)
+	if localRate <= 0 {
+		providers := getOptionalLimitConfig(url, invocation, constant.TPSLimitProvidersKey)
+		if providers <= 0 {
+			logger.Errorf("Found neither tps.limit.local.rate nor tps.limit.providers for the invocation %s, ignores TPS Limiter", url.ServiceKey()+"#"+invocation.MethodName())
+			return nil
+		}
+		// the providers share the rate while the store is unreachable
+		localRate = (limitRate + providers - 1) / providers
+	}
+	local, err := newLimitStrategy(url, invocation, localRate, limitInterval)
+	if err != nil {
+		logger.Warn(err)
+		return nil
+	}
+
+	lease := getOptionalLimitConfig(url, invocation, constant.TPSLimitLeaseKey)
+	if lease <= 0 {
+		lease = 1
+	}
+
+	state := &distributedTpsLimitState{
+		key:       distributedStoreKeyPrefix + limitTarget + ":",
+		rate:      limitRate,
+		interval:  time.Duration(limitInterval) * time.Millisecond,
+		lease:     lease,
+		degrade:   url.GetParamDuration(constant.TPSLimitDegradeKey, constant.DefaultTPSLimitDegrade),
+		local:     local,
+		now:       limiter.now,
+		exhausted: -1,
+	}
+
+	storeName := url.GetParam(constant.TPSLimitStoreKey, store.RedisKey)
+	creator, err := extension.GetTpsLimitStoreCreator(storeName)
+	if err == nil {
+		state.store, err = creator(url)
+	}
+	if err != nil {
+		// it keeps using the local limit
+		logger.Errorf("Failed to create the TpsLimitStore %s for %s, uses the local TPS limit: %v", storeName, limitTarget, err)
+	}
+	return state
+}
+
+// distributedTpsLimitState is the state of a limit target
+type distributedTpsLimitState struct {
+	key      string
+	rate     int64
+	interval time.Duration
+	lease    int64
+	degrade  time.Duration
+	store    filter.TpsLimitStore
+	local    filter.TpsLimitStrategy
+	now      func() time.Time
+
+	mu           sync.Mutex
+	window       int64
+	remaining    int64
+	exhausted    int64
+	degradeUntil time.Time
+	// leasing is the lease in flight, the callers running out of the tokens of its window wait for it
+	leasing *leaseCall
+}
+
+// leaseCall is a lease of the tokens of a window from the store
+type leaseCall struct {
+	window int64
+	done   chan struct{}
+}
+
+func (s *distributedTpsLimitState) isAllowable(ctx context.Context) bool {
+	if s.store == nil {
+		return isStrategyAllowable(ctx, s.local)
+	}
+
+	now := s.now()
+	window := now.UnixNano() / int64(s.interval)
+	s.mu.Lock()
+	for {
+		if now.Before(s.degradeUntil) {
+			s.mu.Unlock()
+			return isStrategyAllowable(ctx, s.local)
+		}
+		if window != s.window {
+			s.window = window
+			s.remaining = 0
+		}
+		if s.remaining > 0 {
+			s.remaining--
+			s.mu.Unlock()
+			return true
+		}
+		if s.exhausted == window {
+			// all the tokens of the window have been leased
+			s.mu.Unlock()
+			return false
+		}
+		call := s.leasing
+		if call == nil || call.window != window {
+			break
+		}
+		// reuses the tokens of the lease in flight instead of leasing more
+		s.mu.Unlock()
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			// the request is given up before the lease in flight is done
+			return false
+		}
+		s.mu.Lock()
+	}
+	call := &leaseCall{window: window, done: make(chan struct{})}
+	s.leasing = call
+	s.mu.Unlock()
+
+	// the lock is not held while accessing the store
+	value, err := s.store.IncrBy(s.key+strconv.FormatInt(window, 10), s.lease, 2*s.interval)
+
+	s.mu.Lock()
+	if s.leasing == call {
+		s.leasing = nil
+	}
+	if err != nil {
+		logger.Warnf("Failed to lease the tokens of %s, uses the local TPS limit in %s: %v", s.key, s.degrade, err)
+		s.degradeUntil = now.Add(s.degrade)
+		s.mu.Unlock()
+		close(call.done)
+		// the local limit may let the request wait, so the lock is not held
+		return isStrategyAllowable(ctx, s.local)
+	}
+	defer s.mu.Unlock()
+	defer close(call.done)
+
+	// the tokens are leased partially if there are not enough tokens left
+	granted := min(s.lease, s.rate-(value-s.lease))
+	if granted <= 0 {
+		if s.window == window {
+			s.exhausted = window
+		}
+		return false
+	}
+	if s.window == window {
+		s.remaining += granted - 1
+	}
+	return true
+}
+
+var (
+	distributedTpsLimiterInstance *DistributedTpsLimiter
+	distributedTpsLimiterOnce     sync.Once
+)
+
+// GetDistributedTpsLimiter returns the DistributedTpsLimiter instance.
+func GetDistributedTpsLimiter() filter.TpsLimiter {
+	distributedTpsLimiterOnce.Do(func() {
+		distributedTpsLimiterInstance = &DistributedTpsLimiter{
+			tpsState: newLimitStates(),
+			now:      time.Now,
+		}
+	})
+	return distributedTpsLimiterInstance
+}
@@ -0,0 +1,295 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package limiter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/filter/tps/strategy"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type fakeTpsLimitStore struct {
+	mu       sync.Mutex
+	counters map[string]int64
+	calls    int
+	err      error
+	// block holds the calls until it is closed
+	block chan struct{}
+}
+
+func (s *fakeTpsLimitStore) IncrBy(key string, n int64, _ time.Duration) (int64, error) {
+	s.mu.Lock()
+	s.calls++
+	block := s.block
+	s.mu.Unlock()
+	if block != nil {
+		<-block
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return 0, s.err
+	}
+	s.counters[key] += n
+	return s.counters[key], nil
+}
+
+func (s *fakeTpsLimitStore) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+type fakeClock struct {
+	current time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.current
+}
+
+// newDistributedTestEnv registers a fake store, and returns the url of the service using it
+func newDistributedTestEnv(params map[string]string) (*fakeTpsLimitStore, *common.URL) {
+	store := &fakeTpsLimitStore{counters: make(map[string]int64)}
+	storeName := "fake-" + uuid.NewString()
+	extension.SetTpsLimitStore(storeName, func(*common.URL) (filter.TpsLimitStore, error) {
+		return store, nil
+	})
+
+	opts := []common.Option{
+		common.WithParamsValue(constant.InterfaceKey, "svc-"+uuid.NewString()),
+		common.WithParamsValue(constant.TPSLimitStoreKey, storeName),
+		common.WithParamsValue(constant.TPSLimitStrategyKey, strategy.FixedWindowKey),
+		common.WithParamsValue(constant.TPSLimitIntervalKey, "1000"),
+		common.WithParamsValue(constant.TPSLimitProvidersKey, "1"),
+	}
+	for k, v := range params {
+		opts = append(opts, common.WithParamsValue(k, v))
+	}
+	return store, common.NewURLWithOptions(opts...)
+}
+
+func newDistributedTestLimiter(clock *fakeClock) *DistributedTpsLimiter {
+	return &DistributedTpsLimiter{tpsState: newLimitStates(), now: clock.now}
+}
+
+func countAllowed(limiter filter.TpsLimiter, url *common.URL, n int) int {
+	invoc := invocation.NewRPCInvocation("hello", nil, nil)
+	allowed := 0
+	for i := 0; i < n; i++ {
+		if limiter.IsAllowable(url, invoc) {
+			allowed++
+		}
+	}
+	return allowed
+}
+
+func TestDistributedTpsLimiterSharesQuota(t *testing.T) {
+	store, url := newDistributedTestEnv(map[string]string{constant.TPSLimitRateKey: "10"})
+	clock := &fakeClock{current: time.Unix(1000, 0)}
+	provider1 := newDistributedTestLimiter(clock)
+	provider2 := newDistributedTestLimiter(clock)
+
+	assert.Equal(t, 6, countAllowed(provider1, url, 6))
+	assert.Equal(t, 4, countAllowed(provider2, url, 6))
+	assert.Equal(t, 0, countAllowed(provider1, url, 2))
+	calls := store.calls
+	// the exhausted window is not asked again
+	assert.Equal(t, 0, countAllowed(provider2, url, 5))
+	assert.Equal(t, calls, store.calls)
+
+	// a new window
+	clock.current = clock.current.Add(time.Second)
+	assert.Equal(t, 10, countAllowed(provider2, url, 12))
+}
+
+func TestDistributedTpsLimiterLease(t *testing.T) {
+	store, url := newDistributedTestEnv(map[string]string{
+		constant.TPSLimitRateKey:  "10",
+		constant.TPSLimitLeaseKey: "4",
+	})
+	clock := &fakeClock{current: time.Unix(1000, 0)}
+	provider1 := newDistributedTestLimiter(clock)
+	provider2 := newDistributedTestLimiter(clock)
+
+	// provider1 leases 4 tokens at once
+	assert.Equal(t, 1, countAllowed(provider1, url, 1))
+	assert.Equal(t, 1, store.calls)
+	assert.Equal(t, 3, countAllowed(provider1, url, 3))
+	assert.Equal(t, 1, store.calls)
+
+	// provider2 leases 4 tokens, then gets the last 2 tokens only
+	assert.Equal(t, 6, countAllowed(provider2, url, 8))
+	assert.Equal(t, 0, countAllowed(provider1, url, 2))
+
+	clock.current = clock.current.Add(time.Second)
+	assert.Equal(t, 10, countAllowed(provider1, url, 12))
+}
+
+func TestDistributedTpsLimiterDegrade(t *testing.T) {
+	store, url := newDistributedTestEnv(map[string]string{
+		constant.TPSLimitRateKey:      "10",
+		constant.TPSLimitLocalRateKey: "3",
+		constant.TPSLimitDegradeKey:   "5s",
+	})
+	clock := &fakeClock{current: time.Now()}
+	limiter := newDistributedTestLimiter(clock)
+
+	store.setErr(errors.New("connection refused"))
+	// the local limit is used
+	assert.Equal(t, 3, countAllowed(limiter, url, 5))
+	assert.Equal(t, 1, store.calls)
+
+	// the store is not asked until the degrade ends
+	store.setErr(nil)
+	clock.current = clock.current.Add(4 * time.Second)
+	countAllowed(limiter, url, 1)
+	assert.Equal(t, 1, store.calls)
+
+	clock.current = clock.current.Add(2 * time.Second)
+	assert.Equal(t, 10, countAllowed(limiter, url, 12))
+	assert.True(t, store.calls > 1)
+}
+
+func TestDistributedTpsLimiterWithoutStore(t *testing.T) {
+	url := common.NewURLWithOptions(
+		common.WithParamsValue(constant.InterfaceKey, "svc-"+uuid.NewString()),
+		common.WithParamsValue(constant.TPSLimitStoreKey, "unknown"),
+		common.WithParamsValue(constant.TPSLimitStrategyKey, strategy.FixedWindowKey),
+		common.WithParamsValue(constant.TPSLimitIntervalKey, "60000"),
+		common.WithParamsValue(constant.TPSLimitRateKey, "5"),
+		common.WithParamsValue(constant.TPSLimitProvidersKey, "2"))
+	limiter := newDistributedTestLimiter(&fakeClock{current: time.Now()})
+
+	// the local limit is the share of the provider
+	assert.Equal(t, 3, countAllowed(limiter, url, 8))
+}
+
+func TestDistributedTpsLimiterDegradeByProviders(t *testing.T) {
+	store, url := newDistributedTestEnv(map[string]string{
+		constant.TPSLimitRateKey:      "10",
+		constant.TPSLimitProvidersKey: "4",
+	})
+	limiter := newDistributedTestLimiter(&fakeClock{current: time.Now()})
+
+	store.setErr(errors.New("connection refused"))
+	assert.Equal(t, 3, countAllowed(limiter, url, 10))
+}
+
+func TestDistributedTpsLimiterWithoutLocalRate(t *testing.T) {
+	store, url := newDistributedTestEnv(map[string]string{
+		constant.TPSLimitRateKey: "1",
+	})
+	url.DelParam(constant.TPSLimitProvidersKey)
+	limiter := newDistributedTestLimiter(&fakeClock{current: time.Now()})
+
+	// the limiter is ignored
+	assert.Equal(t, 3, countAllowed(limiter, url, 3))
+	assert.Equal(t, 0, store.calls)
+}
+
+func TestDistributedTpsLimiterLeaseOnce(t *testing.T) {
+	store, url := newDistributedTestEnv(map[string]string{
+		constant.TPSLimitRateKey:  "100",
+		constant.TPSLimitLeaseKey: "20",
+	})
+	limiter := newDistributedTestLimiter(&fakeClock{current: time.Unix(1000, 0)})
+	invoc := invocation.NewRPCInvocation("hello", nil, nil)
+
+	var wg sync.WaitGroup
+	var allowed atomic.Int32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if limiter.IsAllowable(url, invoc) {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// the callers share the tokens of a single lease
+	assert.Equal(t, int32(20), allowed.Load())
+	assert.Equal(t, 1, store.calls)
+}
+
+func TestDistributedTpsLimiterLeaseWaitCancelled(t *testing.T) {
+	store, url := newDistributedTestEnv(map[string]string{constant.TPSLimitRateKey: "100"})
+	store.block = make(chan struct{})
+	limiter := newDistributedTestLimiter(&fakeClock{current: time.Unix(1000, 0)})
+	invoc := invocation.NewRPCInvocation("hello", nil, nil)
+
+	leased := make(chan bool)
+	go func() {
+		leased <- limiter.IsAllowable(url, invoc)
+	}()
+	assert.Eventually(t, func() bool {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		return store.calls == 1
+	}, time.Second, time.Millisecond)
+
+	// the request waiting for the lease in flight gives up with its context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, limiter.IsAllowableWithContext(ctx, url, invoc))
+
+	close(store.block)
+	assert.True(t, <-leased)
+	assert.True(t, limiter.IsAllowable(url, invoc))
+}
+
+func TestDistributedTpsLimiterByCaller(t *testing.T) {
+	_, url := newDistributedTestEnv(map[string]string{
+		constant.TPSLimitRateKey: "2",
+		constant.TPSLimitByKey:   constant.TPSLimitByApplication,
+	})
+	limiter := newDistributedTestLimiter(&fakeClock{current: time.Unix(1000, 0)})
+
+	for _, app := range []string{"app1", "app2"} {
+		invoc := invocation.NewRPCInvocation("hello", nil, map[string]any{constant.RemoteApplicationKey: app})
+		assert.True(t, limiter.IsAllowable(url, invoc))
+		assert.True(t, limiter.IsAllowable(url, invoc))
+		assert.False(t, limiter.IsAllowable(url, invoc))
+	}
+}
+
+func TestGetDistributedTpsLimiter(t *testing.T) {
+	limiter, err := extension.GetTpsLimiter(DistributedKey)
+	assert.Nil(t, err)
+	assert.Same(t, GetDistributedTpsLimiter(), limiter)
+}
@@ -18,14 +18,19 @@
 package limiter
 
 import (
+	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 )
 
 import (
 	"github.com/dubbogo/gost/log/logger"
 
+	lru "github.com/hashicorp/golang-lru"
+
 	"github.com/modern-go/concurrent"
 )
 
@@ -39,6 +44,9 @@ import (
 
 const (
 	name = "method-service"
+
+	// maxCallerLimitStates bounds the limit states of the callers, the least recently used ones are evicted
+	maxCallerLimitStates = 10000
 )
 
 func init() {
@@ -111,9 +119,21 @@ func init() {
  *      tps.limit.rate: 70,
  *      tps.limit.interval: 40000
  * In this case, only UpdateUser will be limited by its configuration (70 times in 40000ms)
+ *
+ * case4:
+ * "UserProvider":
+ *   ... # other configuration
+ *   tps.limit.strategy: "tokenBucket"
+ *   tps.limit.interval: 1000
+ *   tps.limit.rate: 100
+ *   tps.limit.burst: 20
+ *   tps.limit.by: "application" # or "attachment:{key}", such as "attachment:tenant"
+ * In this case, each consumer application is limited separately (100 times in 1000ms, 20 times at once).
+ * The application is the "remote.application" attachment sent by the consumer.
+ * At most 10000 callers are limited at the same time, the least recently seen caller starts over when evicted.
  */
 type MethodServiceTpsLimiter struct {
-	tpsState *concurrent.Map
+	tpsState *limitStates
 }
 
 // IsAllowable based on method-level and service-level.
@@ -123,25 +143,23 @@ type MethodServiceTpsLimiter struct {
 // This implementation use concurrent map + loadOrStore to make implementation thread-safe
 // You can image that even multiple threads create limiter, but only one could store the limiter into tpsState
 func (limiter MethodServiceTpsLimiter) IsAllowable(url *common.URL, invocation base.Invocation) bool {
+	return limiter.IsAllowableWithContext(context.Background(), url, invocation)
+}
+
+// IsAllowableWithContext is like IsAllowable, and the strategies letting the requests wait respect the deadline of ctx
+func (limiter MethodServiceTpsLimiter) IsAllowableWithContext(ctx context.Context, url *common.URL, invocation base.Invocation) bool {
 	methodConfigPrefix := "methods." + invocation.MethodName() + "."
 
 	methodLimitRateConfig := url.GetParam(methodConfigPrefix+constant.TPSLimitRateKey, "")
 	methodIntervalConfig := url.GetParam(methodConfigPrefix+constant.TPSLimitIntervalKey, "")
 
-	// service-level tps limit
-	limitTarget := url.ServiceKey()
-
-	// method-level tps limit
-	if len(methodIntervalConfig) > 0 || len(methodLimitRateConfig) > 0 {
-		// it means that if the method-level rate limit exist, we will use method-level rate limit strategy
-		limitTarget = limitTarget + "#" + invocation.MethodName()
-	}
+	limitTarget, byCaller := getLimitTarget(url, invocation)
 
 	// looking up the limiter from 'cache'
-	limitState, found := limiter.tpsState.Load(limitTarget)
+	limitState, found := limiter.tpsState.load(limitTarget, byCaller)
 	if found {
 		// the limiter has been cached, we return its result
-		return limitState.(filter.TpsLimitStrategy).IsAllowable()
+		return isStrategyAllowable(ctx, limitState.(filter.TpsLimitStrategy))
 	}
 
 	// we could not find the limiter, and try to create one.
@@ -164,19 +182,66 @@ func (limiter MethodServiceTpsLimiter) IsAllowable(url *common.URL, invocation b
 		return true
 	}
 
-	// find the strategy config and then create one
-	limitStrategyConfig := url.GetParam(methodConfigPrefix+constant.TPSLimitStrategyKey,
-		url.GetParam(constant.TPSLimitStrategyKey, constant.DefaultKey))
-	limitStateCreator, err := extension.GetTpsLimitStrategyCreator(limitStrategyConfig)
+	limitStrategy, err := newLimitStrategy(url, invocation, limitRate, limitInterval)
 	if err != nil {
 		logger.Warn(err)
 		return true
 	}
 
 	// we using loadOrStore to ensure thread-safe
-	limitState, _ = limiter.tpsState.LoadOrStore(limitTarget, limitStateCreator.Create(int(limitRate), int(limitInterval)))
+	limitState = limiter.tpsState.loadOrStore(limitTarget, byCaller, limitStrategy)
+
+	return isStrategyAllowable(ctx, limitState.(filter.TpsLimitStrategy))
+}
+
+// isStrategyAllowable passes ctx to the strategy if it may let the request wait
+func isStrategyAllowable(ctx context.Context, strategy filter.TpsLimitStrategy) bool {
+	if contextual, ok := strategy.(filter.ContextualTpsLimitStrategy); ok {
+		return contextual.IsAllowableWithContext(ctx)
+	}
+	return strategy.IsAllowable()
+}
+
+// getLimitTarget returns the key of the limiter which the invocation belongs to, and whether it's limited by caller
+func getLimitTarget(url *common.URL, invocation base.Invocation) (string, bool) {
+	methodConfigPrefix := "methods." + invocation.MethodName() + "."
+
+	// service-level tps limit
+	limitTarget := url.ServiceKey()
+
+	// method-level tps limit
+	if len(url.GetParam(methodConfigPrefix+constant.TPSLimitIntervalKey, "")) > 0 ||
+		len(url.GetParam(methodConfigPrefix+constant.TPSLimitRateKey, "")) > 0 {
+		// it means that if the method-level rate limit exist, we will use method-level rate limit strategy
+		limitTarget = limitTarget + "#" + invocation.MethodName()
+	}
 
-	return limitState.(filter.TpsLimitStrategy).IsAllowable()
+	// caller-level tps limit, each caller has its own limiter
+	if limitBy := url.GetParam(methodConfigPrefix+constant.TPSLimitByKey,
+		url.GetParam(constant.TPSLimitByKey, "")); len(limitBy) > 0 {
+		return limitTarget + "@" + limitBy + "=" + getCaller(limitBy, invocation), true
+	}
+	return limitTarget, false
+}
+
+// newLimitStrategy creates the method-level or service-level configured strategy with the rate and the interval
+func newLimitStrategy(url *common.URL, invocation base.Invocation, limitRate, limitInterval int64) (filter.TpsLimitStrategy, error) {
+	limitStrategyConfig := url.GetParam("methods."+invocation.MethodName()+"."+constant.TPSLimitStrategyKey,
+		url.GetParam(constant.TPSLimitStrategyKey, constant.DefaultKey))
+	limitStateCreator, err := extension.GetTpsLimitStrategyCreator(limitStrategyConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if creator, ok := limitStateCreator.(filter.ConfigurableTpsLimitStrategyCreator); ok {
+		return creator.CreateWithConfig(&filter.TpsLimitStrategyConfig{
+			Rate:     int(limitRate),
+			Interval: int(limitInterval),
+			Burst:    int(getOptionalLimitConfig(url, invocation, constant.TPSLimitBurstKey)),
+			MaxWait:  time.Duration(getOptionalLimitConfig(url, invocation, constant.TPSLimitMaxWaitKey)) * time.Millisecond,
+		}), nil
+	}
+	return limitStateCreator.Create(int(limitRate), int(limitInterval)), nil
 }
 
 // getLimitConfig will try to fetch the configuration from url.
@@ -208,6 +273,68 @@ func getLimitConfig(methodLevelConfig string,
 	return result
 }
 
+// getOptionalLimitConfig returns the method-level or service-level configuration, 0 if neither is configured
+func getOptionalLimitConfig(url *common.URL, invocation base.Invocation, configKey string) int64 {
+	config := url.GetParam("methods."+invocation.MethodName()+"."+configKey, url.GetParam(configKey, ""))
+	if len(config) == 0 {
+		return 0
+	}
+	result, err := strconv.ParseInt(config, 0, 0)
+	if err != nil {
+		logger.Errorf("Cannot parse the configuration %s for invocation %s # %s, please check your configuration!",
+			configKey, url.ServiceKey(), invocation.MethodName())
+		return 0
+	}
+	return result
+}
+
+// getCaller returns the caller of the invocation, which is its application or an attachment according to limitBy
+func getCaller(limitBy string, invocation base.Invocation) string {
+	if limitBy == constant.TPSLimitByApplication {
+		return invocation.GetAttachmentWithDefaultValue(constant.RemoteApplicationKey, "")
+	}
+	if key, ok := strings.CutPrefix(limitBy, constant.TPSLimitByAttachmentPrefix); ok {
+		return invocation.GetAttachmentWithDefaultValue(key, "")
+	}
+	logger.Errorf("Unknown tps.limit.by %s, it should be %s or %s{key}", limitBy,
+		constant.TPSLimitByApplication, constant.TPSLimitByAttachmentPrefix)
+	return ""
+}
+
+// limitStates holds the limit states of the services and the methods, and a bounded number of the callers' ones,
+// as the callers are told by the attachments sent by anyone
+type limitStates struct {
+	states  *concurrent.Map
+	callers *lru.Cache
+}
+
+func newLimitStates() *limitStates {
+	callers, _ := lru.New(maxCallerLimitStates)
+	return &limitStates{
+		states:  concurrent.NewMap(),
+		callers: callers,
+	}
+}
+
+func (s *limitStates) load(limitTarget string, byCaller bool) (any, bool) {
+	if byCaller {
+		return s.callers.Get(limitTarget)
+	}
+	return s.states.Load(limitTarget)
+}
+
+// loadOrStore returns the existing state of the limitTarget, or stores and returns the given one
+func (s *limitStates) loadOrStore(limitTarget string, byCaller bool, state any) any {
+	if byCaller {
+		if previous, ok, _ := s.callers.PeekOrAdd(limitTarget, state); ok {
+			return previous
+		}
+		return state
+	}
+	actual, _ := s.states.LoadOrStore(limitTarget, state)
+	return actual
+}
+
 var (
 	methodServiceTpsLimiterInstance *MethodServiceTpsLimiter
 	methodServiceTpsLimiterOnce     sync.Once
@@ -217,7 +344,7 @@ var (
 func GetMethodServiceTpsLimiter() filter.TpsLimiter {
 	methodServiceTpsLimiterOnce.Do(func() {
 		methodServiceTpsLimiterInstance = &MethodServiceTpsLimiter{
-			tpsState: concurrent.NewMap(),
+			tpsState: newLimitStates(),
 		}
 	})
 	return methodServiceTpsLimiterInstance
@@ -18,8 +18,11 @@
 package limiter
 
 import (
+	"context"
 	"net/url"
+	"strconv"
 	"testing"
+	"time"
 )
 
 import (
@@ -156,3 +159,76 @@ func (creator *mockStrategyCreator) Create(rate int, interval int) filter.TpsLim
 	assert.Equal(creator.t, creator.interval, interval)
 	return creator.strategy
 }
+
+func TestMethodServiceTpsLimiterImplIsAllowableByCaller(t *testing.T) {
+	methodName := "hello4"
+	invokeUrl := common.NewURLWithOptions(
+		common.WithParams(url.Values{}),
+		common.WithParamsValue(constant.InterfaceKey, methodName),
+		common.WithParamsValue(constant.TPSLimitStrategyKey, strategy.TokenBucketKey),
+		common.WithParamsValue(constant.TPSLimitRateKey, "10"),
+		common.WithParamsValue(constant.TPSLimitIntervalKey, "60000"),
+		common.WithParamsValue(constant.TPSLimitBurstKey, "1"),
+		common.WithParamsValue(constant.TPSLimitByKey, constant.TPSLimitByApplication))
+
+	newInvocation := func(app string) *invocation.RPCInvocation {
+		return invocation.NewRPCInvocation(methodName, []any{"OK"}, map[string]any{
+			constant.RemoteApplicationKey: app,
+		})
+	}
+
+	limiter := GetMethodServiceTpsLimiter()
+	assert.True(t, limiter.IsAllowable(invokeUrl, newInvocation("app-a")))
+	assert.False(t, limiter.IsAllowable(invokeUrl, newInvocation("app-a")))
+	// another application has its own bucket
+	assert.True(t, limiter.IsAllowable(invokeUrl, newInvocation("app-b")))
+
+	methodConfigPrefix := "methods." + methodName + "."
+	invokeUrl.SetParam(methodConfigPrefix+constant.TPSLimitRateKey, "10")
+	invokeUrl.SetParam(methodConfigPrefix+constant.TPSLimitByKey, constant.TPSLimitByAttachmentPrefix+"tenant")
+	newTenantInvocation := func(tenant string) *invocation.RPCInvocation {
+		return invocation.NewRPCInvocation(methodName, []any{"OK"}, map[string]any{"tenant": tenant})
+	}
+	assert.True(t, limiter.IsAllowable(invokeUrl, newTenantInvocation("t1")))
+	assert.False(t, limiter.IsAllowable(invokeUrl, newTenantInvocation("t1")))
+	assert.True(t, limiter.IsAllowable(invokeUrl, newTenantInvocation("t2")))
+}
+
+func TestMethodServiceTpsLimiterImplBoundsCallers(t *testing.T) {
+	invokeUrl := common.NewURLWithOptions(
+		common.WithParams(url.Values{}),
+		common.WithParamsValue(constant.InterfaceKey, "hello5"),
+		common.WithParamsValue(constant.TPSLimitStrategyKey, strategy.FixedWindowKey),
+		common.WithParamsValue(constant.TPSLimitRateKey, "10"),
+		common.WithParamsValue(constant.TPSLimitIntervalKey, "60000"),
+		common.WithParamsValue(constant.TPSLimitByKey, constant.TPSLimitByApplication))
+
+	limiter := &MethodServiceTpsLimiter{tpsState: newLimitStates()}
+	for i := 0; i < maxCallerLimitStates+100; i++ {
+		invoc := invocation.NewRPCInvocation("hello5", nil, map[string]any{
+			constant.RemoteApplicationKey: "app-" + strconv.Itoa(i),
+		})
+		assert.True(t, limiter.IsAllowable(invokeUrl, invoc))
+	}
+	assert.Equal(t, maxCallerLimitStates, limiter.tpsState.callers.Len())
+	_, found := limiter.tpsState.states.Load(invokeUrl.ServiceKey() + "@" + constant.TPSLimitByApplication + "=app-0")
+	assert.False(t, found)
+}
+
+func TestMethodServiceTpsLimiterImplIsAllowableWithContext(t *testing.T) {
+	invokeUrl := common.NewURLWithOptions(
+		common.WithParams(url.Values{}),
+		common.WithParamsValue(constant.InterfaceKey, "hello6"),
+		common.WithParamsValue(constant.TPSLimitStrategyKey, strategy.LeakyBucketKey),
+		common.WithParamsValue(constant.TPSLimitRateKey, "1"),
+		common.WithParamsValue(constant.TPSLimitIntervalKey, "60000"),
+		common.WithParamsValue(constant.TPSLimitMaxWaitKey, "60000"))
+	invoc := invocation.NewRPCInvocation("hello6", nil, nil)
+
+	limiter := &MethodServiceTpsLimiter{tpsState: newLimitStates()}
+	assert.True(t, limiter.IsAllowable(invokeUrl, invoc))
+	// the next turn is a minute later, after the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.False(t, limiter.IsAllowableWithContext(ctx, invokeUrl, invoc))
+}
@@ -0,0 +1,251 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package store provides the built-in TpsLimitStore implementations.
+package store
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+)
+
+const (
+	// RedisKey is the name of the RedisTpsLimitStore
+	RedisKey = "redis"
+
+	redisPoolSize = 8
+)
+
+var (
+	redisStoresMutex sync.Mutex
+	redisStores      = make(map[redisStoreKey]*RedisTpsLimitStore)
+)
+
+// redisStoreKey is the configuration of a RedisTpsLimitStore, the urls configured the same share the store
+type redisStoreKey struct {
+	address  string
+	password string
+	timeout  time.Duration
+}
+
+func init() {
+	extension.SetTpsLimitStore(RedisKey, newRedisTpsLimitStore)
+}
+
+// RedisTpsLimitStore is a TpsLimitStore speaking the Redis protocol, so it works with Redis and the compatible
+// servers. It keeps a small pool of connections, and pipelines the INCRBY and PEXPIRE of a counter in one round trip.
+/**
+ * "UserProvider":
+ *   ... # other configuration
+ *   tps.limiter: "distributed"
+ *   params:
+ *     tps.limit.store: "redis"
+ *     tps.limit.store.address: "127.0.0.1:6379"
+ *     tps.limit.store.password: "" # optional
+ *     tps.limit.store.timeout: "100ms" # the timeout of dialing and each round trip
+ */
+type RedisTpsLimitStore struct {
+	address  string
+	password string
+	timeout  time.Duration
+	conns    chan *redisConn
+}
+
+type redisConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// newRedisTpsLimitStore returns the RedisTpsLimitStore shared by the urls with the same store configuration
+func newRedisTpsLimitStore(url *common.URL) (filter.TpsLimitStore, error) {
+	key := redisStoreKey{
+		address:  url.GetParam(constant.TPSLimitStoreAddressKey, ""),
+		password: url.GetParam(constant.TPSLimitStorePasswordKey, ""),
+		timeout:  url.GetParamDuration(constant.TPSLimitStoreTimeoutKey, constant.DefaultTPSLimitStoreTimeout),
+	}
+	if len(key.address) == 0 {
+		return nil, fmt.Errorf("the %s of the redis tps limit store is required", constant.TPSLimitStoreAddressKey)
+	}
+
+	redisStoresMutex.Lock()
+	defer redisStoresMutex.Unlock()
+	if s, ok := redisStores[key]; ok {
+		return s, nil
+	}
+	s := NewRedisTpsLimitStore(key.address, key.password, key.timeout)
+	redisStores[key] = s
+	return s, nil
+}
+
+// NewRedisTpsLimitStore creates a RedisTpsLimitStore connecting to the address lazily
+func NewRedisTpsLimitStore(address, password string, timeout time.Duration) *RedisTpsLimitStore {
+	return &RedisTpsLimitStore{
+		address:  address,
+		password: password,
+		timeout:  timeout,
+		conns:    make(chan *redisConn, redisPoolSize),
+	}
+}
+
+// IncrBy adds n to the counter of key and refreshes its ttl
+func (s *RedisTpsLimitStore) IncrBy(key string, n int64, ttl time.Duration) (int64, error) {
+	conn, err := s.getConn()
+	if err != nil {
+		return 0, err
+	}
+	value, err := s.incrBy(conn, key, n, ttl)
+	if err != nil {
+		_ = conn.Close()
+		return 0, err
+	}
+	s.putConn(conn)
+	return value, nil
+}
+
+func (s *RedisTpsLimitStore) incrBy(conn *redisConn, key string, n int64, ttl time.Duration) (int64, error) {
+	if err := conn.SetDeadline(time.Now().Add(s.timeout)); err != nil {
+		return 0, err
+	}
+	cmd := appendCommand(nil, "INCRBY", key, strconv.FormatInt(n, 10))
+	cmd = appendCommand(cmd, "PEXPIRE", key, strconv.FormatInt(ttl.Milliseconds(), 10))
+	if _, err := conn.Write(cmd); err != nil {
+		return 0, err
+	}
+	value, err := readInteger(conn.reader)
+	if err != nil {
+		return 0, err
+	}
+	if _, err = readInteger(conn.reader); err != nil {
+		return 0, err
+	}
+	return value, nil
+}
+
+func (s *RedisTpsLimitStore) getConn() (*redisConn, error) {
+	select {
+	case conn := <-s.conns:
+		return conn, nil
+	default:
+	}
+
+	c, err := net.DialTimeout("tcp", s.address, s.timeout)
+	if err != nil {
+		return nil, err
+	}
+	conn := &redisConn{Conn: c, reader: bufio.NewReader(c)}
+	if len(s.password) > 0 {
+		if err = s.auth(conn); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+func (s *RedisTpsLimitStore) auth(conn *redisConn) error {
+	if err := conn.SetDeadline(time.Now().Add(s.timeout)); err != nil {
+		return err
+	}
+	if _, err := conn.Write(appendCommand(nil, "AUTH", s.password)); err != nil {
+		return err
+	}
+	_, err := readReply(conn.reader)
+	return err
+}
+
+func (s *RedisTpsLimitStore) putConn(conn *redisConn) {
+	select {
+	case s.conns <- conn:
+	default:
+		_ = conn.Close()
+	}
+}
+
+// appendCommand encodes the command as an array of bulk strings
+func appendCommand(buf []byte, args ...string) []byte {
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)), 10)
+	buf = append(buf, '\r', '\n')
+	for _, arg := range args {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+		buf = append(buf, '\r', '\n')
+		buf = append(buf, arg...)
+		buf = append(buf, '\r', '\n')
+	}
+	return buf
+}
+
+func readInteger(r *bufio.Reader) (int64, error) {
+	reply, err := readReply(r)
+	if err != nil {
+		return 0, err
+	}
+	value, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected redis reply %v, an integer is expected", reply)
+	}
+	return value, nil
+}
+
+// readReply reads a simple string, error, integer or bulk string reply
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("malformed redis reply %q", line)
+	}
+	line = line[:len(line)-2]
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, errors.New("redis: " + line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, size+2)
+		if _, err = io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:size]), nil
+	default:
+		return nil, fmt.Errorf("unsupported redis reply %q", line)
+	}
+}
@@ -0,0 +1,251 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package store
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+)
+
+// fakeRedis is an in-process server speaking the subset of the Redis protocol used by RedisTpsLimitStore
+type fakeRedis struct {
+	listener net.Listener
+	password string
+
+	mu       sync.Mutex
+	counters map[string]int64
+	ttls     map[string]int64
+	conns    int
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	s := &fakeRedis{
+		listener: l,
+		password: password,
+		counters: make(map[string]int64),
+		ttls:     make(map[string]int64),
+	}
+	go s.serve()
+	t.Cleanup(func() { _ = l.Close() })
+	return s
+}
+
+func (s *fakeRedis) addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *fakeRedis) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns++
+		s.mu.Unlock()
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	authed := len(s.password) == 0
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		var reply string
+		switch strings.ToUpper(args[0]) {
+		case "AUTH":
+			if args[1] == s.password {
+				authed = true
+				reply = "+OK\r\n"
+			} else {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case "INCRBY":
+			if !authed {
+				reply = "-NOAUTH Authentication required.\r\n"
+				break
+			}
+			n, _ := strconv.ParseInt(args[2], 10, 64)
+			s.mu.Lock()
+			s.counters[args[1]] += n
+			reply = ":" + strconv.FormatInt(s.counters[args[1]], 10) + "\r\n"
+			s.mu.Unlock()
+		case "PEXPIRE":
+			if !authed {
+				reply = "-NOAUTH Authentication required.\r\n"
+				break
+			}
+			ttl, _ := strconv.ParseInt(args[2], 10, 64)
+			s.mu.Lock()
+			s.ttls[args[1]] = ttl
+			s.mu.Unlock()
+			reply = ":1\r\n"
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		if _, err = io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		arg, err := readReply(r)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = arg.(string)
+	}
+	return args, nil
+}
+
+func TestRedisTpsLimitStoreIncrBy(t *testing.T) {
+	server := newFakeRedis(t, "")
+	store := NewRedisTpsLimitStore(server.addr(), "", time.Second)
+
+	for i := 1; i <= 5; i++ {
+		value, err := store.IncrBy("svc:1", 10, 2*time.Second)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(10*i), value)
+	}
+	value, err := store.IncrBy("svc:2", 1, time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), value)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.Equal(t, int64(2000), server.ttls["svc:1"])
+	assert.Equal(t, int64(1000), server.ttls["svc:2"])
+	// the connection is reused
+	assert.Equal(t, 1, server.conns)
+}
+
+func TestRedisTpsLimitStoreConcurrent(t *testing.T) {
+	server := newFakeRedis(t, "")
+	store := NewRedisTpsLimitStore(server.addr(), "", time.Second)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				_, err := store.IncrBy("svc", 1, time.Second)
+				assert.Nil(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	value, err := store.IncrBy("svc", 0, time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(200), value)
+}
+
+func TestRedisTpsLimitStoreAuth(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+
+	value, err := NewRedisTpsLimitStore(server.addr(), "secret", time.Second).IncrBy("svc", 3, time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), value)
+
+	_, err = NewRedisTpsLimitStore(server.addr(), "wrong", time.Second).IncrBy("svc", 3, time.Second)
+	assert.ErrorContains(t, err, "WRONGPASS")
+
+	_, err = NewRedisTpsLimitStore(server.addr(), "", time.Second).IncrBy("svc", 3, time.Second)
+	assert.ErrorContains(t, err, "NOAUTH")
+}
+
+func TestRedisTpsLimitStoreUnreachable(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	addr := l.Addr().String()
+	_ = l.Close()
+
+	_, err = NewRedisTpsLimitStore(addr, "", 100*time.Millisecond).IncrBy("svc", 1, time.Second)
+	assert.NotNil(t, err)
+}
+
+func TestNewRedisTpsLimitStore(t *testing.T) {
+	creator, err := extension.GetTpsLimitStoreCreator(RedisKey)
+	assert.Nil(t, err)
+
+	_, err = creator(common.NewURLWithOptions())
+	assert.NotNil(t, err)
+
+	url := common.NewURLWithOptions(common.WithParamsValue(constant.TPSLimitStoreAddressKey, "127.0.0.1:6379"))
+	s1, err := creator(url)
+	assert.Nil(t, err)
+	s2, err := creator(url.Clone())
+	assert.Nil(t, err)
+	assert.Same(t, s1, s2)
+}
+
+func TestNewRedisTpsLimitStoreByConfig(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	creator, err := extension.GetTpsLimitStoreCreator(RedisKey)
+	assert.Nil(t, err)
+
+	// the urls with the same address but different passwords don't share the store
+	wrong, err := creator(common.NewURLWithOptions(
+		common.WithParamsValue(constant.TPSLimitStoreAddressKey, server.addr()),
+		common.WithParamsValue(constant.TPSLimitStorePasswordKey, "wrong")))
+	assert.Nil(t, err)
+	right, err := creator(common.NewURLWithOptions(
+		common.WithParamsValue(constant.TPSLimitStoreAddressKey, server.addr()),
+		common.WithParamsValue(constant.TPSLimitStorePasswordKey, "secret")))
+	assert.Nil(t, err)
+	assert.NotSame(t, wrong, right)
+
+	value, err := right.IncrBy("svc", 3, time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), value)
+}
@@ -0,0 +1,147 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package strategy
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+)
+
+const (
+	// LeakyBucketKey defines limiter limit algorithm
+	LeakyBucketKey = "leakyBucket"
+)
+
+func init() {
+	extension.SetTpsLimitStrategy(LeakyBucketKey, &leakyBucketStrategyCreator{})
+}
+
+// LeakyBucketTpsLimitStrategy implements a thread-safe TPS limit strategy base on leaky bucket.
+/**
+ * The requests leak out of the bucket evenly, one per interval/rate. A request arriving before its turn waits
+ * in the bucket, and it's rejected if it would wait longer than tps.limit.max.wait or past its deadline.
+ * "UserProvider":
+ *   registry: "hangzhouzk"
+ *   protocol : "dubbo"
+ *   interface : "com.ikurento.user.UserProvider"
+ *   ... # other configuration
+ *   tps.limiter: "method-service" # the name of limiter
+ *   tps.limit.strategy: "leakyBucket" # service-level
+ *   tps.limit.interval: 1000
+ *   tps.limit.rate: 100
+ *   tps.limit.max.wait: 200 # the time unit is ms, 0 means the request never waits
+ *   methods:
+ *    - name: "GetUser"
+ *      tps.limit.strategy: "leakyBucket" # method-level
+ */
+type LeakyBucketTpsLimitStrategy struct {
+	mutex *sync.Mutex
+	// gap between two requests
+	gap     time.Duration
+	maxWait time.Duration
+	// the time the next request could leak out
+	next  time.Time
+	now   func() time.Time
+	sleep func(context.Context, time.Duration) bool
+}
+
+// IsAllowable waits for the turn of the request, or returns false at once if the turn is later than the max wait.
+// It is thread-safe.
+func (impl *LeakyBucketTpsLimitStrategy) IsAllowable() bool {
+	return impl.IsAllowableWithContext(context.Background())
+}
+
+// IsAllowableWithContext is like IsAllowable, but it returns false at once if the turn is later than the deadline
+// of ctx, and stops waiting when ctx is done.
+func (impl *LeakyBucketTpsLimitStrategy) IsAllowableWithContext(ctx context.Context) bool {
+	if impl.gap <= 0 {
+		// the rate is not positive
+		return false
+	}
+	impl.mutex.Lock()
+	current := impl.now()
+	if impl.next.Before(current) {
+		impl.next = current
+	}
+	wait := impl.next.Sub(current)
+	if wait > impl.maxWait {
+		impl.mutex.Unlock()
+		return false
+	}
+	if deadline, ok := ctx.Deadline(); ok && wait > 0 && current.Add(wait).After(deadline) {
+		// the request would time out before its turn
+		impl.mutex.Unlock()
+		return false
+	}
+	impl.next = impl.next.Add(impl.gap)
+	impl.mutex.Unlock()
+
+	if wait > 0 && !impl.sleep(ctx, wait) {
+		// gives the turn back, so the requests after it are not delayed by the cancelled one
+		impl.mutex.Lock()
+		impl.next = impl.next.Add(-impl.gap)
+		impl.mutex.Unlock()
+		return false
+	}
+	return true
+}
+
+type leakyBucketStrategyCreator struct{}
+
+// Create returns a LeakyBucketTpsLimitStrategy instance which never lets the requests wait
+func (creator *leakyBucketStrategyCreator) Create(rate int, interval int) filter.TpsLimitStrategy {
+	return creator.CreateWithConfig(&filter.TpsLimitStrategyConfig{Rate: rate, Interval: interval})
+}
+
+// CreateWithConfig returns a LeakyBucketTpsLimitStrategy instance with configured rate, interval and max wait
+func (creator *leakyBucketStrategyCreator) CreateWithConfig(config *filter.TpsLimitStrategyConfig) filter.TpsLimitStrategy {
+	return newLeakyBucket(config, time.Now, sleepContext)
+}
+
+// sleepContext waits for d, it returns false if ctx is done before that
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func newLeakyBucket(config *filter.TpsLimitStrategyConfig, now func() time.Time,
+	sleep func(context.Context, time.Duration) bool) *LeakyBucketTpsLimitStrategy {
+	var gap time.Duration
+	if config.Rate > 0 {
+		gap = time.Duration(config.Interval) * time.Millisecond / time.Duration(config.Rate)
+	}
+	return &LeakyBucketTpsLimitStrategy{
+		mutex:   &sync.Mutex{},
+		gap:     gap,
+		maxWait: config.MaxWait,
+		now:     now,
+		sleep:   sleep,
+	}
+}
@@ -0,0 +1,106 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package strategy
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/filter"
+)
+
+func TestLeakyBucketTpsLimitStrategyImplIsAllowable(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	// one request per 100ms, waits 250ms at most
+	strategy := newLeakyBucket(&filter.TpsLimitStrategyConfig{Rate: 10, Interval: 1000, MaxWait: 250 * time.Millisecond},
+		clock.Now, func(context.Context, time.Duration) bool { return true })
+	assert.True(t, strategy.IsAllowable())  // at once
+	assert.True(t, strategy.IsAllowable())  // waits 100ms
+	assert.True(t, strategy.IsAllowable())  // waits 200ms
+	assert.False(t, strategy.IsAllowable()) // would wait 300ms
+
+	clock.Sleep(time.Second)
+	assert.True(t, strategy.IsAllowable())
+}
+
+func TestLeakyBucketTpsLimitStrategyImplWaits(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	var waited []time.Duration
+	strategy := newLeakyBucket(&filter.TpsLimitStrategyConfig{Rate: 10, Interval: 1000, MaxWait: time.Second},
+		clock.Now, func(_ context.Context, d time.Duration) bool {
+			waited = append(waited, d)
+			return true
+		})
+	for i := 0; i < 3; i++ {
+		assert.True(t, strategy.IsAllowable())
+	}
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, waited)
+
+	// the requests never wait without max wait
+	strategy = newLeakyBucket(&filter.TpsLimitStrategyConfig{Rate: 10, Interval: 1000}, clock.Now, sleepContext)
+	assert.True(t, strategy.IsAllowable())
+	assert.False(t, strategy.IsAllowable())
+
+	strategy = newLeakyBucket(&filter.TpsLimitStrategyConfig{Rate: 0, Interval: 1000}, clock.Now, sleepContext)
+	assert.False(t, strategy.IsAllowable())
+}
+
+func TestLeakyBucketTpsLimitStrategyImplDeadline(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	strategy := newLeakyBucket(&filter.TpsLimitStrategyConfig{Rate: 10, Interval: 1000, MaxWait: time.Second},
+		clock.Now, func(context.Context, time.Duration) bool { return true })
+	ctx, cancel := context.WithDeadline(context.Background(), clock.now.Add(150*time.Millisecond))
+	defer cancel()
+	assert.True(t, strategy.IsAllowableWithContext(ctx))  // at once
+	assert.True(t, strategy.IsAllowableWithContext(ctx))  // waits 100ms
+	assert.False(t, strategy.IsAllowableWithContext(ctx)) // would wait 200ms, past the deadline
+	// the rejected request doesn't take a turn
+	assert.True(t, strategy.IsAllowable())
+
+	// stops waiting when the context is done
+	strategy = newLeakyBucket(&filter.TpsLimitStrategyConfig{Rate: 1, Interval: 60000, MaxWait: time.Minute},
+		time.Now, sleepContext)
+	assert.True(t, strategy.IsAllowable())
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, strategy.IsAllowableWithContext(canceled))
+}
+
+func TestLeakyBucketTpsLimitStrategyImplReleasesCanceledTurn(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	var waited []time.Duration
+	canceled := true
+	strategy := newLeakyBucket(&filter.TpsLimitStrategyConfig{Rate: 10, Interval: 1000, MaxWait: time.Second},
+		clock.Now, func(_ context.Context, d time.Duration) bool {
+			waited = append(waited, d)
+			return !canceled
+		})
+	assert.True(t, strategy.IsAllowable())  // at once
+	assert.False(t, strategy.IsAllowable()) // canceled while waiting 100ms
+	canceled = false
+	// the canceled turn is taken by the next request
+	assert.True(t, strategy.IsAllowable())
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 100 * time.Millisecond}, waited)
+}
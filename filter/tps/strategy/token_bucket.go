@@ -0,0 +1,116 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package strategy
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+)
+
+const (
+	// TokenBucketKey defines limiter limit algorithm
+	TokenBucketKey = "tokenBucket"
+)
+
+func init() {
+	extension.SetTpsLimitStrategy(TokenBucketKey, &tokenBucketStrategyCreator{})
+}
+
+// TokenBucketTpsLimitStrategy implements a thread-safe TPS limit strategy base on token bucket.
+/**
+ * The bucket is refilled with rate tokens per interval smoothly, and holds burst tokens at most.
+ * So it allows a burst of requests after a quiet period, instead of rejecting them at the window boundaries.
+ * "UserProvider":
+ *   registry: "hangzhouzk"
+ *   protocol : "dubbo"
+ *   interface : "com.ikurento.user.UserProvider"
+ *   ... # other configuration
+ *   tps.limiter: "method-service" # the name of limiter
+ *   tps.limit.strategy: "tokenBucket" # service-level
+ *   tps.limit.interval: 1000
+ *   tps.limit.rate: 100
+ *   tps.limit.burst: 20 # the size of the bucket, it's tps.limit.rate by default
+ *   methods:
+ *    - name: "GetUser"
+ *      tps.limit.strategy: "tokenBucket" # method-level
+ */
+type TokenBucketTpsLimitStrategy struct {
+	mutex *sync.Mutex
+	// tokens refilled per nanosecond
+	fillRate float64
+	burst    float64
+	tokens   float64
+	last     time.Time
+	now      func() time.Time
+}
+
+// IsAllowable takes a token from the bucket if there is any.
+// It is thread-safe.
+func (impl *TokenBucketTpsLimitStrategy) IsAllowable() bool {
+	impl.mutex.Lock()
+	defer impl.mutex.Unlock()
+
+	current := impl.now()
+	impl.tokens = math.Min(impl.burst, impl.tokens+float64(current.Sub(impl.last))*impl.fillRate)
+	impl.last = current
+	if impl.tokens < 1 {
+		return false
+	}
+	impl.tokens--
+	return true
+}
+
+type tokenBucketStrategyCreator struct{}
+
+// Create returns a TokenBucketTpsLimitStrategy instance whose burst is the rate
+func (creator *tokenBucketStrategyCreator) Create(rate int, interval int) filter.TpsLimitStrategy {
+	return creator.CreateWithConfig(&filter.TpsLimitStrategyConfig{Rate: rate, Interval: interval})
+}
+
+// CreateWithConfig returns a TokenBucketTpsLimitStrategy instance with configured rate, interval and burst
+func (creator *tokenBucketStrategyCreator) CreateWithConfig(config *filter.TpsLimitStrategyConfig) filter.TpsLimitStrategy {
+	return newTokenBucket(config, time.Now)
+}
+
+func newTokenBucket(config *filter.TpsLimitStrategyConfig, now func() time.Time) *TokenBucketTpsLimitStrategy {
+	burst := config.Burst
+	if burst <= 0 {
+		burst = config.Rate
+	}
+	var fillRate float64
+	if config.Interval > 0 {
+		fillRate = float64(config.Rate) / float64(int64(config.Interval)*int64(time.Millisecond))
+	} else {
+		// the interval is not positive, the empty bucket is never refilled and rejects all the requests
+		burst = 0
+	}
+	return &TokenBucketTpsLimitStrategy{
+		mutex:    &sync.Mutex{},
+		fillRate: fillRate,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		last:     now(),
+		now:      now,
+	}
+}
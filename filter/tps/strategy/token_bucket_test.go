@@ -0,0 +1,78 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package strategy
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/filter"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestTokenBucketTpsLimitStrategyImplIsAllowable(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	// 10 requests per second, 3 at once
+	strategy := newTokenBucket(&filter.TpsLimitStrategyConfig{Rate: 10, Interval: 1000, Burst: 3}, clock.Now)
+	assert.True(t, strategy.IsAllowable())
+	assert.True(t, strategy.IsAllowable())
+	assert.True(t, strategy.IsAllowable())
+	assert.False(t, strategy.IsAllowable())
+
+	clock.Sleep(100 * time.Millisecond)
+	assert.True(t, strategy.IsAllowable())
+	assert.False(t, strategy.IsAllowable())
+
+	// the bucket holds burst tokens at most
+	clock.Sleep(10 * time.Second)
+	assert.True(t, strategy.IsAllowable())
+	assert.True(t, strategy.IsAllowable())
+	assert.True(t, strategy.IsAllowable())
+	assert.False(t, strategy.IsAllowable())
+}
+
+func TestTokenBucketTpsLimitStrategyCreatorDefaultBurst(t *testing.T) {
+	strategy := (&tokenBucketStrategyCreator{}).Create(2, 60000)
+	assert.True(t, strategy.IsAllowable())
+	assert.True(t, strategy.IsAllowable())
+	assert.False(t, strategy.IsAllowable())
+}
+
+func TestTokenBucketTpsLimitStrategyCreatorInvalidInterval(t *testing.T) {
+	for _, interval := range []int{0, -1000} {
+		strategy := (&tokenBucketStrategyCreator{}).Create(2, interval)
+		assert.False(t, strategy.IsAllowable())
+	}
+}
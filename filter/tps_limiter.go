@@ -17,6 +17,10 @@
 
 package filter
 
+import (
+	"context"
+)
+
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/protocol/base"
@@ -52,3 +56,10 @@ import (
 type TpsLimiter interface {
 	IsAllowable(*common.URL, base.Invocation) bool
 }
+
+// ContextualTpsLimiter is the TpsLimiter which passes the context of the invocation to the strategies,
+// so that the strategies letting the requests wait respect the deadline of the invocation.
+type ContextualTpsLimiter interface {
+	TpsLimiter
+	IsAllowableWithContext(context.Context, *common.URL, base.Invocation) bool
+}
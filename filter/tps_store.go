@@ -0,0 +1,40 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter
+
+import (
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+)
+
+// TpsLimitStore is the store of the counters shared by all the providers of a service,
+// which makes the TPS limit cluster-wide.
+//
+// please register your implementation by invoking SetTpsLimitStore
+type TpsLimitStore interface {
+	// IncrBy adds n to the counter of key and returns the new value.
+	// The counter expires after ttl, so the counters of the past windows are cleaned up.
+	IncrBy(key string, n int64, ttl time.Duration) (int64, error)
+}
+
+// TpsLimitStoreCreator creates the TpsLimitStore configured by the url, the implementation may share the
+// store among the urls with the same configuration.
+type TpsLimitStoreCreator func(url *common.URL) (TpsLimitStore, error)
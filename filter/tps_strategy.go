@@ -17,6 +17,11 @@
 
 package filter
 
+import (
+	"context"
+	"time"
+)
+
 // TpsLimitStrategy is the interface which defines how to do the TPS limiting in method level.
 //
 // IsAllowable will return true if this invocation is not over limitation.
@@ -24,6 +29,15 @@ type TpsLimitStrategy interface {
 	IsAllowable() bool
 }
 
+// ContextualTpsLimitStrategy is the TpsLimitStrategy which may let the request wait for its turn.
+//
+// IsAllowableWithContext rejects the request at once if its turn is later than the deadline of ctx,
+// and stops waiting when ctx is done.
+type ContextualTpsLimitStrategy interface {
+	TpsLimitStrategy
+	IsAllowableWithContext(ctx context.Context) bool
+}
+
 // TpsLimitStrategyCreator is the interface which creates TpsLimitStrategy.
 type TpsLimitStrategyCreator interface {
 	// Create will create an instance of TpsLimitStrategy
@@ -33,3 +47,23 @@ type TpsLimitStrategyCreator interface {
 	// which means that the limiter limitation is 100 times per 1000ms (100/1000ms)
 	Create(limit int, interval int) TpsLimitStrategy
 }
+
+// TpsLimitStrategyConfig is the configuration of a TpsLimitStrategy.
+type TpsLimitStrategyConfig struct {
+	// Rate is the max number of requests in Interval
+	Rate int
+	// Interval is in ms
+	Interval int
+	// Burst is the max number of requests allowed at once, Rate is used if it is not positive
+	Burst int
+	// MaxWait is how long a request may wait for its turn before it is rejected
+	MaxWait time.Duration
+}
+
+// ConfigurableTpsLimitStrategyCreator is the TpsLimitStrategyCreator which accepts more configuration
+// than the rate and interval, like the burst and the max wait.
+type ConfigurableTpsLimitStrategyCreator interface {
+	TpsLimitStrategyCreator
+	// CreateWithConfig will create an instance of TpsLimitStrategy
+	CreateWithConfig(config *TpsLimitStrategyConfig) TpsLimitStrategy
+}
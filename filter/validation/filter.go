@@ -0,0 +1,128 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package validation provides a provider filter that validates invocation arguments
+// before they reach the service implementation.
+/*
+ Go struct arguments are checked against their `validate:"..."` tags
+ (see github.com/go-playground/validator), and protobuf arguments used in triple IDL mode
+ are checked against the buf.validate (protovalidate) annotations of their descriptors.
+
+ example:
+ "UserProvider":
+   interface : "com.ikurento.user.UserProvider"
+   validation: true  # adds the filter to the service filter chain
+   methods:
+    - name: "Ping"
+      validation: false  # arguments of Ping are not validated
+
+ With the new programming model, use server.WithServerValidation, server.WithValidation
+ or config.WithValidation for the method level.
+
+ Invalid invocations are rejected with a triple error of CodeInvalidArgument which wraps
+ an *Error listing the offending field paths, and carries a google.rpc.BadRequest detail.
+
+ The standard field rules, buf.validate.message disabled and buf.validate.oneof required are supported.
+ The protobuf arguments whose descriptors declare any other rule, such as CEL expressions, are rejected
+ with a triple error of CodeInternal wrapping ErrUnsupportedRules, rather than passed without the check.
+*/
+package validation
+
+import (
+	"context"
+	"strconv"
+	"sync"
+)
+
+import (
+	"google.golang.org/protobuf/proto"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+)
+
+var (
+	once       sync.Once
+	validation *validationFilter
+)
+
+func init() {
+	extension.SetFilter(constant.ValidationFilterKey, newValidationFilter)
+}
+
+// validationFilter rejects invocations whose arguments break their declared constraints
+type validationFilter struct{}
+
+func newValidationFilter() filter.Filter {
+	if validation == nil {
+		once.Do(func() {
+			validation = &validationFilter{}
+		})
+	}
+	return validation
+}
+
+// Invoke validates the arguments and only calls the invoker when all of them are valid
+func (f *validationFilter) Invoke(ctx context.Context, invoker base.Invoker, invocation base.Invocation) result.Result {
+	url := invoker.GetURL()
+	methodName := invocation.MethodName()
+	if !url.GetMethodParamBool(methodName, constant.ValidationKey, url.GetParamBool(constant.ValidationKey, true)) {
+		return invoker.Invoke(ctx, invocation)
+	}
+	if err := Validate(invocation.Arguments()...); err != nil {
+		return &result.RPCResult{Err: toRPCError(err)}
+	}
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *validationFilter) OnResponse(ctx context.Context, result result.Result, invoker base.Invoker, invocation base.Invocation) result.Result {
+	return result
+}
+
+// Validate checks every argument and returns an *Error collecting all violations, or nil.
+// When more than one argument is given, field paths are prefixed with the argument index.
+// An error wrapping ErrUnsupportedRules is returned instead if the rules of an argument cannot be checked.
+func Validate(args ...any) error {
+	var violations []*Violation
+	for i, arg := range args {
+		var argViolations []*Violation
+		if msg, ok := arg.(proto.Message); ok {
+			var err error
+			if argViolations, err = validateProto(msg); err != nil {
+				return err
+			}
+		} else {
+			argViolations = validateStruct(arg)
+		}
+		if len(args) > 1 {
+			for _, v := range argViolations {
+				v.Field = joinPath("arg"+strconv.Itoa(i), v.Field)
+			}
+		}
+		violations = append(violations, argViolations...)
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return &Error{Violations: violations}
+}
@@ -0,0 +1,100 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validation
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+)
+
+type address struct {
+	City string `validate:"required"`
+}
+
+type user struct {
+	Name    string `validate:"required,min=3"`
+	Age     int    `validate:"gte=18"`
+	Address address
+}
+
+func TestValidationFilterInvoke(t *testing.T) {
+	filter := newValidationFilter()
+	invoker := base.NewBaseInvoker(common.NewURLWithOptions(common.WithParams(url.Values{})))
+
+	res := filter.Invoke(context.Background(), invoker,
+		invocation.NewRPCInvocation("GetUser", []any{&user{Name: "dubbo", Age: 18, Address: address{City: "Hangzhou"}}}, nil))
+	assert.Nil(t, res.Error())
+
+	res = filter.Invoke(context.Background(), invoker,
+		invocation.NewRPCInvocation("GetUser", []any{user{Name: "go", Age: 3}}, nil))
+	assert.NotNil(t, res.Error())
+	assert.Equal(t, triple_protocol.CodeInvalidArgument, triple_protocol.CodeOf(res.Error()))
+
+	var verr *Error
+	assert.True(t, errors.As(res.Error(), &verr))
+	fields := make([]string, 0, len(verr.Violations))
+	for _, v := range verr.Violations {
+		fields = append(fields, v.Field)
+	}
+	assert.ElementsMatch(t, []string{"Name", "Age", "Address.City"}, fields)
+
+	var tripleErr *triple_protocol.Error
+	assert.True(t, errors.As(res.Error(), &tripleErr))
+	assert.Len(t, tripleErr.Details(), 1)
+	detail, err := tripleErr.Details()[0].Value()
+	assert.Nil(t, err)
+	assert.Len(t, detail.(*errdetails.BadRequest).FieldViolations, 3)
+}
+
+func TestValidationFilterInvokeDisabledMethod(t *testing.T) {
+	filter := newValidationFilter()
+	invoker := base.NewBaseInvoker(common.NewURLWithOptions(
+		common.WithParams(url.Values{}),
+		common.WithParamsValue("methods.Ping."+constant.ValidationKey, "false")))
+
+	res := filter.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("Ping", []any{user{}}, nil))
+	assert.Nil(t, res.Error())
+
+	res = filter.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", []any{user{}}, nil))
+	assert.NotNil(t, res.Error())
+}
+
+func TestValidateMultipleArguments(t *testing.T) {
+	var err *Error
+	require.ErrorAs(t, Validate("not a struct", nil, &user{Name: "dubbo", Age: 1, Address: address{City: "Hangzhou"}}), &err)
+	assert.Len(t, err.Violations, 1)
+	assert.Equal(t, "arg2.Age", err.Violations[0].Field)
+	assert.Equal(t, "gte", err.Violations[0].Rule)
+}
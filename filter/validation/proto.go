@@ -0,0 +1,392 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validation
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var (
+	uuidPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hostnamePattern = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?\.)*[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+)
+
+// validateProto checks msg against the buf.validate annotations in its descriptor,
+// descending into nested messages, lists and maps. An error wrapping ErrUnsupportedRules
+// is returned if the descriptors declare rules which cannot be checked.
+func validateProto(msg proto.Message) ([]*Violation, error) {
+	m := msg.ProtoReflect()
+	if !m.IsValid() {
+		return nil, nil
+	}
+	if err := checkRules(m.Descriptor()); err != nil {
+		return nil, err
+	}
+	var violations []*Violation
+	validateMessage(m, "", &violations)
+	return violations, nil
+}
+
+func validateMessage(msg protoreflect.Message, path string, out *[]*Violation) {
+	desc := msg.Descriptor()
+	if rules := messageRulesFor(desc); rules != nil && rules.disabled {
+		return
+	}
+	oneofs := desc.Oneofs()
+	for i := 0; i < oneofs.Len(); i++ {
+		od := oneofs.Get(i)
+		if rules := oneofRulesFor(od); rules != nil && rules.required && msg.WhichOneof(od) == nil {
+			*out = append(*out, &Violation{Field: joinPath(path, string(od.Name())), Rule: "required", Description: "exactly one field is required in oneof"})
+		}
+	}
+	fields := desc.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		validateField(msg, fd, rulesFor(fd), joinPath(path, string(fd.Name())), out)
+	}
+}
+
+func validateField(msg protoreflect.Message, fd protoreflect.FieldDescriptor, rules *fieldRules, path string, out *[]*Violation) {
+	if rules != nil && rules.ignore == ignoreAlways {
+		return
+	}
+	populated := msg.Has(fd)
+	if rules != nil && rules.required && !populated {
+		*out = append(*out, &Violation{Field: path, Rule: "required", Description: "value is required"})
+		return
+	}
+	if !populated && (fd.HasPresence() || (rules != nil && rules.ignore == ignoreIfUnpopulated)) {
+		return
+	}
+
+	val := msg.Get(fd)
+	if rules != nil && rules.ignore == ignoreIfDefaultValue && (!populated || isDefaultValue(fd, val)) {
+		return
+	}
+	switch {
+	case fd.IsList():
+		validateList(fd, val.List(), rules, path, out)
+	case fd.IsMap():
+		validateMap(fd, val.Map(), rules, path, out)
+	case fd.Message() != nil:
+		validateMessage(val.Message(), path, out)
+	default:
+		validateScalar(fd, val, rules, path, out)
+	}
+}
+
+// isDefaultValue reports whether val is the default value of fd, an empty message for message fields
+func isDefaultValue(fd protoreflect.FieldDescriptor, val protoreflect.Value) bool {
+	switch {
+	case fd.IsList():
+		return val.List().Len() == 0
+	case fd.IsMap():
+		return val.Map().Len() == 0
+	case fd.Message() != nil:
+		return proto.Size(val.Message().Interface()) == 0
+	default:
+		return val.Equal(fd.Default())
+	}
+}
+
+func validateList(fd protoreflect.FieldDescriptor, list protoreflect.List, rules *fieldRules, path string, out *[]*Violation) {
+	var items *fieldRules
+	if rules != nil && rules.repeated != nil {
+		r := rules.repeated
+		size := uint64(list.Len())
+		if r.minItems != nil && size < *r.minItems {
+			addViolation(out, path, "repeated.min_items", "value must contain at least %d item(s)", *r.minItems)
+		}
+		if r.maxItems != nil && size > *r.maxItems {
+			addViolation(out, path, "repeated.max_items", "value must contain no more than %d item(s)", *r.maxItems)
+		}
+		if r.unique && fd.Message() == nil {
+			seen := make(map[any]struct{}, list.Len())
+			for i := 0; i < list.Len(); i++ {
+				key := list.Get(i).Interface()
+				if b, ok := key.([]byte); ok {
+					key = string(b)
+				}
+				if _, dup := seen[key]; dup {
+					addViolation(out, path, "repeated.unique", "repeated value must contain unique items")
+					break
+				}
+				seen[key] = struct{}{}
+			}
+		}
+		items = r.items
+	}
+	for i := 0; i < list.Len(); i++ {
+		itemPath := path + "[" + strconv.Itoa(i) + "]"
+		if fd.Message() != nil {
+			validateMessage(list.Get(i).Message(), itemPath, out)
+			continue
+		}
+		validateScalar(fd, list.Get(i), items, itemPath, out)
+	}
+}
+
+func validateMap(fd protoreflect.FieldDescriptor, m protoreflect.Map, rules *fieldRules, path string, out *[]*Violation) {
+	var keys, values *fieldRules
+	if rules != nil && rules.mapRules != nil {
+		r := rules.mapRules
+		size := uint64(m.Len())
+		if r.minPairs != nil && size < *r.minPairs {
+			addViolation(out, path, "map.min_pairs", "map must be at least %d entries", *r.minPairs)
+		}
+		if r.maxPairs != nil && size > *r.maxPairs {
+			addViolation(out, path, "map.max_pairs", "map must be at most %d entries", *r.maxPairs)
+		}
+		keys, values = r.keys, r.values
+	}
+	keyFd, valueFd := fd.MapKey(), fd.MapValue()
+	m.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+		entryPath := path + "[" + fmt.Sprint(k.Interface()) + "]"
+		validateScalar(keyFd, k.Value(), keys, entryPath, out)
+		if valueFd.Message() != nil {
+			validateMessage(v.Message(), entryPath, out)
+		} else {
+			validateScalar(valueFd, v, values, entryPath, out)
+		}
+		return true
+	})
+}
+
+func validateScalar(fd protoreflect.FieldDescriptor, val protoreflect.Value, rules *fieldRules, path string, out *[]*Violation) {
+	if rules == nil {
+		return
+	}
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		if rules.str != nil {
+			validateString(val.String(), rules.str, path, out)
+		}
+	case protoreflect.BytesKind:
+		if rules.bytes != nil {
+			validateBytes(val.Bytes(), rules.bytes, path, out)
+		}
+	case protoreflect.EnumKind:
+		if rules.enum != nil {
+			if rules.enum.definedOnly && fd.Enum().Values().ByNumber(val.Enum()) == nil {
+				addViolation(out, path, "enum.defined_only", "value must be one of the defined enum values")
+			}
+			validateNumber(numberOf(fd.Kind(), val), &rules.enum.number, "enum", path, out)
+		}
+	case protoreflect.BoolKind:
+		return
+	default:
+		if rules.number != nil {
+			validateNumber(numberOf(fd.Kind(), val), rules.number, strings.ToLower(fd.Kind().String()), path, out)
+		}
+	}
+}
+
+// numberOf returns the value of a numeric field in the representation of the rules of its kind.
+func numberOf(kind protoreflect.Kind, val protoreflect.Value) number {
+	switch kind {
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return number{kind: floatNumber, f: val.Float()}
+	case protoreflect.Uint32Kind, protoreflect.Uint64Kind, protoreflect.Fixed32Kind, protoreflect.Fixed64Kind:
+		return number{kind: unsignedNumber, u: val.Uint()}
+	case protoreflect.EnumKind:
+		return number{kind: signedNumber, i: int64(val.Enum())}
+	default:
+		return number{kind: signedNumber, i: val.Int()}
+	}
+}
+
+func validateNumber(n number, r *numberRules, prefix, path string, out *[]*Violation) {
+	if r.constVal != nil && !n.equal(*r.constVal) {
+		addViolation(out, path, prefix+".const", "value must equal %v", *r.constVal)
+	}
+	// the comparisons are written so that NaN breaks every bound
+	var lower, upper *number
+	var lowerRule, upperRule, lowerDesc, upperDesc string
+	lowerOK, upperOK := true, true
+	switch {
+	case r.gt != nil:
+		lower, lowerRule, lowerDesc, lowerOK = r.gt, "gt", "greater than", r.gt.less(n)
+	case r.gte != nil:
+		lower, lowerRule, lowerDesc, lowerOK = r.gte, "gte", "greater than or equal to", r.gte.less(n) || r.gte.equal(n)
+	}
+	switch {
+	case r.lt != nil:
+		upper, upperRule, upperDesc, upperOK = r.lt, "lt", "less than", n.less(*r.lt)
+	case r.lte != nil:
+		upper, upperRule, upperDesc, upperOK = r.lte, "lte", "less than or equal to", n.less(*r.lte) || n.equal(*r.lte)
+	}
+	switch {
+	case lower == nil && upper == nil:
+	case upper == nil:
+		if !lowerOK {
+			addViolation(out, path, prefix+"."+lowerRule, "value must be %s %v", lowerDesc, *lower)
+		}
+	case lower == nil:
+		if !upperOK {
+			addViolation(out, path, prefix+"."+upperRule, "value must be %s %v", upperDesc, *upper)
+		}
+	case upper.less(*lower) || (r.gt != nil && r.lt != nil && r.lt.equal(*r.gt)):
+		// a lower bound above the upper bound only excludes the values between them,
+		// gt and lt on the same value exclude just that value
+		if !lowerOK && !upperOK {
+			addViolation(out, path, prefix+"."+lowerRule+"_"+upperRule+"_exclusive",
+				"value must be %s %v or %s %v", lowerDesc, *lower, upperDesc, *upper)
+		}
+	default:
+		if !lowerOK || !upperOK {
+			addViolation(out, path, prefix+"."+lowerRule+"_"+upperRule,
+				"value must be %s %v and %s %v", lowerDesc, *lower, upperDesc, *upper)
+		}
+	}
+	if len(r.in) > 0 && !containsNumber(r.in, n) {
+		addViolation(out, path, prefix+".in", "value must be in list %v", r.in)
+	}
+	if len(r.notIn) > 0 && containsNumber(r.notIn, n) {
+		addViolation(out, path, prefix+".not_in", "value must not be in list %v", r.notIn)
+	}
+	if r.finite && (math.IsInf(n.f, 0) || math.IsNaN(n.f)) {
+		addViolation(out, path, prefix+".finite", "value must be finite")
+	}
+}
+
+func validateString(s string, r *stringRules, path string, out *[]*Violation) {
+	chars := uint64(utf8.RuneCountInString(s))
+	if r.constVal != nil && s != *r.constVal {
+		addViolation(out, path, "string.const", "value must equal `%s`", *r.constVal)
+	}
+	if r.length != nil && chars != *r.length {
+		addViolation(out, path, "string.len", "value length must be %d characters", *r.length)
+	}
+	if r.minLen != nil && chars < *r.minLen {
+		addViolation(out, path, "string.min_len", "value length must be at least %d characters", *r.minLen)
+	}
+	if r.maxLen != nil && chars > *r.maxLen {
+		addViolation(out, path, "string.max_len", "value length must be at most %d characters", *r.maxLen)
+	}
+	if r.lenBytes != nil && uint64(len(s)) != *r.lenBytes {
+		addViolation(out, path, "string.len_bytes", "value length must be %d bytes", *r.lenBytes)
+	}
+	if r.minBytes != nil && uint64(len(s)) < *r.minBytes {
+		addViolation(out, path, "string.min_bytes", "value length must be at least %d bytes", *r.minBytes)
+	}
+	if r.maxBytes != nil && uint64(len(s)) > *r.maxBytes {
+		addViolation(out, path, "string.max_bytes", "value length must be at most %d bytes", *r.maxBytes)
+	}
+	if r.pattern != nil && !r.pattern.MatchString(s) {
+		addViolation(out, path, "string.pattern", "value does not match regex pattern `%s`", r.pattern.String())
+	}
+	if r.prefix != nil && !strings.HasPrefix(s, *r.prefix) {
+		addViolation(out, path, "string.prefix", "value does not have prefix `%s`", *r.prefix)
+	}
+	if r.suffix != nil && !strings.HasSuffix(s, *r.suffix) {
+		addViolation(out, path, "string.suffix", "value does not have suffix `%s`", *r.suffix)
+	}
+	if r.contains != nil && !strings.Contains(s, *r.contains) {
+		addViolation(out, path, "string.contains", "value does not contain substring `%s`", *r.contains)
+	}
+	if r.notContains != nil && strings.Contains(s, *r.notContains) {
+		addViolation(out, path, "string.not_contains", "value contains substring `%s`", *r.notContains)
+	}
+	if len(r.in) > 0 && !containsString(r.in, s) {
+		addViolation(out, path, "string.in", "value must be in list %v", r.in)
+	}
+	if len(r.notIn) > 0 && containsString(r.notIn, s) {
+		addViolation(out, path, "string.not_in", "value must not be in list %v", r.notIn)
+	}
+	if r.email {
+		if addr, err := mail.ParseAddress(s); err != nil || addr.Address != s {
+			addViolation(out, path, "string.email", "value must be a valid email address")
+		}
+	}
+	if r.hostname && (len(s) > 253 || !hostnamePattern.MatchString(s)) {
+		addViolation(out, path, "string.hostname", "value must be a valid hostname")
+	}
+	ip := net.ParseIP(s)
+	if r.ip && ip == nil {
+		addViolation(out, path, "string.ip", "value must be a valid IP address")
+	}
+	if r.ipv4 && (ip == nil || ip.To4() == nil) {
+		addViolation(out, path, "string.ipv4", "value must be a valid IPv4 address")
+	}
+	if r.ipv6 && (ip == nil || ip.To4() != nil) {
+		addViolation(out, path, "string.ipv6", "value must be a valid IPv6 address")
+	}
+	if r.uri {
+		if u, err := url.Parse(s); err != nil || !u.IsAbs() {
+			addViolation(out, path, "string.uri", "value must be a valid URI")
+		}
+	}
+	if r.uuid && !uuidPattern.MatchString(s) {
+		addViolation(out, path, "string.uuid", "value must be a valid UUID")
+	}
+}
+
+func validateBytes(b []byte, r *bytesRules, path string, out *[]*Violation) {
+	size := uint64(len(b))
+	if r.length != nil && size != *r.length {
+		addViolation(out, path, "bytes.len", "value length must be %d bytes", *r.length)
+	}
+	if r.minLen != nil && size < *r.minLen {
+		addViolation(out, path, "bytes.min_len", "value length must be at least %d bytes", *r.minLen)
+	}
+	if r.maxLen != nil && size > *r.maxLen {
+		addViolation(out, path, "bytes.max_len", "value length must be at most %d bytes", *r.maxLen)
+	}
+}
+
+func addViolation(out *[]*Violation, path, rule, format string, args ...any) {
+	*out = append(*out, &Violation{Field: path, Rule: rule, Description: fmt.Sprintf(format, args...)})
+}
+
+func joinPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+func containsNumber(list []number, n number) bool {
+	for _, item := range list {
+		if item.equal(n) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
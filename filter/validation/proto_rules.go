@@ -0,0 +1,798 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validation
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// bufValidateExtension is the field number of the buf.validate.field, buf.validate.message and
+// buf.validate.oneof extensions on the field, message and oneof options (see buf/validate/validate.proto).
+//
+// The rules are read straight from the wire form of the options, so they work
+// whether or not the generated protovalidate Go package is linked into the binary.
+// Only the standard rules below are checked, the descriptors declaring any other rule,
+// such as the CEL expressions, are rejected by ErrUnsupportedRules instead of being skipped.
+const bufValidateExtension protowire.Number = 1159
+
+// field numbers of buf.validate.FieldRules
+const (
+	fieldRulesFloat    protowire.Number = 1
+	fieldRulesDouble   protowire.Number = 2
+	fieldRulesInt32    protowire.Number = 3
+	fieldRulesInt64    protowire.Number = 4
+	fieldRulesUInt32   protowire.Number = 5
+	fieldRulesUInt64   protowire.Number = 6
+	fieldRulesSInt32   protowire.Number = 7
+	fieldRulesSInt64   protowire.Number = 8
+	fieldRulesFixed32  protowire.Number = 9
+	fieldRulesFixed64  protowire.Number = 10
+	fieldRulesSFixed32 protowire.Number = 11
+	fieldRulesSFixed64 protowire.Number = 12
+	fieldRulesString   protowire.Number = 14
+	fieldRulesBytes    protowire.Number = 15
+	fieldRulesEnum     protowire.Number = 16
+	fieldRulesRepeated protowire.Number = 18
+	fieldRulesMap      protowire.Number = 19
+	fieldRulesCel      protowire.Number = 23
+	fieldRulesRequired protowire.Number = 25
+	fieldRulesIgnore   protowire.Number = 27
+)
+
+// field numbers of buf.validate.MessageRules and buf.validate.OneofRules
+const (
+	messageRulesDisabled protowire.Number = 1
+	oneofRulesRequired   protowire.Number = 1
+)
+
+// values of buf.validate.Ignore
+const (
+	ignoreIfUnpopulated  = 1
+	ignoreIfDefaultValue = 2
+	ignoreAlways         = 3
+)
+
+// numberRuleKinds maps the field kinds to the numeric rules that apply to them
+var numberRuleKinds = map[protoreflect.Kind]protowire.Number{
+	protoreflect.FloatKind:    fieldRulesFloat,
+	protoreflect.DoubleKind:   fieldRulesDouble,
+	protoreflect.Int32Kind:    fieldRulesInt32,
+	protoreflect.Int64Kind:    fieldRulesInt64,
+	protoreflect.Uint32Kind:   fieldRulesUInt32,
+	protoreflect.Uint64Kind:   fieldRulesUInt64,
+	protoreflect.Sint32Kind:   fieldRulesSInt32,
+	protoreflect.Sint64Kind:   fieldRulesSInt64,
+	protoreflect.Fixed32Kind:  fieldRulesFixed32,
+	protoreflect.Fixed64Kind:  fieldRulesFixed64,
+	protoreflect.Sfixed32Kind: fieldRulesSFixed32,
+	protoreflect.Sfixed64Kind: fieldRulesSFixed64,
+}
+
+type fieldRules struct {
+	required bool
+	ignore   uint64
+	number   *numberRules
+	str      *stringRules
+	bytes    *bytesRules
+	enum     *enumRules
+	repeated *repeatedRules
+	mapRules *mapRules
+	// unsupported lists the declared rules which cannot be checked
+	unsupported []string
+}
+
+type messageRules struct {
+	disabled    bool
+	unsupported []string
+}
+
+type oneofRules struct {
+	required    bool
+	unsupported []string
+}
+
+// numberKind tells how a number is stored
+type numberKind uint8
+
+const (
+	signedNumber numberKind = iota
+	unsignedNumber
+	floatNumber
+)
+
+// number is a numeric field or rule value. Integers are not converted to floats,
+// so the 64-bit bounds stay exact.
+type number struct {
+	kind numberKind
+	i    int64
+	u    uint64
+	f    float64
+}
+
+func (n number) less(o number) bool {
+	switch n.kind {
+	case unsignedNumber:
+		return n.u < o.u
+	case floatNumber:
+		return n.f < o.f
+	default:
+		return n.i < o.i
+	}
+}
+
+func (n number) equal(o number) bool {
+	switch n.kind {
+	case unsignedNumber:
+		return n.u == o.u
+	case floatNumber:
+		return n.f == o.f
+	default:
+		return n.i == o.i
+	}
+}
+
+func (n number) String() string {
+	switch n.kind {
+	case unsignedNumber:
+		return strconv.FormatUint(n.u, 10)
+	case floatNumber:
+		return strconv.FormatFloat(n.f, 'g', -1, 64)
+	default:
+		return strconv.FormatInt(n.i, 10)
+	}
+}
+
+type numberRules struct {
+	kind     protowire.Number // the buf.validate.FieldRules field the rules are declared in
+	constVal *number
+	lt       *number
+	lte      *number
+	gt       *number
+	gte      *number
+	in       []number
+	notIn    []number
+	finite   bool
+}
+
+type stringRules struct {
+	constVal    *string
+	length      *uint64
+	minLen      *uint64
+	maxLen      *uint64
+	lenBytes    *uint64
+	minBytes    *uint64
+	maxBytes    *uint64
+	pattern     *regexp.Regexp
+	prefix      *string
+	suffix      *string
+	contains    *string
+	notContains *string
+	in          []string
+	notIn       []string
+	email       bool
+	hostname    bool
+	ip          bool
+	ipv4        bool
+	ipv6        bool
+	uri         bool
+	uuid        bool
+}
+
+type bytesRules struct {
+	length *uint64
+	minLen *uint64
+	maxLen *uint64
+}
+
+type enumRules struct {
+	definedOnly bool
+	number      numberRules
+}
+
+type repeatedRules struct {
+	minItems *uint64
+	maxItems *uint64
+	unique   bool
+	items    *fieldRules
+}
+
+type mapRules struct {
+	minPairs *uint64
+	maxPairs *uint64
+	keys     *fieldRules
+	values   *fieldRules
+}
+
+var (
+	rulesCache   sync.Map // descriptor -> *fieldRules, *messageRules or *oneofRules
+	checkedCache sync.Map // protoreflect.MessageDescriptor -> error
+)
+
+// rulesFor returns the buf.validate rules declared on fd, or nil if there are none.
+func rulesFor(fd protoreflect.FieldDescriptor) *fieldRules {
+	if cached, ok := rulesCache.Load(fd); ok {
+		return cached.(*fieldRules)
+	}
+	var rules *fieldRules
+	if payload := extensionPayload(fd.Options()); len(payload) > 0 {
+		rules = parseFieldRules(payload)
+	}
+	rulesCache.Store(fd, rules)
+	return rules
+}
+
+// messageRulesFor returns the buf.validate rules declared on md, or nil if there are none.
+func messageRulesFor(md protoreflect.MessageDescriptor) *messageRules {
+	if cached, ok := rulesCache.Load(md); ok {
+		return cached.(*messageRules)
+	}
+	var rules *messageRules
+	if payload := extensionPayload(md.Options()); len(payload) > 0 {
+		rules = &messageRules{}
+		consumeFields(payload, func(num protowire.Number, v wireValue) {
+			if num == messageRulesDisabled {
+				rules.disabled = v.num != 0
+				return
+			}
+			rules.unsupported = append(rules.unsupported, unsupportedRule("message", num))
+		})
+	}
+	rulesCache.Store(md, rules)
+	return rules
+}
+
+// oneofRulesFor returns the buf.validate rules declared on od, or nil if there are none.
+func oneofRulesFor(od protoreflect.OneofDescriptor) *oneofRules {
+	if cached, ok := rulesCache.Load(od); ok {
+		return cached.(*oneofRules)
+	}
+	var rules *oneofRules
+	if payload := extensionPayload(od.Options()); len(payload) > 0 {
+		rules = &oneofRules{}
+		consumeFields(payload, func(num protowire.Number, v wireValue) {
+			if num == oneofRulesRequired {
+				rules.required = v.num != 0
+				return
+			}
+			rules.unsupported = append(rules.unsupported, unsupportedRule("oneof", num))
+		})
+	}
+	rulesCache.Store(od, rules)
+	return rules
+}
+
+// checkRules returns an error wrapping ErrUnsupportedRules if md or any message reachable from it
+// declares rules which cannot be checked, or rules which do not match the kinds of their fields.
+func checkRules(md protoreflect.MessageDescriptor) error {
+	if cached, ok := checkedCache.Load(md); ok {
+		err, _ := cached.(error)
+		return err
+	}
+	var problems []string
+	collectProblems(md, map[protoreflect.FullName]bool{}, &problems)
+	if len(problems) == 0 {
+		checkedCache.Store(md, nil)
+		return nil
+	}
+	err := fmt.Errorf("%w: %s", ErrUnsupportedRules, strings.Join(problems, "; "))
+	logger.Errorf("[Validation Filter] the arguments of type %s are rejected: %v", md.FullName(), err)
+	checkedCache.Store(md, err)
+	return err
+}
+
+func collectProblems(md protoreflect.MessageDescriptor, visited map[protoreflect.FullName]bool, out *[]string) {
+	if visited[md.FullName()] {
+		return
+	}
+	visited[md.FullName()] = true
+	if mr := messageRulesFor(md); mr != nil {
+		for _, p := range mr.unsupported {
+			*out = append(*out, string(md.FullName())+": "+p)
+		}
+		if mr.disabled {
+			return
+		}
+	}
+	oneofs := md.Oneofs()
+	for i := 0; i < oneofs.Len(); i++ {
+		od := oneofs.Get(i)
+		if or := oneofRulesFor(od); or != nil {
+			for _, p := range or.unsupported {
+				*out = append(*out, string(od.FullName())+": "+p)
+			}
+		}
+	}
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		for _, p := range rulesFor(fd).problems(fd, false) {
+			*out = append(*out, string(fd.FullName())+": "+p)
+		}
+		switch {
+		case fd.IsMap():
+			if value := fd.MapValue(); value.Message() != nil {
+				collectProblems(value.Message(), visited, out)
+			}
+		case fd.Message() != nil:
+			collectProblems(fd.Message(), visited, out)
+		}
+	}
+}
+
+// problems returns the rules of r which cannot be applied to fd, element tells whether
+// r applies to the items of a list field rather than to the list itself.
+func (r *fieldRules) problems(fd protoreflect.FieldDescriptor, element bool) []string {
+	if r == nil {
+		return nil
+	}
+	problems := append([]string(nil), r.unsupported...)
+	var declared []string
+	if r.number != nil {
+		declared = append(declared, ruleName(r.number.kind))
+	}
+	if r.str != nil {
+		declared = append(declared, "string")
+	}
+	if r.bytes != nil {
+		declared = append(declared, "bytes")
+	}
+	if r.enum != nil {
+		declared = append(declared, "enum")
+	}
+	if r.repeated != nil {
+		declared = append(declared, "repeated")
+	}
+	if r.mapRules != nil {
+		declared = append(declared, "map")
+	}
+	var expected string
+	switch {
+	case fd.IsList() && !element:
+		expected = "repeated"
+		if r.repeated != nil {
+			problems = append(problems, r.repeated.items.problems(fd, true)...)
+		}
+	case fd.IsMap():
+		expected = "map"
+		if r.mapRules != nil {
+			problems = append(problems, r.mapRules.keys.problems(fd.MapKey(), true)...)
+			problems = append(problems, r.mapRules.values.problems(fd.MapValue(), true)...)
+		}
+	case fd.Kind() == protoreflect.StringKind:
+		expected = "string"
+	case fd.Kind() == protoreflect.BytesKind:
+		expected = "bytes"
+	case fd.Kind() == protoreflect.EnumKind:
+		expected = "enum"
+	default:
+		if kind, ok := numberRuleKinds[fd.Kind()]; ok {
+			expected = ruleName(kind)
+		}
+	}
+	for _, name := range declared {
+		if name != expected {
+			problems = append(problems, fmt.Sprintf("%s rules do not apply to %s fields", name, kindName(fd, element)))
+		}
+	}
+	return problems
+}
+
+func kindName(fd protoreflect.FieldDescriptor, element bool) string {
+	switch {
+	case fd.IsList() && !element:
+		return "repeated"
+	case fd.IsMap():
+		return "map"
+	default:
+		return fd.Kind().String()
+	}
+}
+
+// ruleName returns the name of the buf.validate.FieldRules field num
+func ruleName(num protowire.Number) string {
+	switch num {
+	case fieldRulesFloat:
+		return "float"
+	case fieldRulesDouble:
+		return "double"
+	case fieldRulesInt32:
+		return "int32"
+	case fieldRulesInt64:
+		return "int64"
+	case fieldRulesUInt32:
+		return "uint32"
+	case fieldRulesUInt64:
+		return "uint64"
+	case fieldRulesSInt32:
+		return "sint32"
+	case fieldRulesSInt64:
+		return "sint64"
+	case fieldRulesFixed32:
+		return "fixed32"
+	case fieldRulesFixed64:
+		return "fixed64"
+	case fieldRulesSFixed32:
+		return "sfixed32"
+	case fieldRulesSFixed64:
+		return "sfixed64"
+	case fieldRulesString:
+		return "string"
+	case fieldRulesBytes:
+		return "bytes"
+	case fieldRulesEnum:
+		return "enum"
+	case fieldRulesRepeated:
+		return "repeated"
+	case fieldRulesMap:
+		return "map"
+	case fieldRulesCel:
+		return "cel"
+	default:
+		return "#" + strconv.Itoa(int(num))
+	}
+}
+
+func unsupportedRule(parent string, num protowire.Number) string {
+	if parent == "" {
+		return ruleName(num) + " rules are not supported"
+	}
+	return fmt.Sprintf("%s rule #%d is not supported", parent, num)
+}
+
+// extensionPayload returns the merged bytes of every buf.validate extension occurrence in opts.
+func extensionPayload(opts proto.Message) []byte {
+	if opts == nil || !opts.ProtoReflect().IsValid() {
+		return nil
+	}
+	raw, err := proto.MarshalOptions{AllowPartial: true}.Marshal(opts)
+	if err != nil {
+		logger.Warnf("[Validation Filter] failed to marshal the options: %v", err)
+		return nil
+	}
+	var payload []byte
+	consumeFields(raw, func(num protowire.Number, v wireValue) {
+		if num == bufValidateExtension && v.typ == protowire.BytesType {
+			// concatenated encodings of the same message are merged by protobuf semantics
+			payload = append(payload, v.buf...)
+		}
+	})
+	return payload
+}
+
+type wireValue struct {
+	typ protowire.Type
+	num uint64 // payload of varint, fixed32 and fixed64 fields
+	buf []byte // payload of length-delimited fields
+}
+
+// consumeFields walks the top-level fields of an encoded message.
+func consumeFields(b []byte, fn func(protowire.Number, wireValue)) {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return
+		}
+		b = b[n:]
+		v := wireValue{typ: typ}
+		switch typ {
+		case protowire.VarintType:
+			v.num, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed32Type:
+			var x uint32
+			x, n = protowire.ConsumeFixed32(b)
+			v.num = uint64(x)
+		case protowire.Fixed64Type:
+			v.num, n = protowire.ConsumeFixed64(b)
+		case protowire.BytesType:
+			v.buf, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return
+		}
+		b = b[n:]
+		fn(num, v)
+	}
+}
+
+func parseFieldRules(b []byte) *fieldRules {
+	rules := &fieldRules{}
+	consumeFields(b, func(num protowire.Number, v wireValue) {
+		switch {
+		case num == fieldRulesRequired:
+			rules.required = v.num != 0
+		case num == fieldRulesIgnore:
+			rules.ignore = v.num
+		case num >= fieldRulesFloat && num <= fieldRulesSFixed64:
+			if rules.number == nil {
+				rules.number = &numberRules{kind: num}
+			}
+			parseNumberRules(v.buf, num, rules)
+		case num == fieldRulesString:
+			if rules.str == nil {
+				rules.str = &stringRules{}
+			}
+			parseStringRules(v.buf, rules)
+		case num == fieldRulesBytes:
+			if rules.bytes == nil {
+				rules.bytes = &bytesRules{}
+			}
+			parseBytesRules(v.buf, rules)
+		case num == fieldRulesEnum:
+			if rules.enum == nil {
+				rules.enum = &enumRules{number: numberRules{kind: fieldRulesEnum}}
+			}
+			parseEnumRules(v.buf, rules)
+		case num == fieldRulesRepeated:
+			if rules.repeated == nil {
+				rules.repeated = &repeatedRules{}
+			}
+			parseRepeatedRules(v.buf, rules)
+		case num == fieldRulesMap:
+			if rules.mapRules == nil {
+				rules.mapRules = &mapRules{}
+			}
+			parseMapRules(v.buf, rules)
+		default:
+			rules.unsupported = append(rules.unsupported, unsupportedRule("", num))
+		}
+	})
+	return rules
+}
+
+// parseNumberRules parses any of the buf.validate numeric rules messages, which all
+// share the layout const=1, lt=2, lte=3, gt=4, gte=5, in=6, not_in=7, followed by
+// finite=8 and example=9 for floats, or example=8 for integers.
+func parseNumberRules(b []byte, kind protowire.Number, rules *fieldRules) {
+	r := rules.number
+	float := kind == fieldRulesFloat || kind == fieldRulesDouble
+	consumeFields(b, func(num protowire.Number, v wireValue) {
+		switch {
+		case num == 1:
+			r.constVal = numberPtr(decodeNumber(kind, v.num))
+		case num == 2:
+			r.lt = numberPtr(decodeNumber(kind, v.num))
+		case num == 3:
+			r.lte = numberPtr(decodeNumber(kind, v.num))
+		case num == 4:
+			r.gt = numberPtr(decodeNumber(kind, v.num))
+		case num == 5:
+			r.gte = numberPtr(decodeNumber(kind, v.num))
+		case num == 6:
+			r.in = append(r.in, decodeNumbers(kind, v)...)
+		case num == 7:
+			r.notIn = append(r.notIn, decodeNumbers(kind, v)...)
+		case num == 8 && float:
+			r.finite = v.num != 0
+		case num == 8 || (num == 9 && float):
+			// examples do not constrain the value
+		default:
+			rules.unsupported = append(rules.unsupported, unsupportedRule(ruleName(kind), num))
+		}
+	})
+}
+
+// decodeNumber converts the raw wire payload of a numeric rule value of the given kind.
+func decodeNumber(kind protowire.Number, raw uint64) number {
+	switch kind {
+	case fieldRulesFloat:
+		return number{kind: floatNumber, f: float64(math.Float32frombits(uint32(raw)))}
+	case fieldRulesDouble:
+		return number{kind: floatNumber, f: math.Float64frombits(raw)}
+	case fieldRulesInt32, fieldRulesSFixed32, fieldRulesEnum:
+		return number{kind: signedNumber, i: int64(int32(raw))}
+	case fieldRulesInt64, fieldRulesSFixed64:
+		return number{kind: signedNumber, i: int64(raw)}
+	case fieldRulesUInt32, fieldRulesFixed32:
+		return number{kind: unsignedNumber, u: uint64(uint32(raw))}
+	case fieldRulesSInt32:
+		return number{kind: signedNumber, i: int64(int32(protowire.DecodeZigZag(raw & math.MaxUint32)))}
+	case fieldRulesSInt64:
+		return number{kind: signedNumber, i: protowire.DecodeZigZag(raw)}
+	default:
+		return number{kind: unsignedNumber, u: raw}
+	}
+}
+
+// decodeNumbers decodes a repeated numeric rule value, which may be packed.
+func decodeNumbers(kind protowire.Number, v wireValue) []number {
+	if v.typ != protowire.BytesType {
+		return []number{decodeNumber(kind, v.num)}
+	}
+	var out []number
+	b := v.buf
+	for len(b) > 0 {
+		var raw uint64
+		var n int
+		switch kind {
+		case fieldRulesFloat, fieldRulesFixed32, fieldRulesSFixed32:
+			var x uint32
+			x, n = protowire.ConsumeFixed32(b)
+			raw = uint64(x)
+		case fieldRulesDouble, fieldRulesFixed64, fieldRulesSFixed64:
+			raw, n = protowire.ConsumeFixed64(b)
+		default:
+			raw, n = protowire.ConsumeVarint(b)
+		}
+		if n < 0 {
+			break
+		}
+		b = b[n:]
+		out = append(out, decodeNumber(kind, raw))
+	}
+	return out
+}
+
+func parseStringRules(b []byte, rules *fieldRules) {
+	r := rules.str
+	consumeFields(b, func(num protowire.Number, v wireValue) {
+		switch num {
+		case 1:
+			r.constVal = stringPtr(string(v.buf))
+		case 2:
+			r.minLen = uintPtr(v.num)
+		case 3:
+			r.maxLen = uintPtr(v.num)
+		case 4:
+			r.minBytes = uintPtr(v.num)
+		case 5:
+			r.maxBytes = uintPtr(v.num)
+		case 6:
+			re, err := regexp.Compile(string(v.buf))
+			if err != nil {
+				rules.unsupported = append(rules.unsupported, fmt.Sprintf("string.pattern %q is invalid: %v", string(v.buf), err))
+				return
+			}
+			r.pattern = re
+		case 7:
+			r.prefix = stringPtr(string(v.buf))
+		case 8:
+			r.suffix = stringPtr(string(v.buf))
+		case 9:
+			r.contains = stringPtr(string(v.buf))
+		case 10:
+			r.in = append(r.in, string(v.buf))
+		case 11:
+			r.notIn = append(r.notIn, string(v.buf))
+		case 12:
+			r.email = v.num != 0
+		case 13:
+			r.hostname = v.num != 0
+		case 14:
+			r.ip = v.num != 0
+		case 15:
+			r.ipv4 = v.num != 0
+		case 16:
+			r.ipv6 = v.num != 0
+		case 17:
+			r.uri = v.num != 0
+		case 19:
+			r.length = uintPtr(v.num)
+		case 20:
+			r.lenBytes = uintPtr(v.num)
+		case 22:
+			r.uuid = v.num != 0
+		case 23:
+			r.notContains = stringPtr(string(v.buf))
+		case 34:
+			// examples do not constrain the value
+		default:
+			rules.unsupported = append(rules.unsupported, unsupportedRule("string", num))
+		}
+	})
+}
+
+func parseBytesRules(b []byte, rules *fieldRules) {
+	r := rules.bytes
+	consumeFields(b, func(num protowire.Number, v wireValue) {
+		switch num {
+		case 2:
+			r.minLen = uintPtr(v.num)
+		case 3:
+			r.maxLen = uintPtr(v.num)
+		case 13:
+			r.length = uintPtr(v.num)
+		case 14:
+			// examples do not constrain the value
+		default:
+			rules.unsupported = append(rules.unsupported, unsupportedRule("bytes", num))
+		}
+	})
+}
+
+func parseEnumRules(b []byte, rules *fieldRules) {
+	r := rules.enum
+	consumeFields(b, func(num protowire.Number, v wireValue) {
+		switch num {
+		case 1:
+			r.number.constVal = numberPtr(decodeNumber(fieldRulesEnum, v.num))
+		case 2:
+			r.definedOnly = v.num != 0
+		case 3:
+			r.number.in = append(r.number.in, decodeNumbers(fieldRulesEnum, v)...)
+		case 4:
+			r.number.notIn = append(r.number.notIn, decodeNumbers(fieldRulesEnum, v)...)
+		case 5:
+			// examples do not constrain the value
+		default:
+			rules.unsupported = append(rules.unsupported, unsupportedRule("enum", num))
+		}
+	})
+}
+
+func parseRepeatedRules(b []byte, rules *fieldRules) {
+	r := rules.repeated
+	consumeFields(b, func(num protowire.Number, v wireValue) {
+		switch num {
+		case 1:
+			r.minItems = uintPtr(v.num)
+		case 2:
+			r.maxItems = uintPtr(v.num)
+		case 3:
+			r.unique = v.num != 0
+		case 4:
+			r.items = parseFieldRules(v.buf)
+		default:
+			rules.unsupported = append(rules.unsupported, unsupportedRule("repeated", num))
+		}
+	})
+}
+
+func parseMapRules(b []byte, rules *fieldRules) {
+	r := rules.mapRules
+	consumeFields(b, func(num protowire.Number, v wireValue) {
+		switch num {
+		case 1:
+			r.minPairs = uintPtr(v.num)
+		case 2:
+			r.maxPairs = uintPtr(v.num)
+		case 4:
+			r.keys = parseFieldRules(v.buf)
+		case 5:
+			r.values = parseFieldRules(v.buf)
+		default:
+			rules.unsupported = append(rules.unsupported, unsupportedRule("map", num))
+		}
+	})
+}
+
+func numberPtr(n number) *number {
+	return &n
+}
+
+func uintPtr(u uint64) *uint64 {
+	return &u
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,308 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validation
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+)
+
+// rule encodes a single varint field of a buf.validate rules message.
+func rule(num protowire.Number, v uint64) []byte {
+	return protowire.AppendVarint(protowire.AppendTag(nil, num, protowire.VarintType), v)
+}
+
+// nested encodes a length-delimited field of a buf.validate rules message.
+func nested(num protowire.Number, b []byte) []byte {
+	return protowire.AppendBytes(protowire.AppendTag(nil, num, protowire.BytesType), b)
+}
+
+// withRules builds field options carrying the given buf.validate.field payload as
+// unknown fields, just like a descriptor compiled without the protovalidate Go package.
+func withRules(fieldRules ...[]byte) *descriptorpb.FieldOptions {
+	var payload []byte
+	for _, r := range fieldRules {
+		payload = append(payload, r...)
+	}
+	opts := &descriptorpb.FieldOptions{}
+	opts.ProtoReflect().SetUnknown(nested(bufValidateExtension, payload))
+	return opts
+}
+
+func field(name string, num int32, typ descriptorpb.FieldDescriptorProto_Type, opts *descriptorpb.FieldOptions) *descriptorpb.FieldDescriptorProto {
+	return &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		JsonName: proto.String(name),
+		Number:   proto.Int32(num),
+		Type:     typ.Enum(),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Options:  opts,
+	}
+}
+
+func buildUserDescriptor(t *testing.T) protoreflect.MessageDescriptor {
+	cityField := field("city", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING,
+		withRules(nested(fieldRulesString, rule(2, 2))))
+
+	nameField := field("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING,
+		withRules(nested(fieldRulesString, rule(2, 3))))
+	ageField := field("age", 2, descriptorpb.FieldDescriptorProto_TYPE_INT32,
+		withRules(nested(fieldRulesInt32, rule(5, 18))))
+	emailField := field("email", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING,
+		withRules(nested(fieldRulesString, rule(12, 1))))
+	tagsField := field("tags", 4, descriptorpb.FieldDescriptorProto_TYPE_STRING,
+		withRules(nested(fieldRulesRepeated, append(rule(2, 2), nested(4, nested(fieldRulesString, rule(2, 1)))...))))
+	tagsField.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	addressField := field("address", 5, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+		withRules(rule(fieldRulesRequired, 1)))
+	addressField.TypeName = proto.String(".dubbo.validation.test.Address")
+	addressesField := field("addresses", 6, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, nil)
+	addressesField.TypeName = proto.String(".dubbo.validation.test.Address")
+	addressesField.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("dubbo/validation/test.proto"),
+		Package: proto.String("dubbo.validation.test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("Address"), Field: []*descriptorpb.FieldDescriptorProto{cityField}},
+			{Name: proto.String("User"), Field: []*descriptorpb.FieldDescriptorProto{
+				nameField, ageField, emailField, tagsField, addressField, addressesField,
+			}},
+		},
+	}, new(protoregistry.Files))
+	require.Nil(t, err)
+	return file.Messages().ByName("User")
+}
+
+func TestValidateProto(t *testing.T) {
+	userDesc := buildUserDescriptor(t)
+	addressDesc := userDesc.Fields().ByName("address").Message()
+
+	newAddress := func(city string) *dynamicpb.Message {
+		addr := dynamicpb.NewMessage(addressDesc)
+		addr.Set(addressDesc.Fields().ByName("city"), protoreflect.ValueOfString(city))
+		return addr
+	}
+
+	valid := dynamicpb.NewMessage(userDesc)
+	valid.Set(userDesc.Fields().ByName("name"), protoreflect.ValueOfString("dubbo"))
+	valid.Set(userDesc.Fields().ByName("age"), protoreflect.ValueOfInt32(20))
+	valid.Set(userDesc.Fields().ByName("email"), protoreflect.ValueOfString("dev@dubbo.apache.org"))
+	valid.Set(userDesc.Fields().ByName("address"), protoreflect.ValueOfMessage(newAddress("Hangzhou")))
+	violations, err := validateProto(valid)
+	require.Nil(t, err)
+	assert.Empty(t, violations)
+
+	invalid := dynamicpb.NewMessage(userDesc)
+	invalid.Set(userDesc.Fields().ByName("name"), protoreflect.ValueOfString("go"))
+	invalid.Set(userDesc.Fields().ByName("age"), protoreflect.ValueOfInt32(17))
+	invalid.Set(userDesc.Fields().ByName("email"), protoreflect.ValueOfString("not-an-email"))
+	tags := invalid.Mutable(userDesc.Fields().ByName("tags")).List()
+	tags.Append(protoreflect.ValueOfString("a"))
+	tags.Append(protoreflect.ValueOfString(""))
+	tags.Append(protoreflect.ValueOfString("c"))
+	addresses := invalid.Mutable(userDesc.Fields().ByName("addresses")).List()
+	addresses.Append(protoreflect.ValueOfMessage(newAddress("HZ")))
+	addresses.Append(protoreflect.ValueOfMessage(newAddress("X")))
+
+	violations, err = validateProto(invalid)
+	require.Nil(t, err)
+	got := make(map[string]string, len(violations))
+	for _, v := range violations {
+		got[v.Field] = v.Rule
+	}
+	assert.Equal(t, map[string]string{
+		"name":              "string.min_len",
+		"age":               "int32.gte",
+		"email":             "string.email",
+		"tags":              "repeated.max_items",
+		"tags[1]":           "string.min_len",
+		"address":           "required",
+		"addresses[1].city": "string.min_len",
+	}, got)
+
+	var validationErr *Error
+	require.ErrorAs(t, Validate(invalid), &validationErr)
+	assert.Len(t, validationErr.Violations, len(violations))
+}
+
+func TestParseNumberRules(t *testing.T) {
+	// packed sint32 "in" list: zigzag encoded -1 and 2
+	packed := protowire.AppendVarint(protowire.AppendVarint(nil, protowire.EncodeZigZag(-1)), protowire.EncodeZigZag(2))
+	rules := parseFieldRules(nested(fieldRulesSInt32, nested(6, packed)))
+	require.NotNil(t, rules.number)
+	assert.Equal(t, []number{{kind: signedNumber, i: -1}, {kind: signedNumber, i: 2}}, rules.number.in)
+
+	var out []*Violation
+	validateNumber(number{kind: signedNumber, i: 3}, rules.number, "sint32", "value", &out)
+	assert.Len(t, out, 1)
+	assert.Equal(t, "sint32.in", out[0].Rule)
+}
+
+func buildMessage(t *testing.T, name string, fields []*descriptorpb.FieldDescriptorProto, oneofs ...*descriptorpb.OneofDescriptorProto) protoreflect.MessageDescriptor {
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("dubbo/validation/" + name + ".proto"),
+		Package: proto.String("dubbo.validation.test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String(name), Field: fields, OneofDecl: oneofs},
+		},
+	}, new(protoregistry.Files))
+	require.Nil(t, err)
+	return file.Messages().ByName(protoreflect.Name(name))
+}
+
+func TestValidateProtoInt64Bounds(t *testing.T) {
+	// 2^53+1 cannot be represented by a float64
+	const bound = 1<<53 + 1
+	desc := buildMessage(t, "Counter", []*descriptorpb.FieldDescriptorProto{
+		field("signed", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64, withRules(nested(fieldRulesInt64, rule(3, bound)))),
+		field("unsigned", 2, descriptorpb.FieldDescriptorProto_TYPE_UINT64, withRules(nested(fieldRulesUInt64, rule(5, math.MaxUint64)))),
+	})
+	msg := dynamicpb.NewMessage(desc)
+	msg.Set(desc.Fields().ByName("signed"), protoreflect.ValueOfInt64(bound+1))
+	msg.Set(desc.Fields().ByName("unsigned"), protoreflect.ValueOfUint64(math.MaxUint64-1))
+
+	violations, err := validateProto(msg)
+	require.Nil(t, err)
+	require.Len(t, violations, 2)
+	assert.Equal(t, "int64.lte", violations[0].Rule)
+	assert.Equal(t, "value must be less than or equal to 9007199254740993", violations[0].Description)
+	assert.Equal(t, "uint64.gte", violations[1].Rule)
+
+	msg.Set(desc.Fields().ByName("signed"), protoreflect.ValueOfInt64(bound))
+	msg.Set(desc.Fields().ByName("unsigned"), protoreflect.ValueOfUint64(math.MaxUint64))
+	violations, err = validateProto(msg)
+	require.Nil(t, err)
+	assert.Empty(t, violations)
+}
+
+func TestValidateProtoUnsupportedRules(t *testing.T) {
+	celField := field("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING,
+		withRules(nested(fieldRulesCel, nested(3, []byte("this.size() > 1")))))
+	mismatchField := field("age", 2, descriptorpb.FieldDescriptorProto_TYPE_INT32,
+		withRules(nested(fieldRulesInt64, rule(5, 18))))
+	desc := buildMessage(t, "Unsupported", []*descriptorpb.FieldDescriptorProto{celField, mismatchField})
+
+	violations, err := validateProto(dynamicpb.NewMessage(desc))
+	assert.Empty(t, violations)
+	require.ErrorIs(t, err, ErrUnsupportedRules)
+	assert.Contains(t, err.Error(), "dubbo.validation.test.Unsupported.name: cel rules are not supported")
+	assert.Contains(t, err.Error(), "dubbo.validation.test.Unsupported.age: int64 rules do not apply to int32 fields")
+
+	res := newValidationFilter().Invoke(context.Background(), base.NewBaseInvoker(common.NewURLWithOptions()),
+		invocation.NewRPCInvocation("GetUser", []any{dynamicpb.NewMessage(desc)}, nil))
+	assert.ErrorIs(t, res.Error(), ErrUnsupportedRules)
+	assert.Equal(t, triple_protocol.CodeInternal, triple_protocol.CodeOf(res.Error()))
+}
+
+func TestValidateProtoOneofRequired(t *testing.T) {
+	oneofOpts := &descriptorpb.OneofOptions{}
+	oneofOpts.ProtoReflect().SetUnknown(nested(bufValidateExtension, rule(oneofRulesRequired, 1)))
+	email := field("email", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, nil)
+	email.OneofIndex = proto.Int32(0)
+	phone := field("phone", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, nil)
+	phone.OneofIndex = proto.Int32(0)
+	desc := buildMessage(t, "Contact", []*descriptorpb.FieldDescriptorProto{email, phone},
+		&descriptorpb.OneofDescriptorProto{Name: proto.String("contact"), Options: oneofOpts})
+
+	msg := dynamicpb.NewMessage(desc)
+	violations, err := validateProto(msg)
+	require.Nil(t, err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "contact", violations[0].Field)
+	assert.Equal(t, "required", violations[0].Rule)
+
+	msg.Set(desc.Fields().ByName("phone"), protoreflect.ValueOfString("110"))
+	violations, err = validateProto(msg)
+	require.Nil(t, err)
+	assert.Empty(t, violations)
+}
+
+func TestValidateNumberRanges(t *testing.T) {
+	signed := func(i int64) *number {
+		return &number{kind: signedNumber, i: i}
+	}
+	tests := []struct {
+		name  string
+		rules *numberRules
+		value int64
+		rule  string
+	}{
+		{name: "inside the range", rules: &numberRules{gt: signed(0), lt: signed(10)}, value: 5},
+		{name: "below the range", rules: &numberRules{gt: signed(0), lt: signed(10)}, value: 0, rule: "int32.gt_lt"},
+		{name: "above the range", rules: &numberRules{gte: signed(0), lte: signed(10)}, value: 11, rule: "int32.gte_lte"},
+		{name: "on the inclusive bounds", rules: &numberRules{gte: signed(5), lte: signed(5)}, value: 5},
+		{name: "above the exclusive range", rules: &numberRules{gt: signed(10), lt: signed(0)}, value: 11},
+		{name: "below the exclusive range", rules: &numberRules{gte: signed(10), lte: signed(0)}, value: 0},
+		{name: "inside the exclusive range", rules: &numberRules{gte: signed(10), lte: signed(0)}, value: 5, rule: "int32.gte_lte_exclusive"},
+		{name: "apart from the excluded value", rules: &numberRules{gt: signed(5), lt: signed(5)}, value: 4},
+		{name: "on the excluded value", rules: &numberRules{gt: signed(5), lt: signed(5)}, value: 5, rule: "int32.gt_lt_exclusive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out []*Violation
+			validateNumber(*signed(tt.value), tt.rules, "int32", "value", &out)
+			if tt.rule == "" {
+				assert.Empty(t, out)
+				return
+			}
+			require.Len(t, out, 1)
+			assert.Equal(t, tt.rule, out[0].Rule)
+		})
+	}
+}
+
+func TestValidateProtoIgnoreIfDefaultValue(t *testing.T) {
+	desc := buildMessage(t, "Retry", []*descriptorpb.FieldDescriptorProto{
+		field("times", 1, descriptorpb.FieldDescriptorProto_TYPE_INT32,
+			withRules(rule(fieldRulesIgnore, ignoreIfDefaultValue), nested(fieldRulesInt32, rule(5, 3)))),
+	})
+	msg := dynamicpb.NewMessage(desc)
+	violations, err := validateProto(msg)
+	require.Nil(t, err)
+	assert.Empty(t, violations)
+
+	msg.Set(desc.Fields().ByName("times"), protoreflect.ValueOfInt32(1))
+	violations, err = validateProto(msg)
+	require.Nil(t, err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "int32.gte", violations[0].Rule)
+}
@@ -0,0 +1,78 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+var structValidator = validator.New()
+
+// validateStruct checks the `validate:"..."` tags of a Go struct argument.
+// Arguments that are not structs or pointers to structs are ignored.
+func validateStruct(arg any) []*Violation {
+	v := reflect.ValueOf(arg)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	err := structValidator.Struct(v.Interface())
+	if err == nil {
+		return nil
+	}
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return []*Violation{{Rule: "struct", Description: err.Error()}}
+	}
+	violations := make([]*Violation, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		violations = append(violations, &Violation{
+			Field:       trimRootType(fe.StructNamespace()),
+			Rule:        fe.Tag(),
+			Description: structErrorDescription(fe),
+		})
+	}
+	return violations
+}
+
+// trimRootType turns "User.Address.City" into "Address.City".
+func trimRootType(namespace string) string {
+	if idx := strings.Index(namespace, "."); idx >= 0 {
+		return namespace[idx+1:]
+	}
+	return namespace
+}
+
+func structErrorDescription(fe validator.FieldError) string {
+	if fe.Param() == "" {
+		return "failed on the '" + fe.Tag() + "' rule"
+	}
+	return "failed on the '" + fe.Tag() + "=" + fe.Param() + "' rule"
+}
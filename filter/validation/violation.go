@@ -0,0 +1,91 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+)
+
+// ErrUnsupportedRules is wrapped by the error of the arguments declaring buf.validate rules
+// which cannot be checked by the validation filter.
+var ErrUnsupportedRules = errors.New("unsupported buf.validate rules")
+
+// Violation describes a single constraint that an argument field failed to satisfy.
+type Violation struct {
+	// Field is the path of the offending field, e.g. "user.emails[0]".
+	Field string
+	// Rule is the identifier of the broken rule, e.g. "string.min_len" or "required".
+	Rule string
+	// Description is a human-readable explanation of the violation.
+	Description string
+}
+
+// Error is returned by the validation filter when one or more arguments are invalid.
+// Use errors.As to retrieve it from the error of an invocation result.
+type Error struct {
+	Violations []*Violation
+}
+
+func (e *Error) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", v.Field, v.Description))
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// toRPCError wraps e into a triple error with CodeInvalidArgument, attaching a
+// google.rpc.BadRequest detail so that clients can inspect the field paths.
+func (e *Error) toRPCError() error {
+	tripleErr := triple_protocol.NewError(triple_protocol.CodeInvalidArgument, e)
+	badRequest := &errdetails.BadRequest{}
+	for _, v := range e.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+	detail, err := triple_protocol.NewErrorDetail(badRequest)
+	if err != nil {
+		logger.Warnf("[Validation Filter] failed to build BadRequest detail: %v", err)
+		return tripleErr
+	}
+	tripleErr.AddDetail(detail)
+	return tripleErr
+}
+
+// toRPCError converts the error of Validate into the error of the invocation result
+func toRPCError(err error) error {
+	var validationErr *Error
+	if errors.As(err, &validationErr) {
+		return validationErr.toRPCError()
+	}
+	return triple_protocol.NewError(triple_protocol.CodeInternal, err)
+}
@@ -0,0 +1,43 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package global
+
+// ConnPoolConfig represents the pool of the HTTP/2 connections a triple client keeps to each provider,
+// which spreads the streams to the least busy connection instead of multiplexing all of them over one.
+type ConnPoolConfig struct {
+	// MaxConnections is the max connections to a provider, the default value is 4.
+	MaxConnections int `yaml:"max-connections" json:"max-connections,omitempty"`
+	// OpenThreshold is the ratio of the max concurrent streams of the provider, another connection is
+	// opened once the streams of every connection reach it. The default value is 0.8.
+	OpenThreshold float64 `yaml:"open-threshold" json:"open-threshold,omitempty"`
+	// IdleTimeout closes the extra connections without any stream for the duration, e.g. "1m".
+	// The first connection is kept, which is closed by the idle timeout of the triple config.
+	IdleTimeout string `yaml:"idle-timeout" json:"idle-timeout,omitempty"`
+}
+
+// Clone a new ConnPoolConfig
+func (c *ConnPoolConfig) Clone() *ConnPoolConfig {
+	if c == nil {
+		return nil
+	}
+
+	return &ConnPoolConfig{
+		MaxConnections: c.MaxConnections,
+		OpenThreshold:  c.OpenThreshold,
+		IdleTimeout:    c.IdleTimeout,
+	}
+}
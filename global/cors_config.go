@@ -0,0 +1,56 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package global
+
+// CorsConfig represents the CORS config of the triple protocol, which allows browsers
+// to call the triple services with gRPC-Web or the triple protocol directly.
+type CorsConfig struct {
+	// AllowOrigins lists the origins allowed to call, e.g. "https://example.com".
+	// "*" allows any origin and "https://*.example.com" allows the subdomains.
+	// CORS is disabled if it's empty.
+	AllowOrigins []string `yaml:"allow-origins" json:"allow-origins,omitempty"`
+	// AllowMethods lists the methods allowed to call, the default value is POST, GET and OPTIONS.
+	AllowMethods []string `yaml:"allow-methods" json:"allow-methods,omitempty"`
+	// AllowHeaders lists the request headers allowed besides the ones of the triple and gRPC-Web protocols,
+	// "*" allows any header.
+	AllowHeaders []string `yaml:"allow-headers" json:"allow-headers,omitempty"`
+	// ExposeHeaders lists the response headers and trailers readable by browsers besides
+	// the status ones of gRPC, e.g. the custom trailers of the services.
+	ExposeHeaders []string `yaml:"expose-headers" json:"expose-headers,omitempty"`
+	// Whether to allow the requests with credentials, such as cookies.
+	// It can't be used with the AllowOrigins "*".
+	AllowCredentials bool `yaml:"allow-credentials" json:"allow-credentials,omitempty"`
+	// MaxAge is how long the result of a preflight request can be cached, e.g. "2h".
+	MaxAge string `yaml:"max-age" json:"max-age,omitempty"`
+}
+
+// Clone a new CorsConfig
+func (c *CorsConfig) Clone() *CorsConfig {
+	if c == nil {
+		return nil
+	}
+
+	return &CorsConfig{
+		AllowOrigins:     append([]string(nil), c.AllowOrigins...),
+		AllowMethods:     append([]string(nil), c.AllowMethods...),
+		AllowHeaders:     append([]string(nil), c.AllowHeaders...),
+		ExposeHeaders:    append([]string(nil), c.ExposeHeaders...),
+		AllowCredentials: c.AllowCredentials,
+		MaxAge:           c.MaxAge,
+	}
+}
@@ -34,7 +34,26 @@ type Http3Config struct {
 	// ref: https://quic-go.net/docs/http3/server/#advertising-http3-via-alt-svc
 	Negotiation bool `yaml:"negotiation" json:"negotiation,omitempty"`
 
-	// TODO: add more params about http3
+	// Whether to accept 0-RTT connection attempts on the server.
+	// The client caches the TLS sessions to resume them, triple calls are POSTs and never sent as 0-RTT data.
+	// 0-RTT data can be replayed, only idempotent requests should rely on it.
+	// The default value is false.
+	Allow0RTT bool `yaml:"allow-0rtt" json:"allow-0rtt,omitempty"`
+
+	// Whether to enable the unreliable datagram extension (RFC 9221, RFC 9297).
+	// The default value is false.
+	EnableDatagrams bool `yaml:"enable-datagrams" json:"enable-datagrams,omitempty"`
+
+	// Whether to disable the path MTU discovery (RFC 8899).
+	// QUIC then keeps the initial packet size, which helps on paths dropping large UDP packets.
+	// The default value is false.
+	DisablePathMTUDiscovery bool `yaml:"disable-path-mtu-discovery" json:"disable-path-mtu-discovery,omitempty"`
+
+	// The size of the first packets sent, the congestion window is counted in packets of this size
+	// until the path MTU discovery finds a larger one. Zero means the default of quic-go, 1280 bytes.
+	// quic-go doesn't allow to choose the congestion controller, this and the path MTU discovery
+	// are the only congestion related knobs.
+	InitialPacketSize uint16 `yaml:"initial-packet-size" json:"initial-packet-size,omitempty"`
 }
 
 // DefaultHttp3Config returns a default Http3Config instance.
@@ -52,7 +71,11 @@ func (t *Http3Config) Clone() *Http3Config {
 	}
 
 	return &Http3Config{
-		Enable:      t.Enable,
-		Negotiation: t.Negotiation,
+		Enable:                  t.Enable,
+		Negotiation:             t.Negotiation,
+		Allow0RTT:               t.Allow0RTT,
+		EnableDatagrams:         t.EnableDatagrams,
+		DisablePathMTUDiscovery: t.DisablePathMTUDiscovery,
+		InitialPacketSize:       t.InitialPacketSize,
 	}
 }
@@ -36,6 +36,16 @@ type ProtocolConfig struct {
 
 	TripleConfig *TripleConfig `yaml:"triple" json:"triple,omitempty" property:"triple"`
 
+	// UnixSocket is the path of the unix domain socket served instead of Ip and Port, e.g. "/var/run/dubbo.sock".
+	// The services served on it aren't registered, as the remote consumers can't reach it.
+	UnixSocket string `yaml:"unix-socket" json:"unix-socket,omitempty" property:"unix-socket"`
+	// UnixSocketMode is the file permission of UnixSocket in octal, e.g. "0660".
+	UnixSocketMode string `yaml:"unix-socket-mode" json:"unix-socket-mode,omitempty" property:"unix-socket-mode"`
+	// InMemory is the name of the in-memory listener served instead of Ip and Port, which
+	// clients in the same process reach by "memory://name". It's meant for tests, and the services
+	// served on it aren't registered either.
+	InMemory string `yaml:"in-memory" json:"in-memory,omitempty" property:"in-memory"`
+
 	// TODO: remove MaxServerSendMsgSize and MaxServerRecvMsgSize when version 4.0.0
 	//
 	// MaxServerSendMsgSize max size of server send message, 1mb=1000kb=1000000b 1mib=1024kb=1048576b.
@@ -70,6 +80,9 @@ func (c *ProtocolConfig) Clone() *ProtocolConfig {
 		Port:                 c.Port,
 		Params:               c.Params,
 		TripleConfig:         c.TripleConfig.Clone(),
+		UnixSocket:           c.UnixSocket,
+		UnixSocketMode:       c.UnixSocketMode,
+		InMemory:             c.InMemory,
 		MaxServerSendMsgSize: c.MaxServerSendMsgSize,
 		MaxServerRecvMsgSize: c.MaxServerRecvMsgSize,
 	}
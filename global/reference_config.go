@@ -35,6 +35,8 @@ type ReferenceConfig struct {
 	Group            string            `yaml:"group"  json:"group,omitempty" property:"group"`
 	Version          string            `yaml:"version"  json:"version,omitempty" property:"version"`
 	Serialization    string            `yaml:"serialization" json:"serialization" property:"serialization"`
+	Compression      string            `yaml:"compression" json:"compression,omitempty" property:"compression"`
+	CompressMinBytes int               `yaml:"compress-min-bytes" json:"compress-min-bytes,omitempty" property:"compress-min-bytes"`
 	ProvidedBy       string            `yaml:"provided_by"  json:"provided_by,omitempty" property:"provided_by"`
 	Async            bool              `yaml:"async"  json:"async,omitempty" property:"async"`
 	Params           map[string]string `yaml:"params"  json:"params,omitempty" property:"params"`
@@ -189,6 +191,8 @@ func (c *ReferenceConfig) Clone() *ReferenceConfig {
 		Group:                c.Group,
 		Version:              c.Version,
 		Serialization:        c.Serialization,
+		Compression:          c.Compression,
+		CompressMinBytes:     c.CompressMinBytes,
 		ProvidedBy:           c.ProvidedBy,
 		MethodsConfig:        newMethods,
 		ProtocolClientConfig: c.ProtocolClientConfig.Clone(),
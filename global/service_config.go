@@ -31,6 +31,8 @@ type ServiceConfig struct {
 	Warmup                      string            `yaml:"warmup"  json:"warmup,omitempty"  property:"warmup"`
 	Retries                     string            `yaml:"retries"  json:"retries,omitempty" property:"retries"`
 	Serialization               string            `yaml:"serialization" json:"serialization" property:"serialization"`
+	Compression                 string            `yaml:"compression" json:"compression,omitempty" property:"compression"`
+	CompressMinBytes            int               `yaml:"compress-min-bytes" json:"compress-min-bytes,omitempty" property:"compress-min-bytes"`
 	Params                      map[string]string `yaml:"params"  json:"params,omitempty" property:"params"`
 	Token                       string            `yaml:"token" json:"token,omitempty" property:"token"`
 	AccessLog                   string            `yaml:"accesslog" json:"accesslog,omitempty" property:"accesslog"`
@@ -39,6 +41,9 @@ type ServiceConfig struct {
 	TpsLimitRate                string            `yaml:"tps.limit.rate" json:"tps.limit.rate,omitempty" property:"tps.limit.rate"`
 	TpsLimitStrategy            string            `yaml:"tps.limit.strategy" json:"tps.limit.strategy,omitempty" property:"tps.limit.strategy"`
 	TpsLimitRejectedHandler     string            `yaml:"tps.limit.rejected.handler" json:"tps.limit.rejected.handler,omitempty" property:"tps.limit.rejected.handler"`
+	TpsLimitBurst               string            `yaml:"tps.limit.burst" json:"tps.limit.burst,omitempty" property:"tps.limit.burst"`
+	TpsLimitMaxWait             string            `yaml:"tps.limit.max.wait" json:"tps.limit.max.wait,omitempty" property:"tps.limit.max.wait"`
+	TpsLimitBy                  string            `yaml:"tps.limit.by" json:"tps.limit.by,omitempty" property:"tps.limit.by"`
 	ExecuteLimit                string            `yaml:"execute.limit" json:"execute.limit,omitempty" property:"execute.limit"`
 	ExecuteLimitRejectedHandler string            `yaml:"execute.limit.rejected.handler" json:"execute.limit.rejected.handler,omitempty" property:"execute.limit.rejected.handler"`
 	Auth                        string            `yaml:"auth" json:"auth,omitempty" property:"auth"`
@@ -47,6 +52,7 @@ type ServiceConfig struct {
 	Tag                         string            `yaml:"tag" json:"tag,omitempty" property:"tag"`
 	TracingKey                  string            `yaml:"tracing-key" json:"tracing-key,omitempty" propertiy:"tracing-key"`
 	Weight                      int64             `yaml:"weight" json:"weight,omitempty" property:"weight"`
+	Validation                  string            `yaml:"validation" json:"validation,omitempty" property:"validation"`
 
 	RCProtocolsMap  map[string]*ProtocolConfig
 	RCRegistriesMap map[string]*RegistryConfig
@@ -109,6 +115,8 @@ func (c *ServiceConfig) Clone() *ServiceConfig {
 		Warmup:                      c.Warmup,
 		Retries:                     c.Retries,
 		Serialization:               c.Serialization,
+		Compression:                 c.Compression,
+		CompressMinBytes:            c.CompressMinBytes,
 		Params:                      newParams,
 		Token:                       c.Token,
 		AccessLog:                   c.AccessLog,
@@ -117,6 +125,9 @@ func (c *ServiceConfig) Clone() *ServiceConfig {
 		TpsLimitRate:                c.TpsLimitRate,
 		TpsLimitStrategy:            c.TpsLimitStrategy,
 		TpsLimitRejectedHandler:     c.TpsLimitRejectedHandler,
+		TpsLimitBurst:               c.TpsLimitBurst,
+		TpsLimitMaxWait:             c.TpsLimitMaxWait,
+		TpsLimitBy:                  c.TpsLimitBy,
 		ExecuteLimit:                c.ExecuteLimit,
 		ExecuteLimitRejectedHandler: c.ExecuteLimitRejectedHandler,
 		Auth:                        c.Auth,
@@ -128,5 +139,6 @@ func (c *ServiceConfig) Clone() *ServiceConfig {
 		Tag:                         c.Tag,
 		TracingKey:                  c.TracingKey,
 		Weight:                      c.Weight,
+		Validation:                  c.Validation,
 	}
 }
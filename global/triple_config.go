@@ -34,12 +34,39 @@ type TripleConfig struct {
 	// the config of http3 transport
 	Http3 *Http3Config `yaml:"http3" json:"http3,omitempty"`
 
+	// the CORS config for browsers, CORS is disabled if it's nil
+	Cors *CorsConfig `yaml:"cors" json:"cors,omitempty"`
+
+	//
+	// for both server and client
+	//
+
+	// the config of carrying the streams over WebSocket
+	WebSocket *WebSocketConfig `yaml:"websocket" json:"websocket,omitempty"`
+
+	// MaxConcurrentStreams limits the streams a peer may open on one connection, it is advertised by the server
+	// as SETTINGS_MAX_CONCURRENT_STREAMS for HTTP/2 and as the max incoming streams for HTTP/3.
+	MaxConcurrentStreams uint32 `yaml:"max-concurrent-streams" json:"max-concurrent-streams,omitempty" property:"max-concurrent-streams"`
+	// InitialStreamWindowSize is the initial flow-control window of a stream, e.g. "1MiB".
+	// The windows apply to the server and to HTTP/3, the HTTP/2 client of x/net keeps its fixed windows.
+	InitialStreamWindowSize string `yaml:"initial-stream-window-size" json:"initial-stream-window-size,omitempty" property:"initial-stream-window-size"`
+	// InitialConnWindowSize is the initial flow-control window of a connection, e.g. "4MiB".
+	InitialConnWindowSize string `yaml:"initial-conn-window-size" json:"initial-conn-window-size,omitempty" property:"initial-conn-window-size"`
+	// MaxHeaderListSize is the max size of the headers accepted from the peer, e.g. "16KiB".
+	MaxHeaderListSize string `yaml:"max-header-list-size" json:"max-header-list-size,omitempty" property:"max-header-list-size"`
+	// IdleTimeout closes a connection without any active stream after the duration, e.g. "5m".
+	IdleTimeout string `yaml:"idle-timeout" json:"idle-timeout,omitempty" property:"idle-timeout"`
+
 	//
 	// for client
 	//
 
 	KeepAliveInterval string `yaml:"keep-alive-interval" json:"keep-alive-interval,omitempty" property:"keep-alive-interval"`
 	KeepAliveTimeout  string `yaml:"keep-alive-timeout" json:"keep-alive-timeout,omitempty" property:"keep-alive-timeout"`
+	// DialTimeout is the timeout of establishing a connection, including the TLS and QUIC handshakes.
+	DialTimeout string `yaml:"dial-timeout" json:"dial-timeout,omitempty" property:"dial-timeout"`
+	// the pool of the HTTP/2 connections to each provider, a single connection is used if it's nil
+	ConnPool *ConnPoolConfig `yaml:"conn-pool" json:"conn-pool,omitempty"`
 }
 
 // DefaultTripleConfig returns a default TripleConfig instance.
@@ -59,8 +86,18 @@ func (t *TripleConfig) Clone() *TripleConfig {
 		MaxServerSendMsgSize: t.MaxServerSendMsgSize,
 		MaxServerRecvMsgSize: t.MaxServerRecvMsgSize,
 		Http3:                t.Http3.Clone(),
+		Cors:                 t.Cors.Clone(),
+		WebSocket:            t.WebSocket.Clone(),
+
+		MaxConcurrentStreams:    t.MaxConcurrentStreams,
+		InitialStreamWindowSize: t.InitialStreamWindowSize,
+		InitialConnWindowSize:   t.InitialConnWindowSize,
+		MaxHeaderListSize:       t.MaxHeaderListSize,
+		IdleTimeout:             t.IdleTimeout,
 
 		KeepAliveInterval: t.KeepAliveInterval,
 		KeepAliveTimeout:  t.KeepAliveTimeout,
+		DialTimeout:       t.DialTimeout,
+		ConnPool:          t.ConnPool.Clone(),
 	}
 }
@@ -0,0 +1,114 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package global
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestTripleConfigYAML(t *testing.T) {
+	content := `
+max-concurrent-streams: 128
+initial-stream-window-size: 1MiB
+initial-conn-window-size: 4MiB
+max-header-list-size: 16KiB
+idle-timeout: 5m
+keep-alive-interval: 10s
+keep-alive-timeout: 20s
+dial-timeout: 3s
+http3:
+  enable: true
+  negotiation: true
+  allow-0rtt: true
+  enable-datagrams: true
+  disable-path-mtu-discovery: true
+  initial-packet-size: 1200
+cors:
+  allow-origins: ["https://example.com"]
+  allow-headers: ["X-Custom"]
+  expose-headers: ["X-Custom-Trailer"]
+  allow-credentials: true
+  max-age: 2h
+conn-pool:
+  max-connections: 8
+  open-threshold: 0.5
+  idle-timeout: 30s
+websocket:
+  enable: true
+  ping-interval: 15s
+  pong-timeout: 5s
+`
+	c := DefaultTripleConfig()
+	assert.Nil(t, yaml.Unmarshal([]byte(content), c))
+	assert.Equal(t, &TripleConfig{
+		MaxConcurrentStreams:    128,
+		InitialStreamWindowSize: "1MiB",
+		InitialConnWindowSize:   "4MiB",
+		MaxHeaderListSize:       "16KiB",
+		IdleTimeout:             "5m",
+		KeepAliveInterval:       "10s",
+		KeepAliveTimeout:        "20s",
+		DialTimeout:             "3s",
+		Http3: &Http3Config{
+			Enable:                  true,
+			Negotiation:             true,
+			Allow0RTT:               true,
+			EnableDatagrams:         true,
+			DisablePathMTUDiscovery: true,
+			InitialPacketSize:       1200,
+		},
+		Cors: &CorsConfig{
+			AllowOrigins:     []string{"https://example.com"},
+			AllowHeaders:     []string{"X-Custom"},
+			ExposeHeaders:    []string{"X-Custom-Trailer"},
+			AllowCredentials: true,
+			MaxAge:           "2h",
+		},
+		ConnPool: &ConnPoolConfig{
+			MaxConnections: 8,
+			OpenThreshold:  0.5,
+			IdleTimeout:    "30s",
+		},
+		WebSocket: &WebSocketConfig{
+			Enable:       true,
+			PingInterval: "15s",
+			PongTimeout:  "5s",
+		},
+	}, c)
+}
+
+func TestCorsConfigClone(t *testing.T) {
+	var c *CorsConfig
+	assert.Nil(t, c.Clone())
+
+	c = &CorsConfig{
+		AllowOrigins:  []string{"https://example.com"},
+		ExposeHeaders: []string{"X-Custom-Trailer"},
+		MaxAge:        "2h",
+	}
+	clone := c.Clone()
+	assert.Equal(t, c, clone)
+	clone.AllowOrigins[0] = "*"
+	assert.Equal(t, "https://example.com", c.AllowOrigins[0])
+}
@@ -0,0 +1,43 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package global
+
+// WebSocketConfig represents the config of carrying the triple streams over WebSocket, which passes
+// the proxies breaking long-lived HTTP/2 streams. The server accepts WebSocket upgrades regardless of it.
+type WebSocketConfig struct {
+	// Whether the client carries the streaming RPCs over WebSocket, the unary RPCs keep using HTTP/2.
+	// It can be enabled for a single reference by the "websocket" param of its URL as well.
+	Enable bool `yaml:"enable" json:"enable,omitempty"`
+	// PingInterval is how often a ping is sent to keep the connection alive, e.g. "30s".
+	PingInterval string `yaml:"ping-interval" json:"ping-interval,omitempty"`
+	// PongTimeout closes the connection if nothing is received from the peer within the duration
+	// after the ping interval, e.g. "10s".
+	PongTimeout string `yaml:"pong-timeout" json:"pong-timeout,omitempty"`
+}
+
+// Clone a new WebSocketConfig
+func (c *WebSocketConfig) Clone() *WebSocketConfig {
+	if c == nil {
+		return nil
+	}
+
+	return &WebSocketConfig{
+		Enable:       c.Enable,
+		PingInterval: c.PingInterval,
+		PongTimeout:  c.PongTimeout,
+	}
+}
@@ -55,7 +55,11 @@ var IncludeKeys = gxset.NewSet(
 	constant.VersionKey,
 	constant.WarmupKey,
 	constant.WeightKey,
-	constant.ReleaseKey)
+	constant.ReleaseKey,
+	constant.DeprecatedKey,
+	constant.SunsetKey,
+	constant.ReplacementKey,
+	constant.SunsetRejectKey)
 
 // MetadataInfo the metadata information of instance
 type MetadataInfo struct {
@@ -32,6 +32,7 @@ import (
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 )
 
 var (
@@ -143,6 +144,25 @@ func TestServiceInfoGetParams(t *testing.T) {
 	assert.Equal(t, service.GetParams()["loadbalance"], []string{"random"})
 }
 
+func TestServiceInfoDeprecation(t *testing.T) {
+	url := common.NewURLWithOptions(
+		common.WithProtocol("tri"),
+		common.WithPath("/org.apache.dubbo.samples.proto.GreetService"),
+		common.WithInterface("org.apache.dubbo.samples.proto.GreetService"),
+		common.WithMethods([]string{"Greet", "SayHello"}),
+		common.WithParamsValue(constant.DeprecatedKey, "true"),
+		common.WithParamsValue(constant.SunsetRejectKey, "true"),
+		common.WithParamsValue("methods.Greet."+constant.SunsetKey, "2025-03-31"),
+		common.WithParamsValue("methods.Greet."+constant.ReplacementKey, "GreetV2"),
+	)
+	service := NewServiceInfoWithURL(url)
+	assert.Equal(t, "true", service.Params[constant.DeprecatedKey])
+	assert.Equal(t, "true", service.Params[constant.SunsetRejectKey])
+	assert.Equal(t, "2025-03-31", service.Params["Greet."+constant.SunsetKey])
+	params := service.GetParams()
+	assert.Equal(t, "GreetV2", params.Get("methods.Greet."+constant.ReplacementKey))
+}
+
 func TestServiceInfoGetMatchKey(t *testing.T) {
 	si := NewServiceInfoWithURL(serviceUrl)
 	matchKey := si.MatchKey
@@ -214,6 +214,11 @@ type GaugeVec interface {
 	Sub(labels map[string]string, v float64)
 }
 
+// GaugeRemover is implemented by the MetricRegistry able to remove a gauge, e.g. of a departed provider.
+type GaugeRemover interface {
+	RemoveGauge(*MetricId)
+}
+
 // NewGaugeVec create a GaugeVec default implementation.
 func NewGaugeVec(metricRegistry MetricRegistry, metricKey *MetricKey) GaugeVec {
 	return &DefaultGaugeVec{
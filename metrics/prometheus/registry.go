@@ -90,6 +90,13 @@ func (p *promMetricRegistry) Gauge(m *metrics.MetricId) metrics.GaugeMetric {
 	return vec.With(m.Tags)
 }
 
+// RemoveGauge implements metrics.GaugeRemover.
+func (p *promMetricRegistry) RemoveGauge(m *metrics.MetricId) {
+	if vec, ok := p.vecs.Load(m.Name); ok {
+		vec.(*prom.GaugeVec).Delete(m.Tags)
+	}
+}
+
 func (p *promMetricRegistry) Histogram(m *metrics.MetricId) metrics.ObservableMetric {
 	vec := p.getOrComputeVec(m.Name, func() prom.Collector {
 		return prom.NewHistogramVec(prom.HistogramOpts{
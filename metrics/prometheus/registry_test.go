@@ -190,3 +190,14 @@ func waitTimeout(wg *sync.WaitGroup, timeout time.Duration) bool {
 		return true // timed out
 	}
 }
+
+func TestPromMetricRegistryRemoveGauge(t *testing.T) {
+	p := NewPromMetricRegistry(prom.NewRegistry(), url)
+	// removing a gauge never set is a no-op
+	p.RemoveGauge(metricId)
+	p.Gauge(metricId).Set(100)
+	p.RemoveGauge(metricId)
+	text, err := p.Scrape()
+	assert.Nil(t, err)
+	assert.NotContains(t, text, `dubbo_request{app="dubbo",version="1.0.0"}`)
+}
@@ -92,6 +92,10 @@ func (c *rpcCollector) afterInvokeHandler(event *metricsEvent) {
 	labels := buildLabels(url, event.invocation)
 	c.incRequestsTotal(role, labels)
 	c.decRequestsProcessingTotal(role, labels)
+	if fault, ok := event.invocation.GetAttribute(constant.FaultInjectedKey); ok {
+		// injected faults are counted as usual, and by the fault counter to tell them apart from real ones
+		c.incFaultInjectedTotal(role, labels, fault)
+	}
 	if event.result != nil {
 		if event.result.Error() == nil {
 			c.incRequestsSucceedTotal(role, labels)
@@ -163,6 +167,20 @@ func (c *rpcCollector) incRequestsFailedTotal(role string, labels map[string]str
 	}
 }
 
+func (c *rpcCollector) incFaultInjectedTotal(role string, labels map[string]string, fault any) {
+	faultLabels := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		faultLabels[k] = v
+	}
+	faultLabels[constant.TagFault], _ = fault.(string)
+	switch role {
+	case constant.SideProvider:
+		c.metricSet.provider.faultInjectedTotal.Inc(faultLabels)
+	case constant.SideConsumer:
+		c.metricSet.consumer.faultInjectedTotal.Inc(faultLabels)
+	}
+}
+
 func (c *rpcCollector) reportRTMilliseconds(role string, labels map[string]string, cost int64) {
 	switch role {
 	case constant.SideProvider:
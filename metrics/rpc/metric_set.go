@@ -48,6 +48,7 @@ type rpcCommonMetrics struct {
 	rtMilliseconds                metrics.RtVec
 	rtMillisecondsQuantiles       metrics.QuantileMetricVec
 	rtMillisecondsAggregate       metrics.RtVec
+	faultInjectedTotal            metrics.CounterVec
 }
 
 // buildMetricSet will call init functions to initialize the metricSet
@@ -84,6 +85,7 @@ func (pm *providerMetrics) init(registry metrics.MetricRegistry) {
 		metrics.NewMetricKey("dubbo_provider_rt_milliseconds_p95", "The total response time spent by providers processing 95% of requests"),
 		metrics.NewMetricKey("dubbo_provider_rt_milliseconds_p99", "The total response time spent by providers processing 99% of requests"),
 	}, []float64{0.5, 0.9, 0.95, 0.99})
+	pm.faultInjectedTotal = metrics.NewCounterVec(registry, metrics.NewMetricKey("dubbo_provider_requests_fault_injected_total", "The number of requests received by the provider affected by fault injection"))
 }
 
 func (cm *consumerMetrics) init(registry metrics.MetricRegistry) {
@@ -109,4 +111,5 @@ func (cm *consumerMetrics) init(registry metrics.MetricRegistry) {
 		metrics.NewMetricKey("dubbo_consumer_rt_milliseconds_p95", "The total response time spent by consumers processing 95% of requests"),
 		metrics.NewMetricKey("dubbo_consumer_rt_milliseconds_p99", "The total response time spent by consumers processing 99% of requests"),
 	}, []float64{0.5, 0.9, 0.95, 0.99})
+	cm.faultInjectedTotal = metrics.NewCounterVec(registry, metrics.NewMetricKey("dubbo_consumer_requests_fault_injected_total", "The number of requests sent by consumers affected by fault injection"))
 }
@@ -29,11 +29,19 @@ import (
 type TimeWindowCounter struct {
 	window *slidingWindow
 	mux    sync.RWMutex
+	now    func() time.Time
 }
 
 func NewTimeWindowCounter(paneCount int, timeWindowSeconds int64) *TimeWindowCounter {
+	return NewTimeWindowCounterWithClock(paneCount, time.Duration(timeWindowSeconds)*time.Second, time.Now)
+}
+
+// NewTimeWindowCounterWithClock creates a counter of the time window, counted in milliseconds, whose panes
+// slide by the time returned by now. paneCount must not exceed the milliseconds of the window.
+func NewTimeWindowCounterWithClock(paneCount int, window time.Duration, now func() time.Time) *TimeWindowCounter {
 	return &TimeWindowCounter{
-		window: newSlidingWindow(paneCount, timeWindowSeconds*1000),
+		window: newSlidingWindow(paneCount, window.Milliseconds()),
+		now:    now,
 	}
 }
 
@@ -43,7 +51,7 @@ func (t *TimeWindowCounter) Count() float64 {
 	defer t.mux.RUnlock()
 
 	total := float64(0)
-	for _, v := range t.window.values(time.Now().UnixMilli()) {
+	for _, v := range t.window.values(t.now().UnixMilli()) {
 		total += v.(*counter).value
 	}
 	return total
@@ -54,7 +62,7 @@ func (t *TimeWindowCounter) LivedSeconds() int64 {
 	t.mux.RLock()
 	defer t.mux.RUnlock()
 
-	windowLength := len(t.window.values(time.Now().UnixMilli()))
+	windowLength := len(t.window.values(t.now().UnixMilli()))
 	return int64(windowLength) * t.window.paneIntervalInMs / 1000
 }
 
@@ -63,7 +71,7 @@ func (t *TimeWindowCounter) Add(step float64) {
 	t.mux.Lock()
 	defer t.mux.Unlock()
 
-	t.window.currentPane(time.Now().UnixMilli(), t.newEmptyValue).value.(*counter).add(step)
+	t.window.currentPane(t.now().UnixMilli(), t.newEmptyValue).value.(*counter).add(step)
 }
 
 // Inc increments the counter by 1.
@@ -97,3 +97,21 @@ func TestTimeWindowCounterLivedSeconds(t1 *testing.T) {
 		})
 	}
 }
+
+func TestTimeWindowCounterWithClock(t *testing.T) {
+	current := time.Unix(1000, 0)
+	counter := NewTimeWindowCounterWithClock(10, time.Second, func() time.Time {
+		return current
+	})
+	counter.Inc()
+	counter.Add(2)
+	if got := counter.Count(); got != 3 {
+		t.Errorf("Count() = %v, want 3", got)
+	}
+
+	// the counts slide out of the window by the clock
+	current = current.Add(2 * time.Second)
+	if got := counter.Count(); got != 0 {
+		t.Errorf("Count() = %v, want 0", got)
+	}
+}
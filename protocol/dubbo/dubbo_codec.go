@@ -38,6 +38,7 @@ import (
 	invct "dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 	"dubbo.apache.org/dubbo-go/v3/protocol/result"
 	"dubbo.apache.org/dubbo-go/v3/remoting"
+	"dubbo.apache.org/dubbo-go/v3/remoting/getty"
 )
 
 // SerialID serial ID
@@ -86,6 +87,9 @@ func (c *DubboCodec) EncodeRequest(request *remoting.Request) (*bytes.Buffer, er
 		header.SerialID = constant.SHessian2
 	}
 	header.ID = request.ID
+	if header.Compression, err = impl.GetCompressionID(request.Compression); err != nil {
+		return nil, perrors.WithStack(err)
+	}
 	if request.TwoWay {
 		header.Type = impl.PackageRequest_TwoWay
 	} else {
@@ -93,18 +97,27 @@ func (c *DubboCodec) EncodeRequest(request *remoting.Request) (*bytes.Buffer, er
 	}
 
 	pkg := &impl.DubboPackage{
-		Header:  header,
-		Service: svc,
-		Body:    impl.NewRequestPayload(invocation.Arguments(), invocation.Attachments()),
-		Err:     nil,
-		Codec:   impl.NewDubboCodec(nil),
+		Header:              header,
+		Service:             svc,
+		Body:                impl.NewRequestPayload(invocation.Arguments(), invocation.Attachments()),
+		Err:                 nil,
+		Codec:               impl.NewDubboCodec(nil),
+		CompressMinBytes:    request.CompressMinBytes,
+		UncompressedRequest: !request.CompressBody,
 	}
 
 	if err := impl.LoadSerializer(pkg); err != nil {
 		return nil, perrors.WithStack(err)
 	}
 
-	return pkg.Marshal()
+	buf, err := pkg.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	if err = request.BodyLimit.Check(buf.Len() - impl.HEADER_LENGTH); err != nil {
+		return nil, err
+	}
+	return buf, nil
 }
 
 // encode heartbeat request
@@ -135,19 +148,30 @@ func (c *DubboCodec) EncodeResponse(response *remoting.Response) (*bytes.Buffer,
 	if response.IsHeartbeat() {
 		ptype = impl.PackageHeartbeat
 	}
+	compression, err := impl.GetCompressionID(response.Compression)
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
 	resp := &impl.DubboPackage{
 		Header: impl.DubboHeader{
 			SerialID:       response.SerialID,
 			Type:           ptype,
 			ID:             response.ID,
 			ResponseStatus: response.Status,
+			Compression:    compression,
 		},
+		CompressMinBytes: response.CompressMinBytes,
 	}
 	if !response.IsHeartbeat() {
+		rpcResult := response.Result.(result.RPCResult)
+		exception, attachments := rpcResult.Err, rpcResult.Attrs
+		if exception != nil {
+			exception, attachments = encodeResponseError(exception, attachments)
+		}
 		resp.Body = &impl.ResponsePayload{
-			RspObj:      response.Result.(result.RPCResult).Rest,
-			Exception:   response.Result.(result.RPCResult).Err,
-			Attachments: response.Result.(result.RPCResult).Attrs,
+			RspObj:      rpcResult.Rest,
+			Exception:   exception,
+			Attachments: attachments,
 		}
 	}
 
@@ -157,6 +181,14 @@ func (c *DubboCodec) EncodeResponse(response *remoting.Response) (*bytes.Buffer,
 	if err != nil {
 		return nil, perrors.WithStack(err)
 	}
+	if err = response.BodyLimit.Check(len(pkg) - impl.HEADER_LENGTH); err != nil {
+		// replies the error instead of the response too large
+		exception, attachments := encodeResponseError(err, resp.Body.(*impl.ResponsePayload).Attachments)
+		resp.Body = &impl.ResponsePayload{Exception: exception, Attachments: attachments}
+		if pkg, err = codec.Encode(*resp); err != nil {
+			return nil, perrors.WithStack(err)
+		}
+	}
 
 	return bytes.NewBuffer(pkg), nil
 }
@@ -198,6 +230,7 @@ func (c *DubboCodec) decodeRequest(data []byte) (*remoting.Request, int, error)
 	buf := bytes.NewBuffer(data)
 	pkg := impl.NewDubboPackage(buf)
 	pkg.SetBody(make([]any, 7))
+	pkg.MaxBodyLen = getty.GetServerConfig().GettySessionParam.MaxMsgLen
 	err := pkg.Unmarshal()
 	if err != nil {
 		originErr := perrors.Cause(err)
@@ -216,6 +249,8 @@ func (c *DubboCodec) decodeRequest(data []byte) (*remoting.Request, int, error)
 		SerialID: pkg.Header.SerialID,
 		TwoWay:   pkg.Header.Type&impl.PackageRequest_TwoWay != 0x00,
 		Event:    pkg.Header.Type&impl.PackageHeartbeat != 0x00,
+		// the accepted compression of the response
+		Compression: impl.GetCompressionName(pkg.Header.Compression),
 	}
 	if (pkg.Header.Type & impl.PackageHeartbeat) == 0x00 {
 		// convert params of request
@@ -239,6 +274,8 @@ func (c *DubboCodec) decodeRequest(data []byte) (*remoting.Request, int, error)
 		attachments = req[impl.AttachmentsKey].(map[string]any)
 		invoc := invct.NewRPCInvocationWithOptions(invct.WithAttachments(attachments),
 			invct.WithArguments(args), invct.WithMethodName(methodName))
+		// the provider checks the size limit of the method with the decompressed length
+		invoc.SetAttribute(constant.RequestBodyLenKey, pkg.DecodedBodyLen)
 		request.Data = invoc
 
 	}
@@ -249,6 +286,7 @@ func (c *DubboCodec) decodeRequest(data []byte) (*remoting.Request, int, error)
 func (c *DubboCodec) decodeResponse(data []byte) (*remoting.Response, int, error) {
 	buf := bytes.NewBuffer(data)
 	pkg := impl.NewDubboPackage(buf)
+	pkg.MaxBodyLen = getty.GetClientConf().GettySessionParam.MaxMsgLen
 	err := pkg.Unmarshal()
 	if err != nil {
 		originErr := perrors.Cause(err)
@@ -269,6 +307,7 @@ func (c *DubboCodec) decodeResponse(data []byte) (*remoting.Response, int, error
 		SerialID: pkg.Header.SerialID,
 		Status:   pkg.Header.ResponseStatus,
 		Event:    (pkg.Header.Type & impl.PackageHeartbeat) != 0,
+		BodyLen:  pkg.DecodedBodyLen,
 	}
 	var pkgerr error
 	if pkg.Header.Type&impl.PackageHeartbeat != 0x00 {
@@ -292,7 +331,8 @@ func (c *DubboCodec) decodeResponse(data []byte) (*remoting.Response, int, error
 		if pkg.Err != nil {
 			rpcResult.Err = pkg.Err
 		} else if pkg.Body.(*impl.ResponsePayload).Exception != nil {
-			rpcResult.Err = pkg.Body.(*impl.ResponsePayload).Exception
+			rpcResult.Err = decodeResponseError(pkg.Body.(*impl.ResponsePayload).Exception,
+				pkg.Header.ResponseStatus, pkg.Body.(*impl.ResponsePayload).Attachments)
 			response.Error = rpcResult.Err
 		}
 		rpcResult.Attrs = pkg.Body.(*impl.ResponsePayload).Attachments
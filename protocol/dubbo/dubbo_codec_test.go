@@ -0,0 +1,133 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo
+
+import (
+	"strings"
+	"testing"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	"dubbo.apache.org/dubbo-go/v3/protocol/sizelimit"
+	tri "dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+func TestDubboCodec_SizeLimit(t *testing.T) {
+	codec := &DubboCodec{}
+	url := common.NewURLWithOptions(
+		common.WithPath("com.ikurento.user.UserProvider"),
+		common.WithParamsValue(constant.InterfaceKey, "com.ikurento.user.UserProvider"),
+		common.WithParamsValue(constant.MaxRequestSizeKey, "100b"),
+		common.WithParamsValue(constant.MaxResponseSizeKey, "100b"),
+	)
+	payload := strings.Repeat("a", 200)
+
+	// the request over the limit is rejected as it is encoded
+	var inv base.Invocation = invocation.NewRPCInvocationWithOptions(
+		invocation.WithMethodName("Echo"),
+		invocation.WithArguments([]any{payload}),
+		invocation.WithAttachments(map[string]any{
+			constant.PathKey:      "com.ikurento.user.UserProvider",
+			constant.InterfaceKey: "com.ikurento.user.UserProvider",
+		}),
+	)
+	request := remoting.NewRequest("2.0.2")
+	request.Data = &inv
+	request.TwoWay = true
+	request.BodyLimit = sizelimit.RequestLimit(url, constant.SideConsumer, "Echo")
+	_, err := codec.EncodeRequest(request)
+	assert.Equal(t, tri.CodeResourceExhausted, tri.CodeOf(err))
+
+	// the provider gets the body length of the request
+	request.BodyLimit = nil
+	buf, err := codec.EncodeRequest(request)
+	require.NoError(t, err)
+	decoded, _, err := codec.Decode(buf.Bytes())
+	require.NoError(t, err)
+	decodedInv := decoded.Result.(*remoting.Request).Data.(*invocation.RPCInvocation)
+	bodyLen, _ := decodedInv.GetAttributeWithDefaultValue(constant.RequestBodyLenKey, 0).(int)
+	assert.Equal(t, buf.Len()-hessian.HEADER_LENGTH, bodyLen)
+	assert.Error(t, sizelimit.RequestLimit(url, constant.SideProvider, "Echo").Check(bodyLen))
+
+	// the provider checks the decompressed length of a compressed request
+	request.Compression = constant.CompressionGzip
+	request.CompressBody = true
+	compressed, err := codec.EncodeRequest(request)
+	require.NoError(t, err)
+	assert.Less(t, compressed.Len(), buf.Len())
+	decoded, _, err = codec.Decode(compressed.Bytes())
+	require.NoError(t, err)
+	decodedInv = decoded.Result.(*remoting.Request).Data.(*invocation.RPCInvocation)
+	bodyLen, _ = decodedInv.GetAttributeWithDefaultValue(constant.RequestBodyLenKey, 0).(int)
+	assert.Equal(t, buf.Len()-hessian.HEADER_LENGTH, bodyLen)
+	request.Compression = ""
+	request.CompressBody = false
+
+	newResponse := func() *remoting.Response {
+		response := remoting.NewResponse(remoting.SequenceID(), "2.0.2")
+		response.SerialID = 2
+		response.Status = hessian.Response_OK
+		response.Result = result.RPCResult{Rest: payload, Attrs: map[string]any{}}
+		return response
+	}
+
+	// the provider replies an error instead of the response over the limit
+	response := newResponse()
+	response.BodyLimit = sizelimit.ResponseLimit(url, constant.SideProvider, "Echo")
+	buf, err = codec.EncodeResponse(response)
+	require.NoError(t, err)
+	var reply string
+	pending := remoting.NewPendingResponse(response.ID)
+	pending.Reply = &reply
+	remoting.AddPendingResponse(pending)
+	decoded, _, err = codec.Decode(buf.Bytes())
+	require.NoError(t, err)
+	decodedResp := decoded.Result.(*remoting.Response)
+	decodedResp.Handle()
+	assert.Equal(t, tri.CodeResourceExhausted, tri.CodeOf(decodedResp.Result.(*result.RPCResult).Err))
+
+	// the consumer rejects the response over the limit as it is decoded
+	response = newResponse()
+	buf, err = codec.EncodeResponse(response)
+	require.NoError(t, err)
+	pending = remoting.NewPendingResponse(response.ID)
+	pending.Reply = &reply
+	pending.BodyLimit = sizelimit.ResponseLimit(url, constant.SideConsumer, "Echo")
+	remoting.AddPendingResponse(pending)
+	decoded, _, err = codec.Decode(buf.Bytes())
+	require.NoError(t, err)
+	decodedResp = decoded.Result.(*remoting.Response)
+	assert.Equal(t, buf.Len()-hessian.HEADER_LENGTH, decodedResp.BodyLen)
+	decodedResp.Handle()
+	<-pending.Done
+	assert.Equal(t, tri.CodeResourceExhausted, tri.CodeOf(pending.Err))
+	assert.Equal(t, tri.CodeResourceExhausted, tri.CodeOf(decodedResp.Result.(*result.RPCResult).Err))
+}
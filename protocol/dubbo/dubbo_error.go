@@ -0,0 +1,165 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package dubbo
+
+import (
+	"encoding/base64"
+	"errors"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+	"github.com/apache/dubbo-go-hessian2/java_exception"
+
+	"github.com/dubbogo/gost/log/logger"
+
+	statuspb "google.golang.org/genproto/googleapis/rpc/status"
+
+	"google.golang.org/grpc/status"
+
+	"google.golang.org/protobuf/proto"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/dubbo/impl"
+	tri "dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+)
+
+// javaExceptionCodes maps Java exceptions to the codes of triple errors, and
+// the other way around.
+var javaExceptionCodes = map[string]tri.Code{
+	"java.lang.IllegalArgumentException":         tri.CodeInvalidArgument,
+	"java.lang.IllegalStateException":            tri.CodeFailedPrecondition,
+	"java.lang.SecurityException":                tri.CodePermissionDenied,
+	"java.lang.UnsupportedOperationException":    tri.CodeUnimplemented,
+	"java.util.NoSuchElementException":           tri.CodeNotFound,
+	"java.util.concurrent.CancellationException": tri.CodeCanceled,
+	"java.util.concurrent.TimeoutException":      tri.CodeDeadlineExceeded,
+}
+
+// responseStatusCodes maps the statuses of failed dubbo responses to the codes of triple errors.
+var responseStatusCodes = map[byte]tri.Code{
+	hessian.Response_CLIENT_TIMEOUT:    tri.CodeDeadlineExceeded,
+	hessian.Response_SERVER_TIMEOUT:    tri.CodeDeadlineExceeded,
+	hessian.Response_BAD_REQUEST:       tri.CodeInvalidArgument,
+	hessian.Response_SERVICE_NOT_FOUND: tri.CodeUnimplemented,
+	hessian.Response_SERVICE_ERROR:     tri.CodeInternal,
+	hessian.Response_SERVER_ERROR:      tri.CodeInternal,
+}
+
+func newJavaException(code tri.Code, message string) java_exception.Throwabler {
+	switch code {
+	case tri.CodeInvalidArgument:
+		return java_exception.NewIllegalArgumentException(message)
+	case tri.CodeFailedPrecondition:
+		return java_exception.NewIllegalStateException(message)
+	case tri.CodePermissionDenied:
+		return java_exception.NewSecurityException(message)
+	case tri.CodeUnimplemented:
+		return java_exception.NewUnsupportedOperationException(message)
+	case tri.CodeNotFound:
+		return java_exception.NewNoSuchElementException(message)
+	case tri.CodeCanceled:
+		return java_exception.NewCancellationException(message)
+	case tri.CodeDeadlineExceeded:
+		return java_exception.NewTimeoutException(message)
+	default:
+		return java_exception.NewThrowable(message)
+	}
+}
+
+// encodeResponseError converts triple and gRPC errors into the Java exception
+// matching their codes, which Java consumers understand. The code, message and
+// details of the error are carried in the response attachments as well, so that
+// dubbo-go consumers restore the error as it is. Other errors are left untouched.
+func encodeResponseError(err error, attachments map[string]any) (error, map[string]any) {
+	tripleErr, ok := tri.FromError(err)
+	if !ok {
+		return err, attachments
+	}
+	var exception java_exception.Throwabler
+	if !errors.As(err, &exception) {
+		exception = newJavaException(tripleErr.Code(), tripleErr.Error())
+	}
+	statusBytes, marshalErr := proto.Marshal(tripleErr.GRPCStatus().Proto())
+	if marshalErr != nil {
+		logger.Warnf("Failed to marshal the status of error %v: %v", err, marshalErr)
+		return exception, attachments
+	}
+	newAttachments := make(map[string]any, len(attachments)+2)
+	for k, v := range attachments {
+		newAttachments[k] = v
+	}
+	// response attachments are only sent since dubbo 2.0.2
+	if _, ok := newAttachments[impl.DUBBO_VERSION_KEY]; !ok {
+		newAttachments[impl.DUBBO_VERSION_KEY] = impl.DEFAULT_DUBBO_PROTOCOL_VERSION
+	}
+	newAttachments[constant.ErrorStatusKey] = base64.StdEncoding.EncodeToString(statusBytes)
+	return exception, newAttachments
+}
+
+// decodeResponseError restores the triple error encoded by encodeResponseError.
+// Without it, Java exceptions and failed response statuses known to map to a code
+// are given that code by a codedError, whose message is still the original one, so
+// the consumers matching on the messages of the errors are unaffected, while
+// tri.CodeOf and tri.FromError tell the code.
+func decodeResponseError(err error, responseStatus byte, attachments map[string]any) error {
+	if encoded, ok := attachments[constant.ErrorStatusKey].(string); ok {
+		pb := &statuspb.Status{}
+		statusBytes, decodeErr := base64.StdEncoding.DecodeString(encoded)
+		if decodeErr == nil {
+			decodeErr = proto.Unmarshal(statusBytes, pb)
+		}
+		if decodeErr == nil {
+			if tripleErr, ok := tri.FromError(status.FromProto(pb).Err()); ok {
+				return tripleErr
+			}
+		}
+		logger.Warnf("Failed to decode the status of error %v: %v", err, decodeErr)
+	}
+	if code, ok := responseStatusCodes[responseStatus]; ok {
+		return newCodedError(code, err)
+	}
+	var exception java_exception.Throwabler
+	if errors.As(err, &exception) {
+		if code, ok := javaExceptionCodes[exception.JavaClassName()]; ok {
+			return newCodedError(code, err)
+		}
+	}
+	return err
+}
+
+// codedError is an error from a dubbo provider with the code it maps to.
+// It unwraps to both the original error and the triple error with the code.
+type codedError struct {
+	err       error
+	tripleErr *tri.Error
+}
+
+func newCodedError(code tri.Code, err error) error {
+	return &codedError{err: err, tripleErr: tri.NewWireError(code, err)}
+}
+
+// Error returns the message of the original error
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codedError) Unwrap() []error {
+	return []error{e.err, e.tripleErr}
+}
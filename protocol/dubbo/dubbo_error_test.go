@@ -0,0 +1,100 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package dubbo
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+	"github.com/apache/dubbo-go-hessian2/java_exception"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	tri "dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+func TestDubboCodec_ResponseErrorDetails(t *testing.T) {
+	codec := &DubboCodec{}
+	roundTrip := func(err error) error {
+		response := remoting.NewResponse(remoting.SequenceID(), "2.0.2")
+		response.SerialID = 2
+		response.Status = hessian.Response_OK
+		response.Result = result.RPCResult{Err: err, Attrs: map[string]any{}}
+		buf, encodeErr := codec.EncodeResponse(response)
+		assert.NoError(t, encodeErr)
+
+		var reply string
+		pending := remoting.NewPendingResponse(response.ID)
+		pending.Reply = &reply
+		remoting.AddPendingResponse(pending)
+		decoded, _, decodeErr := codec.Decode(buf.Bytes())
+		assert.NoError(t, decodeErr)
+		return decoded.Result.(*remoting.Response).Result.(*result.RPCResult).Err
+	}
+
+	// the code, message and details survive
+	err := roundTrip(tri.NewRetryableError(tri.CodeUnavailable, "overloaded", time.Second))
+	assert.Equal(t, tri.CodeUnavailable, tri.CodeOf(err))
+	assert.True(t, tri.IsWireError(err))
+	tripleErr, ok := tri.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, "overloaded", tripleErr.Message())
+	delay, ok := tri.RetryDelayOf(err)
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, delay)
+
+	// plain errors stay Java throwables
+	err = roundTrip(errors.New("plain"))
+	assert.Equal(t, tri.CodeUnknown, tri.CodeOf(err))
+	var throwable java_exception.Throwabler
+	assert.True(t, errors.As(err, &throwable))
+
+	// known Java exceptions map to codes
+	err = roundTrip(java_exception.NewIllegalArgumentException("bad argument"))
+	assert.Equal(t, tri.CodeInvalidArgument, tri.CodeOf(err))
+	assert.True(t, errors.As(err, &throwable))
+	assert.Equal(t, "java.lang.IllegalArgumentException", throwable.JavaClassName())
+	// the message is unchanged
+	assert.Equal(t, java_exception.NewIllegalArgumentException("bad argument").Error(), err.Error())
+}
+
+func TestEncodeResponseError(t *testing.T) {
+	exception, attachments := encodeResponseError(tri.NewBadRequestError("invalid", map[string]string{"name": "empty"}), nil)
+	var throwable java_exception.Throwabler
+	assert.True(t, errors.As(exception, &throwable))
+	assert.Equal(t, "java.lang.IllegalArgumentException", throwable.JavaClassName())
+	assert.Contains(t, attachments, constant.ErrorStatusKey)
+
+	plain := errors.New("plain")
+	exception, attachments = encodeResponseError(plain, nil)
+	assert.Equal(t, plain, exception)
+	assert.Nil(t, attachments)
+
+	err := decodeResponseError(errors.New("java exception:timeout"), hessian.Response_SERVER_TIMEOUT, nil)
+	assert.Equal(t, tri.CodeDeadlineExceeded, tri.CodeOf(err))
+	assert.True(t, tri.IsWireError(err))
+	assert.Equal(t, "java exception:timeout", err.Error())
+}
@@ -119,6 +119,9 @@ func (di *DubboInvoker) Invoke(ctx context.Context, ivc base.Invocation) result.
 			inv.SetAttachment(k, v)
 		}
 	}
+	if app := di.GetURL().GetParam(constant.ApplicationKey, ""); len(app) > 0 {
+		inv.SetAttachment(constant.RemoteApplicationKey, app)
+	}
 
 	// put the ctx into attachment
 	di.appendCtx(ctx, inv)
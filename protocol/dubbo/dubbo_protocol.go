@@ -37,6 +37,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/protocol/base"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	"dubbo.apache.org/dubbo-go/v3/protocol/sizelimit"
 	"dubbo.apache.org/dubbo-go/v3/remoting"
 	"dubbo.apache.org/dubbo-go/v3/remoting/getty"
 )
@@ -158,6 +159,18 @@ func doHandleRequest(rpcInvocation *invocation.RPCInvocation) result.RPCResult {
 	if invoker != nil {
 		// FIXME
 		ctx := rebuildCtx(rpcInvocation)
+		// the transport compresses the response as the service configures
+		url := invoker.GetURL()
+		rpcInvocation.SetAttribute(constant.CompressionKey, url.GetParam(constant.CompressionKey, ""))
+		rpcInvocation.SetAttribute(constant.CompressMinBytesKey, int(url.GetParamInt(constant.CompressMinBytesKey, 0)))
+		// so does it bound the body of the response
+		rpcInvocation.SetAttribute(constant.MaxResponseSizeKey,
+			sizelimit.ResponseLimit(url, constant.SideProvider, rpcInvocation.MethodName()))
+		bodyLen, _ := rpcInvocation.GetAttributeWithDefaultValue(constant.RequestBodyLenKey, 0).(int)
+		if err := sizelimit.RequestLimit(url, constant.SideProvider, rpcInvocation.MethodName()).Check(bodyLen); err != nil {
+			result.Err = err
+			return result
+		}
 
 		invokeResult := invoker.Invoke(ctx, rpcInvocation)
 		if err := invokeResult.Error(); err != nil {
@@ -74,6 +74,7 @@ func (c *ProtocolCodec) ReadHeader(header *DubboHeader) error {
 	flag = buf[2] & FLAG_REQUEST
 	if flag != Zero {
 		header.Type |= PackageRequest
+		header.Compression = buf[3]
 		flag = buf[2] & FLAG_TWOWAY
 		if flag != Zero {
 			header.Type |= PackageRequest_TwoWay
@@ -165,6 +166,16 @@ func (c *ProtocolCodec) Decode(p *DubboPackage) error {
 	if err != nil {
 		return err
 	}
+	if !p.IsHeartBeat() {
+		maxLen := p.MaxBodyLen
+		if maxLen <= 0 {
+			maxLen = DEFAULT_LEN
+		}
+		if body, err = decompressBody(body, maxLen); err != nil {
+			return err
+		}
+	}
+	p.DecodedBodyLen = len(body)
 	if p.IsResponseWithException() {
 		logger.Infof("response with exception: %+v", p.Header)
 		decoder := hessian.NewDecoder(body)
@@ -228,10 +239,17 @@ func packRequest(p DubboPackage, serializer Serializer) ([]byte, error) {
 		byteArray = append(byteArray, byte('N'))
 		pkgLen = 1
 	} else {
+		// accepted compression of the response
+		byteArray[3] = header.Compression
 		body, err := serializer.Marshal(p)
 		if err != nil {
 			return nil, err
 		}
+		if !p.UncompressedRequest {
+			if body, err = compressBody(header.Compression, p.CompressMinBytes, body); err != nil {
+				return nil, err
+			}
+		}
 		pkgLen = len(body)
 		if pkgLen > int(DEFAULT_LEN) { // recommand 8M
 			logger.Warnf("Data length %d too large, recommand max payload %d. "+
@@ -269,6 +287,11 @@ func packResponse(p DubboPackage, serializer Serializer) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	if !hb {
+		if body, err = compressBody(header.Compression, p.CompressMinBytes, body); err != nil {
+			return nil, err
+		}
+	}
 
 	pkgLen := len(body)
 	if pkgLen > int(DEFAULT_LEN) { // recommand 8M
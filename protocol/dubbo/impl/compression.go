@@ -0,0 +1,172 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package impl
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+)
+
+import (
+	"github.com/golang/snappy"
+
+	"github.com/klauspost/compress/zstd"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// Compressed bodies are prefixed with COMPRESSION_MARKER and the algorithm ID.
+// The marker can never start a hessian2 request or response body, so
+// uncompressed packages from other dubbo implementations are left untouched.
+// The other implementations can't decode the marker though, so consumers only
+// compress the requests to dubbo-go providers.
+// Consumers advertise the algorithm they accept for responses in the fourth
+// header byte of a request, which is otherwise unused.
+const (
+	COMPRESSION_MARKER = byte(0xff)
+
+	CompressionNone   = byte(0x00)
+	CompressionGzip   = byte(0x01)
+	CompressionZstd   = byte(0x02)
+	CompressionSnappy = byte(0x03)
+)
+
+var (
+	compressionIDs = map[string]byte{
+		constant.CompressionGzip:   CompressionGzip,
+		constant.CompressionZstd:   CompressionZstd,
+		constant.CompressionSnappy: CompressionSnappy,
+	}
+
+	// zstd encoders are safe for concurrent use of EncodeAll.
+	zstdEncoder, _ = zstd.NewWriter(nil)
+)
+
+// GetCompressionID returns the algorithm ID of the named compression. The
+// empty name and identity map to CompressionNone.
+func GetCompressionID(name string) (byte, error) {
+	if name == "" || name == constant.CompressionIdentity {
+		return CompressionNone, nil
+	}
+	id, ok := compressionIDs[name]
+	if !ok {
+		return CompressionNone, perrors.Errorf("unsupported compression: %s", name)
+	}
+	return id, nil
+}
+
+// GetCompressionName returns the name of the compression algorithm ID, or
+// the empty string if the ID is unknown or CompressionNone.
+func GetCompressionName(id byte) string {
+	for name, v := range compressionIDs {
+		if v == id {
+			return name
+		}
+	}
+	return ""
+}
+
+// compressBody compresses body with the algorithm id, unless it is shorter than
+// minBytes or the algorithm is CompressionNone.
+func compressBody(id byte, minBytes int, body []byte) ([]byte, error) {
+	if id == CompressionNone || len(body) < minBytes {
+		return body, nil
+	}
+	prefix := []byte{COMPRESSION_MARKER, id}
+	switch id {
+	case CompressionGzip:
+		dst := bytes.NewBuffer(prefix)
+		writer := gzip.NewWriter(dst)
+		if _, err := writer.Write(body); err != nil {
+			return nil, perrors.WithStack(err)
+		}
+		if err := writer.Close(); err != nil {
+			return nil, perrors.WithStack(err)
+		}
+		return dst.Bytes(), nil
+	case CompressionZstd:
+		return zstdEncoder.EncodeAll(body, prefix), nil
+	case CompressionSnappy:
+		dst := make([]byte, len(prefix)+snappy.MaxEncodedLen(len(body)))
+		copy(dst, prefix)
+		n := len(snappy.Encode(dst[len(prefix):], body))
+		return dst[:len(prefix)+n], nil
+	default:
+		return nil, perrors.Errorf("unsupported compression ID: %d", id)
+	}
+}
+
+// decompressBody restores a body compressed by compressBody. Bodies without
+// COMPRESSION_MARKER are returned as is. Bodies decompressing to more than
+// maxLen bytes are rejected before they are fully decompressed.
+func decompressBody(body []byte, maxLen int) ([]byte, error) {
+	if len(body) < 2 || body[0] != COMPRESSION_MARKER {
+		return body, nil
+	}
+	id, src := body[1], body[2:]
+	switch id {
+	case CompressionGzip:
+		reader, err := gzip.NewReader(bytes.NewReader(src))
+		if err != nil {
+			return nil, perrors.WithStack(err)
+		}
+		defer reader.Close()
+		return readLimited(reader, maxLen)
+	case CompressionZstd:
+		decoder, err := zstd.NewReader(bytes.NewReader(src),
+			zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(uint64(maxLen)+1))
+		if err != nil {
+			return nil, perrors.WithStack(err)
+		}
+		defer decoder.Close()
+		dst, err := readLimited(decoder, maxLen)
+		if errors.Is(err, zstd.ErrDecoderSizeExceeded) {
+			return nil, perrors.Errorf("decompressed body exceeds %d bytes", maxLen)
+		}
+		return dst, err
+	case CompressionSnappy:
+		n, err := snappy.DecodedLen(src)
+		if err != nil {
+			return nil, perrors.WithStack(err)
+		}
+		if n > maxLen {
+			return nil, perrors.Errorf("decompressed body exceeds %d bytes", maxLen)
+		}
+		dst, err := snappy.Decode(nil, src)
+		return dst, perrors.WithStack(err)
+	default:
+		return nil, perrors.Errorf("unsupported compression ID: %d", id)
+	}
+}
+
+// readLimited reads reader to the end, failing once more than maxLen bytes are read.
+func readLimited(reader io.Reader, maxLen int) ([]byte, error) {
+	dst, err := io.ReadAll(io.LimitReader(reader, int64(maxLen)+1))
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	if len(dst) > maxLen {
+		return nil, perrors.Errorf("decompressed body exceeds %d bytes", maxLen)
+	}
+	return dst, nil
+}
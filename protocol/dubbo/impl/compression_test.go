@@ -0,0 +1,141 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package impl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+func TestCompressBody(t *testing.T) {
+	body := bytes.Repeat([]byte("dubbo compression body "), 128)
+	for _, name := range []string{constant.CompressionGzip, constant.CompressionZstd, constant.CompressionSnappy} {
+		t.Run(name, func(t *testing.T) {
+			id, err := GetCompressionID(name)
+			assert.NoError(t, err)
+			assert.Equal(t, name, GetCompressionName(id))
+
+			compressed, err := compressBody(id, 0, body)
+			assert.NoError(t, err)
+			assert.Equal(t, []byte{COMPRESSION_MARKER, id}, compressed[:2])
+			assert.Less(t, len(compressed), len(body))
+
+			decompressed, err := decompressBody(compressed, len(body))
+			assert.NoError(t, err)
+			assert.Equal(t, body, decompressed)
+
+			// bodies decompressing beyond the limit are rejected
+			_, err = decompressBody(compressed, len(body)-1)
+			assert.ErrorContains(t, err, "decompressed body exceeds")
+
+			// bodies below the threshold stay uncompressed
+			uncompressed, err := compressBody(id, len(body)+1, body)
+			assert.NoError(t, err)
+			assert.Equal(t, body, uncompressed)
+		})
+	}
+
+	id, err := GetCompressionID(constant.CompressionIdentity)
+	assert.NoError(t, err)
+	assert.Equal(t, CompressionNone, id)
+	_, err = GetCompressionID("br")
+	assert.Error(t, err)
+
+	decompressed, err := decompressBody(body, len(body))
+	assert.NoError(t, err)
+	assert.Equal(t, body, decompressed)
+	_, err = decompressBody([]byte{COMPRESSION_MARKER, 0x7f, 0x00}, len(body))
+	assert.Error(t, err)
+}
+
+func TestDubboPackage_MarshalAndUnmarshalCompressed(t *testing.T) {
+	arg := strings.Repeat("a", 1024)
+	pkg := NewDubboPackage(nil)
+	pkg.Body = []any{arg}
+	pkg.Header.Type = PackageRequest_TwoWay
+	pkg.Header.SerialID = constant.SHessian2
+	pkg.Header.ID = 10086
+	pkg.Header.Compression = CompressionZstd
+	pkg.CompressMinBytes = 512
+	pkg.Service.Path = "path"
+	pkg.Service.Method = "Method"
+	pkg.Service.Timeout = time.Second
+	pkg.SetSerializer(HessianSerializer{})
+
+	data, err := pkg.Marshal()
+	assert.NoError(t, err)
+	assert.Less(t, data.Len(), len(arg))
+
+	pkgres := NewDubboPackage(data)
+	pkgres.SetSerializer(HessianSerializer{})
+	pkgres.Body = make([]any, 7)
+	err = pkgres.Unmarshal()
+	assert.NoError(t, err)
+	assert.Equal(t, CompressionZstd, pkgres.Header.Compression)
+	assert.Equal(t, []any{arg}, pkgres.GetBody().(map[string]any)["args"])
+	assert.Greater(t, pkgres.DecodedBodyLen, len(arg))
+
+	// the decompressed body is bounded by MaxBodyLen
+	data, err = pkg.Marshal()
+	assert.NoError(t, err)
+	pkgres = NewDubboPackage(data)
+	pkgres.SetSerializer(HessianSerializer{})
+	pkgres.Body = make([]any, 7)
+	pkgres.MaxBodyLen = len(arg)
+	assert.ErrorContains(t, pkgres.Unmarshal(), "decompressed body exceeds")
+
+	// requests to other implementations keep the body and still advertise the accepted algorithm
+	pkg.UncompressedRequest = true
+	data, err = pkg.Marshal()
+	assert.NoError(t, err)
+	assert.Greater(t, data.Len(), len(arg))
+	assert.Equal(t, CompressionZstd, data.Bytes()[3])
+
+	// response
+	pkg = NewDubboPackage(nil)
+	pkg.Header.Type = PackageResponse
+	pkg.Header.ResponseStatus = Response_OK
+	pkg.Header.SerialID = constant.SHessian2
+	pkg.Header.ID = 10086
+	pkg.Header.Compression = CompressionSnappy
+	pkg.Body = &ResponsePayload{RspObj: arg}
+	pkg.SetSerializer(HessianSerializer{})
+	data, err = pkg.Marshal()
+	assert.NoError(t, err)
+	assert.Less(t, data.Len(), len(arg))
+
+	var reply string
+	pending := remoting.NewPendingResponse(pkg.Header.ID)
+	pending.Reply = &reply
+	remoting.AddPendingResponse(pending)
+	pkgres = NewDubboPackage(data)
+	pkgres.SetSerializer(HessianSerializer{})
+	err = pkgres.Unmarshal()
+	assert.NoError(t, err)
+	assert.Equal(t, arg, reply)
+}
@@ -47,6 +47,9 @@ type DubboHeader struct {
 	ID             int64
 	BodyLen        int
 	ResponseStatus byte
+	// Compression is the ID of the algorithm compressing the body. For requests,
+	// it is also the algorithm the consumer accepts for the response.
+	Compression byte
 }
 
 // Service defines service instance
@@ -65,6 +68,15 @@ type DubboPackage struct {
 	Body    any
 	Err     error
 	Codec   *ProtocolCodec
+	// CompressMinBytes is the body size below which the body is sent uncompressed
+	CompressMinBytes int
+	// UncompressedRequest sends a request body uncompressed while still advertising Header.Compression
+	// for the response, for providers unable to decode compressed bodies
+	UncompressedRequest bool
+	// MaxBodyLen bounds the decompressed body, DEFAULT_LEN if it is not positive
+	MaxBodyLen int
+	// DecodedBodyLen is the length of the decoded body after decompression
+	DecodedBodyLen int
 }
 
 func (p DubboPackage) String() string {
@@ -132,7 +132,12 @@ func NewClient(url *common.URL) (*Client, error) {
 		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	}
 
-	conn, err := grpc.Dial(url.Location, dialOpts...)
+	target := url.Location
+	if path := url.GetParam(constant.UnixSocketKey, ""); path != "" {
+		// the unix resolver of gRPC dials the unix domain socket
+		target = constant.UnixProtocol + ":" + path
+	}
+	conn, err := grpc.Dial(target, dialOpts...)
 	if err != nil {
 		logger.Errorf("grpc dial error: %v", err)
 		return nil, err
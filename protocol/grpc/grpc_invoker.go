@@ -30,6 +30,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
 )
 
@@ -100,12 +101,20 @@ func (gi *GrpcInvoker) Invoke(ctx context.Context, invocation base.Invocation) r
 
 	methodName := invocation.MethodName()
 	method := client.invoker.MethodByName(methodName)
+	codec := newSizeLimitCodec(gi.GetURL(), method, methodName)
+	if codec != nil {
+		in = append(in, reflect.ValueOf(grpc.ForceCodec(codec)))
+	}
 	res := method.Call(in)
 
 	result.SetResult(res[0])
 	// check err
 	if !res[1].IsNil() {
-		result.SetError(res[1].Interface().(error))
+		err := res[1].Interface().(error)
+		if codec != nil && codec.err != nil {
+			err = codec.err
+		}
+		result.SetError(err)
 	} else {
 		_ = hessian2.ReflectResponse(res[0], invocation.Reply())
 	}
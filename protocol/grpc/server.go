@@ -63,6 +63,7 @@ type DubboGrpcService interface {
 type Server struct {
 	grpcServer *grpc.Server
 	bufferSize int
+	sizeLimits sizeLimitInterceptor
 }
 
 // NewServer creates a new server
@@ -81,7 +82,12 @@ func (s *Server) Start(url *common.URL) {
 		err  error
 	)
 	addr = url.Location
-	lis, err := net.Listen("tcp", addr)
+	var lis net.Listener
+	if path := url.GetParam(constant.UnixSocketKey, ""); path != "" {
+		lis, err = common.ListenUnixSocket(path, url.GetParam(constant.UnixSocketModeKey, ""))
+	} else {
+		lis, err = net.Listen("tcp", addr)
+	}
 	if err != nil {
 		panic(err)
 	}
@@ -100,8 +106,9 @@ func (s *Server) Start(url *common.URL) {
 	tracer := opentracing.GlobalTracer()
 	var serverOpts []grpc.ServerOption
 	serverOpts = append(serverOpts,
-		grpc.UnaryInterceptor(otgrpc.OpenTracingServerInterceptor(tracer)),
+		grpc.ChainUnaryInterceptor(otgrpc.OpenTracingServerInterceptor(tracer), s.sizeLimits.intercept),
 		grpc.StreamInterceptor(otgrpc.OpenTracingStreamServerInterceptor(tracer)),
+		grpc.StatsHandler(payloadLenHandler{}),
 		grpc.MaxRecvMsgSize(maxServerRecvMsgSize),
 		grpc.MaxSendMsgSize(maxServerSendMsgSize),
 	)
@@ -157,7 +164,7 @@ func (s *Server) Start(url *common.URL) {
 		}
 		// wait all exporter ready , then set proxy impl and grpc registerService
 		waitGrpcExporter(providerServices)
-		registerService(providerServices, server)
+		registerService(providerServices, server, &s.sizeLimits.urls)
 		reflection.Register(server)
 
 		if err = server.Serve(lis); err != nil {
@@ -198,8 +205,8 @@ func waitGrpcExporter(providerServices map[string]*config.ServiceConfig) {
 	}
 }
 
-// registerService SetProxyImpl invoker and grpc service
-func registerService(providerServices map[string]*config.ServiceConfig, server *grpc.Server) {
+// registerService SetProxyImpl invoker and grpc service, and stores the urls of the services in urls
+func registerService(providerServices map[string]*config.ServiceConfig, server *grpc.Server, urls *sync.Map) {
 	for key, providerService := range providerServices {
 		service := config.GetProviderService(key)
 		ds, ok := service.(DubboGrpcService)
@@ -218,6 +225,7 @@ func registerService(providerServices map[string]*config.ServiceConfig, server *
 		}
 
 		ds.SetProxyImpl(invoker)
+		urls.Store(ds.ServiceDesc().ServiceName, invoker.GetURL())
 		server.RegisterService(ds.ServiceDesc(), service)
 	}
 }
@@ -0,0 +1,144 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpc
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+import (
+	"github.com/golang/protobuf/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/stats"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/sizelimit"
+)
+
+var clientStreamType = reflect.TypeOf((*grpc.ClientStream)(nil)).Elem()
+
+// sizeLimitCodec checks the lengths of the messages encoded and decoded by the codec of one call.
+type sizeLimitCodec struct {
+	encoding.Codec
+	reqLimit  *sizelimit.Limit
+	respLimit *sizelimit.Limit
+	// err is the error of the limit exceeded, which grpc reports as an internal error
+	err error
+}
+
+func (c *sizeLimitCodec) Marshal(v any) ([]byte, error) {
+	data, err := c.Codec.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.reqLimit.Check(len(data)); err != nil {
+		c.err = err
+		return nil, err
+	}
+	return data, nil
+}
+
+func (c *sizeLimitCodec) Unmarshal(data []byte, v any) error {
+	if err := c.respLimit.Check(len(data)); err != nil {
+		c.err = err
+		return err
+	}
+	return c.Codec.Unmarshal(data, v)
+}
+
+// newSizeLimitCodec returns the codec of a call of the unary method, nil if the method is unlimited.
+func newSizeLimitCodec(url *common.URL, method reflect.Value, methodName string) *sizeLimitCodec {
+	methodType := method.Type()
+	if !methodType.IsVariadic() || methodType.NumOut() == 0 || methodType.Out(0).Implements(clientStreamType) {
+		return nil
+	}
+	reqLimit := sizelimit.RequestLimit(url, constant.SideConsumer, methodName)
+	respLimit := sizelimit.ResponseLimit(url, constant.SideConsumer, methodName)
+	if reqLimit == nil && respLimit == nil {
+		return nil
+	}
+	return &sizeLimitCodec{
+		Codec:     encoding.GetCodec(clientConf.ContentSubType),
+		reqLimit:  reqLimit,
+		respLimit: respLimit,
+	}
+}
+
+// payloadLenKey is the context key of the wire length of the request of a unary call.
+type payloadLenKey struct{}
+
+// payloadLenHandler records the wire lengths of the requests for sizeLimitInterceptor.
+type payloadLenHandler struct{}
+
+func (payloadLenHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, payloadLenKey{}, new(int))
+}
+
+func (payloadLenHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	if in, ok := s.(*stats.InPayload); ok {
+		if payloadLen, ok := ctx.Value(payloadLenKey{}).(*int); ok {
+			*payloadLen = in.WireLength
+		}
+	}
+}
+
+func (payloadLenHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (payloadLenHandler) HandleConn(context.Context, stats.ConnStats) {}
+
+// sizeLimitInterceptor rejects the requests and the responses of the unary methods over their size limits.
+type sizeLimitInterceptor struct {
+	urls sync.Map // the full name of the grpc service -> the url of its exporter
+}
+
+func (i *sizeLimitInterceptor) intercept(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (any, error) {
+	service, methodName, ok := strings.Cut(strings.TrimPrefix(info.FullMethod, "/"), "/")
+	if !ok {
+		return handler(ctx, req)
+	}
+	url, ok := i.urls.Load(service)
+	if !ok {
+		return handler(ctx, req)
+	}
+	if payloadLen, ok := ctx.Value(payloadLenKey{}).(*int); ok {
+		if err := sizelimit.RequestLimit(url.(*common.URL), constant.SideProvider, methodName).Check(*payloadLen); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	if msg, ok := resp.(proto.Message); ok {
+		if err = sizelimit.ResponseLimit(url.(*common.URL), constant.SideProvider, methodName).Check(proto.Size(msg)); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
@@ -275,6 +275,49 @@ func (r *RPCInvocation) MergeAttachmentFromContext(ctx context.Context) {
 	}
 }
 
+// CopyWithArguments returns a copy of the invocation with the arguments, the reply and the attachments replaced.
+// The parameter values are rebuilt from the arguments, the attributes are copied into the new invocation.
+func CopyWithArguments(inv base.Invocation, args []any, reply any, attachments map[string]any) *RPCInvocation {
+	// the raw values of triple are the arguments followed by the reply
+	var rawValues []any
+	if raw := inv.ParameterRawValues(); len(raw) > 0 {
+		rawValues = append(rawValues, args...)
+		if len(raw) > len(inv.Arguments()) {
+			rawValues = append(rawValues, reply)
+		}
+	}
+	var values []reflect.Value
+	if origin := inv.ParameterValues(); len(origin) == len(args) {
+		values = make([]reflect.Value, len(args))
+		for i, arg := range args {
+			if arg == nil {
+				values[i] = reflect.Zero(origin[i].Type())
+			} else {
+				values[i] = reflect.ValueOf(arg)
+			}
+		}
+	}
+
+	copied := NewRPCInvocationWithOptions(
+		WithMethodName(inv.MethodName()),
+		WithParameterTypes(inv.ParameterTypes()),
+		WithParameterTypeNames(inv.ParameterTypeNames()),
+		WithParameterValues(values),
+		WithParameterRawValues(rawValues),
+		WithArguments(args),
+		WithReply(reply),
+		WithAttachments(attachments),
+		WithInvoker(inv.Invoker()),
+	)
+	if rpcInv, ok := inv.(*RPCInvocation); ok {
+		copied.SetCallBack(rpcInv.CallBack())
+	}
+	for k, v := range inv.Attributes() {
+		copied.SetAttribute(k, v)
+	}
+	return copied
+}
+
 // /////////////////////////
 // option
 // /////////////////////////
@@ -73,3 +73,23 @@ func TestRPCInvocation_ServiceKey(t *testing.T) {
 	}))
 	assert.Equal(t, providerUrl.ServiceKey(), invocation.ServiceKey())
 }
+
+func TestCopyWithArguments(t *testing.T) {
+	var reply, newReply string
+	inv := NewRPCInvocationWithOptions(WithMethodName("Echo"), WithArguments([]any{"plain"}),
+		WithParameterRawValues([]any{"plain", &reply}), WithReply(&reply),
+		WithAttachments(map[string]any{"k": "v"}))
+	inv.SetAttribute("attr", "value")
+
+	copied := CopyWithArguments(inv, []any{"cipher"}, &newReply, map[string]any{"k": "w"})
+	assert.Equal(t, "Echo", copied.MethodName())
+	assert.Equal(t, []any{"cipher"}, copied.Arguments())
+	assert.Equal(t, []any{"cipher", &newReply}, copied.ParameterRawValues())
+	assert.Equal(t, &newReply, copied.Reply())
+	assert.Equal(t, "w", copied.GetAttachmentInterface("k"))
+	assert.Equal(t, "value", copied.GetAttributeWithDefaultValue("attr", nil))
+
+	// the attributes are copied, not shared
+	copied.SetAttribute("attr", "changed")
+	assert.Equal(t, "value", inv.GetAttributeWithDefaultValue("attr", nil))
+}
@@ -43,6 +43,7 @@ import (
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/sizelimit"
 )
 
 // Request is HTTP protocol request
@@ -142,8 +143,12 @@ func (c *HTTPClient) Call(ctx context.Context, service *common.URL, req *Request
 	if err != nil {
 		return perrors.WithStack(err)
 	}
+	if err = sizelimit.RequestLimit(service, constant.SideConsumer, req.method).Check(len(reqBody)); err != nil {
+		return err
+	}
 
-	rspBody, err := c.Do(service.Location, service.Path, httpHeader, reqBody)
+	rspLimit := sizelimit.ResponseLimit(service, constant.SideConsumer, req.method)
+	rspBody, err := c.do(service.Location, service.Path, httpHeader, reqBody, rspLimit)
 	if err != nil {
 		return perrors.WithStack(err)
 	}
@@ -154,6 +159,11 @@ func (c *HTTPClient) Call(ctx context.Context, service *common.URL, req *Request
 // Do is the high level of complexity and the likelihood that the fasthttp client has not been extensively used
 // in production means that you would need to expect a very large benefit to justify the adoption of fasthttp today.
 func (c *HTTPClient) Do(addr, path string, httpHeader http.Header, body []byte) ([]byte, error) {
+	return c.do(addr, path, httpHeader, body, nil)
+}
+
+// do reads the response body up to rspLimit, which is unlimited if nil.
+func (c *HTTPClient) do(addr, path string, httpHeader http.Header, body []byte, rspLimit *sizelimit.Limit) ([]byte, error) {
 	u := url.URL{Host: strings.TrimSuffix(addr, ":"), Path: path}
 	httpReq, err := http.NewRequest("POST", u.String(), bytes.NewBuffer(body))
 	if err != nil {
@@ -194,10 +204,17 @@ func (c *HTTPClient) Do(addr, path string, httpHeader http.Header, body []byte)
 	}
 	defer httpRsp.Body.Close()
 
-	b, err := io.ReadAll(httpRsp.Body)
+	var rspBody io.Reader = httpRsp.Body
+	if max := rspLimit.Max(); max > 0 {
+		rspBody = io.LimitReader(rspBody, int64(max)+1)
+	}
+	b, err := io.ReadAll(rspBody)
 	if err != nil {
 		return nil, perrors.WithStack(err)
 	}
+	if err = rspLimit.Check(len(b)); err != nil {
+		return nil, err
+	}
 
 	if httpRsp.StatusCode != http.StatusOK {
 		return nil, perrors.New(fmt.Sprintf("http status:%q, error string:%q", httpRsp.Status, string(b)))
@@ -42,6 +42,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/protocol/sizelimit"
 )
 
 // A value sent as a placeholder for the server's response value when the server
@@ -340,6 +341,30 @@ func serveRequest(ctx context.Context, header map[string]string, body []byte, co
 		return perrors.New("service/method request ill-formed: " + path + "/" + methodName)
 	}
 
+	exporter, _ := jsonrpcProtocol.ExporterMap().Load(path)
+	invoker := exporter.(*JsonrpcExporter).GetInvoker()
+	if invoker == nil {
+		return nil
+	}
+	url := invoker.GetURL()
+
+	sendInvokeErr := func(err error) error {
+		rspStream, codecErr := codec.Write(err.Error(), invalidRequest)
+		if codecErr != nil {
+			return perrors.WithStack(codecErr)
+		}
+		if errRsp := sendErrorResp(header, rspStream); errRsp != nil {
+			logger.Warnf("Exporter: sendErrorResp(header:%#v, error:%v) = error:%s",
+				header, err, errRsp)
+		}
+		return nil
+	}
+
+	// the request over the size limit is rejected before its arguments are decoded
+	if err = sizelimit.RequestLimit(url, constant.SideProvider, methodName).Check(len(body)); err != nil {
+		return sendInvokeErr(err)
+	}
+
 	// read body
 	var args []any
 	if err = codec.ReadBody(&args); err != nil {
@@ -348,33 +373,22 @@ func serveRequest(ctx context.Context, header map[string]string, body []byte, co
 	logger.Debugf("args: %v", args)
 
 	// exporter invoke
-	exporter, _ := jsonrpcProtocol.ExporterMap().Load(path)
-	invoker := exporter.(*JsonrpcExporter).GetInvoker()
-	if invoker != nil {
-		result := invoker.Invoke(ctx, invocation.NewRPCInvocation(methodName, args, map[string]any{
-			constant.PathKey:    path,
-			constant.VersionKey: codec.req.Version,
-		}))
-		if err := result.Error(); err != nil {
-			rspStream, codecErr := codec.Write(err.Error(), invalidRequest)
-			if codecErr != nil {
-				return perrors.WithStack(codecErr)
-			}
-			if errRsp := sendErrorResp(header, rspStream); errRsp != nil {
-				logger.Warnf("Exporter: sendErrorResp(header:%#v, error:%v) = error:%s",
-					header, err, errRsp)
-			}
-		} else {
-			res := result.Result()
-			rspStream, err := codec.Write("", res)
-			if err != nil {
-				return perrors.WithStack(err)
-			}
-			if errRsp := sendResp(header, rspStream); errRsp != nil {
-				logger.Warnf("Exporter: sendResp(header:%#v, error:%v) = error:%s",
-					header, err, errRsp)
-			}
-		}
+	result := invoker.Invoke(ctx, invocation.NewRPCInvocation(methodName, args, map[string]any{
+		constant.PathKey:    path,
+		constant.VersionKey: codec.req.Version,
+	}))
+	if err = result.Error(); err != nil {
+		return sendInvokeErr(err)
+	}
+	rspStream, err := codec.Write("", result.Result())
+	if err != nil {
+		return perrors.WithStack(err)
+	}
+	if err = sizelimit.ResponseLimit(url, constant.SideProvider, methodName).Check(len(rspStream)); err != nil {
+		return sendInvokeErr(err)
+	}
+	if errRsp := sendResp(header, rspStream); errRsp != nil {
+		logger.Warnf("Exporter: sendResp(header:%#v) = error:%s", header, errRsp)
 	}
 
 	return nil
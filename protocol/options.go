@@ -18,6 +18,7 @@
 package protocol
 
 import (
+	"os"
 	"strconv"
 )
 
@@ -267,6 +268,48 @@ func WithPort(port int) ServerOption {
 	return &portOption{strconv.Itoa(port)}
 }
 
+type unixSocketOption struct {
+	Path string
+}
+
+func (o *unixSocketOption) applyToServer(config *ServerOptions) {
+	config.Protocol.UnixSocket = o.Path
+}
+
+// WithUnixSocket serves the protocol on the unix domain socket at path instead of the ip and port,
+// clients reach it by "unix:///path". Only triple and gRPC support it.
+func WithUnixSocket(path string) ServerOption {
+	return &unixSocketOption{path}
+}
+
+type unixSocketModeOption struct {
+	Mode os.FileMode
+}
+
+func (o *unixSocketModeOption) applyToServer(config *ServerOptions) {
+	config.Protocol.UnixSocketMode = strconv.FormatUint(uint64(o.Mode.Perm()), 8)
+}
+
+// WithUnixSocketMode sets the file permission of the unix domain socket, e.g. 0660.
+func WithUnixSocketMode(mode os.FileMode) ServerOption {
+	return &unixSocketModeOption{mode}
+}
+
+type inMemoryOption struct {
+	Name string
+}
+
+func (o *inMemoryOption) applyToServer(config *ServerOptions) {
+	config.Protocol.InMemory = o.Name
+}
+
+// WithInMemory serves the protocol on the in-memory listener of name instead of the ip and port,
+// clients in the same process reach it by "memory://name". It's meant for tests without real ports,
+// only triple supports it.
+func WithInMemory(name string) ServerOption {
+	return &inMemoryOption{name}
+}
+
 type paramsOption struct {
 	Params any
 }
@@ -19,6 +19,7 @@ package client_impl
 
 import (
 	"context"
+	"io"
 	"net"
 	"net/http"
 	"path"
@@ -48,8 +49,8 @@ type RestyClient struct {
 // NewRestyClient a constructor of RestyClient
 func NewRestyClient(restOption *client.RestOptions) client.RestClient {
 	client := resty.New()
-	client.SetTransport(
-		&http.Transport{
+	client.SetTransport(&sizeLimitTransport{
+		RoundTripper: &http.Transport{
 			DialContext: func(_ context.Context, network, addr string) (net.Conn, error) {
 				c, err := net.DialTimeout(network, addr, restOption.ConnectTimeout)
 				if err != nil {
@@ -58,7 +59,8 @@ func NewRestyClient(restOption *client.RestOptions) client.RestClient {
 				return c, nil
 			},
 			IdleConnTimeout: restOption.KeppAliveTimeout,
-		})
+		},
+	})
 	client.SetTimeout(restOption.RequestTimeout)
 	return &RestyClient{
 		client: client,
@@ -69,6 +71,9 @@ func NewRestyClient(restOption *client.RestOptions) client.RestClient {
 func (rc *RestyClient) Do(restRequest *client.RestClientRequest, res any) error {
 	req := rc.client.R()
 	req.Header = restRequest.Header
+	if restRequest.MaxRequestSize > 0 || restRequest.MaxResponseSize > 0 {
+		req.SetContext(context.WithValue(context.Background(), sizeLimitKey{}, restRequest))
+	}
 	resp, err := req.
 		SetPathParams(restRequest.PathParams).
 		SetQueryParams(restRequest.QueryParams).
@@ -83,3 +88,59 @@ func (rc *RestyClient) Do(restRequest *client.RestClientRequest, res any) error
 	}
 	return nil
 }
+
+type sizeLimitKey struct{}
+
+// sizeLimitTransport limits the bodies by the sizes of the client.RestClientRequest in the context.
+type sizeLimitTransport struct {
+	http.RoundTripper
+}
+
+func (t *sizeLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	restRequest, ok := req.Context().Value(sizeLimitKey{}).(*client.RestClientRequest)
+	if !ok {
+		return t.RoundTripper.RoundTrip(req)
+	}
+	if max := int64(restRequest.MaxRequestSize); max > 0 {
+		if req.ContentLength > max {
+			return nil, client.ErrRequestTooLarge
+		}
+		if req.Body != nil && req.ContentLength < 0 {
+			req.Body = &limitedBody{ReadCloser: req.Body, remaining: max, err: client.ErrRequestTooLarge}
+		}
+	}
+	resp, err := t.RoundTripper.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if max := int64(restRequest.MaxResponseSize); max > 0 {
+		if resp.ContentLength > max {
+			resp.Body.Close()
+			return nil, client.ErrResponseTooLarge
+		}
+		resp.Body = &limitedBody{ReadCloser: resp.Body, remaining: max, err: client.ErrResponseTooLarge}
+	}
+	return resp, nil
+}
+
+// limitedBody fails with err once more than the remaining bytes are read.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	err       error
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, b.err
+	}
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return 0, b.err
+	}
+	return n, err
+}
@@ -18,10 +18,18 @@
 package client
 
 import (
+	"errors"
 	"net/http"
 	"time"
 )
 
+var (
+	// ErrRequestTooLarge is returned by RestClient if the request body exceeds MaxRequestSize
+	ErrRequestTooLarge = errors.New("the request body exceeds the size limit")
+	// ErrResponseTooLarge is returned by RestClient if the response body exceeds MaxResponseSize
+	ErrResponseTooLarge = errors.New("the response body exceeds the size limit")
+)
+
 type RestOptions struct {
 	RequestTimeout   time.Duration
 	ConnectTimeout   time.Duration
@@ -36,6 +44,9 @@ type RestClientRequest struct {
 	PathParams  map[string]string
 	QueryParams map[string]string
 	Body        any
+	// MaxRequestSize and MaxResponseSize are the max lengths of the bodies, 0 means unlimited
+	MaxRequestSize  int
+	MaxResponseSize int
 }
 
 // RestClient user can implement this client interface to send request
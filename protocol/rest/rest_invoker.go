@@ -19,6 +19,7 @@ package rest
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 )
@@ -29,11 +30,13 @@ import (
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/protocol/base"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 	"dubbo.apache.org/dubbo-go/v3/protocol/rest/client"
 	"dubbo.apache.org/dubbo-go/v3/protocol/rest/config"
 	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	"dubbo.apache.org/dubbo-go/v3/protocol/sizelimit"
 )
 
 // RestInvoker invokes REST services using a RestClient and method mappings.
@@ -83,18 +86,27 @@ func (ri *RestInvoker) Invoke(ctx context.Context, inv base.Invocation) result.R
 	if len(rpcInv.Arguments()) > methodConfig.Body && methodConfig.Body >= 0 {
 		body = rpcInv.Arguments()[methodConfig.Body]
 	}
+	reqLimit := sizelimit.RequestLimit(ri.GetURL(), constant.SideConsumer, rpcInv.MethodName())
+	respLimit := sizelimit.ResponseLimit(ri.GetURL(), constant.SideConsumer, rpcInv.MethodName())
 	req := &client.RestClientRequest{
-		Location:    ri.GetURL().Location,
-		Method:      methodConfig.MethodType,
-		Path:        methodConfig.Path,
-		PathParams:  pathParams,
-		QueryParams: queryParams,
-		Body:        body,
-		Header:      header,
+		Location:        ri.GetURL().Location,
+		Method:          methodConfig.MethodType,
+		Path:            methodConfig.Path,
+		PathParams:      pathParams,
+		QueryParams:     queryParams,
+		Body:            body,
+		Header:          header,
+		MaxRequestSize:  reqLimit.Max(),
+		MaxResponseSize: respLimit.Max(),
 	}
 	result.Err = ri.client.Do(req, rpcInv.Reply())
-	if result.Err == nil {
+	switch {
+	case result.Err == nil:
 		result.Rest = rpcInv.Reply()
+	case reqLimit != nil && errors.Is(result.Err, client.ErrRequestTooLarge):
+		result.Err = reqLimit.Exceeded()
+	case respLimit != nil && errors.Is(result.Err, client.ErrResponseTooLarge):
+		result.Err = respLimit.Exceeded()
 	}
 	return &result
 }
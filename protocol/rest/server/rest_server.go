@@ -33,13 +33,17 @@ import (
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/protocol/base"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 	rest_config "dubbo.apache.org/dubbo-go/v3/protocol/rest/config"
+	"dubbo.apache.org/dubbo-go/v3/protocol/sizelimit"
 )
 
 const parseParameterErrorStr = "an error occurred while parsing parameters on the server"
 
+var errResponseTooLarge = errors.New("the response exceeds the size limit")
+
 // RestServer user can implement this server interface
 type RestServer interface {
 	// Start rest server
@@ -82,13 +86,30 @@ type RestServerResponse interface {
 	WriteEntity(value any) error
 }
 
+// ResponseWriterSetter is implemented by the RestServerResponse whose underlying writer can be replaced,
+// which buffers the responses to check their size limits before they are written.
+type ResponseWriterSetter interface {
+	// SetResponseWriter replaces the underlying writer with w, and returns the replaced one
+	SetResponseWriter(w http.ResponseWriter) http.ResponseWriter
+}
+
 // GetRouteFunc is a route function will be invoked by http server
 func GetRouteFunc(invoker base.Invoker, methodConfig *rest_config.RestMethodConfig) func(req RestServerRequest, resp RestServerResponse) {
+	reqLimit := sizelimit.RequestLimit(invoker.GetURL(), constant.SideProvider, methodConfig.MethodName)
+	respLimit := sizelimit.ResponseLimit(invoker.GetURL(), constant.SideProvider, methodConfig.MethodName)
 	return func(req RestServerRequest, resp RestServerResponse) {
 		var (
 			err  error
 			args []any
 		)
+		if max := int64(reqLimit.Max()); max > 0 {
+			rawReq := req.RawRequest()
+			if rawReq.ContentLength > max {
+				writeSizeLimitError(resp, http.StatusRequestEntityTooLarge, reqLimit)
+				return
+			}
+			rawReq.Body = http.MaxBytesReader(resp, rawReq.Body, max)
+		}
 		svc := common.ServiceMap.GetServiceByServiceKey(invoker.GetURL().Protocol, invoker.GetURL().ServiceKey())
 		// get method
 		method := svc.Method()[methodConfig.MethodName]
@@ -103,6 +124,11 @@ func GetRouteFunc(invoker base.Invoker, methodConfig *rest_config.RestMethodConf
 		} else {
 			args, err = getArgsFromRequest(req, argsTypes, methodConfig)
 		}
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeSizeLimitError(resp, http.StatusRequestEntityTooLarge, reqLimit)
+			return
+		}
 		if err != nil {
 			logger.Errorf("[Go Restful] parsing http parameters error:%v", err)
 			err = resp.WriteError(http.StatusInternalServerError, errors.New(parseParameterErrorStr))
@@ -118,11 +144,69 @@ func GetRouteFunc(invoker base.Invoker, methodConfig *rest_config.RestMethodConf
 			}
 			return
 		}
+		setter, ok := resp.(ResponseWriterSetter)
+		if !ok || respLimit == nil {
+			err = resp.WriteEntity(result.Result())
+			if err != nil {
+				logger.Errorf("[Go Restful] WriteEntity error:%v", err)
+			}
+			return
+		}
+		// the response is buffered up to its size limit before it is written
+		limitedWriter := &limitedResponseWriter{max: respLimit.Max()}
+		limitedWriter.ResponseWriter = setter.SetResponseWriter(limitedWriter)
 		err = resp.WriteEntity(result.Result())
+		setter.SetResponseWriter(limitedWriter.ResponseWriter)
+		if limitedWriter.exceeded {
+			writeSizeLimitError(resp, http.StatusInternalServerError, respLimit)
+			return
+		}
 		if err != nil {
 			logger.Errorf("[Go Restful] WriteEntity error:%v", err)
 		}
+		if err = limitedWriter.flush(); err != nil {
+			logger.Errorf("[Go Restful] WriteEntity error:%v", err)
+		}
+	}
+}
+
+// writeSizeLimitError replies the error of the payload exceeding limit.
+func writeSizeLimitError(resp RestServerResponse, httpStatus int, limit *sizelimit.Limit) {
+	resp.Header().Del("Content-Type")
+	if err := resp.WriteError(httpStatus, limit.Exceeded()); err != nil {
+		logger.Errorf("[Go Restful] WriteError error:%v", err)
+	}
+}
+
+// limitedResponseWriter buffers the response up to max bytes.
+type limitedResponseWriter struct {
+	http.ResponseWriter
+	max      int
+	status   int
+	body     []byte
+	exceeded bool
+}
+
+func (w *limitedResponseWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *limitedResponseWriter) Write(b []byte) (int, error) {
+	if len(w.body)+len(b) > w.max {
+		w.exceeded = true
+		return 0, errResponseTooLarge
+	}
+	w.body = append(w.body, b...)
+	return len(b), nil
+}
+
+// flush writes the buffered response to the replaced writer.
+func (w *limitedResponseWriter) flush() error {
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
 	}
+	_, err := w.ResponseWriter.Write(w.body)
+	return err
 }
 
 // getArgsInterfaceFromRequest when service function like GetUser(req []any, rsp *User) error
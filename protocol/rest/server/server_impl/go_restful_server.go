@@ -90,7 +90,7 @@ func (grs *GoRestfulServer) Start(url *common.URL) {
 // The routeFunc should be invoked when the server receive a request
 func (grs *GoRestfulServer) Deploy(restMethodConfig *config.RestMethodConfig, routeFunc func(request server.RestServerRequest, response server.RestServerResponse)) {
 	rf := func(req *restful.Request, resp *restful.Response) {
-		routeFunc(NewGoRestfulRequestAdapter(req), resp)
+		routeFunc(NewGoRestfulRequestAdapter(req), &GoRestfulResponseAdapter{Response: resp})
 	}
 	grs.ws.Route(grs.ws.Method(restMethodConfig.MethodType).
 		Produces(strings.Split(restMethodConfig.Produces, ",")...).
@@ -172,3 +172,15 @@ func (grra *GoRestfulRequestAdapter) HeaderParameter(name string) string {
 func (grra *GoRestfulRequestAdapter) ReadEntity(entityPointer any) error {
 	return grra.request.ReadEntity(entityPointer)
 }
+
+// GoRestfulResponseAdapter a adapter struct about RestServerResponse
+type GoRestfulResponseAdapter struct {
+	*restful.Response
+}
+
+// SetResponseWriter a adapter func of server.ResponseWriterSetter's SetResponseWriter
+func (grra *GoRestfulResponseAdapter) SetResponseWriter(w http.ResponseWriter) http.ResponseWriter {
+	replaced := grra.Response.ResponseWriter
+	grra.Response.ResponseWriter = w
+	return replaced
+}
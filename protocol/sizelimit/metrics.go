@@ -0,0 +1,67 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sizelimit
+
+import (
+	"sync/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+)
+
+var (
+	consumerRejectedKey = metrics.NewMetricKey("dubbo_consumer_size_limit_rejected_total",
+		"The Requests and Responses Rejected by Size Limit in Consumer")
+	providerRejectedKey = metrics.NewMetricKey("dubbo_provider_size_limit_rejected_total",
+		"The Requests and Responses Rejected by Size Limit in Provider")
+
+	// the counters are set once the metrics module is initialized
+	consumerRejected atomic.Pointer[metrics.CounterVec]
+	providerRejected atomic.Pointer[metrics.CounterVec]
+)
+
+func init() {
+	metrics.AddCollector("size_limit", func(registry metrics.MetricRegistry, _ *common.URL) {
+		consumer := metrics.NewCounterVec(registry, consumerRejectedKey)
+		consumerRejected.Store(&consumer)
+		provider := metrics.NewCounterVec(registry, providerRejectedKey)
+		providerRejected.Store(&provider)
+	})
+}
+
+// reportRejected counts a request or response rejected by the size limit
+func reportRejected(url *common.URL, side, method, direction string) {
+	rejected := consumerRejected.Load()
+	if side == constant.SideProvider {
+		rejected = providerRejected.Load()
+	}
+	if rejected == nil {
+		return
+	}
+	(*rejected).Inc(map[string]string{
+		constant.TagApplicationName: url.GetParam(constant.ApplicationKey, ""),
+		constant.TagInterface:       url.Service(),
+		constant.TagMethod:          method,
+		constant.TagGroup:           url.Group(),
+		constant.TagVersion:         url.GetParam(constant.VersionKey, ""),
+		constant.TagDirection:       direction,
+	})
+}
@@ -0,0 +1,161 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package sizelimit resolves the per-method limits of the request and response sizes, which are
+// enforced by the codec of every protocol.
+/*
+ The limits are configured per service or reference, and overridden per method, for example:
+
+ services:
+   "UserProvider":
+     interface: "com.ikurento.user.UserProvider"
+     params:
+       max-request-size: "1mib"     # the max encoded size of the requests
+       max-response-size: "4mib"    # the max encoded size of the responses
+     methods:
+       - name: "UploadAvatar"
+         max-request-size: "8mib"
+
+ The sizes are the lengths of the payloads on the wire, measured by the codecs as they read and write
+ them, so the payloads are never serialized twice:
+   - dubbo: the body length of the packet. The provider checks the request once the packet is decoded,
+     as the method is only known from the body, and before the invocation. The consumer checks the
+     response as it is decoded. Both sides check their outgoing bodies as they are encoded, and a
+     provider replies an error instead of a response too large. The packets are bounded by max-msg-len
+     of getty before.
+   - triple: the read and send max bytes of every method, so a larger message is rejected while it is
+     read, before it is decoded. They never exceed max-server-recv-msg-size and the others of triple.
+   - grpc: the codec of every call of the consumer checks the encoded and the received messages. The
+     provider checks the wire length of the request before the invocation, and the size of the
+     response message after it.
+   - jsonrpc: the length of the HTTP body, the provider checks the request before decoding its
+     arguments, and the encoded response before writing it.
+   - rest: the Content-Length of the request, or the bytes read from its body. The response is
+     buffered up to its limit before it is written. A custom RestClient has to honour MaxRequestSize
+     and MaxResponseSize of the client.RestClientRequest.
+
+ An invocation over a limit fails with a resource-exhausted error, and is counted by the metric
+ dubbo_{side}_size_limit_rejected_total. The streaming methods are not limited, except that the
+ limits of the triple consumers bound every message of their streams.
+*/
+package sizelimit
+
+import (
+	"fmt"
+	"sync"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+
+	"github.com/dustin/go-humanize"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	triple_protocol "dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+)
+
+const (
+	DirectionRequest  = "request"
+	DirectionResponse = "response"
+)
+
+// sizes caches the parsed size limits, an invalid limit is cached as 0
+var sizes sync.Map // size string -> int
+
+// Limit is the size limit of the requests or the responses of a method on one side. A nil Limit
+// is unlimited.
+type Limit struct {
+	url       *common.URL
+	side      string
+	method    string
+	direction string
+	max       int
+}
+
+// RequestLimit returns the limit of the requests of method configured by url, nil if unlimited.
+func RequestLimit(url *common.URL, side, method string) *Limit {
+	return newLimit(url, side, method, DirectionRequest, constant.MaxRequestSizeKey)
+}
+
+// ResponseLimit returns the limit of the responses of method configured by url, nil if unlimited.
+func ResponseLimit(url *common.URL, side, method string) *Limit {
+	return newLimit(url, side, method, DirectionResponse, constant.MaxResponseSizeKey)
+}
+
+func newLimit(url *common.URL, side, method, direction, key string) *Limit {
+	if url == nil {
+		return nil
+	}
+	max := parseSize(url, url.GetMethodParam(method, key, url.GetParam(key, "")))
+	if max == 0 {
+		return nil
+	}
+	return &Limit{url: url, side: side, method: method, direction: direction, max: max}
+}
+
+// Max returns the max size in bytes, 0 means unlimited.
+func (l *Limit) Max() int {
+	if l == nil {
+		return 0
+	}
+	return l.max
+}
+
+// Check returns the error of Exceeded if size exceeds the limit.
+func (l *Limit) Check(size int) error {
+	if l == nil || size <= l.max {
+		return nil
+	}
+	return l.Exceeded()
+}
+
+// Exceeded counts a payload rejected by the limit, and returns its resource-exhausted error.
+func (l *Limit) Exceeded() error {
+	l.Report()
+	target := l.url.Service()
+	if l.method != "" {
+		target += "#" + l.method
+	}
+	return triple_protocol.NewError(triple_protocol.CodeResourceExhausted,
+		fmt.Errorf("the %s of %s exceeds the size limit %d", l.direction, target, l.max))
+}
+
+// Report counts a payload rejected by the limit, for the codecs raising the errors themselves.
+func (l *Limit) Report() {
+	if l != nil {
+		reportRejected(l.url, l.side, l.method, l.direction)
+	}
+}
+
+func parseSize(url *common.URL, size string) int {
+	if size == "" {
+		return 0
+	}
+	if limit, ok := sizes.Load(size); ok {
+		return limit.(int)
+	}
+	limit, err := humanize.ParseBytes(size)
+	if err != nil {
+		logger.Warnf("[Size Limit] the size limit %q of %s is invalid, it is ignored: %v", size, url.Service(), err)
+		limit = 0
+	}
+	sizes.Store(size, int(limit))
+	return int(limit)
+}
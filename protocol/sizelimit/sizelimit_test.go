@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sizelimit
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+)
+
+func newURL(opts ...common.Option) *common.URL {
+	return common.NewURLWithOptions(append([]common.Option{
+		common.WithPath("com.ikurento.user.UserProvider"),
+		common.WithParamsValue(constant.InterfaceKey, "com.ikurento.user.UserProvider"),
+	}, opts...)...)
+}
+
+func TestLimits(t *testing.T) {
+	url := newURL(
+		common.WithParamsValue(constant.MaxRequestSizeKey, "1kb"),
+		common.WithParamsValue("methods.Upload."+constant.MaxRequestSizeKey, "2kb"),
+		common.WithParamsValue("methods.Download."+constant.MaxResponseSizeKey, "4kb"),
+	)
+
+	limit := RequestLimit(url, constant.SideProvider, "Echo")
+	require.NotNil(t, limit)
+	assert.Equal(t, 1000, limit.Max())
+	assert.NoError(t, limit.Check(1000))
+	err := limit.Check(1001)
+	assert.Equal(t, triple_protocol.CodeResourceExhausted, triple_protocol.CodeOf(err))
+	assert.Contains(t, err.Error(), "com.ikurento.user.UserProvider#Echo")
+
+	assert.Equal(t, 2000, RequestLimit(url, constant.SideProvider, "Upload").Max())
+	assert.Equal(t, 4000, ResponseLimit(url, constant.SideProvider, "Download").Max())
+
+	// the unlimited method
+	limit = ResponseLimit(url, constant.SideProvider, "Echo")
+	assert.Nil(t, limit)
+	assert.Equal(t, 0, limit.Max())
+	assert.NoError(t, limit.Check(1<<30))
+}
+
+func TestInvalidLimit(t *testing.T) {
+	url := newURL(common.WithParamsValue(constant.MaxRequestSizeKey, "1 apple"))
+	assert.Nil(t, RequestLimit(url, constant.SideConsumer, "Echo"))
+}
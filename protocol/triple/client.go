@@ -25,6 +25,7 @@ import (
 	"net"
 	"net/http"
 	"reflect"
+	"slices"
 	"strings"
 	"time"
 )
@@ -34,7 +35,6 @@ import (
 
 	"github.com/dustin/go-humanize"
 
-	"github.com/quic-go/quic-go"
 	"github.com/quic-go/quic-go/http3"
 
 	"golang.org/x/net/http2"
@@ -44,6 +44,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/global"
+	"dubbo.apache.org/dubbo-go/v3/protocol/sizelimit"
 	tri "dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
 	dubbotls "dubbo.apache.org/dubbo-go/v3/tls"
 )
@@ -60,6 +61,8 @@ type clientManager struct {
 	isIDL bool
 	// triple_protocol clients, key is method name
 	triClients map[string]*tri.Client
+	// the pool of the HTTP/2 connections, nil if a single connection is used
+	connPool *tri.ConnPool
 }
 
 // TODO: code a triple client between clientManager and triple_protocol client
@@ -124,8 +127,9 @@ func (cm *clientManager) callBidiStream(ctx context.Context, method string) (any
 }
 
 func (cm *clientManager) close() error {
-	// There is no need to release resources right now.
-	// But we leave this function here for future use.
+	if cm.connPool != nil {
+		cm.connPool.Close()
+	}
 	return nil
 }
 
@@ -150,6 +154,22 @@ func newClientManager(url *common.URL) (*clientManager, error) {
 		panic(fmt.Sprintf("Unsupported serialization: %s", serialization))
 	}
 
+	// set compression
+	switch compression := url.GetParam(constant.CompressionKey, ""); compression {
+	case "", constant.CompressionIdentity:
+	case constant.CompressionGzip:
+		cliOpts = append(cliOpts, tri.WithSendGzip())
+	case constant.CompressionZstd:
+		cliOpts = append(cliOpts, tri.WithSendZstd())
+	case constant.CompressionSnappy:
+		cliOpts = append(cliOpts, tri.WithSendSnappy())
+	default:
+		return nil, fmt.Errorf("unsupported compression: %s", compression)
+	}
+	if minBytes := url.GetParamInt(constant.CompressMinBytesKey, 0); minBytes > 0 {
+		cliOpts = append(cliOpts, tri.WithCompressMinBytes(int(minBytes)))
+	}
+
 	// set timeout
 	timeout := url.GetParamDuration(constant.TimeoutKey, "")
 	cliOpts = append(cliOpts, tri.WithTimeout(timeout))
@@ -202,8 +222,16 @@ func newClientManager(url *common.URL) (*clientManager, error) {
 	}
 	cliOpts = append(cliOpts, cliKeepAliveOpts...)
 
+	settings, err := tri.NewTransportSettings(tripleConf)
+	if err != nil {
+		logger.Errorf("triple transport settings err: %v", err)
+		return nil, err
+	}
+
 	// handle http transport of triple protocol
 	var transport http.RoundTripper
+	// the HTTP/2 transport spreading the streams over a connection pool, HTTP/3 has no head-of-line blocking
+	var pooledTransport *http2.Transport
 
 	var callProtocol string
 	if tripleConf != nil && tripleConf.Http3 != nil && tripleConf.Http3.Enable {
@@ -213,6 +241,15 @@ func newClientManager(url *common.URL) (*clientManager, error) {
 		callProtocol = constant.CallHTTP2
 	}
 
+	if settings.DialContext = getLocalDialer(url); settings.DialContext != nil {
+		// HTTP/3 runs over UDP, which unix domain sockets and in-memory listeners don't carry.
+		callProtocol = constant.CallHTTP2
+	}
+
+	if callProtocol != constant.CallHTTP3 && settings.HasHTTP2ClientWindows() {
+		logger.Warnf("Triple HTTP/2 client keeps its fixed flow-control windows, the initial window sizes only apply to HTTP/3")
+	}
+
 	switch callProtocol {
 	// This case might be for backward compatibility,
 	// it's not useful for the Triple protocol, HTTP/1 lacks trailer functionality.
@@ -223,38 +260,26 @@ func newClientManager(url *common.URL) (*clientManager, error) {
 		}
 		cliOpts = append(cliOpts, tri.WithTriple())
 	case constant.CallHTTP2:
-		// TODO: Enrich the http2 transport config for triple protocol.
+		h2Transport := &http2.Transport{
+			ReadIdleTimeout: keepAliveInterval,
+			PingTimeout:     keepAliveTimeout,
+		}
 		if tlsFlag {
-			transport = &http2.Transport{
-				TLSClientConfig: cfg,
-				ReadIdleTimeout: keepAliveInterval,
-				PingTimeout:     keepAliveTimeout,
-			}
+			h2Transport.TLSClientConfig = cfg
 		} else {
-			transport = &http2.Transport{
-				DialTLSContext: func(_ context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
-					return net.Dial(network, addr)
-				},
-				AllowHTTP:       true,
-				ReadIdleTimeout: keepAliveInterval,
-				PingTimeout:     keepAliveTimeout,
-			}
+			h2Transport.AllowHTTP = true
+		}
+		settings.ConfigureHTTP2Transport(h2Transport)
+		transport = h2Transport
+		if settings.MaxConnections > 0 {
+			pooledTransport = h2Transport
 		}
 	case constant.CallHTTP3:
 		if !tlsFlag {
 			return nil, fmt.Errorf("TRIPLE http3 client must have TLS config, but TLS config is nil")
 		}
 
-		// TODO: Enrich the http3 transport config for triple protocol.
-		transport = &http3.Transport{
-			TLSClientConfig: cfg,
-			QUICConfig: &quic.Config{
-				// ref: https://quic-go.net/docs/quic/connection/#keeping-a-connection-alive
-				KeepAlivePeriod: keepAliveInterval,
-				// ref: https://quic-go.net/docs/quic/connection/#idle-timeout
-				MaxIdleTimeout: keepAliveTimeout,
-			},
-		}
+		transport = newHttp3Transport(cfg, settings, keepAliveInterval, keepAliveTimeout)
 
 		logger.Infof("Triple http3 client transport init successfully")
 	case constant.CallHTTP2AndHTTP3:
@@ -263,7 +288,7 @@ func newClientManager(url *common.URL) (*clientManager, error) {
 		}
 
 		// Create a dual transport that can handle both HTTP/2 and HTTP/3
-		transport = newDualTransport(cfg, keepAliveInterval, keepAliveTimeout)
+		transport = newDualTransport(cfg, settings, keepAliveInterval, keepAliveTimeout)
 		logger.Infof("Triple HTTP/2 and HTTP/3 client transport init successfully")
 	default:
 		return nil, fmt.Errorf("unsupported http protocol: %s", callProtocol)
@@ -273,6 +298,11 @@ func newClientManager(url *common.URL) (*clientManager, error) {
 		Transport: transport,
 	}
 
+	// carry the streams over WebSocket for the proxies breaking long-lived HTTP/2 streams
+	if url.GetParamBool(constant.WebSocketKey, false) || (tripleConf != nil && tripleConf.WebSocket != nil && tripleConf.WebSocket.Enable) {
+		cliOpts = append(cliOpts, tri.WithStreamHTTPClient(settings.NewWebSocketClient(cfg)))
+	}
+
 	var baseTriURL string
 	baseTriURL = strings.TrimPrefix(url.Location, httpPrefix)
 	baseTriURL = strings.TrimPrefix(baseTriURL, httpsPrefix)
@@ -290,7 +320,7 @@ func newClientManager(url *common.URL) (*clientManager, error) {
 			if err != nil {
 				return nil, fmt.Errorf("JoinPath failed for base %s, interface %s, method %s", baseTriURL, url.Interface(), method)
 			}
-			triClient := tri.NewClient(httpClient, triURL, cliOpts...)
+			triClient := tri.NewClient(httpClient, triURL, slices.Concat(cliOpts, getSizeLimitCliOpts(url, method))...)
 			triClients[method] = triClient
 		}
 	} else {
@@ -309,30 +339,46 @@ func newClientManager(url *common.URL) (*clientManager, error) {
 			if err != nil {
 				return nil, fmt.Errorf("JoinPath failed for base %s, interface %s, method %s", baseTriURL, url.Interface(), methodName)
 			}
-			triClient := tri.NewClient(httpClient, triURL, cliOpts...)
+			triClient := tri.NewClient(httpClient, triURL, slices.Concat(cliOpts, getSizeLimitCliOpts(url, methodName))...)
 			triClients[methodName] = triClient
 		}
 	}
 
+	var connPool *tri.ConnPool
+	if pooledTransport != nil {
+		connPool = tri.NewConnPool(pooledTransport, settings, connPoolReporter(url))
+	}
+
 	return &clientManager{
 		isIDL:      isIDL,
 		triClients: triClients,
+		connPool:   connPool,
 	}, nil
 }
 
+// getLocalDialer returns the dialer of the in-memory listener or the unix domain socket
+// configured by url, the dialer is nil if the client dials the TCP address of url.
+func getLocalDialer(url *common.URL) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	if name := url.GetParam(constant.InMemoryKey, ""); name != "" {
+		return func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return tri.DialMemory(ctx, name)
+		}
+	}
+	if path := url.GetParam(constant.UnixSocketKey, ""); path != "" {
+		return func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", path)
+		}
+	}
+	return nil
+}
+
 func genKeepAliveOptions(url *common.URL, tripleConf *global.TripleConfig) ([]tri.ClientOption, time.Duration, time.Duration, error) {
 	var cliKeepAliveOpts []tri.ClientOption
 
 	// set max send and recv msg size
-	maxCallRecvMsgSize := constant.DefaultMaxCallRecvMsgSize
-	if recvMsgSize, err := humanize.ParseBytes(url.GetParam(constant.MaxCallRecvMsgSize, "")); err == nil && recvMsgSize > 0 {
-		maxCallRecvMsgSize = int(recvMsgSize)
-	}
+	maxCallRecvMsgSize, maxCallSendMsgSize := getCallMsgSizes(url)
 	cliKeepAliveOpts = append(cliKeepAliveOpts, tri.WithReadMaxBytes(maxCallRecvMsgSize))
-	maxCallSendMsgSize := constant.DefaultMaxCallSendMsgSize
-	if sendMsgSize, err := humanize.ParseBytes(url.GetParam(constant.MaxCallSendMsgSize, "")); err == nil && sendMsgSize > 0 {
-		maxCallSendMsgSize = int(sendMsgSize)
-	}
 	cliKeepAliveOpts = append(cliKeepAliveOpts, tri.WithSendMaxBytes(maxCallSendMsgSize))
 
 	// set keepalive interval and keepalive timeout
@@ -363,6 +409,40 @@ func genKeepAliveOptions(url *common.URL, tripleConf *global.TripleConfig) ([]tr
 	return cliKeepAliveOpts, keepAliveInterval, keepAliveTimeout, nil
 }
 
+// getCallMsgSizes returns the max sizes of the messages received and sent by the client.
+func getCallMsgSizes(url *common.URL) (int, int) {
+	maxCallRecvMsgSize := constant.DefaultMaxCallRecvMsgSize
+	if recvMsgSize, err := humanize.ParseBytes(url.GetParam(constant.MaxCallRecvMsgSize, "")); err == nil && recvMsgSize > 0 {
+		maxCallRecvMsgSize = int(recvMsgSize)
+	}
+	maxCallSendMsgSize := constant.DefaultMaxCallSendMsgSize
+	if sendMsgSize, err := humanize.ParseBytes(url.GetParam(constant.MaxCallSendMsgSize, "")); err == nil && sendMsgSize > 0 {
+		maxCallSendMsgSize = int(sendMsgSize)
+	}
+	return maxCallRecvMsgSize, maxCallSendMsgSize
+}
+
+// getSizeLimitCliOpts narrows the max bytes of the client of methodName to its size limits.
+func getSizeLimitCliOpts(url *common.URL, methodName string) []tri.ClientOption {
+	reqLimit := sizelimit.RequestLimit(url, constant.SideConsumer, methodName)
+	respLimit := sizelimit.ResponseLimit(url, constant.SideConsumer, methodName)
+	if reqLimit == nil && respLimit == nil {
+		return nil
+	}
+	maxCallRecvMsgSize, maxCallSendMsgSize := getCallMsgSizes(url)
+	return []tri.ClientOption{
+		tri.WithReadMaxBytes(narrowMaxBytes(maxCallRecvMsgSize, respLimit.Max())),
+		tri.WithSendMaxBytes(narrowMaxBytes(maxCallSendMsgSize, reqLimit.Max())),
+		tri.WithMaxBytesObserver(func(send bool) {
+			if send {
+				reqLimit.Report()
+			} else {
+				respLimit.Report()
+			}
+		}),
+	}
+}
+
 // dualTransport is a transport that can handle both HTTP/2 and HTTP/3
 // It uses HTTP Alternative Services (Alt-Svc) for protocol negotiation
 type dualTransport struct {
@@ -372,25 +452,28 @@ type dualTransport struct {
 	altSvcCache *tri.AltSvcCache
 }
 
+// newHttp3Transport creates the HTTP/3 transport tuned by settings
+func newHttp3Transport(tlsConfig *tls.Config, settings *tri.TransportSettings, keepAliveInterval, keepAliveTimeout time.Duration) *http3.Transport {
+	return &http3.Transport{
+		TLSClientConfig:        settings.ClientTLSConfig(tlsConfig),
+		QUICConfig:             settings.QUICConfig(keepAliveInterval, keepAliveTimeout),
+		EnableDatagrams:        settings.EnableDatagrams,
+		MaxResponseHeaderBytes: int64(settings.MaxHeaderListSize),
+	}
+}
+
 // newDualTransport creates a new dual transport that supports both HTTP/2 and HTTP/3
-func newDualTransport(tlsConfig *tls.Config, keepAliveInterval, keepAliveTimeout time.Duration) http.RoundTripper {
+func newDualTransport(tlsConfig *tls.Config, settings *tri.TransportSettings, keepAliveInterval, keepAliveTimeout time.Duration) http.RoundTripper {
 	http2Transport := &http2.Transport{
 		TLSClientConfig: tlsConfig,
 		ReadIdleTimeout: keepAliveInterval,
 		PingTimeout:     keepAliveTimeout,
 	}
-
-	http3Transport := &http3.Transport{
-		TLSClientConfig: tlsConfig,
-		QUICConfig: &quic.Config{
-			KeepAlivePeriod: keepAliveInterval,
-			MaxIdleTimeout:  keepAliveTimeout,
-		},
-	}
+	settings.ConfigureHTTP2Transport(http2Transport)
 
 	return &dualTransport{
 		http2Transport: http2Transport,
-		http3Transport: http3Transport,
+		http3Transport: newHttp3Transport(tlsConfig, settings, keepAliveInterval, keepAliveTimeout),
 		altSvcCache:    tri.NewAltSvcCache(),
 	}
 }
@@ -18,6 +18,8 @@
 package triple
 
 import (
+	"context"
+	"net"
 	"net/http"
 	"testing"
 	"time"
@@ -25,12 +27,15 @@ import (
 
 import (
 	"github.com/stretchr/testify/assert"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/global"
+	tri "dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
 )
 
 func TestClientManager_HTTP2AndHTTP3(t *testing.T) {
@@ -85,7 +90,7 @@ func TestDualTransport(t *testing.T) {
 	keepAliveTimeout := 5 * time.Second
 
 	// Test newDualTransport function
-	transport := newDualTransport(nil, keepAliveInterval, keepAliveTimeout)
+	transport := newDualTransport(nil, &tri.TransportSettings{}, keepAliveInterval, keepAliveTimeout)
 	assert.NotNil(t, transport)
 
 	// Verify that transport implements http.RoundTripper interface
@@ -94,3 +99,107 @@ func TestDualTransport(t *testing.T) {
 	})
 	assert.True(t, ok, "transport should implement http.RoundTripper")
 }
+
+func TestClientManager_TransportSettings(t *testing.T) {
+	url := &common.URL{
+		Location: "localhost:20000",
+		Path:     "com.example.TestService",
+		Methods:  []string{"testMethod"},
+	}
+	url.SetAttribute(constant.TripleConfigKey, &global.TripleConfig{
+		MaxConcurrentStreams:    100,
+		InitialStreamWindowSize: "1MiB",
+		MaxHeaderListSize:       "16KiB",
+		IdleTimeout:             "1m",
+		DialTimeout:             "3s",
+	})
+	clientManager, err := newClientManager(url)
+	assert.Nil(t, err)
+	assert.NotNil(t, clientManager.triClients["testMethod"])
+
+	url.SetAttribute(constant.TripleConfigKey, &global.TripleConfig{DialTimeout: "3"})
+	_, err = newClientManager(url)
+	assert.NotNil(t, err)
+}
+
+func TestClientManager_ConnPool(t *testing.T) {
+	url := &common.URL{
+		Location: "localhost:20000",
+		Path:     "com.example.TestService",
+		Methods:  []string{"testMethod"},
+	}
+	url.SetAttribute(constant.TripleConfigKey, &global.TripleConfig{})
+	clientManager, err := newClientManager(url)
+	assert.Nil(t, err)
+	assert.Nil(t, clientManager.connPool)
+
+	url.SetAttribute(constant.TripleConfigKey, &global.TripleConfig{
+		ConnPool: &global.ConnPoolConfig{MaxConnections: 4},
+	})
+	clientManager, err = newClientManager(url)
+	assert.Nil(t, err)
+	assert.NotNil(t, clientManager.connPool)
+	assert.Nil(t, clientManager.close())
+
+	url.SetAttribute(constant.TripleConfigKey, &global.TripleConfig{
+		ConnPool: &global.ConnPoolConfig{OpenThreshold: 2},
+	})
+	_, err = newClientManager(url)
+	assert.NotNil(t, err)
+}
+
+func TestClientManager_Compression(t *testing.T) {
+	url := &common.URL{
+		Location: "localhost:20000",
+		Path:     "com.example.TestService",
+		Methods:  []string{"testMethod"},
+	}
+	url.SetAttribute(constant.TripleConfigKey, &global.TripleConfig{})
+	url.SetParam(constant.CompressionKey, constant.CompressionZstd)
+	url.SetParam(constant.CompressMinBytesKey, "1024")
+	clientManager, err := newClientManager(url)
+	assert.Nil(t, err)
+	assert.NotNil(t, clientManager.triClients["testMethod"])
+
+	url.SetParam(constant.CompressionKey, "br")
+	_, err = newClientManager(url)
+	assert.NotNil(t, err)
+}
+
+func TestClientManager_WebSocket(t *testing.T) {
+	srv := tri.NewServer("", nil)
+	err := srv.RegisterBidiStreamHandler("/com.example.TestService/testStream",
+		func(ctx context.Context, stream *tri.BidiStream) error {
+			msg := &wrapperspb.StringValue{}
+			if err := stream.Receive(msg); err != nil {
+				return err
+			}
+			return stream.Send(wrapperspb.String("hello " + msg.GetValue()))
+		})
+	assert.Nil(t, err)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	go func() {
+		_ = srv.Serve(lis, nil)
+	}()
+	defer srv.Stop()
+
+	url := &common.URL{
+		Location: lis.Addr().String(),
+		Path:     "com.example.TestService",
+		Methods:  []string{"testStream"},
+	}
+	url.SetParam(constant.InterfaceKey, "com.example.TestService")
+	url.SetParam(constant.WebSocketKey, "true")
+	clientManager, err := newClientManager(url)
+	assert.Nil(t, err)
+	raw, err := clientManager.callBidiStream(context.Background(), "testStream")
+	assert.Nil(t, err)
+	stream := raw.(*tri.BidiStreamForClient)
+	assert.Nil(t, stream.Send(wrapperspb.String("dubbo")))
+	assert.Nil(t, stream.CloseRequest())
+	msg := &wrapperspb.StringValue{}
+	assert.Nil(t, stream.Receive(msg))
+	assert.Equal(t, "hello dubbo", msg.GetValue())
+	assert.Nil(t, stream.CloseResponse())
+}
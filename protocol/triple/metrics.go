@@ -0,0 +1,92 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package triple
+
+import (
+	"sync/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	tri "dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+)
+
+var (
+	connectionsKey = metrics.NewMetricKey("dubbo_consumer_triple_connections",
+		"The HTTP/2 Connections of Triple Consumer to Provider")
+	activeStreamsKey = metrics.NewMetricKey("dubbo_consumer_triple_streams_active",
+		"The Active Streams of Triple Consumer on the Connections to Provider")
+	maxStreamsKey = metrics.NewMetricKey("dubbo_consumer_triple_streams_max",
+		"The Max Concurrent Streams Allowed by Provider on the Connections")
+
+	// connPoolMetrics is set once the metrics module is initialized
+	connPoolMetrics atomic.Pointer[connPoolMetricSet]
+)
+
+func init() {
+	metrics.AddCollector("triple_conn_pool", func(registry metrics.MetricRegistry, _ *common.URL) {
+		connPoolMetrics.Store(&connPoolMetricSet{
+			registry:      registry,
+			connections:   metrics.NewGaugeVec(registry, connectionsKey),
+			activeStreams: metrics.NewGaugeVec(registry, activeStreamsKey),
+			maxStreams:    metrics.NewGaugeVec(registry, maxStreamsKey),
+		})
+	})
+}
+
+type connPoolMetricSet struct {
+	registry      metrics.MetricRegistry
+	connections   metrics.GaugeVec
+	activeStreams metrics.GaugeVec
+	maxStreams    metrics.GaugeVec
+}
+
+// remove removes the gauges of labels, if the registry supports it
+func (m *connPoolMetricSet) remove(labels map[string]string) {
+	remover, ok := m.registry.(metrics.GaugeRemover)
+	if !ok {
+		return
+	}
+	for _, key := range []*metrics.MetricKey{connectionsKey, activeStreamsKey, maxStreamsKey} {
+		remover.RemoveGauge(metrics.NewMetricIdByLabels(key, labels))
+	}
+}
+
+// connPoolReporter returns the function exporting the stats of the connection pool of url,
+// the gauges of an address without connections are removed
+func connPoolReporter(url *common.URL) func(tri.ConnPoolStats) {
+	return func(stats tri.ConnPoolStats) {
+		m := connPoolMetrics.Load()
+		if m == nil {
+			return
+		}
+		labels := map[string]string{
+			constant.TagApplicationName: url.GetParam(constant.ApplicationKey, ""),
+			constant.TagInterface:       url.Interface(),
+			constant.TagAddress:         stats.Addr,
+		}
+		if stats.Connections == 0 {
+			m.remove(labels)
+			return
+		}
+		m.connections.Set(labels, float64(stats.Connections))
+		m.activeStreams.Set(labels, float64(stats.ActiveStreams))
+		m.maxStreams.Set(labels, float64(stats.MaxStreams))
+	}
+}
@@ -92,6 +92,166 @@ func WithMaxServerRecvMsgSize(size string) Option {
 	}
 }
 
+// WithDialTimeout sets the timeout of establishing a connection, including the TLS and QUIC handshakes.
+// If not set, the dialing is only bounded by the context of the call.
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(opts *Options) {
+		opts.Triple.DialTimeout = timeout.String()
+	}
+}
+
+// WithIdleTimeout sets the duration after which a connection without any active stream is closed.
+// It applies to both HTTP/2 and HTTP/3, on HTTP/3 it takes precedence over the keep-alive timeout.
+func WithIdleTimeout(timeout time.Duration) Option {
+	return func(opts *Options) {
+		opts.Triple.IdleTimeout = timeout.String()
+	}
+}
+
+// WithMaxConcurrentStreams sets the max streams a peer may open on one connection.
+// The server advertises it, and the client then waits for a free stream instead of dialing extra connections.
+// If not set, the HTTP/2 default is 250 and the HTTP/3 default is 100.
+func WithMaxConcurrentStreams(n uint32) Option {
+	return func(opts *Options) {
+		opts.Triple.MaxConcurrentStreams = n
+	}
+}
+
+// WithInitialStreamWindowSize sets the initial flow-control window of a stream.
+// It applies to the server and to HTTP/3, the HTTP/2 client keeps its fixed windows.
+// size: specified as a string (e.g., "1MiB"), at most 2^31-1 bytes.
+func WithInitialStreamWindowSize(size string) Option {
+	return func(opts *Options) {
+		opts.Triple.InitialStreamWindowSize = size
+	}
+}
+
+// WithInitialConnWindowSize sets the initial flow-control window of a connection.
+// It applies to the server and to HTTP/3, the HTTP/2 client keeps its fixed windows.
+// size: specified as a string (e.g., "4MiB"), at most 2^31-1 bytes.
+func WithInitialConnWindowSize(size string) Option {
+	return func(opts *Options) {
+		opts.Triple.InitialConnWindowSize = size
+	}
+}
+
+// WithMaxHeaderListSize sets the max size of the headers accepted from the peer.
+// size: specified as a string (e.g., "16KiB").
+// If not set, the server accepts 1MB and the client accepts 10MB.
+func WithMaxHeaderListSize(size string) Option {
+	return func(opts *Options) {
+		opts.Triple.MaxHeaderListSize = size
+	}
+}
+
+// WithMaxConnections makes the client keep a pool of up to n HTTP/2 connections to each provider,
+// which spreads the streams to the least busy connection instead of multiplexing all of them over one.
+// If the pool is enabled without it, n is 4.
+func WithMaxConnections(n int) Option {
+	return func(opts *Options) {
+		opts.connPool().MaxConnections = n
+	}
+}
+
+// WithConnOpenThreshold sets the ratio of the max concurrent streams of the provider, the pool opens
+// another connection once the streams of every connection reach it.
+// ratio: in (0, 1], the default value is 0.8.
+func WithConnOpenThreshold(ratio float64) Option {
+	return func(opts *Options) {
+		opts.connPool().OpenThreshold = ratio
+	}
+}
+
+// WithConnIdleTimeout sets the duration after which the pool closes an extra connection without any stream.
+// If not set, it's 1m.
+func WithConnIdleTimeout(timeout time.Duration) Option {
+	return func(opts *Options) {
+		opts.connPool().IdleTimeout = timeout.String()
+	}
+}
+
+func (o *Options) connPool() *global.ConnPoolConfig {
+	if o.Triple.ConnPool == nil {
+		o.Triple.ConnPool = &global.ConnPoolConfig{}
+	}
+	return o.Triple.ConnPool
+}
+
+// WithCorsAllowOrigins enables CORS for the origins, so browsers can call the services
+// with gRPC-Web or the Triple protocol without a proxy.
+// origins: e.g. "https://example.com", "*" allows any origin and "https://*.example.com" allows the subdomains.
+func WithCorsAllowOrigins(origins ...string) Option {
+	return func(opts *Options) {
+		cors := opts.cors()
+		cors.AllowOrigins = append(cors.AllowOrigins, origins...)
+	}
+}
+
+// WithCorsAllowHeaders allows browsers to send the headers besides the ones of the protocols.
+func WithCorsAllowHeaders(headers ...string) Option {
+	return func(opts *Options) {
+		cors := opts.cors()
+		cors.AllowHeaders = append(cors.AllowHeaders, headers...)
+	}
+}
+
+// WithCorsExposeHeaders allows browsers to read the response headers and trailers besides the gRPC status ones.
+func WithCorsExposeHeaders(headers ...string) Option {
+	return func(opts *Options) {
+		cors := opts.cors()
+		cors.ExposeHeaders = append(cors.ExposeHeaders, headers...)
+	}
+}
+
+// WithCorsAllowCredentials allows the cross-origin requests with credentials, such as cookies.
+// It can't be used with the origin "*", the origins must be listed.
+func WithCorsAllowCredentials() Option {
+	return func(opts *Options) {
+		opts.cors().AllowCredentials = true
+	}
+}
+
+// WithCorsMaxAge sets how long browsers can cache the result of a preflight request.
+func WithCorsMaxAge(maxAge time.Duration) Option {
+	return func(opts *Options) {
+		opts.cors().MaxAge = maxAge.String()
+	}
+}
+
+func (o *Options) cors() *global.CorsConfig {
+	if o.Triple.Cors == nil {
+		o.Triple.Cors = &global.CorsConfig{}
+	}
+	return o.Triple.Cors
+}
+
+// WithWebSocket makes the client carry the streaming RPCs over WebSocket, which passes the proxies
+// breaking long-lived HTTP/2 streams. The unary RPCs keep using HTTP/2, and the server accepts
+// WebSocket upgrades without it.
+func WithWebSocket() Option {
+	return func(opts *Options) {
+		opts.webSocket().Enable = true
+	}
+}
+
+// WithWebSocketKeepAlive sets how often a ping is sent on the WebSocket connections, and how long to wait
+// for the peer after the interval before closing the connection.
+// If not set, the interval is 30s and the timeout is 10s.
+func WithWebSocketKeepAlive(interval, timeout time.Duration) Option {
+	return func(opts *Options) {
+		ws := opts.webSocket()
+		ws.PingInterval = interval.String()
+		ws.PongTimeout = timeout.String()
+	}
+}
+
+func (o *Options) webSocket() *global.WebSocketConfig {
+	if o.Triple.WebSocket == nil {
+		o.Triple.WebSocket = &global.WebSocketConfig{}
+	}
+	return o.Triple.WebSocket
+}
+
 // Http3Enable enables HTTP/3 support for the Triple protocol.
 // This option configures the server to start both HTTP/2 and HTTP/3 servers
 // simultaneously, providing modern HTTP/3 capabilities alongside traditional HTTP/2.
@@ -161,3 +321,57 @@ func Http3Negotiation(negotiation bool) Option {
 		opts.Triple.Http3.Negotiation = negotiation
 	}
 }
+
+// Http3Allow0RTT makes the HTTP/3 server accept 0-RTT connection attempts,
+// which saves a round trip on resumed connections. 0-RTT data can be replayed,
+// so only idempotent requests should be sent with it. The HTTP/3 client only
+// caches the TLS sessions to resume them, triple calls are never sent as 0-RTT data.
+//
+// # Experimental
+//
+// NOTICE: This API is EXPERIMENTAL and may be changed or removed in
+// a later release.
+func Http3Allow0RTT() Option {
+	return func(opts *Options) {
+		opts.Triple.Http3.Allow0RTT = true
+	}
+}
+
+// Http3EnableDatagrams enables the unreliable datagram extension of QUIC and HTTP/3.
+//
+// # Experimental
+//
+// NOTICE: This API is EXPERIMENTAL and may be changed or removed in
+// a later release.
+func Http3EnableDatagrams() Option {
+	return func(opts *Options) {
+		opts.Triple.Http3.EnableDatagrams = true
+	}
+}
+
+// Http3DisablePathMTUDiscovery disables the path MTU discovery of QUIC,
+// the packets then keep the initial size on paths dropping large UDP packets.
+//
+// # Experimental
+//
+// NOTICE: This API is EXPERIMENTAL and may be changed or removed in
+// a later release.
+func Http3DisablePathMTUDiscovery() Option {
+	return func(opts *Options) {
+		opts.Triple.Http3.DisablePathMTUDiscovery = true
+	}
+}
+
+// Http3InitialPacketSize sets the size of the first QUIC packets. The congestion window
+// is counted in packets of this size until the path MTU discovery finds a larger one.
+// If not set, default value is 1280 bytes.
+//
+// # Experimental
+//
+// NOTICE: This API is EXPERIMENTAL and may be changed or removed in
+// a later release.
+func Http3InitialPacketSize(size uint16) Option {
+	return func(opts *Options) {
+		opts.Triple.Http3.InitialPacketSize = size
+	}
+}
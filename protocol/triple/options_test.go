@@ -0,0 +1,103 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package triple
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOptions_Transport(t *testing.T) {
+	opts := NewOptions(
+		WithDialTimeout(3*time.Second),
+		WithIdleTimeout(5*time.Minute),
+		WithMaxConcurrentStreams(128),
+		WithInitialStreamWindowSize("1MiB"),
+		WithInitialConnWindowSize("4MiB"),
+		WithMaxHeaderListSize("16KiB"),
+		Http3Enable(),
+		Http3Allow0RTT(),
+		Http3EnableDatagrams(),
+		Http3DisablePathMTUDiscovery(),
+		Http3InitialPacketSize(1200),
+	)
+
+	conf := opts.Triple
+	assert.Equal(t, "3s", conf.DialTimeout)
+	assert.Equal(t, "5m0s", conf.IdleTimeout)
+	assert.Equal(t, uint32(128), conf.MaxConcurrentStreams)
+	assert.Equal(t, "1MiB", conf.InitialStreamWindowSize)
+	assert.Equal(t, "4MiB", conf.InitialConnWindowSize)
+	assert.Equal(t, "16KiB", conf.MaxHeaderListSize)
+	assert.True(t, conf.Http3.Enable)
+	assert.True(t, conf.Http3.Negotiation)
+	assert.True(t, conf.Http3.Allow0RTT)
+	assert.True(t, conf.Http3.EnableDatagrams)
+	assert.True(t, conf.Http3.DisablePathMTUDiscovery)
+	assert.Equal(t, uint16(1200), conf.Http3.InitialPacketSize)
+}
+
+func TestNewOptions_Cors(t *testing.T) {
+	assert.Nil(t, NewOptions().Triple.Cors)
+
+	opts := NewOptions(
+		WithCorsAllowOrigins("https://example.com"),
+		WithCorsAllowOrigins("https://*.example.org"),
+		WithCorsAllowHeaders("X-Custom"),
+		WithCorsExposeHeaders("X-Custom-Trailer"),
+		WithCorsAllowCredentials(),
+		WithCorsMaxAge(2*time.Hour),
+	)
+	cors := opts.Triple.Cors
+	assert.Equal(t, []string{"https://example.com", "https://*.example.org"}, cors.AllowOrigins)
+	assert.Equal(t, []string{"X-Custom"}, cors.AllowHeaders)
+	assert.Equal(t, []string{"X-Custom-Trailer"}, cors.ExposeHeaders)
+	assert.True(t, cors.AllowCredentials)
+	assert.Equal(t, "2h0m0s", cors.MaxAge)
+}
+
+func TestNewOptions_WebSocket(t *testing.T) {
+	assert.Nil(t, NewOptions().Triple.WebSocket)
+
+	opts := NewOptions(
+		WithWebSocket(),
+		WithWebSocketKeepAlive(15*time.Second, 5*time.Second),
+	)
+	ws := opts.Triple.WebSocket
+	assert.True(t, ws.Enable)
+	assert.Equal(t, "15s", ws.PingInterval)
+	assert.Equal(t, "5s", ws.PongTimeout)
+}
+
+func TestNewOptions_ConnPool(t *testing.T) {
+	assert.Nil(t, NewOptions().Triple.ConnPool)
+
+	opts := NewOptions(
+		WithMaxConnections(8),
+		WithConnOpenThreshold(0.5),
+		WithConnIdleTimeout(30*time.Second),
+	)
+	pool := opts.Triple.ConnPool
+	assert.Equal(t, 8, pool.MaxConnections)
+	assert.Equal(t, 0.5, pool.OpenThreshold)
+	assert.Equal(t, "30s", pool.IdleTimeout)
+}
@@ -21,8 +21,10 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"net"
 	"net/http"
 	"reflect"
+	"slices"
 	"strings"
 	"sync"
 )
@@ -36,7 +38,14 @@ import (
 
 	"github.com/dustin/go-humanize"
 
+	"google.golang.org/genproto/googleapis/api/annotations"
+
 	"google.golang.org/grpc"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
 )
 
 import (
@@ -48,6 +57,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/protocol/base"
 	"dubbo.apache.org/dubbo-go/v3/protocol/dubbo3"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/protocol/sizelimit"
 	tri "dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
 	dubbotls "dubbo.apache.org/dubbo-go/v3/tls"
 )
@@ -146,6 +156,7 @@ func (s *Server) Start(invoker base.Invoker, info *common.ServiceInfo) {
 	if info != nil {
 		// new triple idl mode
 		s.handleServiceWithInfo(intfName, invoker, info, hanOpts...)
+		s.registerHttpRules(intfName, info.InterfaceName)
 		s.saveServiceInfo(intfName, info)
 	} else if IDLMode == constant.NONIDL {
 		// new triple non-idl mode
@@ -158,13 +169,44 @@ func (s *Server) Start(invoker base.Invoker, info *common.ServiceInfo) {
 	}
 	internal.ReflectionRegister(s)
 
+	lis, err := listenLocal(url)
+	if err != nil {
+		logger.Errorf("TRIPLE Server listen failed. err: %v", err)
+		return
+	}
+
 	go func() {
-		if runErr := s.triServer.Run(callProtocol, tlsConf); runErr != nil {
+		var runErr error
+		if lis != nil {
+			if callProtocol != constant.CallHTTP2 {
+				logger.Warnf("TRIPLE Server only serves HTTP/2 on %s %s", lis.Addr().Network(), lis.Addr())
+			}
+			runErr = s.triServer.Serve(lis, tlsConf)
+		} else {
+			runErr = s.triServer.Run(callProtocol, tlsConf)
+		}
+		if runErr != nil {
 			logger.Errorf("server serve failed with err: %v", runErr)
 		}
 	}()
 }
 
+// listenLocal listens on the in-memory listener or the unix domain socket configured by url,
+// the listener is nil if the server listens on the TCP address of url.
+func listenLocal(url *common.URL) (net.Listener, error) {
+	if name := url.GetParam(constant.InMemoryKey, ""); name != "" {
+		lis, err := tri.ListenMemory(name)
+		if err != nil {
+			return nil, err
+		}
+		return lis, nil
+	}
+	if path := url.GetParam(constant.UnixSocketKey, ""); path != "" {
+		return common.ListenUnixSocket(path, url.GetParam(constant.UnixSocketModeKey, ""))
+	}
+	return nil, nil
+}
+
 // todo(DMwangnima): extract a common function
 // RefreshService refreshes Triple Service
 func (s *Server) RefreshService(invoker base.Invoker, info *common.ServiceInfo) {
@@ -184,6 +226,7 @@ func (s *Server) RefreshService(invoker base.Invoker, info *common.ServiceInfo)
 	intfName := URL.Interface()
 	if info != nil {
 		s.handleServiceWithInfo(intfName, invoker, info, hanOpts...)
+		s.registerHttpRules(intfName, info.InterfaceName)
 		s.saveServiceInfo(intfName, info)
 	} else {
 		s.compatHandleService(intfName, URL.Group(), URL.Version(), hanOpts...)
@@ -195,13 +238,30 @@ func getHanOpts(url *common.URL, tripleConf *global.TripleConfig) (hanOpts []tri
 	version := url.GetParam(constant.VersionKey, "")
 	hanOpts = append(hanOpts, tri.WithGroup(group), tri.WithVersion(version))
 
+	maxServerRecvMsgSize, maxServerSendMsgSize := getServerMsgSizes(url, tripleConf)
+	hanOpts = append(hanOpts, tri.WithReadMaxBytes(maxServerRecvMsgSize), tri.WithSendMaxBytes(maxServerSendMsgSize))
+
+	// a configured compression restricts the service to that algorithm only
+	if compression := url.GetParam(constant.CompressionKey, ""); compression != "" {
+		hanOpts = append(hanOpts, getCompressionHanOpts(compression)...)
+	}
+	if minBytes := url.GetParamInt(constant.CompressMinBytesKey, 0); minBytes > 0 {
+		hanOpts = append(hanOpts, tri.WithCompressMinBytes(int(minBytes)))
+	}
+
+	// todo:// open tracing
+
+	return hanOpts
+}
+
+// getServerMsgSizes returns the max sizes of the messages received and sent by the server.
+func getServerMsgSizes(url *common.URL, tripleConf *global.TripleConfig) (int, int) {
 	// Deprecated：use TripleConfig
 	// TODO: remove MaxServerSendMsgSize and MaxServerRecvMsgSize when version 4.0.0
 	maxServerRecvMsgSize := constant.DefaultMaxServerRecvMsgSize
 	if recvMsgSize, convertErr := humanize.ParseBytes(url.GetParam(constant.MaxServerRecvMsgSize, "")); convertErr == nil && recvMsgSize != 0 {
 		maxServerRecvMsgSize = int(recvMsgSize)
 	}
-	hanOpts = append(hanOpts, tri.WithReadMaxBytes(maxServerRecvMsgSize))
 
 	// Deprecated：use TripleConfig
 	// TODO: remove MaxServerSendMsgSize and MaxServerRecvMsgSize when version 4.0.0
@@ -209,10 +269,9 @@ func getHanOpts(url *common.URL, tripleConf *global.TripleConfig) (hanOpts []tri
 	if sendMsgSize, convertErr := humanize.ParseBytes(url.GetParam(constant.MaxServerSendMsgSize, "")); convertErr == nil && sendMsgSize != 0 {
 		maxServerSendMsgSize = int(sendMsgSize)
 	}
-	hanOpts = append(hanOpts, tri.WithSendMaxBytes(maxServerSendMsgSize))
 
 	if tripleConf == nil {
-		return hanOpts
+		return maxServerRecvMsgSize, maxServerSendMsgSize
 	}
 
 	if tripleConf.MaxServerRecvMsgSize != "" {
@@ -220,7 +279,6 @@ func getHanOpts(url *common.URL, tripleConf *global.TripleConfig) (hanOpts []tri
 		if recvMsgSize, convertErr := humanize.ParseBytes(tripleConf.MaxServerRecvMsgSize); convertErr == nil && recvMsgSize != 0 {
 			maxServerRecvMsgSize = int(recvMsgSize)
 		}
-		hanOpts = append(hanOpts, tri.WithReadMaxBytes(maxServerRecvMsgSize))
 	}
 
 	if tripleConf.MaxServerSendMsgSize != "" {
@@ -228,11 +286,59 @@ func getHanOpts(url *common.URL, tripleConf *global.TripleConfig) (hanOpts []tri
 		if sendMsgSize, convertErr := humanize.ParseBytes(tripleConf.MaxServerSendMsgSize); convertErr == nil && sendMsgSize != 0 {
 			maxServerSendMsgSize = int(sendMsgSize)
 		}
-		hanOpts = append(hanOpts, tri.WithSendMaxBytes(maxServerSendMsgSize))
 	}
 
-	// todo:// open tracing
+	return maxServerRecvMsgSize, maxServerSendMsgSize
+}
+
+// getSizeLimitHanOpts narrows the read and send max bytes of a unary method to its size limits,
+// counting the messages rejected by them.
+func getSizeLimitHanOpts(url *common.URL, methodName string) []tri.HandlerOption {
+	reqLimit := sizelimit.RequestLimit(url, constant.SideProvider, methodName)
+	respLimit := sizelimit.ResponseLimit(url, constant.SideProvider, methodName)
+	if reqLimit == nil && respLimit == nil {
+		return nil
+	}
+	var tripleConf *global.TripleConfig
+	if tripleConfRaw, ok := url.GetAttribute(constant.TripleConfigKey); ok {
+		tripleConf, _ = tripleConfRaw.(*global.TripleConfig)
+	}
+	maxRecvMsgSize, maxSendMsgSize := getServerMsgSizes(url, tripleConf)
+	return []tri.HandlerOption{
+		tri.WithReadMaxBytes(narrowMaxBytes(maxRecvMsgSize, reqLimit.Max())),
+		tri.WithSendMaxBytes(narrowMaxBytes(maxSendMsgSize, respLimit.Max())),
+		tri.WithMaxBytesObserver(func(send bool) {
+			if send {
+				respLimit.Report()
+			} else {
+				reqLimit.Report()
+			}
+		}),
+	}
+}
 
+// narrowMaxBytes returns the smaller one of the max bytes, 0 means unlimited.
+func narrowMaxBytes(max, limit int) int {
+	if limit > 0 && (max <= 0 || limit < max) {
+		return limit
+	}
+	return max
+}
+
+// getCompressionHanOpts unregisters every default compression algorithm except the
+// configured one. identity disables response compression entirely.
+func getCompressionHanOpts(compression string) []tri.HandlerOption {
+	supported := []string{constant.CompressionGzip, constant.CompressionZstd, constant.CompressionSnappy}
+	if compression != constant.CompressionIdentity && !slices.Contains(supported, compression) {
+		logger.Warnf("Unsupported compression %s, fall back to the default compression algorithms", compression)
+		return nil
+	}
+	var hanOpts []tri.HandlerOption
+	for _, name := range supported {
+		if name != compression {
+			hanOpts = append(hanOpts, tri.WithCompression(name, nil, nil))
+		}
+	}
 	return hanOpts
 }
 
@@ -270,18 +376,21 @@ func (s *Server) compatHandleService(interfaceName string, group, version string
 		s.compatSaveServiceInfo(ds.XXX_ServiceDesc())
 		// inject invoker, it has all invocation logics
 		ds.XXX_SetProxyImpl(invoker)
-		s.compatRegisterHandler(interfaceName, ds, opts...)
+		s.compatRegisterHandler(invoker.GetURL(), interfaceName, ds, opts...)
+		s.registerHttpRules(interfaceName, ds.XXX_ServiceDesc().ServiceName)
 	}
 }
 
-func (s *Server) compatRegisterHandler(interfaceName string, svc dubbo3.Dubbo3GrpcService, opts ...tri.HandlerOption) {
+func (s *Server) compatRegisterHandler(url *common.URL, interfaceName string, svc dubbo3.Dubbo3GrpcService, opts ...tri.HandlerOption) {
 	desc := svc.XXX_ServiceDesc()
 	// init unary handlers
 	for _, method := range desc.Methods {
 		// please refer to protocol/triple/internal/proto/triple_gen/greettriple for procedure examples
 		// error could be ignored because base is empty string
 		procedure := joinProcedure(interfaceName, method.MethodName)
-		_ = s.triServer.RegisterCompatUnaryHandler(procedure, method.MethodName, svc, tri.MethodHandler(method.Handler), opts...)
+		methodOpts := getMethodHanOpts(url, desc.ServiceName, method.MethodName, opts)
+		methodOpts = append(methodOpts, getSizeLimitHanOpts(url, method.MethodName)...)
+		_ = s.triServer.RegisterCompatUnaryHandler(procedure, method.MethodName, svc, tri.MethodHandler(method.Handler), methodOpts...)
 	}
 
 	// init stream handlers
@@ -307,8 +416,10 @@ func (s *Server) handleServiceWithInfo(interfaceName string, invoker base.Invoke
 	for _, method := range info.Methods {
 		m := method
 		procedure := joinProcedure(interfaceName, method.Name)
+		methodOpts := getMethodHanOpts(invoker.GetURL(), info.InterfaceName, method.Name, opts)
 		switch m.Type {
 		case constant.CallUnary:
+			methodOpts = append(methodOpts, getSizeLimitHanOpts(invoker.GetURL(), method.Name)...)
 			_ = s.triServer.RegisterUnaryHandler(
 				procedure,
 				m.ReqInitFunc,
@@ -329,6 +440,7 @@ func (s *Server) handleServiceWithInfo(interfaceName string, invoker base.Invoke
 					// inject attachments
 					ctx = context.WithValue(ctx, constant.AttachmentKey, attachments)
 					invo := invocation.NewRPCInvocation(m.Name, args, attachments)
+					invo.SetAttribute(constant.CallTypeKey, constant.CallUnary)
 					res := invoker.Invoke(ctx, invo)
 					// todo(DMwangnima): modify InfoInvoker to get a unified processing logic
 					// please refer to server/InfoInvoker.Invoke()
@@ -351,7 +463,7 @@ func (s *Server) handleServiceWithInfo(interfaceName string, invoker base.Invoke
 					}
 					return triResp, res.Error()
 				},
-				opts...,
+				methodOpts...,
 			)
 		case constant.CallClientStream:
 			_ = s.triServer.RegisterClientStreamHandler(
@@ -363,6 +475,7 @@ func (s *Server) handleServiceWithInfo(interfaceName string, invoker base.Invoke
 					// inject attachments
 					ctx = context.WithValue(ctx, constant.AttachmentKey, attachments)
 					invo := invocation.NewRPCInvocation(m.Name, args, attachments)
+					invo.SetAttribute(constant.CallTypeKey, constant.CallClientStream)
 					res := invoker.Invoke(ctx, invo)
 					if triResp, ok := res.Result().(*tri.Response); ok {
 						return triResp, res.Error()
@@ -371,7 +484,7 @@ func (s *Server) handleServiceWithInfo(interfaceName string, invoker base.Invoke
 					triResp := tri.NewResponse([]any{res.Result()})
 					return triResp, res.Error()
 				},
-				opts...,
+				methodOpts...,
 			)
 		case constant.CallServerStream:
 			_ = s.triServer.RegisterServerStreamHandler(
@@ -384,10 +497,11 @@ func (s *Server) handleServiceWithInfo(interfaceName string, invoker base.Invoke
 					// inject attachments
 					ctx = context.WithValue(ctx, constant.AttachmentKey, attachments)
 					invo := invocation.NewRPCInvocation(m.Name, args, attachments)
+					invo.SetAttribute(constant.CallTypeKey, constant.CallServerStream)
 					res := invoker.Invoke(ctx, invo)
 					return res.Error()
 				},
-				opts...,
+				methodOpts...,
 			)
 		case constant.CallBidiStream:
 			_ = s.triServer.RegisterBidiStreamHandler(
@@ -399,15 +513,74 @@ func (s *Server) handleServiceWithInfo(interfaceName string, invoker base.Invoke
 					// inject attachments
 					ctx = context.WithValue(ctx, constant.AttachmentKey, attachments)
 					invo := invocation.NewRPCInvocation(m.Name, args, attachments)
+					invo.SetAttribute(constant.CallTypeKey, constant.CallBidiStream)
 					res := invoker.Invoke(ctx, invo)
 					return res.Error()
 				},
-				opts...,
+				methodOpts...,
 			)
 		}
 	}
 }
 
+// getMethodHanOpts appends the handler options of a method to the ones of its service,
+// that is, the idempotency level declared in the proto IDL and the configured cache control.
+func getMethodHanOpts(url *common.URL, serviceName, methodName string, opts []tri.HandlerOption) []tri.HandlerOption {
+	hanOpts := make([]tri.HandlerOption, 0, len(opts)+2)
+	hanOpts = append(hanOpts, opts...)
+	if sd := findServiceDescriptor(serviceName); sd != nil {
+		if md := sd.Methods().ByName(protoreflect.Name(methodName)); md != nil {
+			options, _ := md.Options().(*descriptorpb.MethodOptions)
+			switch options.GetIdempotencyLevel() {
+			case descriptorpb.MethodOptions_NO_SIDE_EFFECTS:
+				hanOpts = append(hanOpts, tri.WithIdempotency(tri.IdempotencyNoSideEffects))
+			case descriptorpb.MethodOptions_IDEMPOTENT:
+				hanOpts = append(hanOpts, tri.WithIdempotency(tri.IdempotencyIdempotent))
+			}
+		}
+	}
+	if cacheControl := url.GetMethodParam(methodName, constant.CacheControlKey, ""); cacheControl != "" {
+		hanOpts = append(hanOpts, tri.WithCacheControl(cacheControl))
+	}
+	return hanOpts
+}
+
+// findServiceDescriptor looks up the descriptor of a proto service by its full name in the
+// global registry, returning nil for non-idl services.
+func findServiceDescriptor(serviceName string) protoreflect.ServiceDescriptor {
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return nil
+	}
+	sd, _ := desc.(protoreflect.ServiceDescriptor)
+	return sd
+}
+
+// registerHttpRules serves the RESTful routes described by the google.api.http options
+// of the proto service, which is looked up by its full name in the global registry.
+func (s *Server) registerHttpRules(interfaceName, serviceName string) {
+	sd := findServiceDescriptor(serviceName)
+	if sd == nil {
+		return
+	}
+	methods := sd.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		md := methods.Get(i)
+		rule, ok := proto.GetExtension(md.Options(), annotations.E_Http).(*annotations.HttpRule)
+		if !ok || rule == nil {
+			continue
+		}
+		if md.IsStreamingClient() || md.IsStreamingServer() {
+			logger.Warnf("TRIPLE Server ignores the http rule of streaming method %s", md.FullName())
+			continue
+		}
+		procedure := joinProcedure(interfaceName, string(md.Name()))
+		if err := s.triServer.RegisterHttpRule(procedure, md, rule); err != nil {
+			logger.Errorf("TRIPLE Server failed to register the http rule of %s: %v", md.FullName(), err)
+		}
+	}
+}
+
 func (s *Server) saveServiceInfo(interfaceName string, info *common.ServiceInfo) {
 	ret := grpc.ServiceInfo{}
 	ret.Methods = make([]grpc.MethodInfo, 0, len(info.Methods))
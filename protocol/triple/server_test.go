@@ -18,17 +18,28 @@
 package triple
 
 import (
+	"context"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
 import (
 	"github.com/stretchr/testify/assert"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/global"
+	tri "dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
 )
 
 func Test_generateAttachments(t *testing.T) {
@@ -145,3 +156,87 @@ func TestServer_ProtocolSelection(t *testing.T) {
 		})
 	}
 }
+
+func Test_getMethodHanOpts(t *testing.T) {
+	method := func(name string, level descriptorpb.MethodOptions_IdempotencyLevel) *descriptorpb.MethodDescriptorProto {
+		return &descriptorpb.MethodDescriptorProto{
+			Name:       proto.String(name),
+			InputType:  proto.String(".google.protobuf.Empty"),
+			OutputType: proto.String(".google.protobuf.Empty"),
+			Options:    &descriptorpb.MethodOptions{IdempotencyLevel: level.Enum()},
+		}
+	}
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("triple_method_options_test.proto"),
+		Package:    proto.String("triple.test"),
+		Dependency: []string{"google/protobuf/empty.proto"},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("QueryService"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				method("Get", descriptorpb.MethodOptions_NO_SIDE_EFFECTS),
+				method("Put", descriptorpb.MethodOptions_IDEMPOTENCY_UNKNOWN),
+			},
+		}},
+	}, protoregistry.GlobalFiles)
+	assert.Nil(t, err)
+	assert.Nil(t, protoregistry.GlobalFiles.RegisterFile(fd))
+
+	url := common.NewURLWithOptions(
+		common.WithParamsValue("methods.Get."+constant.CacheControlKey, "public, max-age=60"),
+		common.WithParamsValue("methods.Put."+constant.CacheControlKey, "public, max-age=60"),
+	)
+	get := func(methodName string) *httptest.ResponseRecorder {
+		opts := getMethodHanOpts(url, "triple.test.QueryService", methodName, nil)
+		handler := tri.NewUnaryHandler(
+			"/triple.test.QueryService/"+methodName,
+			func() any { return &emptypb.Empty{} },
+			func(ctx context.Context, req *tri.Request) (*tri.Response, error) {
+				return tri.NewResponse(&emptypb.Empty{}), nil
+			},
+			opts...,
+		)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/triple.test.QueryService/"+methodName+"?encoding=json&message={}", nil))
+		return w
+	}
+
+	w := get("Get")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=60", w.Header().Get("Cache-Control"))
+	// methods with side effects only accept POST
+	w = get("Put")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	assert.Len(t, getMethodHanOpts(url, "triple.test.UnknownService", "Get", nil), 1)
+	assert.Len(t, getMethodHanOpts(common.NewURLWithOptions(), "triple.test.QueryService", "Put", nil), 0)
+}
+
+func Test_getHanOpts_Compression(t *testing.T) {
+	url := common.NewURLWithOptions(
+		common.WithParamsValue(constant.CompressionKey, constant.CompressionZstd),
+		common.WithParamsValue(constant.CompressMinBytesKey, "64"),
+	)
+	handler := tri.NewUnaryHandler(
+		"/triple.test.CompressionService/Echo",
+		func() any { return &emptypb.Empty{} },
+		func(ctx context.Context, req *tri.Request) (*tri.Response, error) {
+			return tri.NewResponse(&emptypb.Empty{}), nil
+		},
+		getHanOpts(url, nil)...,
+	)
+	post := func(encoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/triple.test.CompressionService/Echo", strings.NewReader("{}"))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Encoding", encoding)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("gzip")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "supported encodings are zstd")
+	assert.Equal(t, constant.CompressionZstd, w.Header().Get("Accept-Encoding"))
+	assert.Len(t, getCompressionHanOpts(constant.CompressionIdentity), 3)
+	assert.Len(t, getCompressionHanOpts("br"), 0)
+}
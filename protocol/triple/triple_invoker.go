@@ -215,6 +215,9 @@ func parseAttachments(ctx context.Context, url *common.URL, invocation base.Invo
 			invocation.SetAttachment(key, val)
 		}
 	}
+	if app := url.GetParam(constant.ApplicationKey, ""); len(app) > 0 {
+		invocation.SetAttachment(constant.RemoteApplicationKey, app)
+	}
 }
 
 // IsAvailable get available status
@@ -39,6 +39,9 @@ type Client struct {
 	callUnary      func(context.Context, *Request, *Response) error
 	protocolClient protocolClient
 	err            error
+
+	// the protocol client of the streaming calls, which is protocolClient unless WithStreamHTTPClient is set
+	streamProtocolClient protocolClient
 }
 
 // NewClient constructs a new Client.
@@ -50,29 +53,39 @@ func NewClient(httpClient HTTPClient, url string, options ...ClientOption) *Clie
 		return client
 	}
 	client.config = config
-	protocolCli, protocolErr := client.config.Protocol.NewClient(
-		&protocolClientParams{
-			CompressionName: config.RequestCompressionName,
-			CompressionPools: newReadOnlyCompressionPools(
-				config.CompressionPools,
-				config.CompressionNames,
-			),
-			Codec:            config.Codec,
-			Protobuf:         config.protobuf(),
-			CompressMinBytes: config.CompressMinBytes,
-			HTTPClient:       httpClient,
-			URL:              config.URL,
-			BufferPool:       config.BufferPool,
-			ReadMaxBytes:     config.ReadMaxBytes,
-			SendMaxBytes:     config.SendMaxBytes,
-			GetURLMaxBytes:   config.GetURLMaxBytes,
-		},
-	)
+	params := &protocolClientParams{
+		CompressionName: config.RequestCompressionName,
+		CompressionPools: newReadOnlyCompressionPools(
+			config.CompressionPools,
+			config.CompressionNames,
+		),
+		Codec:            config.Codec,
+		Protobuf:         config.protobuf(),
+		CompressMinBytes: config.CompressMinBytes,
+		HTTPClient:       httpClient,
+		URL:              config.URL,
+		BufferPool:       config.BufferPool,
+		ReadMaxBytes:     config.ReadMaxBytes,
+		SendMaxBytes:     config.SendMaxBytes,
+		EnableGet:        config.EnableGet,
+		GetURLMaxBytes:   config.GetURLMaxBytes,
+		GetUseFallback:   config.GetUseFallback,
+	}
+	protocolCli, protocolErr := client.config.Protocol.NewClient(params)
 	if protocolErr != nil {
 		client.err = protocolErr
 		return client
 	}
 	client.protocolClient = protocolCli
+	client.streamProtocolClient = protocolCli
+	if config.StreamHTTPClient != nil {
+		streamParams := *params
+		streamParams.HTTPClient = config.StreamHTTPClient
+		if client.streamProtocolClient, protocolErr = client.config.Protocol.NewClient(&streamParams); protocolErr != nil {
+			client.err = protocolErr
+			return client
+		}
+	}
 	// Rather than applying unary interceptors along the hot path, we can do it
 	// once at client creation.
 	unarySpec := config.newSpec(StreamTypeUnary)
@@ -82,6 +95,7 @@ func NewClient(httpClient HTTPClient, url string, options ...ClientOption) *Clie
 		// We want the user to continue to call Receive in those cases to get the
 		// full error from the server-side.
 		if err := conn.Send(request.Any()); err != nil && !errors.Is(err, io.EOF) {
+			config.observeMaxBytes(err, true)
 			// for HTTP/1.1 case, CloseRequest must happen before CloseResponse
 			// since HTTP/1.1 is of request-response type
 			_ = conn.CloseRequest()
@@ -93,6 +107,7 @@ func NewClient(httpClient HTTPClient, url string, options ...ClientOption) *Clie
 			return err
 		}
 		if err := receiveUnaryResponse(conn, response); err != nil {
+			config.observeMaxBytes(err, false)
 			_ = conn.CloseResponse()
 			return err
 		}
@@ -175,8 +190,8 @@ func (c *Client) newConn(ctx context.Context, streamType StreamType) StreamingCl
 		header := make(http.Header, 8) // arbitrary power of two, prevent immediate resizing
 		mergeHeaders(header, ExtractFromOutgoingContext(ctx))
 		applyGroupVersionHeaders(header, c.config)
-		c.protocolClient.WriteRequestHeader(streamType, header)
-		return c.protocolClient.NewConn(ctx, spec, header)
+		c.streamProtocolClient.WriteRequestHeader(streamType, header)
+		return c.streamProtocolClient.NewConn(ctx, spec, header)
 	}
 	if interceptor := c.config.Interceptor; interceptor != nil {
 		newConn = interceptor.WrapStreamingClient(newConn)
@@ -197,8 +212,11 @@ type clientConfig struct {
 	BufferPool             *bufferPool
 	ReadMaxBytes           int
 	SendMaxBytes           int
+	MaxBytesObserver       func(send bool)
+	EnableGet              bool
 	GetURLMaxBytes         int
 	GetUseFallback         bool
+	StreamHTTPClient       HTTPClient
 	IdempotencyLevel       IdempotencyLevel
 	Timeout                time.Duration
 	Group                  string
@@ -251,6 +269,14 @@ func (c *clientConfig) protobuf() Codec {
 	return &protoBinaryCodec{}
 }
 
+// observeMaxBytes reports err to the observer if the request or the response exceeds the
+// send or read max bytes of the client.
+func (c *clientConfig) observeMaxBytes(err error, send bool) {
+	if c.MaxBytesObserver != nil && isMaxBytesError(err) {
+		c.MaxBytesObserver(send)
+	}
+}
+
 func (c *clientConfig) newSpec(t StreamType) Spec {
 	return Spec{
 		StreamType:       t,
@@ -20,6 +20,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -75,6 +76,63 @@ func TestNewClient_InitFailure(t *testing.T) {
 	})
 }
 
+func TestClient_HTTPGet(t *testing.T) {
+	t.Parallel()
+	var (
+		mu      sync.Mutex
+		methods []string
+	)
+	takeMethods := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		taken := methods
+		methods = nil
+		return taken
+	}
+	mux := http.NewServeMux()
+	mux.Handle(pingv1connect.NewPingServiceHandler(pingServer{}))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		methods = append(methods, r.Method)
+		mu.Unlock()
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	ping := func(t *testing.T, text string, opts ...triple.ClientOption) error {
+		t.Helper()
+		client := pingv1connect.NewPingServiceClient(
+			server.Client(),
+			server.URL,
+			triple.WithTriple(),
+			triple.WithClientOptions(opts...),
+		)
+		resp := &pingv1.PingResponse{}
+		err := client.Ping(context.Background(), triple.NewRequest(&pingv1.PingRequest{Number: 42, Text: text}), triple.NewResponse(resp))
+		if err == nil {
+			assert.Equal(t, resp.Number, int64(42))
+			assert.Equal(t, resp.Text, text)
+		}
+		return err
+	}
+	short, long := "get", strings.Repeat("get", 512)
+
+	assert.Nil(t, ping(t, short, triple.WithHTTPGet()))
+	assert.Nil(t, ping(t, short, triple.WithHTTPGet(), triple.WithProtoJSON()))
+	// the procedures with side effects are still called with POST
+	client := pingv1connect.NewPingServiceClient(server.Client(), server.URL, triple.WithTriple(), triple.WithHTTPGet())
+	err := client.Fail(context.Background(), triple.NewRequest(&pingv1.FailRequest{Code: int32(triple.CodeNotFound)}), triple.NewResponse(&pingv1.FailResponse{}))
+	assert.Equal(t, triple.CodeOf(err), triple.CodeNotFound)
+	assert.Equal(t, takeMethods(), []string{http.MethodGet, http.MethodGet, http.MethodPost})
+
+	// too long URLs are compressed, then fall back to POST or fail
+	assert.Nil(t, ping(t, long, triple.WithHTTPGet(), triple.WithHTTPGetMaxURLSize(512, false), triple.WithSendGzip()))
+	assert.Nil(t, ping(t, strings.Repeat("-", 1024), triple.WithHTTPGet(), triple.WithHTTPGetMaxURLSize(512, true)))
+	assert.Equal(t, takeMethods(), []string{http.MethodGet, http.MethodPost})
+	err = ping(t, strings.Repeat("-", 1024), triple.WithHTTPGet(), triple.WithHTTPGetMaxURLSize(512, false))
+	assert.Equal(t, triple.CodeOf(err), triple.CodeResourceExhausted)
+}
+
 func TestClientPeer(t *testing.T) {
 	t.Parallel()
 	mux := http.NewServeMux()
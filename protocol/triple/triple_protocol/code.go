@@ -219,10 +219,10 @@ func (c *Code) UnmarshalText(data []byte) error {
 	return fmt.Errorf("invalid code %q", dataStr)
 }
 
-// CodeOf returns the error's status code if it is or wraps an [*Error] and
-// [CodeUnknown] otherwise.
+// CodeOf returns the error's status code if it is or wraps an [*Error] or a
+// gRPC status error, and [CodeUnknown] otherwise.
 func CodeOf(err error) Code {
-	if tripleErr, ok := asError(err); ok {
+	if tripleErr, ok := FromError(err); ok {
 		return tripleErr.Code()
 	}
 	return CodeUnknown
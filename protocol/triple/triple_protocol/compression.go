@@ -23,8 +23,16 @@ import (
 	"sync"
 )
 
+import (
+	"github.com/golang/snappy"
+
+	"github.com/klauspost/compress/zstd"
+)
+
 const (
 	compressionGzip     = "gzip"
+	compressionZstd     = "zstd"
+	compressionSnappy   = "snappy"
 	compressionIdentity = "identity"
 )
 
@@ -213,3 +221,47 @@ func (m *namedCompressionPools) Contains(name string) bool {
 func (m *namedCompressionPools) CommaSeparatedNames() string {
 	return m.commaSeparatedNames
 }
+
+// zstdDecompressor adapts [*zstd.Decoder] to the Decompressor interface. The
+// decoder is pooled and reused, so Close must not release its resources.
+type zstdDecompressor struct {
+	*zstd.Decoder
+}
+
+func newZstdDecompressor() Decompressor {
+	// zstd.NewReader only fails on invalid options.
+	decoder, _ := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+	return &zstdDecompressor{Decoder: decoder}
+}
+
+func (d *zstdDecompressor) Close() error {
+	return nil
+}
+
+func newZstdCompressor() Compressor {
+	// zstd.NewWriter only fails on invalid options.
+	encoder, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+	return encoder
+}
+
+// snappyDecompressor adapts [*snappy.Reader] to the Decompressor interface.
+type snappyDecompressor struct {
+	*snappy.Reader
+}
+
+func newSnappyDecompressor() Decompressor {
+	return &snappyDecompressor{Reader: snappy.NewReader(nil)}
+}
+
+func (d *snappyDecompressor) Close() error {
+	return nil
+}
+
+func (d *snappyDecompressor) Reset(reader io.Reader) error {
+	d.Reader.Reset(reader)
+	return nil
+}
+
+func newSnappyCompressor() Compressor {
+	return snappy.NewBufferedWriter(io.Discard)
+}
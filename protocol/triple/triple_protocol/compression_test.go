@@ -15,6 +15,7 @@
 package triple_protocol
 
 import (
+	"bytes"
 	"context"
 	"net/http"
 	"net/http/httptest"
@@ -135,35 +136,56 @@ func TestHandlerCompressionOptionTest(t *testing.T) {
 	t.Run("defaults", func(t *testing.T) {
 		t.Parallel()
 		config := newHandlerConfig(testProc, nil)
-		assert.Equal(t, config.CompressionNames, []string{compressionGzip})
+		assert.Equal(t, config.CompressionNames, []string{compressionSnappy, compressionZstd, compressionGzip})
 		checkPools(t, config)
 	})
 	t.Run("WithCompression", func(t *testing.T) {
 		t.Parallel()
 		opts := []HandlerOption{WithCompression("foo", dummyDecompressCtor, dummyCompressCtor)}
 		config := newHandlerConfig(testProc, opts)
-		assert.Equal(t, config.CompressionNames, []string{compressionGzip, "foo"})
+		assert.Equal(t, config.CompressionNames, []string{compressionSnappy, compressionZstd, compressionGzip, "foo"})
 		checkPools(t, config)
 	})
 	t.Run("WithCompression-empty-name-noop", func(t *testing.T) {
 		t.Parallel()
 		opts := []HandlerOption{WithCompression("", dummyDecompressCtor, dummyCompressCtor)}
 		config := newHandlerConfig(testProc, opts)
-		assert.Equal(t, config.CompressionNames, []string{compressionGzip})
+		assert.Equal(t, config.CompressionNames, []string{compressionSnappy, compressionZstd, compressionGzip})
 		checkPools(t, config)
 	})
 	t.Run("WithCompression-nil-ctors-noop", func(t *testing.T) {
 		t.Parallel()
 		opts := []HandlerOption{WithCompression("foo", nil, nil)}
 		config := newHandlerConfig(testProc, opts)
-		assert.Equal(t, config.CompressionNames, []string{compressionGzip})
+		assert.Equal(t, config.CompressionNames, []string{compressionSnappy, compressionZstd, compressionGzip})
 		checkPools(t, config)
 	})
 	t.Run("WithCompression-nil-ctors-unregisters", func(t *testing.T) {
 		t.Parallel()
 		opts := []HandlerOption{WithCompression("gzip", nil, nil)}
 		config := newHandlerConfig(testProc, opts)
-		assert.Equal(t, config.CompressionNames, []string(nil))
+		assert.Equal(t, config.CompressionNames, []string{compressionSnappy, compressionZstd})
 		checkPools(t, config)
 	})
 }
+
+func TestCompressionPoolRoundTrip(t *testing.T) {
+	t.Parallel()
+	payload := bytes.Repeat([]byte("triple compression payload "), 256)
+	for _, opt := range []Option{withGzip(), withZstd(), withSnappy()} {
+		option, ok := opt.(*compressionOption)
+		assert.True(t, ok)
+		pool := option.CompressionPool
+		t.Run(option.Name, func(t *testing.T) {
+			t.Parallel()
+			for i := 0; i < 2; i++ { // exercise pooled reuse
+				compressed := &bytes.Buffer{}
+				assert.Nil(t, pool.Compress(compressed, bytes.NewBuffer(payload)))
+				assert.True(t, compressed.Len() < len(payload))
+				decompressed := &bytes.Buffer{}
+				assert.Nil(t, pool.Decompress(decompressed, compressed, 0))
+				assert.Equal(t, decompressed.Bytes(), payload)
+			}
+		})
+	}
+}
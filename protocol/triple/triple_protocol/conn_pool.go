@@ -0,0 +1,395 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package triple_protocol
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+import (
+	"golang.org/x/net/http2"
+)
+
+const (
+	// the max interval of reaping the idle connections and reporting the stats of a ConnPool
+	connPoolMaxReapInterval = 10 * time.Second
+	// how long the active streams may take to finish once a ConnPool is closed
+	connPoolShutdownTimeout = 30 * time.Second
+	// the dial timeout of a ConnPool without the one of the settings, a dial is shared by the waiting streams
+	// and bound to no request, so it must never hang
+	connPoolDefaultDialTimeout = 10 * time.Second
+)
+
+// ConnPoolStats is the usage of the connections of a ConnPool to an address.
+type ConnPoolStats struct {
+	Addr        string
+	Connections int
+	// ActiveStreams counts the streams open or waiting for a free stream on the connections.
+	ActiveStreams int
+	// MaxStreams is the sum of the max concurrent streams advertised by the server on the connections.
+	MaxStreams int
+}
+
+// ConnPool is the http2.ClientConnPool keeping up to MaxConnections of the settings to each address.
+// A stream goes to the least busy connection, and another connection is opened in the background once
+// the streams of every connection reach ConnOpenThreshold of the max concurrent streams of the server.
+// The connections but the first one are closed after idling for ConnIdleTimeout.
+type ConnPool struct {
+	transport   *http2.Transport
+	maxConns    int
+	threshold   float64
+	idleTimeout time.Duration
+	dialTimeout time.Duration
+	report      func(ConnPoolStats)
+
+	mu      sync.Mutex
+	conns   map[string][]*pooledConn
+	dialing map[string]*connPoolDial
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+type pooledConn struct {
+	*http2.ClientConn
+	opened time.Time
+}
+
+// idleSince returns when the connection became idle, or when it was opened if it has never been used.
+func (c *pooledConn) idleSince(state http2.ClientConnState) time.Time {
+	if state.LastIdle.IsZero() {
+		return c.opened
+	}
+	return state.LastIdle
+}
+
+// connPoolDial is an in-flight dial, which is shared by the streams waiting for a connection.
+type connPoolDial struct {
+	done chan struct{}
+	err  error
+}
+
+// NewConnPool creates the pool of the connections dialed by transport with the settings, and sets it
+// as the ConnPool of transport. report is called with the stats of each address once the connections
+// change and periodically, it may be nil.
+func NewConnPool(transport *http2.Transport, settings *TransportSettings, report func(ConnPoolStats)) *ConnPool {
+	p := &ConnPool{
+		transport:   transport,
+		maxConns:    settings.MaxConnections,
+		threshold:   settings.ConnOpenThreshold,
+		idleTimeout: settings.ConnIdleTimeout,
+		dialTimeout: settings.DialTimeout,
+		report:      report,
+		conns:       make(map[string][]*pooledConn),
+		dialing:     make(map[string]*connPoolDial),
+		done:        make(chan struct{}),
+	}
+	if p.maxConns <= 0 {
+		p.maxConns = defaultMaxConnections
+	}
+	if p.threshold <= 0 {
+		p.threshold = defaultConnOpenThreshold
+	}
+	if p.idleTimeout <= 0 {
+		p.idleTimeout = defaultConnIdleTimeout
+	}
+	if p.dialTimeout <= 0 {
+		p.dialTimeout = connPoolDefaultDialTimeout
+	}
+	// the streams beyond the limit of the server wait on the connection, the pool decides when to dial
+	transport.StrictMaxConcurrentStreams = true
+	transport.ConnPool = p
+	go p.reapLoop()
+	return p
+}
+
+// GetClientConn implements http2.ClientConnPool.
+func (p *ConnPool) GetClientConn(req *http.Request, addr string) (*http2.ClientConn, error) {
+	var dialErr error
+	for {
+		p.mu.Lock()
+		cc, streams, maxStreams := p.leastBusyLocked(addr)
+		// the max concurrent streams is unknown until the settings of the server arrive
+		busy := maxStreams > 0 && float64(streams+1) > p.threshold*float64(maxStreams)
+		full := maxStreams > 0 && streams >= maxStreams
+		call := p.dialing[addr]
+		if call == nil && dialErr == nil && (cc == nil || busy && len(p.conns[addr]) < p.maxConns) {
+			call = p.dialLocked(addr)
+		}
+		// a full connection is used only if no other connection is coming
+		if cc != nil && (!full || call == nil) {
+			reserved := cc.ReserveNewRequest()
+			p.mu.Unlock()
+			if reserved {
+				return cc, nil
+			}
+			// the connection is closing, pick another one
+			continue
+		}
+		p.mu.Unlock()
+		if call == nil {
+			return nil, dialErr
+		}
+
+		select {
+		case <-call.done:
+			// queue on the existing connections if the dial has failed
+			dialErr = call.err
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// MarkDead implements http2.ClientConnPool.
+func (p *ConnPool) MarkDead(cc *http2.ClientConn) {
+	p.mu.Lock()
+	var removed []string
+	for addr, conns := range p.conns {
+		for i, c := range conns {
+			if c.ClientConn == cc {
+				p.conns[addr] = append(conns[:i:i], conns[i+1:]...)
+				removed = append(removed, addr)
+				break
+			}
+		}
+	}
+	p.mu.Unlock()
+	for _, addr := range removed {
+		p.reportAddr(addr)
+	}
+}
+
+// leastBusyLocked returns the connection to addr with the least streams, its streams and the max
+// concurrent streams of the server, which is zero if it's unknown.
+func (p *ConnPool) leastBusyLocked(addr string) (*http2.ClientConn, int, int) {
+	var (
+		leastBusy    *http2.ClientConn
+		leastStreams int
+		maxStreams   int
+	)
+	for _, cc := range p.conns[addr] {
+		if !cc.CanTakeNewRequest() {
+			continue
+		}
+		state := cc.State()
+		streams := state.StreamsActive + state.StreamsReserved + state.StreamsPending
+		if leastBusy == nil || streams < leastStreams {
+			leastBusy, leastStreams, maxStreams = cc.ClientConn, streams, int(state.MaxConcurrentStreams)
+		}
+	}
+	return leastBusy, leastStreams, maxStreams
+}
+
+// dialLocked dials a connection to addr in the background, which is added to the pool once it's ready.
+// The dial is not bound to the context of any request, since the connection is shared, but to the dial timeout.
+func (p *ConnPool) dialLocked(addr string) *connPoolDial {
+	call := &connPoolDial{done: make(chan struct{})}
+	p.dialing[addr] = call
+	go func() {
+		cc, err := p.dial(addr)
+		p.mu.Lock()
+		delete(p.dialing, addr)
+		select {
+		case <-p.done:
+			if err == nil {
+				_ = cc.Close()
+				err = http.ErrServerClosed
+			}
+		default:
+			if err == nil {
+				p.conns[addr] = append(p.conns[addr], &pooledConn{ClientConn: cc, opened: time.Now()})
+			}
+		}
+		call.err = err
+		p.mu.Unlock()
+		close(call.done)
+		p.reportAddr(addr)
+	}()
+	return call
+}
+
+func (p *ConnPool) dial(addr string) (*http2.ClientConn, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	tlsConf := &tls.Config{}
+	if p.transport.TLSClientConfig != nil {
+		tlsConf = p.transport.TLSClientConfig.Clone()
+	}
+	if tlsConf.ServerName == "" {
+		tlsConf.ServerName = host
+	}
+	tlsConf.NextProtos = []string{http2.NextProtoTLS}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.dialTimeout)
+	defer cancel()
+	var conn net.Conn
+	if dial := p.transport.DialTLSContext; dial != nil {
+		conn, err = dial(ctx, "tcp", addr, tlsConf)
+	} else {
+		conn, err = (&tls.Dialer{Config: tlsConf}).DialContext(ctx, "tcp", addr)
+		if err == nil {
+			if proto := conn.(*tls.Conn).ConnectionState().NegotiatedProtocol; proto != http2.NextProtoTLS {
+				_ = conn.Close()
+				return nil, fmt.Errorf("http2: unexpected ALPN protocol %q; want %q", proto, http2.NextProtoTLS)
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	cc, err := p.transport.NewClientConn(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return cc, nil
+}
+
+func (p *ConnPool) reapLoop() {
+	interval := p.idleTimeout / 2
+	if interval > connPoolMaxReapInterval {
+		interval = connPoolMaxReapInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			p.reap()
+			for _, stats := range p.Stats() {
+				if p.report != nil {
+					p.report(stats)
+				}
+			}
+		}
+	}
+}
+
+// reap closes the connections idle for the idle timeout, the first connection to each address is kept.
+// The addresses left without connections, e.g. of the departed providers, are forgotten and reported
+// without connections once more.
+func (p *ConnPool) reap() {
+	var (
+		idle      []*pooledConn
+		forgotten []string
+	)
+	p.mu.Lock()
+	for addr, conns := range p.conns {
+		kept := conns[:0]
+		for _, cc := range conns {
+			state := cc.State()
+			if state.Closed {
+				continue
+			}
+			streams := state.StreamsActive + state.StreamsReserved + state.StreamsPending
+			if len(kept) > 0 && streams == 0 && time.Since(cc.idleSince(state)) > p.idleTimeout {
+				idle = append(idle, cc)
+				continue
+			}
+			kept = append(kept, cc)
+		}
+		if len(kept) == 0 && p.dialing[addr] == nil {
+			delete(p.conns, addr)
+			forgotten = append(forgotten, addr)
+			continue
+		}
+		p.conns[addr] = kept
+	}
+	p.mu.Unlock()
+	for _, cc := range idle {
+		_ = cc.Close()
+	}
+	if p.report != nil {
+		for _, addr := range forgotten {
+			p.report(ConnPoolStats{Addr: addr})
+		}
+	}
+}
+
+// Stats returns the usage of the connections to each address.
+func (p *ConnPool) Stats() []ConnPoolStats {
+	p.mu.Lock()
+	addrs := make([]string, 0, len(p.conns))
+	for addr := range p.conns {
+		addrs = append(addrs, addr)
+	}
+	p.mu.Unlock()
+	sort.Strings(addrs)
+
+	stats := make([]ConnPoolStats, 0, len(addrs))
+	for _, addr := range addrs {
+		stats = append(stats, p.statsOf(addr))
+	}
+	return stats
+}
+
+func (p *ConnPool) statsOf(addr string) ConnPoolStats {
+	p.mu.Lock()
+	conns := append([]*pooledConn(nil), p.conns[addr]...)
+	p.mu.Unlock()
+	stats := ConnPoolStats{Addr: addr, Connections: len(conns)}
+	for _, cc := range conns {
+		state := cc.State()
+		stats.ActiveStreams += state.StreamsActive + state.StreamsPending
+		stats.MaxStreams += int(state.MaxConcurrentStreams)
+	}
+	return stats
+}
+
+func (p *ConnPool) reportAddr(addr string) {
+	if p.report != nil {
+		p.report(p.statsOf(addr))
+	}
+}
+
+// Close stops reaping and shuts the connections down in the background once their active streams
+// finish, the pool must not be used any more.
+func (p *ConnPool) Close() {
+	p.closeOnce.Do(func() {
+		close(p.done)
+		p.mu.Lock()
+		conns := p.conns
+		p.conns = make(map[string][]*pooledConn)
+		p.mu.Unlock()
+		for addr, cs := range conns {
+			for _, cc := range cs {
+				go func(cc *pooledConn) {
+					ctx, cancel := context.WithTimeout(context.Background(), connPoolShutdownTimeout)
+					defer cancel()
+					if err := cc.Shutdown(ctx); err != nil {
+						_ = cc.Close()
+					}
+				}(cc)
+			}
+			if p.report != nil {
+				p.report(ConnPoolStats{Addr: addr})
+			}
+		}
+	})
+}
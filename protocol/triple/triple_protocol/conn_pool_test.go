@@ -0,0 +1,173 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package triple_protocol
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"golang.org/x/net/http2"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/global"
+)
+
+func TestConnPool(t *testing.T) {
+	release := make(chan struct{})
+	srv := NewServer("", &global.TripleConfig{MaxConcurrentStreams: 2})
+	err := srv.RegisterUnaryHandler("/greet.GreetService/Greet", func() any { return &wrapperspb.StringValue{} },
+		func(ctx context.Context, req *Request) (*Response, error) {
+			if req.Msg.(*wrapperspb.StringValue).GetValue() == "wait" {
+				<-release
+			}
+			return NewResponse(wrapperspb.String("hello")), nil
+		})
+	assert.Nil(t, err)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	go func() {
+		_ = srv.Serve(lis, nil)
+	}()
+	defer srv.Stop()
+
+	settings := &TransportSettings{
+		MaxConnections:    3,
+		ConnOpenThreshold: 0.5,
+		ConnIdleTimeout:   100 * time.Millisecond,
+	}
+	transport := &http2.Transport{AllowHTTP: true}
+	settings.ConfigureHTTP2Transport(transport)
+	var (
+		mu       sync.Mutex
+		reported ConnPoolStats
+	)
+	pool := NewConnPool(transport, settings, func(stats ConnPoolStats) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = stats
+	})
+	defer pool.Close()
+	client := NewClient(&http.Client{Transport: transport}, "http://"+lis.Addr().String()+"/greet.GreetService/Greet")
+	call := func(name string) error {
+		return client.CallUnary(context.Background(), NewRequest(wrapperspb.String(name)), NewResponse(&wrapperspb.StringValue{}))
+	}
+
+	// learn the max concurrent streams of the server
+	assert.Nil(t, call("dubbo"))
+	assert.Equal(t, 1, pool.Stats()[0].Connections)
+
+	// the busy connections make the pool open more, up to the max connections
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, call("wait"))
+		}()
+	}
+	assert.Eventually(t, func() bool {
+		stats := pool.Stats()[0]
+		return stats.Connections == 3 && stats.ActiveStreams == 6 && stats.MaxStreams == 6
+	}, 5*time.Second, 10*time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// the idle connections but the first one are reaped
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return pool.Stats()[0].Connections == 1 && reported.Connections == 1 && reported.ActiveStreams == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Nil(t, call("dubbo"))
+}
+
+func TestConnPoolForgetDepartedAddr(t *testing.T) {
+	srv := NewServer("", nil)
+	err := srv.RegisterUnaryHandler("/greet.GreetService/Greet", func() any { return &wrapperspb.StringValue{} },
+		func(ctx context.Context, req *Request) (*Response, error) {
+			return NewResponse(wrapperspb.String("hello")), nil
+		})
+	assert.Nil(t, err)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	go func() {
+		_ = srv.Serve(lis, nil)
+	}()
+
+	settings := &TransportSettings{ConnIdleTimeout: 100 * time.Millisecond}
+	transport := &http2.Transport{AllowHTTP: true}
+	settings.ConfigureHTTP2Transport(transport)
+	var (
+		mu       sync.Mutex
+		reported []ConnPoolStats
+	)
+	pool := NewConnPool(transport, settings, func(stats ConnPoolStats) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = append(reported, stats)
+	})
+	defer pool.Close()
+	client := NewClient(&http.Client{Transport: transport}, "http://"+lis.Addr().String()+"/greet.GreetService/Greet")
+	assert.Nil(t, client.CallUnary(context.Background(), NewRequest(wrapperspb.String("dubbo")), NewResponse(&wrapperspb.StringValue{})))
+	assert.Len(t, pool.Stats(), 1)
+
+	// the address of the departed provider is reported without connections and forgotten
+	srv.Stop()
+	pool.mu.Lock()
+	cc := pool.conns[lis.Addr().String()][0]
+	pool.mu.Unlock()
+	assert.Nil(t, cc.Close())
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(pool.Stats()) == 0 && reported[len(reported)-1].Connections == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestConnPoolDialTimeout(t *testing.T) {
+	transport := &http2.Transport{
+		// a dial hanging until its context is done
+		DialTLSContext: func(ctx context.Context, _, _ string, _ *tls.Config) (net.Conn, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+	pool := NewConnPool(transport, &TransportSettings{DialTimeout: 50 * time.Millisecond}, nil)
+	defer pool.Close()
+
+	req, err := http.NewRequest(http.MethodPost, "https://127.0.0.1:1", nil)
+	assert.Nil(t, err)
+	_, err = pool.GetClientConn(req, "127.0.0.1:1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// the dial of a pool is bounded without a dial timeout too
+	pool = NewConnPool(&http2.Transport{}, &TransportSettings{}, nil)
+	defer pool.Close()
+	assert.Equal(t, connPoolDefaultDialTimeout, pool.dialTimeout)
+}
@@ -0,0 +1,193 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package triple_protocol
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/global"
+)
+
+var (
+	// the request headers used by the triple, gRPC and gRPC-Web protocols
+	corsDefaultAllowHeaders = []string{
+		headerContentType,
+		headerXUserAgent,
+		"X-Grpc-Web",
+		grpcHeaderTimeout,
+		grpcHeaderCompression,
+		grpcHeaderAcceptCompression,
+		tripleHeaderTimeout,
+		tripleHeaderProtocolVersion,
+		TripleServiceGroup,
+		TripleServiceVersion,
+	}
+	// the response headers and trailers carrying the status of gRPC and gRPC-Web
+	corsDefaultExposeHeaders = []string{
+		grpcHeaderStatus,
+		grpcHeaderMessage,
+		grpcHeaderDetails,
+		grpcHeaderCompression,
+	}
+	corsDefaultAllowMethods = []string{http.MethodPost, http.MethodGet, http.MethodOptions}
+)
+
+// corsHandler answers the preflight requests of browsers and adds the CORS headers to the
+// responses of the allowed origins, so browsers can call the triple services without a proxy.
+type corsHandler struct {
+	handler http.Handler
+
+	allowAnyOrigin   bool
+	allowOrigins     map[string]struct{}
+	allowWildcards   [][2]string // the prefix and suffix of the origins like "https://*.example.com"
+	allowMethods     string
+	allowAnyHeader   bool
+	allowHeaders     string
+	exposeHeaders    string
+	allowCredentials bool
+	maxAge           string
+}
+
+// NewCorsHandler wraps handler with the CORS handling of conf, handler is returned as is if
+// conf allows no origin.
+func NewCorsHandler(handler http.Handler, conf *global.CorsConfig) (http.Handler, error) {
+	if conf == nil || len(conf.AllowOrigins) == 0 {
+		return handler, nil
+	}
+
+	h := &corsHandler{
+		handler:          handler,
+		allowOrigins:     make(map[string]struct{}),
+		allowCredentials: conf.AllowCredentials,
+	}
+	for _, origin := range conf.AllowOrigins {
+		origin = strings.ToLower(strings.TrimSpace(origin))
+		switch {
+		case origin == "*":
+			h.allowAnyOrigin = true
+		case strings.Contains(origin, "*"):
+			i := strings.Index(origin, "*")
+			h.allowWildcards = append(h.allowWildcards, [2]string{origin[:i], origin[i+1:]})
+		default:
+			h.allowOrigins[origin] = struct{}{}
+		}
+	}
+	// echoing any origin with credentials would let every site act on behalf of the users
+	if h.allowAnyOrigin && h.allowCredentials {
+		return nil, errors.New(`CORS allow-origins "*" can't be used with allow-credentials, list the origins instead`)
+	}
+
+	methods := conf.AllowMethods
+	if len(methods) == 0 {
+		methods = corsDefaultAllowMethods
+	}
+	h.allowMethods = strings.ToUpper(strings.Join(methods, ", "))
+
+	allowHeaders := append([]string(nil), corsDefaultAllowHeaders...)
+	for _, header := range conf.AllowHeaders {
+		if header == "*" {
+			h.allowAnyHeader = true
+			continue
+		}
+		allowHeaders = append(allowHeaders, header)
+	}
+	h.allowHeaders = strings.Join(allowHeaders, ", ")
+	h.exposeHeaders = strings.Join(append(append([]string(nil), corsDefaultExposeHeaders...), conf.ExposeHeaders...), ", ")
+
+	if conf.MaxAge != "" {
+		maxAge, err := time.ParseDuration(conf.MaxAge)
+		if err != nil {
+			return nil, err
+		}
+		h.maxAge = strconv.Itoa(int(maxAge.Seconds()))
+	}
+	return h, nil
+}
+
+func (h *corsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	header := w.Header()
+	header.Add("Vary", "Origin")
+	preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+	if preflight {
+		header.Add("Vary", "Access-Control-Request-Method")
+		header.Add("Vary", "Access-Control-Request-Headers")
+	}
+	if !h.isAllowedOrigin(origin) {
+		if preflight {
+			// without the CORS headers, browsers reject the actual request
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	if h.allowAnyOrigin {
+		header.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		header.Set("Access-Control-Allow-Origin", origin)
+	}
+	if h.allowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if !preflight {
+		header.Set("Access-Control-Expose-Headers", h.exposeHeaders)
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	header.Set("Access-Control-Allow-Methods", h.allowMethods)
+	allowHeaders := h.allowHeaders
+	if requested := r.Header.Get("Access-Control-Request-Headers"); h.allowAnyHeader && requested != "" {
+		allowHeaders = requested
+	}
+	header.Set("Access-Control-Allow-Headers", allowHeaders)
+	if h.maxAge != "" {
+		header.Set("Access-Control-Max-Age", h.maxAge)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *corsHandler) isAllowedOrigin(origin string) bool {
+	if h.allowAnyOrigin {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if _, ok := h.allowOrigins[origin]; ok {
+		return true
+	}
+	for _, wildcard := range h.allowWildcards {
+		if len(origin) > len(wildcard[0])+len(wildcard[1]) &&
+			strings.HasPrefix(origin, wildcard[0]) && strings.HasSuffix(origin, wildcard[1]) {
+			return true
+		}
+	}
+	return false
+}
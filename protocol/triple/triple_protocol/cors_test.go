@@ -0,0 +1,160 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package triple_protocol
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/global"
+)
+
+func TestNewCorsHandler(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	handler, err := NewCorsHandler(next, nil)
+	assert.Nil(t, err)
+	assert.NotNil(t, handler)
+	_, ok := handler.(*corsHandler)
+	assert.False(t, ok)
+
+	_, err = NewCorsHandler(next, &global.CorsConfig{AllowOrigins: []string{"*"}, MaxAge: "1"})
+	assert.NotNil(t, err)
+}
+
+func TestCorsHandler(t *testing.T) {
+	var served int
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served++
+		w.WriteHeader(http.StatusOK)
+	})
+	handler, err := NewCorsHandler(next, &global.CorsConfig{
+		AllowOrigins:  []string{"https://example.com", "https://*.example.org"},
+		AllowHeaders:  []string{"X-Custom"},
+		ExposeHeaders: []string{"X-Custom-Trailer"},
+		MaxAge:        "2h",
+	})
+	assert.Nil(t, err)
+
+	serve := func(method, origin string, header map[string]string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/greet.GreetService/Greet", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		for k, v := range header {
+			r.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("preflight", func(t *testing.T) {
+		w := serve(http.MethodOptions, "https://example.com", map[string]string{
+			"Access-Control-Request-Method":  http.MethodPost,
+			"Access-Control-Request-Headers": "content-type,x-grpc-web",
+		})
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "https://example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "POST, GET, OPTIONS", w.Header().Get("Access-Control-Allow-Methods"))
+		assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), "X-Grpc-Web")
+		assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), "X-Custom")
+		assert.Equal(t, "7200", w.Header().Get("Access-Control-Max-Age"))
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Zero(t, served)
+	})
+
+	t.Run("wildcard_subdomain", func(t *testing.T) {
+		w := serve(http.MethodOptions, "https://api.example.org", map[string]string{
+			"Access-Control-Request-Method": http.MethodPost,
+		})
+		assert.Equal(t, "https://api.example.org", w.Header().Get("Access-Control-Allow-Origin"))
+
+		w = serve(http.MethodOptions, "https://example.org", map[string]string{
+			"Access-Control-Request-Method": http.MethodPost,
+		})
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("disallowed_preflight", func(t *testing.T) {
+		w := serve(http.MethodOptions, "https://evil.com", map[string]string{
+			"Access-Control-Request-Method": http.MethodPost,
+		})
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Zero(t, served)
+	})
+
+	t.Run("actual_request", func(t *testing.T) {
+		w := serve(http.MethodPost, "https://example.com", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 1, served)
+		assert.Equal(t, "https://example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "Grpc-Status, Grpc-Message, Grpc-Status-Details-Bin, Grpc-Encoding, X-Custom-Trailer",
+			w.Header().Get("Access-Control-Expose-Headers"))
+		assert.Equal(t, []string{"Origin"}, w.Header().Values("Vary"))
+	})
+
+	t.Run("disallowed_request", func(t *testing.T) {
+		w := serve(http.MethodPost, "https://evil.com", nil)
+		assert.Equal(t, 2, served)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("same_origin", func(t *testing.T) {
+		w := serve(http.MethodPost, "", nil)
+		assert.Equal(t, 3, served)
+		assert.Empty(t, w.Header().Get("Vary"))
+	})
+}
+
+func TestCorsHandler_AnyOrigin(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	preflight := func(handler http.Handler) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodOptions, "/", nil)
+		r.Header.Set("Origin", "https://example.com")
+		r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		r.Header.Set("Access-Control-Request-Headers", "x-anything")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	handler, err := NewCorsHandler(next, &global.CorsConfig{
+		AllowOrigins: []string{"*"},
+		AllowHeaders: []string{"*"},
+	})
+	assert.Nil(t, err)
+	w := preflight(handler)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "x-anything", w.Header().Get("Access-Control-Allow-Headers"))
+
+	// any origin is never allowed with credentials
+	_, err = NewCorsHandler(next, &global.CorsConfig{
+		AllowOrigins:     []string{"*"},
+		AllowCredentials: true,
+	})
+	assert.NotNil(t, err)
+}
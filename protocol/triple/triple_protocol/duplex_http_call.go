@@ -152,6 +152,10 @@ func (d *duplexHTTPCall) URL() *url.URL {
 // SetMethod changes the method of the request before it is sent.
 func (d *duplexHTTPCall) SetMethod(method string) {
 	d.request.Method = method
+	if method == http.MethodGet {
+		// GET requests carry the message in the URL, so there is no body to send
+		d.request.Body = http.NoBody
+	}
 }
 
 // Read from the response body. Returns the first error passed to SetError.
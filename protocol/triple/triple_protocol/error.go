@@ -244,6 +244,10 @@ func wrapIfUncoded(err error) error {
 	if _, ok := asError(maybeCodedErr); ok {
 		return maybeCodedErr
 	}
+	// keep the codes and details of gRPC status errors
+	if tripleErr, ok := FromError(maybeCodedErr); ok {
+		return tripleErr
+	}
 	return NewError(CodeUnknown, maybeCodedErr)
 }
 
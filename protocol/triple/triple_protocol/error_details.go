@@ -0,0 +1,196 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package triple_protocol
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+import (
+	dubbostatus "github.com/dubbogo/grpc-go/status"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	statuspb "google.golang.org/genproto/googleapis/rpc/status"
+
+	"google.golang.org/grpc/status"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// NewErrorWithDetails annotates a Go error with a status code and attaches
+// each of the supplied Protobuf messages as an [ErrorDetail]. It returns an
+// error if any of the details cannot be marshaled.
+func NewErrorWithDetails(c Code, underlying error, details ...proto.Message) (*Error, error) {
+	tripleErr := NewError(c, underlying)
+	for _, detail := range details {
+		errDetail, err := NewErrorDetail(detail)
+		if err != nil {
+			return nil, err
+		}
+		tripleErr.AddDetail(errDetail)
+	}
+	return tripleErr, nil
+}
+
+// NewErrorInfoError returns an error carrying an [errdetails.ErrorInfo], which
+// describes the cause of the error with a machine-readable reason, the domain
+// the reason belongs to and additional metadata.
+func NewErrorInfoError(c Code, message, reason, domain string, metadata map[string]string) *Error {
+	return newErrorWithDetail(c, message, &errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   domain,
+		Metadata: metadata,
+	})
+}
+
+// NewRetryableError returns an error carrying an [errdetails.RetryInfo], which
+// tells clients to wait for at least delay before retrying the request.
+// Clusters retrying failed invocations honor the delay.
+func NewRetryableError(c Code, message string, delay time.Duration) *Error {
+	return newErrorWithDetail(c, message, &errdetails.RetryInfo{
+		RetryDelay: durationpb.New(delay),
+	})
+}
+
+// NewBadRequestError returns a [CodeInvalidArgument] error carrying an
+// [errdetails.BadRequest], which maps request fields to the description of
+// their violations.
+func NewBadRequestError(message string, violations map[string]string) *Error {
+	fields := make([]string, 0, len(violations))
+	for field := range violations {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	badRequest := &errdetails.BadRequest{}
+	for _, field := range fields {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: violations[field],
+		})
+	}
+	return newErrorWithDetail(CodeInvalidArgument, message, badRequest)
+}
+
+// NewQuotaFailureError returns a [CodeResourceExhausted] error carrying an
+// [errdetails.QuotaFailure], which describes the exhausted quota checks.
+func NewQuotaFailureError(message string, violations ...*errdetails.QuotaFailure_Violation) *Error {
+	return newErrorWithDetail(CodeResourceExhausted, message, &errdetails.QuotaFailure{
+		Violations: violations,
+	})
+}
+
+func newErrorWithDetail(c Code, message string, detail proto.Message) *Error {
+	tripleErr := NewError(c, errors.New(message))
+	// well-known error details always marshal successfully
+	if errDetail, err := NewErrorDetail(detail); err == nil {
+		tripleErr.AddDetail(errDetail)
+	}
+	return tripleErr
+}
+
+// ErrorInfoOf returns the first [errdetails.ErrorInfo] attached to err.
+func ErrorInfoOf(err error) (*errdetails.ErrorInfo, bool) {
+	return detailOf[*errdetails.ErrorInfo](err)
+}
+
+// RetryInfoOf returns the first [errdetails.RetryInfo] attached to err.
+func RetryInfoOf(err error) (*errdetails.RetryInfo, bool) {
+	return detailOf[*errdetails.RetryInfo](err)
+}
+
+// BadRequestOf returns the first [errdetails.BadRequest] attached to err.
+func BadRequestOf(err error) (*errdetails.BadRequest, bool) {
+	return detailOf[*errdetails.BadRequest](err)
+}
+
+// QuotaFailureOf returns the first [errdetails.QuotaFailure] attached to err.
+func QuotaFailureOf(err error) (*errdetails.QuotaFailure, bool) {
+	return detailOf[*errdetails.QuotaFailure](err)
+}
+
+// RetryDelayOf returns the delay suggested by the [errdetails.RetryInfo]
+// attached to err.
+func RetryDelayOf(err error) (time.Duration, bool) {
+	retryInfo, ok := RetryInfoOf(err)
+	if !ok || retryInfo.GetRetryDelay() == nil {
+		return 0, false
+	}
+	return retryInfo.GetRetryDelay().AsDuration(), true
+}
+
+// detailOf looks up the first detail of type T attached to err, which may be
+// any error accepted by FromError.
+func detailOf[T proto.Message](err error) (T, bool) {
+	var zero T
+	tripleErr, ok := FromError(err)
+	if !ok {
+		return zero, false
+	}
+	for _, detail := range tripleErr.Details() {
+		value, valueErr := detail.Value()
+		if valueErr != nil {
+			continue
+		}
+		if typed, ok := value.(T); ok {
+			return typed, true
+		}
+	}
+	return zero, false
+}
+
+// FromError unwraps err to a triple [*Error]. Besides triple errors, it
+// converts the status errors of gRPC, keeping their codes, messages and
+// details, so that errors from every protocol can be inspected the same way.
+func FromError(err error) (*Error, bool) {
+	if err == nil {
+		return nil, false
+	}
+	if tripleErr, ok := asError(err); ok {
+		return tripleErr, true
+	}
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		return fromStatusProto(grpcErr.GRPCStatus().Proto())
+	}
+	var dubboGrpcErr interface{ GRPCStatus() *dubbostatus.Status }
+	if errors.As(err, &dubboGrpcErr) {
+		return compatError(err)
+	}
+	return nil, false
+}
+
+// GRPCStatus converts the error to a gRPC status, so that gRPC servers send
+// its code, message and details as they are.
+func (e *Error) GRPCStatus() *status.Status {
+	return status.FromProto(&statuspb.Status{
+		Code:    int32(e.code),
+		Message: e.Message(),
+		Details: e.detailsAsAny(),
+	})
+}
+
+func fromStatusProto(pb *statuspb.Status) (*Error, bool) {
+	tripleErr := NewWireError(Code(pb.GetCode()), errors.New(pb.GetMessage()))
+	for _, detail := range pb.GetDetails() {
+		tripleErr.details = append(tripleErr.details, &ErrorDetail{pb: proto.Clone(detail).(*anypb.Any)})
+	}
+	return tripleErr, true
+}
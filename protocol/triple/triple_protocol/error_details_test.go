@@ -0,0 +1,98 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package triple_protocol
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+import (
+	dubbostatus "github.com/dubbogo/grpc-go/status"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol/internal/assert"
+)
+
+func TestErrorDetailHelpers(t *testing.T) {
+	t.Parallel()
+	err := NewErrorInfoError(CodeFailedPrecondition, "order locked", "ORDER_LOCKED", "order.example.com", map[string]string{"id": "42"})
+	assert.Equal(t, err.Code(), CodeFailedPrecondition)
+	info, ok := ErrorInfoOf(fmt.Errorf("wrapped: %w", err))
+	assert.True(t, ok)
+	assert.Equal(t, info.GetReason(), "ORDER_LOCKED")
+	assert.Equal(t, info.GetMetadata()["id"], "42")
+	_, ok = RetryInfoOf(err)
+	assert.False(t, ok)
+
+	delay, ok := RetryDelayOf(NewRetryableError(CodeUnavailable, "overloaded", time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, delay, time.Second)
+
+	badRequest, ok := BadRequestOf(NewBadRequestError("invalid", map[string]string{"name": "empty", "age": "negative"}))
+	assert.True(t, ok)
+	assert.Equal(t, len(badRequest.GetFieldViolations()), 2)
+	assert.Equal(t, badRequest.GetFieldViolations()[0].GetField(), "age")
+
+	quotaErr := NewQuotaFailureError("quota exceeded", &errdetails.QuotaFailure_Violation{Subject: "user:1"})
+	assert.Equal(t, quotaErr.Code(), CodeResourceExhausted)
+	quota, ok := QuotaFailureOf(quotaErr)
+	assert.True(t, ok)
+	assert.Equal(t, quota.GetViolations()[0].GetSubject(), "user:1")
+
+	withDetails, detailErr := NewErrorWithDetails(CodeAborted, errors.New("conflict"), &errdetails.RetryInfo{RetryDelay: durationpb.New(time.Millisecond)})
+	assert.Nil(t, detailErr)
+	delay, ok = RetryDelayOf(withDetails)
+	assert.True(t, ok)
+	assert.Equal(t, delay, time.Millisecond)
+
+	_, ok = ErrorInfoOf(errors.New("plain"))
+	assert.False(t, ok)
+}
+
+func TestErrorGRPCStatusConversion(t *testing.T) {
+	t.Parallel()
+	err := NewRetryableError(CodeUnavailable, "overloaded", time.Second)
+	grpcStatus, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, grpcStatus.Code(), codes.Unavailable)
+	assert.Equal(t, grpcStatus.Message(), "overloaded")
+	assert.Equal(t, len(grpcStatus.Details()), 1)
+
+	// errors received from gRPC servers convert back
+	tripleErr, ok := FromError(grpcStatus.Err())
+	assert.True(t, ok)
+	assert.True(t, IsWireError(tripleErr))
+	assert.Equal(t, tripleErr.Code(), CodeUnavailable)
+	assert.Equal(t, tripleErr.Message(), "overloaded")
+	delay, ok := RetryDelayOf(grpcStatus.Err())
+	assert.True(t, ok)
+	assert.Equal(t, delay, time.Second)
+	assert.Equal(t, CodeOf(status.Error(codes.NotFound, "missing")), CodeNotFound)
+	assert.Equal(t, CodeOf(dubbostatus.Error(12, "unimplemented")), CodeUnimplemented)
+	assert.Equal(t, CodeOf(errors.New("plain")), CodeUnknown)
+}
@@ -63,10 +63,20 @@ func NewErrorWriter(opts ...HandlerOption) *ErrorWriter {
 		writer.grpcContentTypes[grpcContentTypeDefault] = struct{}{}
 		writer.allContentTypes[grpcContentTypeDefault] = struct{}{}
 		for name := range config.Codecs {
-			ct := grpcContentTypeFromCodecName(name)
+			ct := grpcContentTypeFromCodecName(false, name)
 			writer.grpcContentTypes[ct] = struct{}{}
 			writer.allContentTypes[ct] = struct{}{}
 		}
+		for _, ct := range []string{grpcWebContentTypeDefault, grpcWebTextContentTypeDefault} {
+			writer.grpcWebContentTypes[ct] = struct{}{}
+			writer.allContentTypes[ct] = struct{}{}
+		}
+		for name := range config.Codecs {
+			for _, ct := range grpcContentTypesFromCodecName(true, name) {
+				writer.grpcWebContentTypes[ct] = struct{}{}
+				writer.allContentTypes[ct] = struct{}{}
+			}
+		}
 	}
 	return writer
 }
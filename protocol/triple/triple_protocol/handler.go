@@ -47,7 +47,7 @@ func NewUnaryHandler(
 	options ...HandlerOption,
 ) *Handler {
 	config := newHandlerConfig(procedure, options)
-	implementation := generateUnaryHandlerFunc(procedure, reqInitFunc, unary, config.Interceptor)
+	implementation := config.observeMaxBytes(generateUnaryHandlerFunc(procedure, reqInitFunc, unary, config.Interceptor))
 	protocolHandlers := config.newProtocolHandlers(StreamTypeUnary)
 
 	hdl := &Handler{
@@ -388,9 +388,11 @@ type handlerConfig struct {
 	HandleGRPC                  bool
 	RequireTripleProtocolHeader bool
 	IdempotencyLevel            IdempotencyLevel
+	CacheControl                string
 	BufferPool                  *bufferPool
 	ReadMaxBytes                int
 	SendMaxBytes                int
+	MaxBytesObserver            func(send bool)
 	Group                       string
 	Version                     string
 }
@@ -408,6 +410,9 @@ func newHandlerConfig(procedure string, options []HandlerOption) *handlerConfig
 	withProtoJSONCodecs().applyToHandler(&config)
 	withHessian2Codec().applyToHandler(&config)
 	withMsgPackCodec().applyToHandler(&config)
+	// gzip is registered last, so it stays the most preferred algorithm
+	withSnappy().applyToHandler(&config)
+	withZstd().applyToHandler(&config)
 	withGzip().applyToHandler(&config)
 	for _, opt := range options {
 		opt.applyToHandler(&config)
@@ -430,7 +435,7 @@ func (c *handlerConfig) newProtocolHandlers(streamType StreamType) []protocolHan
 		protocols = append(protocols, &protocolTriple{})
 	}
 	if c.HandleGRPC {
-		protocols = append(protocols, &protocolGRPC{})
+		protocols = append(protocols, &protocolGRPC{}, &protocolGRPC{web: true})
 	}
 	// protocol -> protocolHandler
 	handlers := make([]protocolHandler, 0, len(protocols))
@@ -454,11 +459,52 @@ func (c *handlerConfig) newProtocolHandlers(streamType StreamType) []protocolHan
 			SendMaxBytes:                c.SendMaxBytes,
 			RequireTripleProtocolHeader: c.RequireTripleProtocolHeader,
 			IdempotencyLevel:            c.IdempotencyLevel,
+			CacheControl:                c.CacheControl,
 		}))
 	}
 	return handlers
 }
 
+// observeMaxBytes wraps implementation to report the messages rejected for exceeding the
+// read or send max bytes to the observer of the handler.
+func (c *handlerConfig) observeMaxBytes(implementation StreamingHandlerFunc) StreamingHandlerFunc {
+	observe := c.MaxBytesObserver
+	if observe == nil {
+		return implementation
+	}
+	return func(ctx context.Context, conn StreamingHandlerConn) error {
+		return implementation(ctx, &maxBytesObservedConn{StreamingHandlerConn: conn, observe: observe})
+	}
+}
+
+type maxBytesObservedConn struct {
+	StreamingHandlerConn
+
+	observe func(send bool)
+}
+
+func (c *maxBytesObservedConn) Receive(msg any) error {
+	err := c.StreamingHandlerConn.Receive(msg)
+	if isMaxBytesError(err) {
+		c.observe(false)
+	}
+	return err
+}
+
+func (c *maxBytesObservedConn) Send(msg any) error {
+	err := c.StreamingHandlerConn.Send(msg)
+	if isMaxBytesError(err) {
+		c.observe(true)
+	}
+	return err
+}
+
+// isMaxBytesError reports whether err is raised locally for a message exceeding the read
+// or send max bytes, which are the only resource exhausted errors of the codec.
+func isMaxBytesError(err error) bool {
+	return err != nil && CodeOf(err) == CodeResourceExhausted && !IsWireError(err)
+}
+
 func getIdentifier(group, version string) string {
 	return group + "/" + version
 }
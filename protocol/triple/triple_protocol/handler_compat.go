@@ -108,7 +108,7 @@ func NewCompatUnaryHandler(
 	options ...HandlerOption,
 ) *Handler {
 	config := newHandlerConfig(procedure, options)
-	implementation := generateCompatUnaryHandlerFunc(procedure, method, srv, unary, config.Interceptor)
+	implementation := config.observeMaxBytes(generateCompatUnaryHandlerFunc(procedure, method, srv, unary, config.Interceptor))
 	protocolHandlers := config.newProtocolHandlers(StreamTypeUnary)
 
 	hdl := &Handler{
@@ -15,17 +15,27 @@
 package triple_protocol_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 )
 
+import (
+	"google.golang.org/protobuf/proto"
+)
+
 import (
 	triple "dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol/internal/assert"
+	pingv1 "dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol/internal/gen/proto/connect/ping/v1"
 	"dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol/internal/gen/proto/connect/ping/v1/pingv1connect"
 )
 
@@ -126,6 +136,18 @@ func TestHandler_ServeHTTP(t *testing.T) {
 			"application/grpc+json; charset=utf-8",
 			"application/grpc+msgpack",
 			"application/grpc+proto",
+			"application/grpc-web",
+			"application/grpc-web+hessian2",
+			"application/grpc-web+json",
+			"application/grpc-web+json; charset=utf-8",
+			"application/grpc-web+msgpack",
+			"application/grpc-web+proto",
+			"application/grpc-web-text",
+			"application/grpc-web-text+hessian2",
+			"application/grpc-web-text+json",
+			"application/grpc-web-text+json; charset=utf-8",
+			"application/grpc-web-text+msgpack",
+			"application/grpc-web-text+proto",
 			"application/hessian2",
 			"application/json",
 			"application/json; charset=utf-8",
@@ -189,11 +211,148 @@ func TestHandler_ServeHTTP(t *testing.T) {
 		var message errorMessage
 		err = json.NewDecoder(resp.Body).Decode(&message)
 		assert.Nil(t, err)
-		assert.Equal(t, message.Message, `unknown compression "invalid": supported encodings are gzip`)
+		assert.Equal(t, message.Message, `unknown compression "invalid": supported encodings are gzip,zstd,snappy`)
 		assert.Equal(t, message.Code, triple.CodeUnimplemented.String())
 	})
 }
 
+func TestHandler_GRPCWebText(t *testing.T) {
+	t.Parallel()
+	mux := http.NewServeMux()
+	mux.Handle(pingv1connect.NewPingServiceHandler(pingServer{}))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	data, err := proto.Marshal(&pingv1.PingRequest{Number: 42, Text: "web"})
+	assert.Nil(t, err)
+	body := base64.StdEncoding.EncodeToString(append([]byte{0, 0, 0, 0, byte(len(data))}, data...))
+	request, err := http.NewRequestWithContext(
+		context.Background(),
+		http.MethodPost,
+		server.URL+"/"+pingv1connect.PingServiceName+"/Ping",
+		strings.NewReader(body),
+	)
+	assert.Nil(t, err)
+	request.Header.Set("Content-Type", "application/grpc-web-text+proto")
+	request.Header.Set("X-Grpc-Web", "1")
+	resp, err := server.Client().Do(request)
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+	assert.Equal(t, resp.Header.Get("Content-Type"), "application/grpc-web-text+proto")
+	assert.Equal(t, resp.Header.Get(handlerHeader), headerValue)
+
+	// the response is made of padded base64 chunks
+	encoded, err := io.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	var decoded []byte
+	for i := 0; i+4 <= len(encoded); i += 4 {
+		quantum, decodeErr := base64.StdEncoding.DecodeString(string(encoded[i : i+4]))
+		assert.Nil(t, decodeErr)
+		decoded = append(decoded, quantum...)
+	}
+
+	var envelopes [][]byte
+	var flags []byte
+	for len(decoded) >= 5 {
+		size := binary.BigEndian.Uint32(decoded[1:5])
+		flags = append(flags, decoded[0])
+		envelopes = append(envelopes, decoded[5:5+size])
+		decoded = decoded[5+size:]
+	}
+	assert.Equal(t, flags, []byte{0, 0x80})
+	msg := &pingv1.PingResponse{}
+	assert.Nil(t, proto.Unmarshal(envelopes[0], msg))
+	assert.Equal(t, msg.Number, int64(42))
+	assert.Equal(t, msg.Text, "web")
+	// the trailers are carried in the body
+	assert.True(t, bytes.Contains(envelopes[1], []byte("grpc-status: 0\r\n")))
+	assert.True(t, bytes.Contains(envelopes[1], []byte(strings.ToLower(handlerTrailer)+": "+trailerValue)))
+}
+
+func TestHandler_HTTPGet(t *testing.T) {
+	t.Parallel()
+	mux := http.NewServeMux()
+	mux.Handle(pingv1connect.NewPingServiceHandler(
+		pingServer{},
+		triple.WithCacheControl("public, max-age=60"),
+	))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	get := func(t *testing.T, procedure string, query url.Values) *http.Response {
+		t.Helper()
+		request, err := http.NewRequestWithContext(
+			context.Background(),
+			http.MethodGet,
+			server.URL+"/"+pingv1connect.PingServiceName+"/"+procedure+"?"+query.Encode(),
+			nil,
+		)
+		assert.Nil(t, err)
+		resp, err := server.Client().Do(request)
+		assert.Nil(t, err)
+		t.Cleanup(func() {
+			_ = resp.Body.Close()
+		})
+		return resp
+	}
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+		resp := get(t, "Ping", url.Values{
+			"encoding": {"json"},
+			"message":  {`{"number":"42","text":"get"}`},
+		})
+		assert.Equal(t, resp.StatusCode, http.StatusOK)
+		assert.Equal(t, resp.Header.Get("Content-Type"), "application/json")
+		assert.Equal(t, resp.Header.Get("Cache-Control"), "public, max-age=60")
+		var msg map[string]any
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(&msg))
+		assert.Equal(t, msg["number"], any("42"))
+		assert.Equal(t, msg["text"], any("get"))
+	})
+	t.Run("base64_proto", func(t *testing.T) {
+		t.Parallel()
+		data, err := proto.Marshal(&pingv1.PingRequest{Number: 42})
+		assert.Nil(t, err)
+		resp := get(t, "Ping", url.Values{
+			"encoding": {"proto"},
+			"base64":   {"1"},
+			"message":  {base64.RawURLEncoding.EncodeToString(data)},
+		})
+		assert.Equal(t, resp.StatusCode, http.StatusOK)
+		body, err := io.ReadAll(resp.Body)
+		assert.Nil(t, err)
+		msg := &pingv1.PingResponse{}
+		assert.Nil(t, proto.Unmarshal(body, msg))
+		assert.Equal(t, msg.Number, int64(42))
+	})
+	t.Run("invalid_base64", func(t *testing.T) {
+		t.Parallel()
+		resp := get(t, "Ping", url.Values{
+			"encoding": {"proto"},
+			"base64":   {"1"},
+			"message":  {"!"},
+		})
+		assert.Equal(t, resp.StatusCode, http.StatusBadRequest)
+		assert.Equal(t, resp.Header.Get("Cache-Control"), "")
+	})
+	t.Run("unknown_encoding", func(t *testing.T) {
+		t.Parallel()
+		resp := get(t, "Ping", url.Values{"encoding": {"xml"}})
+		assert.Equal(t, resp.StatusCode, http.StatusUnsupportedMediaType)
+	})
+	t.Run("side_effects", func(t *testing.T) {
+		t.Parallel()
+		resp := get(t, "Fail", url.Values{
+			"encoding": {"json"},
+			"message":  {"{}"},
+		})
+		assert.Equal(t, resp.StatusCode, http.StatusMethodNotAllowed)
+		assert.Equal(t, resp.Header.Get("Allow"), http.MethodPost)
+	})
+}
+
 type successPingServer struct {
 	pingv1connect.UnimplementedPingServiceHandler
 }
@@ -0,0 +1,122 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package triple_protocol
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+)
+
+// memoryListeners are the in-memory listeners by name.
+var memoryListeners sync.Map
+
+// MemoryListener is a net.Listener served in memory, like the bufconn of gRPC. Clients
+// connect to it by name with DialMemory, so that a server and its clients in the same
+// process talk without any real port, which is handy for tests.
+type MemoryListener struct {
+	name      string
+	conns     chan net.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// ListenMemory creates the in-memory listener of name, which must not be in use.
+func ListenMemory(name string) (*MemoryListener, error) {
+	lis := &MemoryListener{
+		name:  name,
+		conns: make(chan net.Conn),
+		done:  make(chan struct{}),
+	}
+	if _, loaded := memoryListeners.LoadOrStore(name, lis); loaded {
+		return nil, fmt.Errorf("in-memory listener %q is already in use", name)
+	}
+	return lis, nil
+}
+
+// DialMemory connects to the in-memory listener of name.
+func DialMemory(ctx context.Context, name string) (net.Conn, error) {
+	value, ok := memoryListeners.Load(name)
+	if !ok {
+		return nil, fmt.Errorf("in-memory listener %q not found", name)
+	}
+	return value.(*MemoryListener).dial(ctx)
+}
+
+func (l *MemoryListener) dial(ctx context.Context) (net.Conn, error) {
+	serverConn, clientConn := net.Pipe()
+	select {
+	case l.conns <- &memoryConn{Conn: serverConn, addr: l.Addr()}:
+		return &memoryConn{Conn: clientConn, addr: l.Addr()}, nil
+	case <-l.done:
+		return nil, fmt.Errorf("in-memory listener %q is closed", l.name)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Accept waits for and returns the next connection dialed to the listener.
+func (l *MemoryListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close closes the listener and releases its name. Accepted connections are not closed.
+func (l *MemoryListener) Close() error {
+	err := net.ErrClosed
+	l.closeOnce.Do(func() {
+		close(l.done)
+		memoryListeners.CompareAndDelete(l.name, l)
+		err = nil
+	})
+	return err
+}
+
+// Addr returns the address of the listener, whose network is "memory".
+func (l *MemoryListener) Addr() net.Addr {
+	return memoryAddr(l.name)
+}
+
+type memoryAddr string
+
+func (a memoryAddr) Network() string {
+	return "memory"
+}
+
+func (a memoryAddr) String() string {
+	return string(a)
+}
+
+// memoryConn reports the address of the listener instead of the "pipe" of net.Pipe.
+type memoryConn struct {
+	net.Conn
+	addr net.Addr
+}
+
+func (c *memoryConn) LocalAddr() net.Addr {
+	return c.addr
+}
+
+func (c *memoryConn) RemoteAddr() net.Addr {
+	return c.addr
+}
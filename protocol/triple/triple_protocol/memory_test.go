@@ -0,0 +1,72 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package triple_protocol
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"golang.org/x/net/http2"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestMemoryListener(t *testing.T) {
+	lis, err := ListenMemory("TestMemoryListener")
+	assert.Nil(t, err)
+	_, err = ListenMemory("TestMemoryListener")
+	assert.NotNil(t, err)
+	assert.Equal(t, "memory", lis.Addr().Network())
+
+	srv := NewServer("", nil)
+	err = srv.RegisterUnaryHandler("/greet.GreetService/Greet", func() any { return &wrapperspb.StringValue{} },
+		func(ctx context.Context, req *Request) (*Response, error) {
+			name := req.Msg.(*wrapperspb.StringValue).GetValue()
+			return NewResponse(wrapperspb.String("hello " + name)), nil
+		})
+	assert.Nil(t, err)
+	go func() {
+		_ = srv.Serve(lis, nil)
+	}()
+	defer srv.Stop()
+
+	transport := &http2.Transport{AllowHTTP: true}
+	settings := &TransportSettings{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return DialMemory(ctx, "TestMemoryListener")
+		},
+	}
+	settings.ConfigureHTTP2Transport(transport)
+	client := NewClient(&http.Client{Transport: transport}, "http://TestMemoryListener/greet.GreetService/Greet")
+	resp := NewResponse(&wrapperspb.StringValue{})
+	err = client.CallUnary(context.Background(), NewRequest(wrapperspb.String("dubbo")), resp)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello dubbo", resp.Msg.(*wrapperspb.StringValue).GetValue())
+
+	// the name is released on close
+	assert.Nil(t, lis.Close())
+	assert.NotNil(t, lis.Close())
+	_, err = DialMemory(context.Background(), "TestMemoryListener")
+	assert.NotNil(t, err)
+}
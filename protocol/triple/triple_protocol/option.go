@@ -67,6 +67,12 @@ func WithTriple() ClientOption {
 	return &tripleOption{}
 }
 
+// WithGRPCWeb configures clients to use the gRPC-Web protocol, which carries
+// the trailers in the response body and works through HTTP/1.1 proxies.
+func WithGRPCWeb() ClientOption {
+	return &grpcWebOption{}
+}
+
 // WithProtoJSON configures a client to send JSON-encoded data instead of
 // binary Protobuf. It uses the standard Protobuf JSON mapping as implemented
 // by [google.golang.org/protobuf/encoding/protojson]: fields are named using
@@ -105,6 +111,51 @@ func WithSendGzip() ClientOption {
 	return WithSendCompression(compressionGzip)
 }
 
+// WithSendZstd configures the client to compress requests with zstd and to
+// accept zstd-compressed responses. Unlike gzip, zstd isn't registered on
+// clients by default, so WithSendZstd also registers the algorithm.
+//
+// Handlers support zstd by default, but other servers may not.
+func WithSendZstd() ClientOption {
+	return WithClientOptions(withZstd(), WithSendCompression(compressionZstd))
+}
+
+// WithSendSnappy configures the client to compress requests with snappy and
+// to accept snappy-compressed responses. Unlike gzip, snappy isn't registered
+// on clients by default, so WithSendSnappy also registers the algorithm.
+//
+// Handlers support snappy by default, but other servers may not.
+func WithSendSnappy() ClientOption {
+	return WithClientOptions(withSnappy(), WithSendCompression(compressionSnappy))
+}
+
+// WithHTTPGet configures the client to send unary requests of procedures
+// declared with [IdempotencyNoSideEffects] as HTTP GET requests, encoding the
+// message in the query parameters of the URL. It only takes effect with the
+// Triple protocol, and requires a codec that supports stable marshaling, such
+// as the default Protobuf and JSON codecs. Since GET requests are cacheable,
+// responses may be served by intermediaries such as CDNs and browser caches.
+//
+// Combine it with [WithHTTPGetMaxURLSize] to limit the length of the URLs.
+func WithHTTPGet() ClientOption {
+	return &enableGetOption{}
+}
+
+// WithHTTPGetMaxURLSize sets the maximum size in bytes of the URLs of GET
+// requests. When a URL would exceed the limit even after compression, the
+// request either fails with [CodeResourceExhausted], or is sent as a POST
+// request if fallback is true. Zero means no limit.
+func WithHTTPGetMaxURLSize(bytes int, fallback bool) ClientOption {
+	return &getURLMaxBytesOption{Max: bytes, Fallback: fallback}
+}
+
+// WithStreamHTTPClient makes the client, server and bidi streams use httpClient instead of the
+// HTTPClient of NewClient, e.g. the client of [TransportSettings.NewWebSocketClient] to carry
+// the streams over WebSocket. The unary calls are not affected.
+func WithStreamHTTPClient(httpClient HTTPClient) ClientOption {
+	return &streamHTTPClientOption{HTTPClient: httpClient}
+}
+
 // WithTimeout configures the default timeout of client call including unary
 // and stream. If you want to specify the timeout of a specific request, please
 // use context.WithTimeout, then default timeout would be overridden.
@@ -127,7 +178,8 @@ type HandlerOption interface {
 // compressors and decompressors.
 //
 // By default, handlers support gzip using the standard library's
-// [compress/gzip] package at the default compression level. To remove support for
+// [compress/gzip] package at the default compression level, as well as zstd and
+// snappy. To remove support for
 // a previously-registered compression algorithm, use WithCompression with nil
 // decompressor and compressor constructors.
 //
@@ -178,6 +230,14 @@ func WithRequireTripleProtocolHeader() HandlerOption {
 	return &requireTripleProtocolHeaderOption{}
 }
 
+// WithCacheControl sets the Cache-Control header of successful responses to
+// unary requests sent with HTTP GET, e.g. "public, max-age=60". It only takes
+// effect on procedures declared with [IdempotencyNoSideEffects], which are the
+// only ones that accept GET requests.
+func WithCacheControl(value string) HandlerOption {
+	return &cacheControlOption{value: value}
+}
+
 func WithGroup(group string) Option {
 	return &groupOption{group}
 }
@@ -255,6 +315,14 @@ func WithSendMaxBytes(max int) Option {
 	return &sendMaxBytesOption{Max: max}
 }
 
+// WithMaxBytesObserver calls observe whenever a message is rejected locally for
+// exceeding WithReadMaxBytes or WithSendMaxBytes. send reports whether the message
+// is the one being sent, that is, the response for handlers and the request for
+// clients. The errors sent by the other party are not observed.
+func WithMaxBytesObserver(observe func(send bool)) Option {
+	return &maxBytesObserverOption{Observe: observe}
+}
+
 // todo(DMwangnima): consider how to expose this functionality to users
 // WithIdempotency declares the idempotency of the procedure. This can determine
 // whether a procedure call can safely be retried, and may affect which request
@@ -423,6 +491,18 @@ func (o *sendMaxBytesOption) applyToHandler(config *handlerConfig) {
 	config.SendMaxBytes = o.Max
 }
 
+type maxBytesObserverOption struct {
+	Observe func(send bool)
+}
+
+func (o *maxBytesObserverOption) applyToClient(config *clientConfig) {
+	config.MaxBytesObserver = o.Observe
+}
+
+func (o *maxBytesObserverOption) applyToHandler(config *handlerConfig) {
+	config.MaxBytesObserver = o.Observe
+}
+
 type handlerOptionsOption struct {
 	options []HandlerOption
 }
@@ -493,6 +573,36 @@ func (o *tripleOption) applyToClient(config *clientConfig) {
 	config.Protocol = &protocolTriple{}
 }
 
+type grpcWebOption struct{}
+
+func (o *grpcWebOption) applyToClient(config *clientConfig) {
+	config.Protocol = &protocolGRPC{web: true}
+}
+
+type enableGetOption struct{}
+
+func (o *enableGetOption) applyToClient(config *clientConfig) {
+	config.EnableGet = true
+}
+
+type getURLMaxBytesOption struct {
+	Max      int
+	Fallback bool
+}
+
+func (o *getURLMaxBytesOption) applyToClient(config *clientConfig) {
+	config.GetURLMaxBytes = o.Max
+	config.GetUseFallback = o.Fallback
+}
+
+type cacheControlOption struct {
+	value string
+}
+
+func (o *cacheControlOption) applyToHandler(config *handlerConfig) {
+	config.CacheControl = o.value
+}
+
 type interceptorsOption struct {
 	Interceptors []Interceptor
 }
@@ -534,6 +644,14 @@ func (o *optionsOption) applyToHandler(config *handlerConfig) {
 	}
 }
 
+type streamHTTPClientOption struct {
+	HTTPClient HTTPClient
+}
+
+func (o *streamHTTPClientOption) applyToClient(config *clientConfig) {
+	config.StreamHTTPClient = o.HTTPClient
+}
+
 type sendCompressionOption struct {
 	Name string
 }
@@ -560,6 +678,20 @@ func withGzip() Option {
 	}
 }
 
+func withZstd() Option {
+	return &compressionOption{
+		Name:            compressionZstd,
+		CompressionPool: newCompressionPool(newZstdDecompressor, newZstdCompressor),
+	}
+}
+
+func withSnappy() Option {
+	return &compressionOption{
+		Name:            compressionSnappy,
+		CompressionPool: newCompressionPool(newSnappyDecompressor, newSnappyCompressor),
+	}
+}
+
 func withProtoBinaryCodec() Option {
 	return WithCodec(&protoBinaryCodec{})
 }
@@ -80,6 +80,7 @@ type protocolHandlerParams struct {
 	SendMaxBytes                int
 	RequireTripleProtocolHeader bool
 	IdempotencyLevel            IdempotencyLevel
+	CacheControl                string
 }
 
 // Handler is the server side of a protocol. HTTP handlers typically support
@@ -48,8 +48,16 @@ const (
 	grpcTimeoutMaxHours = math.MaxInt64 / int64(time.Hour) // how many hours fit into a time.Duration?
 	grpcMaxTimeoutChars = 8                                // from gRPC protocol
 
-	grpcContentTypeDefault = "application/grpc"
-	grpcContentTypePrefix  = grpcContentTypeDefault + "+"
+	grpcContentTypeDefault    = "application/grpc"
+	grpcContentTypePrefix     = grpcContentTypeDefault + "+"
+	grpcWebContentTypeDefault = "application/grpc-web"
+	grpcWebContentTypePrefix  = grpcWebContentTypeDefault + "+"
+	// grpc-web-text is used by browsers which can't read binary streams,
+	// the bodies of both directions are base64 encoded.
+	grpcWebTextContentTypeDefault = "application/grpc-web-text"
+	grpcWebTextContentTypePrefix  = grpcWebTextContentTypeDefault + "+"
+
+	headerXUserAgent = "X-User-Agent"
 )
 
 var (
@@ -88,23 +96,33 @@ func init() {
 	}
 }
 
-type protocolGRPC struct{}
+// protocolGRPC is the gRPC protocol, or the gRPC-Web protocol if web is set.
+type protocolGRPC struct {
+	web bool
+}
 
 // for server side
 
 // NewHandler implements protocol, so it must return an interface.
 func (g *protocolGRPC) NewHandler(params *protocolHandlerParams) protocolHandler {
-	bare, prefix := grpcContentTypeDefault, grpcContentTypePrefix
 	contentTypes := make(map[string]struct{})
 	for _, name := range params.Codecs.Names() {
-		contentTypes[canonicalizeContentType(prefix+name)] = struct{}{}
+		for _, ct := range grpcContentTypesFromCodecName(g.web, name) {
+			contentTypes[canonicalizeContentType(ct)] = struct{}{}
+		}
 	}
 	// default codec
 	if params.Codecs.Get(codecNameProto) != nil {
-		contentTypes[bare] = struct{}{}
+		if g.web {
+			contentTypes[grpcWebContentTypeDefault] = struct{}{}
+			contentTypes[grpcWebTextContentTypeDefault] = struct{}{}
+		} else {
+			contentTypes[grpcContentTypeDefault] = struct{}{}
+		}
 	}
 	return &grpcHandler{
 		protocolHandlerParams: *params,
+		web:                   g.web,
 		accept:                contentTypes,
 	}
 }
@@ -114,8 +132,12 @@ func (g *protocolGRPC) NewHandler(params *protocolHandlerParams) protocolHandler
 // NewClient implements protocol, so it must return an interface.
 func (g *protocolGRPC) NewClient(params *protocolClientParams) (protocolClient, error) {
 	peer := newPeerFromURL(params.URL, ProtocolGRPC)
+	if g.web {
+		peer = newPeerFromURL(params.URL, ProtocolGRPCWeb)
+	}
 	return &grpcClient{
 		protocolClientParams: *params,
+		web:                  g.web,
 		peer:                 peer,
 	}, nil
 }
@@ -123,6 +145,7 @@ func (g *protocolGRPC) NewClient(params *protocolClientParams) (protocolClient,
 type grpcHandler struct {
 	protocolHandlerParams
 
+	web    bool
 	accept map[string]struct{}
 }
 
@@ -179,24 +202,35 @@ func (g *grpcHandler) NewConn(
 	//
 	// Since we know that these header keys are already in canonical form, we can
 	// skip the normalization in Header.Set.
+	contentType := getHeaderCanonical(request.Header, headerContentType)
+	var requestBody io.Reader = request.Body
+	if g.web && strings.HasPrefix(contentType, grpcWebTextContentTypeDefault) {
+		// decode the request and encode the response of browsers in the text mode
+		requestBody = newGRPCWebTextReader(request.Body)
+		responseWriter = newGRPCWebTextResponseWriter(responseWriter)
+	}
 	header := responseWriter.Header()
-	header[headerContentType] = []string{getHeaderCanonical(request.Header, headerContentType)}
+	header[headerContentType] = []string{contentType}
 	header[grpcHeaderAcceptCompression] = []string{g.CompressionPools.CommaSeparatedNames()}
 	if responseCompression != compressionIdentity {
 		header[grpcHeaderCompression] = []string{responseCompression}
 	}
 
 	// content-type -> codecName -> codec
-	codecName := grpcCodecFromContentType(getHeaderCanonical(request.Header, headerContentType))
+	codecName := grpcCodecFromContentType(g.web, contentType)
 	codec := g.Codecs.Get(codecName) // handler.go guarantees this is not nil
 	backupCodec := g.Codecs.Get(g.ExpectedCodecName)
 	protocolName := ProtocolGRPC
+	if g.web {
+		protocolName = ProtocolGRPCWeb
+	}
 	conn := wrapHandlerConnWithCodedErrors(&grpcHandlerConn{
 		spec: g.Spec,
 		peer: Peer{
 			Addr:     request.RemoteAddr,
 			Protocol: protocolName,
 		},
+		web:        g.web,
 		bufferPool: g.BufferPool,
 		protobuf:   g.Codecs.Protobuf(), // for errors
 		marshaler: grpcMarshaler{
@@ -216,7 +250,7 @@ func (g *grpcHandler) NewConn(
 		request:         request,
 		unmarshaler: grpcUnmarshaler{
 			envelopeReader: envelopeReader{
-				reader:          request.Body,
+				reader:          requestBody,
 				codec:           codec,
 				backupCodec:     backupCodec,
 				compressionPool: g.CompressionPools.Get(requestCompression),
@@ -236,6 +270,7 @@ func (g *grpcHandler) NewConn(
 type grpcClient struct {
 	protocolClientParams
 
+	web  bool
 	peer Peer
 }
 
@@ -249,7 +284,12 @@ func (g *grpcClient) WriteRequestHeader(_ StreamType, header http.Header) {
 	if getHeaderCanonical(header, headerUserAgent) == "" {
 		header[headerUserAgent] = []string{defaultGrpcUserAgent}
 	}
-	header[headerContentType] = []string{grpcContentTypeFromCodecName(g.Codec.Name())}
+	if g.web && getHeaderCanonical(header, headerXUserAgent) == "" {
+		// The gRPC-Web specification asks for X-User-Agent, since browsers
+		// don't allow to set User-Agent. Set both for backend clients.
+		header[headerXUserAgent] = []string{defaultGrpcUserAgent}
+	}
+	header[headerContentType] = []string{grpcContentTypeFromCodecName(g.web, g.Codec.Name())}
 	// gRPC handles compression on a per-message basis, so we don't want to
 	// compress the whole stream. By default, http.Client will ask the server
 	// to gzip the stream if we don't set Accept-Encoding.
@@ -260,9 +300,11 @@ func (g *grpcClient) WriteRequestHeader(_ StreamType, header http.Header) {
 	if acceptCompression := g.CompressionPools.CommaSeparatedNames(); acceptCompression != "" {
 		header[grpcHeaderAcceptCompression] = []string{acceptCompression}
 	}
-	// The gRPC-HTTP2 specification requires this - it flushes out proxies that
-	// don't support HTTP trailers.
-	header["Te"] = []string{"trailers"}
+	if !g.web {
+		// The gRPC-HTTP2 specification requires this - it flushes out proxies that
+		// don't support HTTP trailers.
+		header["Te"] = []string{"trailers"}
+	}
 }
 
 func (g *grpcClient) NewConn(
@@ -313,10 +355,17 @@ func (g *grpcClient) NewConn(
 		responseTrailer: make(http.Header),
 	}
 	duplexCall.SetValidateResponse(conn.validateResponse)
-	conn.readTrailers = func(_ *grpcUnmarshaler, call *duplexHTTPCall) http.Header {
-		// To access HTTP trailers, we need to read the body to EOF.
-		_ = discard(call)
-		return call.ResponseTrailer()
+	if g.web {
+		// gRPC-Web carries the trailers in the last envelope of the body.
+		conn.readTrailers = func(unmarshaler *grpcUnmarshaler, _ *duplexHTTPCall) http.Header {
+			return unmarshaler.WebTrailer()
+		}
+	} else {
+		conn.readTrailers = func(_ *grpcUnmarshaler, call *duplexHTTPCall) http.Header {
+			// To access HTTP trailers, we need to read the body to EOF.
+			_ = discard(call)
+			return call.ResponseTrailer()
+		}
 	}
 	return wrapClientConnWithCodedErrors(conn)
 }
@@ -853,19 +902,41 @@ func grpcEncodeTimeout(timeout time.Duration) (string, error) {
 	return "", errNoTimeout
 }
 
-func grpcCodecFromContentType(contentType string) string {
-	if contentType == grpcContentTypeDefault {
+func grpcCodecFromContentType(web bool, contentType string) string {
+	if !web {
+		if contentType == grpcContentTypeDefault {
+			// implicitly protobuf
+			return codecNameProto
+		}
+		return strings.TrimPrefix(contentType, grpcContentTypePrefix)
+	}
+
+	if contentType == grpcWebContentTypeDefault || contentType == grpcWebTextContentTypeDefault {
 		// implicitly protobuf
 		return codecNameProto
 	}
-
-	return strings.TrimPrefix(contentType, grpcContentTypePrefix)
+	if strings.HasPrefix(contentType, grpcWebTextContentTypePrefix) {
+		return strings.TrimPrefix(contentType, grpcWebTextContentTypePrefix)
+	}
+	return strings.TrimPrefix(contentType, grpcWebContentTypePrefix)
 }
 
-func grpcContentTypeFromCodecName(name string) string {
+func grpcContentTypeFromCodecName(web bool, name string) string {
+	if web {
+		return grpcWebContentTypePrefix + name
+	}
 	return grpcContentTypePrefix + name
 }
 
+// grpcContentTypesFromCodecName returns the content types accepted by the handler for the codec,
+// gRPC-Web accepts both the binary and the text mode.
+func grpcContentTypesFromCodecName(web bool, name string) []string {
+	if web {
+		return []string{grpcWebContentTypePrefix + name, grpcWebTextContentTypePrefix + name}
+	}
+	return []string{grpcContentTypePrefix + name}
+}
+
 func grpcErrorToTrailer(bufferPool *bufferPool, trailer http.Header, protobuf Codec, err error) {
 	if err == nil {
 		setHeaderCanonical(trailer, grpcHeaderStatus, "0") // zero is the gRPC OK status
@@ -0,0 +1,111 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package triple_protocol
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net/http"
+)
+
+// grpcWebTextReader decodes the base64 body of a grpc-web-text request. The body may be made
+// of several padded chunks, so it's decoded by quantums of 4 bytes instead of as a whole.
+type grpcWebTextReader struct {
+	reader  io.Reader
+	encoded []byte // the bytes read but not decoded yet
+	decoded []byte // the bytes decoded but not returned yet
+	err     error
+}
+
+func newGRPCWebTextReader(reader io.Reader) *grpcWebTextReader {
+	return &grpcWebTextReader{reader: reader}
+}
+
+func (r *grpcWebTextReader) Read(p []byte) (int, error) {
+	for len(r.decoded) == 0 {
+		if r.err != nil {
+			if r.err == io.EOF && len(r.encoded) > 0 {
+				return 0, errorf(CodeInvalidArgument, "grpc-web-text: truncated base64 body")
+			}
+			return 0, r.err
+		}
+		r.fill()
+	}
+	n := copy(p, r.decoded)
+	r.decoded = r.decoded[n:]
+	return n, nil
+}
+
+func (r *grpcWebTextReader) fill() {
+	buf := make([]byte, 4096)
+	n, err := r.reader.Read(buf)
+	for _, b := range buf[:n] {
+		// line breaks are tolerated like the decoders of browsers do
+		if b != '\r' && b != '\n' {
+			r.encoded = append(r.encoded, b)
+		}
+	}
+	r.err = err
+
+	size := len(r.encoded) / 4 * 4
+	decoded := make([]byte, 0, size/4*3)
+	quantum := make([]byte, 3)
+	for i := 0; i < size; i += 4 {
+		m, decodeErr := base64.StdEncoding.Decode(quantum, r.encoded[i:i+4])
+		if decodeErr != nil {
+			r.err = errorf(CodeInvalidArgument, "grpc-web-text: invalid base64 body: %w", decodeErr)
+			return
+		}
+		decoded = append(decoded, quantum[:m]...)
+	}
+	r.decoded = decoded
+	r.encoded = r.encoded[size:]
+}
+
+// grpcWebTextResponseWriter encodes the body of a grpc-web-text response. The writes are
+// buffered until flushed, and each flush sends a padded base64 chunk.
+type grpcWebTextResponseWriter struct {
+	http.ResponseWriter
+	buffer bytes.Buffer
+}
+
+func newGRPCWebTextResponseWriter(w http.ResponseWriter) *grpcWebTextResponseWriter {
+	return &grpcWebTextResponseWriter{ResponseWriter: w}
+}
+
+func (w *grpcWebTextResponseWriter) Write(p []byte) (int, error) {
+	return w.buffer.Write(p)
+}
+
+func (w *grpcWebTextResponseWriter) Flush() {
+	if w.buffer.Len() > 0 {
+		encoded := make([]byte, base64.StdEncoding.EncodedLen(w.buffer.Len()))
+		base64.StdEncoding.Encode(encoded, w.buffer.Bytes())
+		w.buffer.Reset()
+		if _, err := w.ResponseWriter.Write(encoded); err != nil {
+			return
+		}
+	}
+	flushResponseWriter(w.ResponseWriter)
+}
+
+// Unwrap returns the underlying http.ResponseWriter for http.ResponseController.
+func (w *grpcWebTextResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
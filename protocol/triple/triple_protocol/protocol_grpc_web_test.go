@@ -0,0 +1,67 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package triple_protocol
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGRPCWebTextReader(t *testing.T) {
+	// a padded chunk followed by another one, with line breaks in between
+	body := base64.StdEncoding.EncodeToString([]byte("hello")) + "\r\n" +
+		base64.StdEncoding.EncodeToString([]byte(" web"))
+	data, err := io.ReadAll(newGRPCWebTextReader(strings.NewReader(body)))
+	assert.Nil(t, err)
+	assert.Equal(t, "hello web", string(data))
+
+	_, err = io.ReadAll(newGRPCWebTextReader(strings.NewReader("aGVsbG8")))
+	assert.Equal(t, CodeInvalidArgument, CodeOf(err))
+
+	_, err = io.ReadAll(newGRPCWebTextReader(strings.NewReader("a!bc")))
+	assert.Equal(t, CodeInvalidArgument, CodeOf(err))
+}
+
+func TestGRPCWebTextResponseWriter(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	w := newGRPCWebTextResponseWriter(recorder)
+	_, err := w.Write([]byte("hel"))
+	assert.Nil(t, err)
+	_, err = w.Write([]byte("lo"))
+	assert.Nil(t, err)
+	// nothing is sent before flushing
+	assert.Zero(t, recorder.Body.Len())
+
+	w.Flush()
+	_, err = w.Write([]byte(" web"))
+	assert.Nil(t, err)
+	w.Flush()
+	assert.True(t, recorder.Flushed)
+	assert.Equal(t, "aGVsbG8=IHdlYg==", recorder.Body.String())
+
+	data, err := io.ReadAll(newGRPCWebTextReader(recorder.Body))
+	assert.Nil(t, err)
+	assert.Equal(t, "hello web", string(data))
+}
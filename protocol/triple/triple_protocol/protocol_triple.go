@@ -24,6 +24,7 @@ import (
 	"io"
 	"math"
 	"net/http"
+	"net/url"
 	"runtime"
 	"strconv"
 	"strings"
@@ -46,6 +47,14 @@ const (
 
 	tripleUnaryContentTypePrefix = "application/"
 	tripleUnaryContentTypeJSON   = tripleUnaryContentTypePrefix + "json"
+
+	// query parameters of unary requests sent with GET
+	tripleUnaryMessageQueryParameter     = "message"
+	tripleUnaryEncodingQueryParameter    = "encoding"
+	tripleUnaryBase64QueryParameter      = "base64"
+	tripleUnaryCompressionQueryParameter = "compression"
+
+	headerCacheControl = "Cache-Control"
 )
 
 // defaultTripleUserAgent returns a User-Agent string similar to those used in gRPC.
@@ -61,8 +70,12 @@ func (*protocolTriple) NewHandler(params *protocolHandlerParams) protocolHandler
 		panic("protocol triple does not support stream type rpc")
 	}
 	methods := make(map[string]struct{})
-	// we decide not to support GET
 	methods[http.MethodPost] = struct{}{}
+	if params.Spec.IdempotencyLevel == IdempotencyNoSideEffects {
+		// procedures without side effects may be called with GET, which makes
+		// their responses cacheable by browsers and CDNs
+		methods[http.MethodGet] = struct{}{}
+	}
 
 	contentTypes := make(map[string]struct{})
 	for _, name := range params.Codecs.Names() {
@@ -119,6 +132,10 @@ func (*tripleHandler) SetTimeout(request *http.Request) (context.Context, contex
 }
 
 func (h *tripleHandler) CanHandlePayload(request *http.Request, contentType string) bool {
+	if request.Method == http.MethodGet {
+		codecName := request.URL.Query().Get(tripleUnaryEncodingQueryParameter)
+		contentType = canonicalizeContentType(tripleContentTypeFromCodecName(h.Spec.StreamType, codecName))
+	}
 	_, ok := h.accept[contentType]
 	return ok
 }
@@ -127,13 +144,20 @@ func (h *tripleHandler) NewConn(
 	responseWriter http.ResponseWriter,
 	request *http.Request,
 ) (handlerConnCloser, bool) {
-	if request.Method != http.MethodPost {
+	isGet := request.Method == http.MethodGet
+	if request.Method != http.MethodPost && !isGet {
 		return nil, false
 	}
 	// We need to parse metadata before entering the interceptor stack; we'll
 	// send the error to the client later on.
+	var query url.Values
 	var contentEncoding, acceptEncoding string
-	contentEncoding = getHeaderCanonical(request.Header, tripleUnaryHeaderCompression)
+	if isGet {
+		query = request.URL.Query()
+		contentEncoding = query.Get(tripleUnaryCompressionQueryParameter)
+	} else {
+		contentEncoding = getHeaderCanonical(request.Header, tripleUnaryHeaderCompression)
+	}
 	acceptEncoding = getHeaderCanonical(request.Header, tripleUnaryHeaderAcceptCompression)
 	requestCompression, responseCompression, failed := negotiateCompression(
 		h.CompressionPools,
@@ -142,7 +166,9 @@ func (h *tripleHandler) NewConn(
 	)
 	if failed == nil {
 		version := getHeaderCanonical(request.Header, tripleHeaderProtocolVersion)
-		if version == "" && h.RequireTripleProtocolHeader {
+		// GET requests are usually issued by browsers and caches, which can't be
+		// expected to send the header
+		if version == "" && h.RequireTripleProtocolHeader && !isGet {
 			failed = errorf(CodeInvalidArgument, "missing required header: set %s to %q", tripleHeaderProtocolVersion, tripleProtocolVersion)
 		} else if version != "" && version != tripleProtocolVersion {
 			failed = errorf(CodeInvalidArgument, "%s must be %q: got %q", tripleHeaderProtocolVersion, tripleProtocolVersion, version)
@@ -154,6 +180,14 @@ func (h *tripleHandler) NewConn(
 	requestBody = request.Body
 	//Prioritize codec specified by content-type
 	contentType = getHeaderCanonical(request.Header, headerContentType)
+	if isGet {
+		contentType = tripleContentTypeFromCodecName(h.Spec.StreamType, query.Get(tripleUnaryEncodingQueryParameter))
+		message, err := decodeTripleQueryMessage(query)
+		if err != nil && failed == nil {
+			failed = err
+		}
+		requestBody = io.NopCloser(bytes.NewReader(message))
+	}
 	codecName = tripleCodecFromContentType(
 		h.Spec.StreamType,
 		contentType,
@@ -184,7 +218,7 @@ func (h *tripleHandler) NewConn(
 		Addr:     request.RemoteAddr,
 		Protocol: ProtocolTriple,
 	}
-	conn = &tripleUnaryHandlerConn{
+	unaryConn := &tripleUnaryHandlerConn{
 		spec:           h.Spec,
 		peer:           peer,
 		request:        request,
@@ -210,7 +244,10 @@ func (h *tripleHandler) NewConn(
 		},
 		responseTrailer: make(http.Header),
 	}
-	conn = wrapHandlerConnWithCodedErrors(conn)
+	if isGet {
+		unaryConn.cacheControl = h.CacheControl
+	}
+	conn = wrapHandlerConnWithCodedErrors(unaryConn)
 
 	if failed != nil {
 		// Negotiation failed, so we can't establish a stream.
@@ -267,6 +304,7 @@ func (c *tripleClient) NewConn(
 		compressionPools: c.CompressionPools,
 		bufferPool:       c.BufferPool,
 		marshaler: tripleUnaryRequestMarshaler{
+			duplexCall: duplexCall,
 			tripleUnaryMarshaler: tripleUnaryMarshaler{
 				writer:           duplexCall,
 				codec:            c.Codec,
@@ -287,6 +325,14 @@ func (c *tripleClient) NewConn(
 		responseHeader:  make(http.Header),
 		responseTrailer: make(http.Header),
 	}
+	if c.EnableGet && spec.IdempotencyLevel == IdempotencyNoSideEffects {
+		unaryConn.marshaler.enableGet = true
+		unaryConn.marshaler.getURLMaxBytes = c.GetURLMaxBytes
+		unaryConn.marshaler.getUseFallback = c.GetUseFallback
+		if stable, ok := c.Codec.(stableCodec); ok {
+			unaryConn.marshaler.stableCodec = stable
+		}
+	}
 	duplexCall.SetValidateResponse(unaryConn.validateResponse)
 	return wrapClientConnWithCodedErrors(unaryConn)
 }
@@ -400,6 +446,7 @@ type tripleUnaryHandlerConn struct {
 	marshaler       tripleUnaryMarshaler
 	unmarshaler     tripleUnaryUnmarshaler
 	responseTrailer http.Header
+	cacheControl    string
 	wroteBody       bool
 }
 
@@ -472,6 +519,9 @@ func (hc *tripleUnaryHandlerConn) writeResponseHeader(err error) {
 		if tripleErr, ok := asError(err); ok {
 			mergeHeaders(header, tripleErr.meta)
 		}
+	} else if hc.cacheControl != "" && getHeaderCanonical(header, headerCacheControl) == "" {
+		// the implementation may decide the caching of each response by itself
+		header[headerCacheControl] = []string{hc.cacheControl}
 	}
 	for k, v := range hc.responseTrailer {
 		header[tripleUnaryTrailerPrefix+k] = v
@@ -537,12 +587,130 @@ func (m *tripleUnaryMarshaler) write(data []byte) *Error {
 
 type tripleUnaryRequestMarshaler struct {
 	tripleUnaryMarshaler
+
+	enableGet      bool
+	getURLMaxBytes int
+	getUseFallback bool
+	stableCodec    stableCodec
+	duplexCall     *duplexHTTPCall
 }
 
 func (m *tripleUnaryRequestMarshaler) Marshal(message any) *Error {
+	if m.enableGet {
+		if m.stableCodec == nil && !m.getUseFallback {
+			return errorf(CodeInternal, "codec %s doesn't support stable marshal; can't use get", m.codec.Name())
+		}
+		if m.stableCodec != nil {
+			return m.marshalWithGet(message)
+		}
+	}
 	return m.tripleUnaryMarshaler.Marshal(message)
 }
 
+// marshalWithGet sends the message in the URL of a GET request, compressing it
+// when the URL would be too long, and falls back to POST if allowed.
+func (m *tripleUnaryRequestMarshaler) marshalWithGet(message any) *Error {
+	var data []byte
+	if message != nil {
+		var err error
+		if data, err = m.stableCodec.MarshalStable(message); err != nil {
+			return errorf(CodeInternal, "marshal message stable: %w", err)
+		}
+	}
+	isTooBig := m.sendMaxBytes > 0 && len(data) > m.sendMaxBytes
+	if isTooBig && m.compressionPool == nil {
+		return NewError(CodeResourceExhausted, fmt.Errorf(
+			"message size %d exceeds sendMaxBytes %d: enabling request compression may help",
+			len(data),
+			m.sendMaxBytes,
+		))
+	}
+	if !isTooBig {
+		getURL := m.buildGetURL(data, false /* compressed */)
+		if m.getURLMaxBytes <= 0 || len(getURL.String()) < m.getURLMaxBytes {
+			m.writeWithGet(getURL)
+			return nil
+		}
+		if m.compressionPool == nil {
+			if m.getUseFallback {
+				return m.write(data)
+			}
+			return NewError(CodeResourceExhausted, fmt.Errorf(
+				"url size %d exceeds getURLMaxBytes %d: enabling request compression may help",
+				len(getURL.String()),
+				m.getURLMaxBytes,
+			))
+		}
+	}
+	// compress the message to try to make it fit in the URL
+	uncompressed := bytes.NewBuffer(data)
+	defer m.bufferPool.Put(uncompressed)
+	compressed := m.bufferPool.Get()
+	defer m.bufferPool.Put(compressed)
+	if err := m.compressionPool.Compress(compressed, uncompressed); err != nil {
+		return err
+	}
+	if m.sendMaxBytes > 0 && compressed.Len() > m.sendMaxBytes {
+		return NewError(CodeResourceExhausted, fmt.Errorf(
+			"compressed message size %d exceeds sendMaxBytes %d",
+			compressed.Len(),
+			m.sendMaxBytes,
+		))
+	}
+	getURL := m.buildGetURL(compressed.Bytes(), true /* compressed */)
+	if m.getURLMaxBytes <= 0 || len(getURL.String()) < m.getURLMaxBytes {
+		m.writeWithGet(getURL)
+		return nil
+	}
+	if m.getUseFallback {
+		setHeaderCanonical(m.header, tripleUnaryHeaderCompression, m.compressionName)
+		return m.write(compressed.Bytes())
+	}
+	return NewError(CodeResourceExhausted, fmt.Errorf(
+		"url size %d exceeds getURLMaxBytes %d",
+		len(getURL.String()),
+		m.getURLMaxBytes,
+	))
+}
+
+func (m *tripleUnaryRequestMarshaler) buildGetURL(data []byte, compressed bool) *url.URL {
+	getURL := *m.duplexCall.URL()
+	query := getURL.Query()
+	query.Set(tripleUnaryEncodingQueryParameter, m.codec.Name())
+	if m.stableCodec.IsBinary() || compressed {
+		query.Set(tripleUnaryMessageQueryParameter, base64.RawURLEncoding.EncodeToString(data))
+		query.Set(tripleUnaryBase64QueryParameter, "1")
+	} else {
+		query.Set(tripleUnaryMessageQueryParameter, string(data))
+	}
+	if compressed {
+		query.Set(tripleUnaryCompressionQueryParameter, m.compressionName)
+	}
+	getURL.RawQuery = query.Encode()
+	return &getURL
+}
+
+func (m *tripleUnaryRequestMarshaler) writeWithGet(getURL *url.URL) {
+	delHeaderCanonical(m.header, headerContentType)
+	delHeaderCanonical(m.header, tripleUnaryHeaderCompression)
+	m.duplexCall.SetMethod(http.MethodGet)
+	*m.duplexCall.URL() = *getURL
+}
+
+// decodeTripleQueryMessage reads the message of a GET request from its query,
+// where binary or compressed messages are encoded with unpadded URL-safe base64.
+func decodeTripleQueryMessage(query url.Values) ([]byte, *Error) {
+	message := query.Get(tripleUnaryMessageQueryParameter)
+	if query.Get(tripleUnaryBase64QueryParameter) != "1" {
+		return []byte(message), nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(message, "="))
+	if err != nil {
+		return nil, errorf(CodeInvalidArgument, "decode base64 message: %w", err)
+	}
+	return data, nil
+}
+
 type tripleUnaryUnmarshaler struct {
 	reader          io.Reader
 	codec           Codec
@@ -21,6 +21,7 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"net"
 	"net/http"
 )
 
@@ -29,7 +30,6 @@ import (
 
 	"github.com/dubbogo/grpc-go"
 
-	"github.com/quic-go/quic-go"
 	"github.com/quic-go/quic-go/http3"
 
 	"golang.org/x/net/http2"
@@ -50,6 +50,10 @@ type Server struct {
 	httpSrv      *http.Server
 	http3Srv     *http3.Server
 	tripleConfig *global.TripleConfig // Configuration for the triple protocol
+	settings     *TransportSettings   // HTTP/2 and HTTP/3 tuning parsed from tripleConfig
+	handler      http.Handler         // mux wrapped with the WebSocket and CORS handling
+	webSocket    *webSocketHandler    // the calls over WebSocket
+	transcoder   *transcoder          // RESTful routes of the google.api.http rules
 }
 
 func (s *Server) RegisterUnaryHandler(
@@ -65,7 +69,7 @@ func (s *Server) RegisterUnaryHandler(
 		s.mux.Handle(procedure, hdl)
 	} else {
 		config := newHandlerConfig(procedure, options)
-		implementation := generateUnaryHandlerFunc(procedure, reqInitFunc, unary, config.Interceptor)
+		implementation := config.observeMaxBytes(generateUnaryHandlerFunc(procedure, reqInitFunc, unary, config.Interceptor))
 		hdl.processImplementation(getIdentifier(config.Group, config.Version), implementation)
 	}
 
@@ -144,7 +148,7 @@ func (s *Server) RegisterCompatUnaryHandler(
 		s.mux.Handle(procedure, hdl)
 	} else {
 		config := newHandlerConfig(procedure, options)
-		implementation := generateCompatUnaryHandlerFunc(procedure, method, srv, unary, config.Interceptor)
+		implementation := config.observeMaxBytes(generateCompatUnaryHandlerFunc(procedure, method, srv, unary, config.Interceptor))
 		hdl.processImplementation(getIdentifier(config.Group, config.Version), implementation)
 	}
 
@@ -172,7 +176,30 @@ func (s *Server) RegisterCompatStreamHandler(
 	return nil
 }
 
+// init parses the transport settings and wraps the mux with the WebSocket and CORS handling.
+func (s *Server) init() error {
+	settings, err := NewTransportSettings(s.tripleConfig)
+	if err != nil {
+		return err
+	}
+	s.settings = settings
+
+	var corsConf *global.CorsConfig
+	if s.tripleConfig != nil {
+		corsConf = s.tripleConfig.Cors
+	}
+	s.webSocket = newWebSocketHandler(s.mux, settings)
+	if s.handler, err = NewCorsHandler(s.webSocket, corsConf); err != nil {
+		return fmt.Errorf("invalid triple cors config: %w", err)
+	}
+	return nil
+}
+
 func (s *Server) Run(callProtocol string, tlsConf *tls.Config) error {
+	if err := s.init(); err != nil {
+		return err
+	}
+
 	// Support for starting HTTP/2 and HTTP/3 servers simultaneously.
 	switch callProtocol {
 	case constant.CallHTTP2:
@@ -186,16 +213,46 @@ func (s *Server) Run(callProtocol string, tlsConf *tls.Config) error {
 	}
 }
 
-func (s *Server) startHttp2(tlsConf *tls.Config) error {
-	s.httpSrv = &http.Server{
+// newHttpServer creates the HTTP/2 server of handler, which accepts h2c as well.
+func (s *Server) newHttpServer(handler http.Handler, tlsConf *tls.Config) (*http.Server, error) {
+	h2Srv := &http2.Server{}
+	httpSrv := &http.Server{
 		Addr:      s.addr,
-		Handler:   h2c.NewHandler(s.mux, &http2.Server{}),
 		TLSConfig: tlsConf,
 	}
+	s.settings.ConfigureHTTP2Server(httpSrv, h2Srv)
+	if tlsConf != nil {
+		// let HTTP/2 over TLS use the tuned server instead of the one bundled in net/http
+		if err := http2.ConfigureServer(httpSrv, h2Srv); err != nil {
+			return nil, err
+		}
+	}
+	httpSrv.Handler = h2c.NewHandler(handler, h2Srv)
+	return httpSrv, nil
+}
 
-	logger.Debugf("TRIPLE HTTP/2 Server starting on %v", s.addr)
+// newHttp3Server creates the HTTP/3 server of handler.
+func (s *Server) newHttp3Server(handler http.Handler, tlsConf *tls.Config) *http3.Server {
+	return &http3.Server{
+		Addr:    s.addr,
+		Handler: handler,
+		// Adapt and enhance a generic tls.Config object into a configuration
+		// specifically for HTTP/3 services.
+		// ref: https://quic-go.net/docs/http3/server/#setting-up-a-http3server
+		TLSConfig:       http3.ConfigureTLSConfig(tlsConf),
+		QUICConfig:      s.settings.QUICConfig(0, 0),
+		MaxHeaderBytes:  int(s.settings.MaxHeaderListSize),
+		EnableDatagrams: s.settings.EnableDatagrams,
+	}
+}
 
+func (s *Server) startHttp2(tlsConf *tls.Config) error {
 	var err error
+	if s.httpSrv, err = s.newHttpServer(s.handler, tlsConf); err != nil {
+		return err
+	}
+
+	logger.Debugf("TRIPLE HTTP/2 Server starting on %v", s.addr)
 
 	if tlsConf != nil {
 		err = s.httpSrv.ListenAndServeTLS("", "")
@@ -206,21 +263,35 @@ func (s *Server) startHttp2(tlsConf *tls.Config) error {
 	return err
 }
 
+// Serve serves HTTP/2 on lis instead of listening on the address of the server, e.g. on
+// a unix domain socket or a MemoryListener. HTTP/3 needs UDP and is not served.
+func (s *Server) Serve(lis net.Listener, tlsConf *tls.Config) error {
+	if err := s.init(); err != nil {
+		return err
+	}
+
+	var err error
+	if s.httpSrv, err = s.newHttpServer(s.handler, tlsConf); err != nil {
+		return err
+	}
+
+	logger.Debugf("TRIPLE HTTP/2 Server starting on %v", lis.Addr())
+
+	if tlsConf != nil {
+		err = s.httpSrv.ServeTLS(lis, "", "")
+	} else {
+		err = s.httpSrv.Serve(lis)
+	}
+
+	return err
+}
+
 func (s *Server) startHttp3(tlsConf *tls.Config) error {
 	if tlsConf == nil {
 		return fmt.Errorf("TRIPLE HTTP/3 Server must have TLS config, but TLS config is nil")
 	}
 
-	s.http3Srv = &http3.Server{
-		Addr:    s.addr,
-		Handler: s.mux,
-		// Adapt and enhance a generic tls.Config object into a configuration
-		// specifically for HTTP/3 services.
-		// ref: https://quic-go.net/docs/http3/server/#setting-up-a-http3server
-		TLSConfig: http3.ConfigureTLSConfig(tlsConf),
-		// TODO: Detailed QUIC configuration.
-		QUICConfig: &quic.Config{},
-	}
+	s.http3Srv = s.newHttp3Server(s.handler, tlsConf)
 
 	logger.Debugf("TRIPLE HTTP/3 Server starting on %v", s.addr)
 
@@ -234,25 +305,19 @@ func (s *Server) startHttp2AndHttp3(tlsConf *tls.Config) error {
 	}
 
 	// Start HTTP/3 server first to get its configuration
-	s.http3Srv = &http3.Server{
-		Addr:       s.addr,
-		Handler:    s.mux,
-		TLSConfig:  http3.ConfigureTLSConfig(tlsConf),
-		QUICConfig: &quic.Config{},
-	}
+	s.http3Srv = s.newHttp3Server(s.handler, tlsConf)
 
 	// Create Alt-Svc handler wrapper for HTTP/2 server
 	var negotiation bool
 	if s.tripleConfig != nil && s.tripleConfig.Http3 != nil {
 		negotiation = s.tripleConfig.Http3.Negotiation
 	}
-	altSvcHandler := NewAltSvcHandler(s.mux, s.http3Srv, negotiation)
+	altSvcHandler := NewAltSvcHandler(s.handler, s.http3Srv, negotiation)
 
 	// Start HTTP/2 server with Alt-Svc handler wrapper
-	s.httpSrv = &http.Server{
-		Addr:      s.addr,
-		Handler:   h2c.NewHandler(altSvcHandler, &http2.Server{}),
-		TLSConfig: tlsConf,
+	var err error
+	if s.httpSrv, err = s.newHttpServer(altSvcHandler, tlsConf); err != nil {
+		return err
 	}
 
 	logger.Debugf("TRIPLE HTTP/2 and HTTP/3 Server starting on %v", s.addr)
@@ -304,6 +369,11 @@ func (s *Server) Stop() error {
 		})
 	}
 
+	// the hijacked WebSocket connections are not closed by the HTTP server
+	if s.webSocket != nil {
+		s.webSocket.Close()
+	}
+
 	// Wait for all goroutines to complete and collect any errors
 	return eg.Wait()
 }
@@ -18,6 +18,7 @@
 package triple_protocol
 
 import (
+	"crypto/tls"
 	"net/http"
 	"net/url"
 	"testing"
@@ -28,6 +29,7 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/global"
 )
 
@@ -98,3 +100,30 @@ func TestServer_RegisterMuxHandle(t *testing.T) {
 		assert.Equal(t, test.path, pattern)
 	}
 }
+
+func TestServer_TransportSettings(t *testing.T) {
+	srv := NewServer("127.0.0.1:0", &global.TripleConfig{IdleTimeout: "5"})
+	assert.NotNil(t, srv.Run(constant.CallHTTP2, nil))
+
+	srv = NewServer("127.0.0.1:0", &global.TripleConfig{
+		MaxConcurrentStreams:    64,
+		InitialStreamWindowSize: "1MiB",
+		MaxHeaderListSize:       "16KiB",
+	})
+	srv.settings, _ = NewTransportSettings(srv.tripleConfig)
+	httpSrv, err := srv.newHttpServer(srv.mux, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 16<<10, httpSrv.MaxHeaderBytes)
+
+	http3Srv := srv.newHttp3Server(srv.mux, &tls.Config{})
+	assert.Equal(t, int64(64), http3Srv.QUICConfig.MaxIncomingStreams)
+	assert.Equal(t, uint64(1<<20), http3Srv.QUICConfig.InitialStreamReceiveWindow)
+	assert.Equal(t, 16<<10, http3Srv.MaxHeaderBytes)
+}
+
+func TestServer_Cors(t *testing.T) {
+	srv := NewServer("127.0.0.1:0", &global.TripleConfig{
+		Cors: &global.CorsConfig{AllowOrigins: []string{"*"}, MaxAge: "1"},
+	})
+	assert.NotNil(t, srv.Run(constant.CallHTTP2, nil))
+}
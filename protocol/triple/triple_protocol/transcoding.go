@@ -0,0 +1,664 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package triple_protocol
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+import (
+	"github.com/dustin/go-humanize"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// httpRoute is a RESTful binding of a unary procedure described by a google.api.http rule.
+type httpRoute struct {
+	method       string
+	template     *pathTemplate
+	procedure    string
+	handler      *Handler
+	input        protoreflect.MessageDescriptor
+	output       protoreflect.MessageDescriptor
+	body         string
+	responseBody string
+}
+
+// transcoder serves the RESTful routes by translating them into triple unary calls
+// with JSON payloads, so that the procedure handlers stay unaware of them.
+type transcoder struct {
+	readMaxBytes int64
+
+	mu     sync.RWMutex
+	routes []*httpRoute
+}
+
+// RegisterHttpRule exposes the unary procedure, which must have been registered before,
+// at the RESTful routes described by rule and its additional bindings. Path variables,
+// query parameters and the request body are mapped onto the input message of md.
+func (s *Server) RegisterHttpRule(procedure string, md protoreflect.MethodDescriptor, rule *annotations.HttpRule) error {
+	hdl, ok := s.handlers[procedure]
+	if !ok {
+		return fmt.Errorf("procedure %s is not registered", procedure)
+	}
+	if md.IsStreamingClient() || md.IsStreamingServer() {
+		return fmt.Errorf("procedure %s is a streaming method and can not be transcoded", procedure)
+	}
+	routes, err := newHttpRoutes(procedure, md, rule)
+	if err != nil {
+		return err
+	}
+	// the handler of a procedure is kept across re-registrations, so the routes hold it
+	// and never touch the handlers map while serving
+	for _, route := range routes {
+		route.handler = hdl
+	}
+	if s.transcoder == nil {
+		readMaxBytes := int64(constant.DefaultMaxServerRecvMsgSize)
+		if s.tripleConfig != nil && s.tripleConfig.MaxServerRecvMsgSize != "" {
+			size, err := humanize.ParseBytes(s.tripleConfig.MaxServerRecvMsgSize)
+			if err != nil {
+				return fmt.Errorf("invalid triple max-server-recv-msg-size %q: %w", s.tripleConfig.MaxServerRecvMsgSize, err)
+			}
+			readMaxBytes = int64(size)
+		}
+		s.transcoder = &transcoder{readMaxBytes: readMaxBytes}
+		// the procedures are registered with exact patterns, so every other path
+		// falls through to the transcoder
+		s.mux.Handle("/", s.transcoder)
+	}
+	return s.transcoder.addRoutes(routes)
+}
+
+func newHttpRoutes(procedure string, md protoreflect.MethodDescriptor, rule *annotations.HttpRule) ([]*httpRoute, error) {
+	var routes []*httpRoute
+	rules := append([]*annotations.HttpRule{rule}, rule.GetAdditionalBindings()...)
+	for i, r := range rules {
+		if i > 0 && len(r.GetAdditionalBindings()) > 0 {
+			return nil, fmt.Errorf("procedure %s: additional bindings must not be nested", procedure)
+		}
+		method, path := httpRulePattern(r)
+		if method == "" {
+			continue
+		}
+		template, err := parsePathTemplate(path)
+		if err != nil {
+			return nil, fmt.Errorf("procedure %s: %w", procedure, err)
+		}
+		route := &httpRoute{
+			method:       method,
+			template:     template,
+			procedure:    procedure,
+			input:        md.Input(),
+			output:       md.Output(),
+			body:         r.GetBody(),
+			responseBody: r.GetResponseBody(),
+		}
+		if err := route.validate(); err != nil {
+			return nil, fmt.Errorf("procedure %s: %w", procedure, err)
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+func httpRulePattern(rule *annotations.HttpRule) (string, string) {
+	switch pattern := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		return http.MethodGet, pattern.Get
+	case *annotations.HttpRule_Put:
+		return http.MethodPut, pattern.Put
+	case *annotations.HttpRule_Post:
+		return http.MethodPost, pattern.Post
+	case *annotations.HttpRule_Delete:
+		return http.MethodDelete, pattern.Delete
+	case *annotations.HttpRule_Patch:
+		return http.MethodPatch, pattern.Patch
+	case *annotations.HttpRule_Custom:
+		return pattern.Custom.GetKind(), pattern.Custom.GetPath()
+	}
+	return "", ""
+}
+
+func (r *httpRoute) validate() error {
+	for _, variable := range r.template.variables {
+		fields, err := lookupFieldPath(r.input, variable.fieldPath)
+		if err != nil {
+			return err
+		}
+		if leaf := fields[len(fields)-1]; leaf.IsList() || leaf.IsMap() || leaf.Message() != nil {
+			return fmt.Errorf("path variable %s must be bound to a singular scalar field", variable.fieldPath)
+		}
+	}
+	if r.body != "" && r.body != "*" && r.input.Fields().ByName(protoreflect.Name(r.body)) == nil {
+		return fmt.Errorf("body field %s is not found in %s", r.body, r.input.FullName())
+	}
+	if r.responseBody != "" && r.output.Fields().ByName(protoreflect.Name(r.responseBody)) == nil {
+		return fmt.Errorf("response body field %s is not found in %s", r.responseBody, r.output.FullName())
+	}
+	return nil
+}
+
+func (t *transcoder) addRoutes(routes []*httpRoute) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	added := make([]*httpRoute, 0, len(routes))
+	for _, route := range routes {
+		duplicated := false
+		for _, existing := range t.routes {
+			if existing.method != route.method || existing.template.raw != route.template.raw {
+				continue
+			}
+			// the same service may be exported several times with different groups or versions
+			if existing.procedure != route.procedure {
+				return fmt.Errorf("http route %s %s is already bound to %s",
+					route.method, route.template.raw, existing.procedure)
+			}
+			duplicated = true
+		}
+		if !duplicated {
+			added = append(added, route)
+		}
+	}
+	t.routes = append(t.routes, added...)
+	return nil
+}
+
+// match returns the route of the request with the values of its path variables. When
+// only the method differs, the allowed methods are returned instead.
+func (t *transcoder) match(method, path string) (*httpRoute, map[string]string, []string) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	var allowed []string
+	for _, route := range t.routes {
+		values, ok := route.template.match(path)
+		if !ok {
+			continue
+		}
+		if route.method != method {
+			allowed = append(allowed, route.method)
+			continue
+		}
+		return route, values, nil
+	}
+	return nil, nil, allowed
+}
+
+func (t *transcoder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	route, values, allowed := t.match(r.Method, r.URL.EscapedPath())
+	if route == nil {
+		if len(allowed) > 0 {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			writeTranscodingError(w, http.StatusMethodNotAllowed,
+				errorf(CodeUnimplemented, "method %s is not allowed", r.Method))
+			return
+		}
+		http.NotFound(w, r)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, t.readMaxBytes))
+	if err != nil {
+		if maxBytesErr := asMaxBytesError(err, "read request body"); maxBytesErr != nil {
+			writeTranscodingError(w, tripleCodeToHTTP(maxBytesErr.Code()), maxBytesErr)
+			return
+		}
+		writeTranscodingError(w, http.StatusBadRequest, errorf(CodeInvalidArgument, "read request body: %w", err))
+		return
+	}
+	payload, err := route.transcodeRequest(values, r.URL.Query(), body)
+	if err != nil {
+		writeTranscodingError(w, http.StatusBadRequest, NewError(CodeInvalidArgument, err))
+		return
+	}
+	hdl := route.handler
+
+	req := r.Clone(r.Context())
+	req.Method = http.MethodPost
+	req.URL.Path = route.procedure
+	req.URL.RawPath = ""
+	req.URL.RawQuery = ""
+	req.RequestURI = route.procedure
+	req.Body = io.NopCloser(bytes.NewReader(payload))
+	req.ContentLength = int64(len(payload))
+	req.Header.Set(headerContentType, tripleUnaryContentTypeJSON)
+	req.Header.Set(tripleHeaderProtocolVersion, tripleProtocolVersion)
+	req.Header.Del(tripleUnaryHeaderCompression)
+	req.Header.Del("Content-Length")
+	if route.responseBody == "" {
+		hdl.ServeHTTP(w, req)
+		return
+	}
+
+	// the response has to be rewritten, so it is buffered without compression
+	req.Header.Del(tripleUnaryHeaderAcceptCompression)
+	buffered := &bufferedResponseWriter{header: make(http.Header), status: http.StatusOK}
+	hdl.ServeHTTP(buffered, req)
+	data := buffered.body.Bytes()
+	if buffered.status == http.StatusOK {
+		if data, err = route.transcodeResponse(data); err != nil {
+			writeTranscodingError(w, http.StatusInternalServerError, NewError(CodeInternal, err))
+			return
+		}
+	}
+	mergeHeaders(w.Header(), buffered.header)
+	w.Header().Del("Content-Length")
+	w.WriteHeader(buffered.status)
+	_, _ = w.Write(data)
+}
+
+// bufferedResponseWriter keeps the response of a procedure so that it can be rewritten.
+type bufferedResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedResponseWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedResponseWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+// transcodeRequest builds the JSON payload of the input message from the request body,
+// the path variables and the query parameters, in that order of precedence.
+func (r *httpRoute) transcodeRequest(values map[string]string, query url.Values, body []byte) ([]byte, error) {
+	msg := dynamicpb.NewMessage(r.input)
+	if r.body != "" && len(bytes.TrimSpace(body)) > 0 {
+		data := body
+		if r.body != "*" {
+			fd := r.input.Fields().ByName(protoreflect.Name(r.body))
+			wrapped, err := json.Marshal(map[string]json.RawMessage{fd.JSONName(): body})
+			if err != nil {
+				return nil, fmt.Errorf("invalid request body: %w", err)
+			}
+			data = wrapped
+		}
+		if err := protojson.Unmarshal(data, msg); err != nil {
+			return nil, fmt.Errorf("invalid request body: %w", err)
+		}
+	}
+	for fieldPath, value := range values {
+		if err := setFieldPath(msg, fieldPath, []string{value}); err != nil {
+			return nil, fmt.Errorf("invalid path variable %s: %w", fieldPath, err)
+		}
+	}
+	if r.body != "*" {
+		for key, vals := range query {
+			if r.isBound(key) {
+				continue
+			}
+			if _, err := lookupFieldPath(r.input, key); err != nil {
+				// unknown query parameters are ignored, like the other HTTP frameworks do
+				continue
+			}
+			if err := setFieldPath(msg, key, vals); err != nil {
+				return nil, fmt.Errorf("invalid query parameter %s: %w", key, err)
+			}
+		}
+	}
+	return protojson.Marshal(msg)
+}
+
+// isBound reports whether the field is already populated by a path variable or the body.
+func (r *httpRoute) isBound(fieldPath string) bool {
+	bound := make([]string, 0, len(r.template.variables)+1)
+	for _, variable := range r.template.variables {
+		bound = append(bound, variable.fieldPath)
+	}
+	if r.body != "" {
+		bound = append(bound, r.body)
+	}
+	for _, b := range bound {
+		if fieldPath == b || strings.HasPrefix(fieldPath, b+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// transcodeResponse extracts the response_body field from the JSON of the output message.
+func (r *httpRoute) transcodeResponse(data []byte) ([]byte, error) {
+	msg := dynamicpb.NewMessage(r.output)
+	if err := protojson.Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("invalid response message: %w", err)
+	}
+	fd := r.output.Fields().ByName(protoreflect.Name(r.responseBody))
+	field := dynamicpb.NewMessage(r.output)
+	field.Set(fd, msg.Get(fd))
+	all, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(field)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(all, &fields); err != nil {
+		return nil, err
+	}
+	return fields[fd.JSONName()], nil
+}
+
+func writeTranscodingError(w http.ResponseWriter, status int, err *Error) {
+	w.Header().Set(headerContentType, tripleUnaryContentTypeJSON)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(newTripleWireError(err))
+}
+
+// lookupFieldPath resolves a dot-separated path, whose elements are either proto or
+// JSON field names, to the field descriptors along it.
+func lookupFieldPath(md protoreflect.MessageDescriptor, fieldPath string) ([]protoreflect.FieldDescriptor, error) {
+	names := strings.Split(fieldPath, ".")
+	fields := make([]protoreflect.FieldDescriptor, 0, len(names))
+	for i, name := range names {
+		if md == nil {
+			return nil, fmt.Errorf("field %s is not a message", strings.Join(names[:i], "."))
+		}
+		fd := md.Fields().ByName(protoreflect.Name(name))
+		if fd == nil {
+			fd = md.Fields().ByJSONName(name)
+		}
+		if fd == nil {
+			return nil, fmt.Errorf("field %s is not found in %s", name, md.FullName())
+		}
+		if i < len(names)-1 && (fd.IsList() || fd.IsMap()) {
+			return nil, fmt.Errorf("field %s is repeated and can not be traversed", name)
+		}
+		fields = append(fields, fd)
+		md = fd.Message()
+	}
+	return fields, nil
+}
+
+func setFieldPath(msg protoreflect.Message, fieldPath string, values []string) error {
+	fields, err := lookupFieldPath(msg.Descriptor(), fieldPath)
+	if err != nil {
+		return err
+	}
+	for _, fd := range fields[:len(fields)-1] {
+		msg = msg.Mutable(fd).Message()
+	}
+	leaf := fields[len(fields)-1]
+	switch {
+	case leaf.IsMap():
+		return fmt.Errorf("map field %s is not supported", leaf.Name())
+	case leaf.IsList():
+		list := msg.Mutable(leaf).List()
+		for _, value := range values {
+			elem, err := parseFieldValue(leaf, list.NewElement(), value)
+			if err != nil {
+				return err
+			}
+			list.Append(elem)
+		}
+	default:
+		if len(values) == 0 {
+			return nil
+		}
+		// like the query strings of repeated fields, the last value wins
+		value, err := parseFieldValue(leaf, msg.NewField(leaf), values[len(values)-1])
+		if err != nil {
+			return err
+		}
+		msg.Set(leaf, value)
+	}
+	return nil
+}
+
+// parseFieldValue converts the text of a path variable or query parameter into a value
+// of the field. zero is a new value of the field, which is used for message fields.
+func parseFieldValue(fd protoreflect.FieldDescriptor, zero protoreflect.Value, value string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		v, err := strconv.ParseBool(value)
+		return protoreflect.ValueOfBool(v), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		v, err := strconv.ParseInt(value, 10, 32)
+		return protoreflect.ValueOfInt32(int32(v)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		v, err := strconv.ParseInt(value, 10, 64)
+		return protoreflect.ValueOfInt64(v), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		v, err := strconv.ParseUint(value, 10, 32)
+		return protoreflect.ValueOfUint32(uint32(v)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		v, err := strconv.ParseUint(value, 10, 64)
+		return protoreflect.ValueOfUint64(v), err
+	case protoreflect.FloatKind:
+		v, err := strconv.ParseFloat(value, 32)
+		return protoreflect.ValueOfFloat32(float32(v)), err
+	case protoreflect.DoubleKind:
+		v, err := strconv.ParseFloat(value, 64)
+		return protoreflect.ValueOfFloat64(v), err
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(value), nil
+	case protoreflect.BytesKind:
+		v, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			v, err = base64.URLEncoding.DecodeString(value)
+		}
+		return protoreflect.ValueOfBytes(v), err
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByName(protoreflect.Name(value)); ev != nil {
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+		v, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("unknown value %q of enum %s", value, fd.Enum().FullName())
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(v)), nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		// well-known types such as Timestamp, Duration and wrappers take their JSON form
+		m := zero.Message().Interface()
+		if json.Valid([]byte(value)) && protojson.Unmarshal([]byte(value), m) == nil {
+			return zero, nil
+		}
+		if err := protojson.Unmarshal([]byte(strconv.Quote(value)), m); err != nil {
+			return protoreflect.Value{}, err
+		}
+		return zero, nil
+	}
+	return protoreflect.Value{}, fmt.Errorf("unsupported field kind %s", fd.Kind())
+}
+
+var escapedSlashReplacer = strings.NewReplacer("%2F", "%252F", "%2f", "%252f")
+
+// pathTemplate is a parsed path of a google.api.http rule:
+//
+//	Template = "/" Segments [ Verb ] ;
+//	Segments = Segment { "/" Segment } ;
+//	Segment  = "*" | "**" | LITERAL | Variable ;
+//	Variable = "{" FieldPath [ "=" Segments ] "}" ;
+//	Verb     = ":" LITERAL ;
+type pathTemplate struct {
+	raw       string
+	segments  []string // literals, "*" or "**"
+	verb      string
+	variables []pathVariable
+}
+
+// pathVariable binds the segments[start:end] of a template to a field.
+type pathVariable struct {
+	fieldPath string
+	start     int
+	end       int
+}
+
+func parsePathTemplate(path string) (*pathTemplate, error) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("invalid http path template %q: must start with /", path)
+	}
+	template := &pathTemplate{raw: path}
+	var pieces []string
+	depth, begin := 0, 1
+	for i := 1; i < len(path); i++ {
+		switch path[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case '/':
+			if depth == 0 {
+				pieces = append(pieces, path[begin:i])
+				begin = i + 1
+			}
+		}
+		if depth < 0 || depth > 1 {
+			return nil, fmt.Errorf("invalid http path template %q: unbalanced braces", path)
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("invalid http path template %q: unbalanced braces", path)
+	}
+	last := path[begin:]
+	if idx := strings.LastIndexByte(last, ':'); idx >= 0 && idx > strings.LastIndexByte(last, '}') {
+		template.verb = last[idx+1:]
+		last = last[:idx]
+		if template.verb == "" {
+			return nil, fmt.Errorf("invalid http path template %q: empty verb", path)
+		}
+	}
+	pieces = append(pieces, last)
+
+	for _, piece := range pieces {
+		if !strings.HasPrefix(piece, "{") {
+			if err := template.appendSegment(piece); err != nil {
+				return nil, fmt.Errorf("invalid http path template %q: %w", path, err)
+			}
+			continue
+		}
+		if !strings.HasSuffix(piece, "}") {
+			return nil, fmt.Errorf("invalid http path template %q: malformed variable %s", path, piece)
+		}
+		fieldPath, segments, found := strings.Cut(piece[1:len(piece)-1], "=")
+		if !found {
+			segments = "*"
+		}
+		if fieldPath == "" {
+			return nil, fmt.Errorf("invalid http path template %q: empty variable name", path)
+		}
+		variable := pathVariable{fieldPath: fieldPath, start: len(template.segments)}
+		for _, segment := range strings.Split(segments, "/") {
+			if err := template.appendSegment(segment); err != nil {
+				return nil, fmt.Errorf("invalid http path template %q: %w", path, err)
+			}
+		}
+		variable.end = len(template.segments)
+		template.variables = append(template.variables, variable)
+	}
+	for i, segment := range template.segments {
+		if segment == "**" && i != len(template.segments)-1 {
+			return nil, fmt.Errorf("invalid http path template %q: ** must be the last segment", path)
+		}
+	}
+	return template, nil
+}
+
+func (t *pathTemplate) appendSegment(segment string) error {
+	if segment == "" {
+		return errors.New("empty segment")
+	}
+	if strings.ContainsAny(segment, "{}=") {
+		return fmt.Errorf("malformed segment %s", segment)
+	}
+	t.segments = append(t.segments, segment)
+	return nil
+}
+
+// match matches the escaped path of a request, returning the unescaped values of the variables.
+func (t *pathTemplate) match(path string) (map[string]string, bool) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, false
+	}
+	path = path[1:]
+	if t.verb != "" {
+		if !strings.HasSuffix(path, ":"+t.verb) {
+			return nil, false
+		}
+		path = strings.TrimSuffix(path, ":"+t.verb)
+	}
+	parts := strings.Split(path, "/")
+	matched := make([]string, len(t.segments))
+	for i, segment := range t.segments {
+		if segment == "**" {
+			// ** needs a non-empty tail, /v1/{name=**} does not match /v1 or /v1/
+			if i >= len(parts) || parts[i] == "" {
+				return nil, false
+			}
+			matched[i] = strings.Join(parts[i:], "/")
+			parts = parts[:i+1]
+			break
+		}
+		if i >= len(parts) || parts[i] == "" {
+			return nil, false
+		}
+		if segment != "*" {
+			literal, err := url.PathUnescape(parts[i])
+			if err != nil || literal != segment {
+				return nil, false
+			}
+		}
+		matched[i] = parts[i]
+	}
+	if len(parts) != len(t.segments) {
+		return nil, false
+	}
+
+	values := make(map[string]string, len(t.variables))
+	for _, variable := range t.variables {
+		segments := matched[variable.start:variable.end]
+		if len(segments) == 1 {
+			// a single segment variable is fully unescaped, including %2F
+			value, err := url.PathUnescape(segments[0])
+			if err != nil {
+				return nil, false
+			}
+			values[variable.fieldPath] = value
+			continue
+		}
+		// a multi segment variable keeps the escaped slashes, so that they are
+		// distinguishable from the separators
+		value, err := url.PathUnescape(escapedSlashReplacer.Replace(strings.Join(segments, "/")))
+		if err != nil {
+			return nil, false
+		}
+		values[variable.fieldPath] = value
+	}
+	return values, true
+}
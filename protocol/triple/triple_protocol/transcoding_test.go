@@ -0,0 +1,399 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package triple_protocol
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// newTranscodingService builds the descriptor of
+//
+//	service UserService {
+//	  rpc GetUser(GetUserRequest) returns (User) {
+//	    option (google.api.http) = {
+//	      get: "/v1/users/{id}"
+//	      additional_bindings { get: "/v1/{name=orgs/*/users/*}" }
+//	    };
+//	  }
+//	  rpc UpdateUser(UpdateUserRequest) returns (UserResponse) {
+//	    option (google.api.http) = { patch: "/v1/users/{id}" body: "user" response_body: "user" };
+//	  }
+//	}
+func newTranscodingService(t *testing.T) protoreflect.ServiceDescriptor {
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+		fd := &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(number),
+			Type:   typ.Enum(),
+			Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		}
+		if typeName != "" {
+			fd.TypeName = proto.String(typeName)
+		}
+		return fd
+	}
+	method := func(name, input, output string, rule *annotations.HttpRule) *descriptorpb.MethodDescriptorProto {
+		options := &descriptorpb.MethodOptions{}
+		proto.SetExtension(options, annotations.E_Http, rule)
+		return &descriptorpb.MethodDescriptorProto{
+			Name:       proto.String(name),
+			InputType:  proto.String(input),
+			OutputType: proto.String(output),
+			Options:    options,
+		}
+	}
+	tags := field("tags", 4, descriptorpb.FieldDescriptorProto_TYPE_STRING, "")
+	tags.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("transcoding_test.proto"),
+		Package: proto.String("transcoding.test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("GetUserRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("id", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+					field("name", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+					field("page_size", 3, descriptorpb.FieldDescriptorProto_TYPE_INT32, ""),
+					tags,
+					field("filter", 5, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".transcoding.test.Filter"),
+				},
+			},
+			{
+				Name: proto.String("Filter"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("active", 1, descriptorpb.FieldDescriptorProto_TYPE_BOOL, ""),
+				},
+			},
+			{
+				Name: proto.String("User"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("id", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+					field("display_name", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+				},
+			},
+			{
+				Name: proto.String("UpdateUserRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("id", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+					field("user", 2, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".transcoding.test.User"),
+				},
+			},
+			{
+				Name: proto.String("UserResponse"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("user", 1, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".transcoding.test.User"),
+				},
+			},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{
+			{
+				Name: proto.String("UserService"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					method("GetUser", ".transcoding.test.GetUserRequest", ".transcoding.test.User", &annotations.HttpRule{
+						Pattern: &annotations.HttpRule_Get{Get: "/v1/users/{id}"},
+						AdditionalBindings: []*annotations.HttpRule{
+							{Pattern: &annotations.HttpRule_Get{Get: "/v1/{name=orgs/*/users/*}"}},
+						},
+					}),
+					method("UpdateUser", ".transcoding.test.UpdateUserRequest", ".transcoding.test.UserResponse", &annotations.HttpRule{
+						Pattern:      &annotations.HttpRule_Patch{Patch: "/v1/users/{id}"},
+						Body:         "user",
+						ResponseBody: "user",
+					}),
+				},
+			},
+		},
+	}
+	fd, err := protodesc.NewFile(file, nil)
+	assert.Nil(t, err)
+	return fd.Services().Get(0)
+}
+
+func TestParsePathTemplate(t *testing.T) {
+	tests := []struct {
+		path      string
+		segments  []string
+		verb      string
+		variables []pathVariable
+		wantErr   bool
+	}{
+		{path: "/v1/users", segments: []string{"v1", "users"}},
+		{
+			path:      "/v1/users/{id}",
+			segments:  []string{"v1", "users", "*"},
+			variables: []pathVariable{{fieldPath: "id", start: 2, end: 3}},
+		},
+		{
+			path:      "/v1/{name=projects/*/jobs/*}:cancel",
+			segments:  []string{"v1", "projects", "*", "jobs", "*"},
+			verb:      "cancel",
+			variables: []pathVariable{{fieldPath: "name", start: 1, end: 5}},
+		},
+		{
+			path:      "/v1/{bucket}/{object=**}",
+			segments:  []string{"v1", "*", "**"},
+			variables: []pathVariable{{fieldPath: "bucket", start: 1, end: 2}, {fieldPath: "object", start: 2, end: 3}},
+		},
+		{path: "v1/users", wantErr: true},
+		{path: "/v1/{id", wantErr: true},
+		{path: "/v1/{a={b}}", wantErr: true},
+		{path: "/v1//users", wantErr: true},
+		{path: "/v1/**/users", wantErr: true},
+		{path: "/v1/users:", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			template, err := parsePathTemplate(test.path)
+			if test.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, test.segments, template.segments)
+			assert.Equal(t, test.verb, template.verb)
+			assert.Equal(t, test.variables, template.variables)
+		})
+	}
+}
+
+func TestPathTemplate_Match(t *testing.T) {
+	tests := []struct {
+		template string
+		path     string
+		values   map[string]string
+		ok       bool
+	}{
+		{template: "/v1/users/{id}", path: "/v1/users/42", values: map[string]string{"id": "42"}, ok: true},
+		{template: "/v1/users/{id}", path: "/v1/users/a%2Fb", values: map[string]string{"id": "a/b"}, ok: true},
+		{template: "/v1/users/{id}", path: "/v1/users", ok: false},
+		{template: "/v1/users/{id}", path: "/v1/users/", ok: false},
+		{template: "/v1/users/{id}", path: "/v1/users/42/posts", ok: false},
+		{template: "/v1/users/{id}", path: "/v2/users/42", ok: false},
+		{
+			template: "/v1/{name=projects/*/jobs/*}:cancel",
+			path:     "/v1/projects/p%201/jobs/j%2F1:cancel",
+			values:   map[string]string{"name": "projects/p 1/jobs/j%2F1"},
+			ok:       true,
+		},
+		{template: "/v1/{name=projects/*/jobs/*}:cancel", path: "/v1/projects/p/jobs/j", ok: false},
+		{
+			template: "/v1/{bucket}/{object=**}",
+			path:     "/v1/b/dir/file.txt",
+			values:   map[string]string{"bucket": "b", "object": "dir/file.txt"},
+			ok:       true,
+		},
+		{template: "/v1/{name=**}", path: "/v1/a", values: map[string]string{"name": "a"}, ok: true},
+		{template: "/v1/{name=**}", path: "/v1", ok: false},
+		{template: "/v1/{name=**}", path: "/v1/", ok: false},
+		{template: "/v1/{bucket}/{object=**}", path: "/v1/b", ok: false},
+	}
+	for _, test := range tests {
+		t.Run(test.template+" "+test.path, func(t *testing.T) {
+			template, err := parsePathTemplate(test.template)
+			assert.Nil(t, err)
+			values, ok := template.match(test.path)
+			assert.Equal(t, test.ok, ok)
+			if test.ok {
+				assert.Equal(t, test.values, values)
+			}
+		})
+	}
+}
+
+func TestHttpRoute_TranscodeRequest(t *testing.T) {
+	sd := newTranscodingService(t)
+	getUser := sd.Methods().ByName("GetUser")
+	routes, err := newHttpRoutes("/transcoding.test.UserService/GetUser", getUser,
+		proto.GetExtension(getUser.Options(), annotations.E_Http).(*annotations.HttpRule))
+	assert.Nil(t, err)
+	assert.Len(t, routes, 2)
+
+	query := url.Values{
+		"id":            {"ignored"},
+		"pageSize":      {"10"},
+		"tags":          {"a", "b"},
+		"filter.active": {"true"},
+		"unknown":       {"x"},
+	}
+	payload, err := routes[0].transcodeRequest(map[string]string{"id": "42"}, query, nil)
+	assert.Nil(t, err)
+	msg := dynamicpb.NewMessage(getUser.Input())
+	assert.Nil(t, protojson.Unmarshal(payload, msg))
+	fields := getUser.Input().Fields()
+	assert.Equal(t, "42", msg.Get(fields.ByName("id")).String())
+	assert.Equal(t, int64(10), msg.Get(fields.ByName("page_size")).Int())
+	assert.Equal(t, 2, msg.Get(fields.ByName("tags")).List().Len())
+	filter := msg.Get(fields.ByName("filter")).Message()
+	assert.True(t, filter.Get(filter.Descriptor().Fields().ByName("active")).Bool())
+
+	_, err = routes[0].transcodeRequest(map[string]string{"id": "42"}, url.Values{"page_size": {"ten"}}, nil)
+	assert.NotNil(t, err)
+
+	updateUser := sd.Methods().ByName("UpdateUser")
+	routes, err = newHttpRoutes("/transcoding.test.UserService/UpdateUser", updateUser,
+		proto.GetExtension(updateUser.Options(), annotations.E_Http).(*annotations.HttpRule))
+	assert.Nil(t, err)
+	payload, err = routes[0].transcodeRequest(map[string]string{"id": "42"}, nil, []byte(`{"displayName":"dubbo"}`))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"id":"42","user":{"displayName":"dubbo"}}`, string(payload))
+
+	_, err = routes[0].transcodeRequest(map[string]string{"id": "42"}, nil, []byte(`{"unknown":1}`))
+	assert.NotNil(t, err)
+
+	_, err = newHttpRoutes("/transcoding.test.UserService/UpdateUser", updateUser, &annotations.HttpRule{
+		Pattern: &annotations.HttpRule_Post{Post: "/v1/users/{user}"},
+	})
+	assert.NotNil(t, err)
+	_, err = newHttpRoutes("/transcoding.test.UserService/UpdateUser", updateUser, &annotations.HttpRule{
+		Pattern: &annotations.HttpRule_Post{Post: "/v1/users"},
+		Body:    "profile",
+	})
+	assert.NotNil(t, err)
+}
+
+func TestServer_RegisterHttpRule(t *testing.T) {
+	sd := newTranscodingService(t)
+	getUser := sd.Methods().ByName("GetUser")
+	updateUser := sd.Methods().ByName("UpdateUser")
+	user := getUser.Output()
+
+	s := NewServer("127.0.0.1:0", nil)
+	err := s.RegisterHttpRule("/transcoding.test.UserService/GetUser", getUser,
+		proto.GetExtension(getUser.Options(), annotations.E_Http).(*annotations.HttpRule))
+	assert.NotNil(t, err)
+
+	err = s.RegisterUnaryHandler(
+		"/transcoding.test.UserService/GetUser",
+		func() any { return dynamicpb.NewMessage(getUser.Input()) },
+		func(ctx context.Context, req *Request) (*Response, error) {
+			in := req.Msg.(*dynamicpb.Message)
+			id := in.Get(getUser.Input().Fields().ByName("id")).String()
+			if id == "missing" {
+				return nil, NewError(CodeNotFound, nil)
+			}
+			if name := in.Get(getUser.Input().Fields().ByName("name")).String(); name != "" {
+				id = name
+			}
+			out := dynamicpb.NewMessage(user)
+			out.Set(user.Fields().ByName("id"), protoreflect.ValueOfString(id))
+			return NewResponse(out), nil
+		},
+	)
+	assert.Nil(t, err)
+	err = s.RegisterUnaryHandler(
+		"/transcoding.test.UserService/UpdateUser",
+		func() any { return dynamicpb.NewMessage(updateUser.Input()) },
+		func(ctx context.Context, req *Request) (*Response, error) {
+			in := req.Msg.(*dynamicpb.Message)
+			out := dynamicpb.NewMessage(updateUser.Output())
+			out.Set(updateUser.Output().Fields().ByName("user"), in.Get(updateUser.Input().Fields().ByName("user")))
+			return NewResponse(out), nil
+		},
+	)
+	assert.Nil(t, err)
+	for _, md := range []protoreflect.MethodDescriptor{getUser, updateUser} {
+		err = s.RegisterHttpRule("/transcoding.test.UserService/"+string(md.Name()), md,
+			proto.GetExtension(md.Options(), annotations.E_Http).(*annotations.HttpRule))
+		assert.Nil(t, err)
+	}
+	// registering the same rules again is a no-op
+	err = s.RegisterHttpRule("/transcoding.test.UserService/GetUser", getUser,
+		proto.GetExtension(getUser.Options(), annotations.E_Http).(*annotations.HttpRule))
+	assert.Nil(t, err)
+	err = s.RegisterHttpRule("/transcoding.test.UserService/UpdateUser", updateUser, &annotations.HttpRule{
+		Pattern: &annotations.HttpRule_Get{Get: "/v1/users/{id}"},
+	})
+	assert.NotNil(t, err)
+
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, target, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		s.mux.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve(http.MethodGet, "/v1/users/42", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":"42"}`, w.Body.String())
+
+	w = serve(http.MethodGet, "/v1/orgs/dubbo/users/7", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":"orgs/dubbo/users/7"}`, w.Body.String())
+
+	w = serve(http.MethodPatch, "/v1/users/42", `{"id":"42","displayName":"dubbo"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":"42","displayName":"dubbo"}`, w.Body.String())
+
+	w = serve(http.MethodGet, "/v1/users/missing", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	var wireErr map[string]any
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &wireErr))
+	assert.Equal(t, CodeNotFound.String(), wireErr["code"])
+
+	w = serve(http.MethodGet, "/v1/users/42?pageSize=ten", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serve(http.MethodDelete, "/v1/users/42", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET, PATCH", w.Header().Get("Allow"))
+
+	w = serve(http.MethodGet, "/v1/groups/42", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// exporting other services while serving must not race with the transcoded calls
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			err := s.RegisterUnaryHandler(
+				fmt.Sprintf("/transcoding.test.OtherService/Call%d", i),
+				func() any { return dynamicpb.NewMessage(getUser.Input()) },
+				func(ctx context.Context, req *Request) (*Response, error) { return NewResponse(nil), nil },
+			)
+			assert.Nil(t, err)
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		w = serve(http.MethodGet, "/v1/users/42", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,280 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package triple_protocol
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"time"
+)
+
+import (
+	"github.com/dustin/go-humanize"
+
+	"github.com/quic-go/quic-go"
+
+	"golang.org/x/net/http2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/global"
+)
+
+const (
+	// the max receive windows of quic-go, which the initial windows must not exceed
+	quicMaxStreamReceiveWindow     = 6 << 20
+	quicMaxConnectionReceiveWindow = 15 << 20
+
+	defaultMaxConnections    = 4
+	defaultConnOpenThreshold = 0.8
+	defaultConnIdleTimeout   = time.Minute
+)
+
+// TransportSettings is the parsed HTTP/2 and HTTP/3 tuning of a global.TripleConfig,
+// the zero value of each field keeps the default of the underlying transport.
+type TransportSettings struct {
+	MaxConcurrentStreams    uint32
+	InitialStreamWindowSize uint32
+	InitialConnWindowSize   uint32
+	MaxHeaderListSize       uint32
+	IdleTimeout             time.Duration
+	DialTimeout             time.Duration
+
+	Allow0RTT               bool
+	EnableDatagrams         bool
+	DisablePathMTUDiscovery bool
+	InitialPacketSize       uint16
+
+	WebSocketPingInterval time.Duration
+	WebSocketPongTimeout  time.Duration
+
+	// MaxConnections is the max connections of a ConnPool to each address, no pool is used if it's zero.
+	MaxConnections    int
+	ConnOpenThreshold float64
+	ConnIdleTimeout   time.Duration
+
+	// DialContext replaces dialing TCP for HTTP/2, e.g. to reach a unix domain socket or a MemoryListener.
+	DialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// NewTransportSettings parses the transport tuning of tripleConf, tripleConf may be nil.
+func NewTransportSettings(tripleConf *global.TripleConfig) (*TransportSettings, error) {
+	ts := &TransportSettings{}
+	if tripleConf == nil {
+		return ts, nil
+	}
+
+	var err error
+	ts.MaxConcurrentStreams = tripleConf.MaxConcurrentStreams
+	if ts.InitialStreamWindowSize, err = parseSize("initial-stream-window-size", tripleConf.InitialStreamWindowSize); err != nil {
+		return nil, err
+	}
+	if ts.InitialConnWindowSize, err = parseSize("initial-conn-window-size", tripleConf.InitialConnWindowSize); err != nil {
+		return nil, err
+	}
+	if ts.MaxHeaderListSize, err = parseSize("max-header-list-size", tripleConf.MaxHeaderListSize); err != nil {
+		return nil, err
+	}
+	if ts.IdleTimeout, err = parseDuration("idle-timeout", tripleConf.IdleTimeout); err != nil {
+		return nil, err
+	}
+	if ts.DialTimeout, err = parseDuration("dial-timeout", tripleConf.DialTimeout); err != nil {
+		return nil, err
+	}
+
+	if http3Conf := tripleConf.Http3; http3Conf != nil {
+		ts.Allow0RTT = http3Conf.Allow0RTT
+		ts.EnableDatagrams = http3Conf.EnableDatagrams
+		ts.DisablePathMTUDiscovery = http3Conf.DisablePathMTUDiscovery
+		ts.InitialPacketSize = http3Conf.InitialPacketSize
+	}
+	if poolConf := tripleConf.ConnPool; poolConf != nil {
+		if ts.MaxConnections, ts.ConnOpenThreshold, ts.ConnIdleTimeout, err = parseConnPool(poolConf); err != nil {
+			return nil, err
+		}
+	}
+	if wsConf := tripleConf.WebSocket; wsConf != nil {
+		if ts.WebSocketPingInterval, err = parseDuration("websocket ping-interval", wsConf.PingInterval); err != nil {
+			return nil, err
+		}
+		if ts.WebSocketPongTimeout, err = parseDuration("websocket pong-timeout", wsConf.PongTimeout); err != nil {
+			return nil, err
+		}
+	}
+	return ts, nil
+}
+
+func parseSize(name, value string) (uint32, error) {
+	if value == "" {
+		return 0, nil
+	}
+	size, err := humanize.ParseBytes(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid triple %s %q: %w", name, value, err)
+	}
+	// the flow-control windows of HTTP/2 are limited to 2^31-1
+	if size > math.MaxInt32 {
+		return 0, fmt.Errorf("invalid triple %s %q: exceeds %d bytes", name, value, math.MaxInt32)
+	}
+	return uint32(size), nil
+}
+
+func parseConnPool(poolConf *global.ConnPoolConfig) (int, float64, time.Duration, error) {
+	maxConns, threshold := poolConf.MaxConnections, poolConf.OpenThreshold
+	if maxConns < 0 {
+		return 0, 0, 0, fmt.Errorf("invalid triple conn-pool max-connections %d", maxConns)
+	}
+	if maxConns == 0 {
+		maxConns = defaultMaxConnections
+	}
+	if threshold < 0 || threshold > 1 {
+		return 0, 0, 0, fmt.Errorf("invalid triple conn-pool open-threshold %v: not in (0, 1]", threshold)
+	}
+	if threshold == 0 {
+		threshold = defaultConnOpenThreshold
+	}
+	idleTimeout, err := parseDuration("conn-pool idle-timeout", poolConf.IdleTimeout)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if idleTimeout == 0 {
+		idleTimeout = defaultConnIdleTimeout
+	}
+	return maxConns, threshold, idleTimeout, nil
+}
+
+func parseDuration(name, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid triple %s %q: %w", name, value, err)
+	}
+	return d, nil
+}
+
+// ConfigureHTTP2Server applies the settings to the HTTP/2 server and the http.Server serving it.
+func (ts *TransportSettings) ConfigureHTTP2Server(httpSrv *http.Server, h2Srv *http2.Server) {
+	h2Srv.MaxConcurrentStreams = ts.MaxConcurrentStreams
+	h2Srv.MaxUploadBufferPerStream = int32(ts.InitialStreamWindowSize)
+	h2Srv.MaxUploadBufferPerConnection = int32(ts.InitialConnWindowSize)
+	h2Srv.IdleTimeout = ts.IdleTimeout
+	httpSrv.MaxHeaderBytes = int(ts.MaxHeaderListSize)
+	httpSrv.IdleTimeout = ts.IdleTimeout
+}
+
+// ConfigureHTTP2Transport applies the settings to the HTTP/2 transport. The HTTP/2 client of x/net
+// has no flow-control knobs and keeps its fixed windows, see HasHTTP2ClientWindows.
+func (ts *TransportSettings) ConfigureHTTP2Transport(transport *http2.Transport) {
+	transport.MaxHeaderListSize = ts.MaxHeaderListSize
+	transport.IdleConnTimeout = ts.IdleTimeout
+	// honor the stream limit of the server instead of opening extra connections
+	transport.StrictMaxConcurrentStreams = ts.MaxConcurrentStreams > 0
+
+	dial := ts.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	// the dial timeout bounds both dialing and the TLS handshake
+	withDialTimeout := func(ctx context.Context) (context.Context, context.CancelFunc) {
+		if ts.DialTimeout > 0 {
+			return context.WithTimeout(ctx, ts.DialTimeout)
+		}
+		return ctx, func() {}
+	}
+	if transport.AllowHTTP {
+		// h2c dials a plain-text connection for the TLS one expected by the transport
+		transport.DialTLSContext = func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			ctx, cancel := withDialTimeout(ctx)
+			defer cancel()
+			return dial(ctx, network, addr)
+		}
+		return
+	}
+	if ts.DialTimeout > 0 || ts.DialContext != nil {
+		transport.DialTLSContext = func(ctx context.Context, network, addr string, cfg *tls.Config) (net.Conn, error) {
+			ctx, cancel := withDialTimeout(ctx)
+			defer cancel()
+			rawConn, err := dial(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			conn := tls.Client(rawConn, cfg)
+			if err = conn.HandshakeContext(ctx); err != nil {
+				_ = rawConn.Close()
+				return nil, err
+			}
+			if proto := conn.ConnectionState().NegotiatedProtocol; proto != http2.NextProtoTLS {
+				_ = conn.Close()
+				return nil, fmt.Errorf("http2: unexpected ALPN protocol %q; want %q", proto, http2.NextProtoTLS)
+			}
+			return conn, nil
+		}
+	}
+}
+
+// ClientTLSConfig returns the TLS config of the HTTP/3 client. With Allow0RTT the connections share a
+// session cache, so that a reconnection resumes the TLS session of the previous one. Triple requests
+// are POSTs, which HTTP/3 never sends as 0-RTT data, the resumption only spares the certificate exchange.
+func (ts *TransportSettings) ClientTLSConfig(tlsConf *tls.Config) *tls.Config {
+	if !ts.Allow0RTT || tlsConf == nil || tlsConf.ClientSessionCache != nil {
+		return tlsConf
+	}
+	tlsConf = tlsConf.Clone()
+	tlsConf.ClientSessionCache = tls.NewLRUClientSessionCache(0)
+	return tlsConf
+}
+
+// HasHTTP2ClientWindows reports whether flow-control windows are set, which the HTTP/2 client ignores.
+func (ts *TransportSettings) HasHTTP2ClientWindows() bool {
+	return ts.InitialStreamWindowSize > 0 || ts.InitialConnWindowSize > 0
+}
+
+// QUICConfig returns the QUIC config of HTTP/3 with the given keepalive period and the settings.
+// The idle timeout falls back to keepAliveTimeout, the dial timeout bounds the handshake.
+func (ts *TransportSettings) QUICConfig(keepAlivePeriod, keepAliveTimeout time.Duration) *quic.Config {
+	conf := &quic.Config{
+		// ref: https://quic-go.net/docs/quic/connection/#keeping-a-connection-alive
+		KeepAlivePeriod: keepAlivePeriod,
+		// ref: https://quic-go.net/docs/quic/connection/#idle-timeout
+		MaxIdleTimeout:                 keepAliveTimeout,
+		HandshakeIdleTimeout:           ts.DialTimeout,
+		MaxIncomingStreams:             int64(ts.MaxConcurrentStreams),
+		InitialStreamReceiveWindow:     uint64(ts.InitialStreamWindowSize),
+		InitialConnectionReceiveWindow: uint64(ts.InitialConnWindowSize),
+		InitialPacketSize:              ts.InitialPacketSize,
+		DisablePathMTUDiscovery:        ts.DisablePathMTUDiscovery,
+		Allow0RTT:                      ts.Allow0RTT,
+		EnableDatagrams:                ts.EnableDatagrams,
+	}
+	if ts.IdleTimeout > 0 {
+		conf.MaxIdleTimeout = ts.IdleTimeout
+	}
+	if conf.InitialStreamReceiveWindow > quicMaxStreamReceiveWindow {
+		conf.MaxStreamReceiveWindow = conf.InitialStreamReceiveWindow
+	}
+	if conf.InitialConnectionReceiveWindow > quicMaxConnectionReceiveWindow {
+		conf.MaxConnectionReceiveWindow = conf.InitialConnectionReceiveWindow
+	}
+	return conf
+}
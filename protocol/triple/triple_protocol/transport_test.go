@@ -0,0 +1,206 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package triple_protocol
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"golang.org/x/net/http2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/global"
+)
+
+func TestNewTransportSettings(t *testing.T) {
+	ts, err := NewTransportSettings(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, &TransportSettings{}, ts)
+
+	ts, err = NewTransportSettings(&global.TripleConfig{
+		MaxConcurrentStreams:    128,
+		InitialStreamWindowSize: "1MiB",
+		InitialConnWindowSize:   "32MiB",
+		MaxHeaderListSize:       "16KiB",
+		IdleTimeout:             "5m",
+		DialTimeout:             "3s",
+		Http3: &global.Http3Config{
+			Allow0RTT:               true,
+			EnableDatagrams:         true,
+			DisablePathMTUDiscovery: true,
+			InitialPacketSize:       1200,
+		},
+		WebSocket: &global.WebSocketConfig{
+			PingInterval: "15s",
+			PongTimeout:  "5s",
+		},
+		ConnPool: &global.ConnPoolConfig{
+			MaxConnections: 8,
+			IdleTimeout:    "30s",
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &TransportSettings{
+		MaxConcurrentStreams:    128,
+		InitialStreamWindowSize: 1 << 20,
+		InitialConnWindowSize:   32 << 20,
+		MaxHeaderListSize:       16 << 10,
+		IdleTimeout:             5 * time.Minute,
+		DialTimeout:             3 * time.Second,
+		Allow0RTT:               true,
+		EnableDatagrams:         true,
+		DisablePathMTUDiscovery: true,
+		InitialPacketSize:       1200,
+		WebSocketPingInterval:   15 * time.Second,
+		WebSocketPongTimeout:    5 * time.Second,
+		MaxConnections:          8,
+		ConnOpenThreshold:       0.8,
+		ConnIdleTimeout:         30 * time.Second,
+	}, ts)
+
+	invalids := []*global.TripleConfig{
+		{InitialStreamWindowSize: "1XB"},
+		{InitialConnWindowSize: "4GiB"},
+		{MaxHeaderListSize: "-1"},
+		{IdleTimeout: "5"},
+		{DialTimeout: "soon"},
+		{WebSocket: &global.WebSocketConfig{PongTimeout: "1"}},
+		{ConnPool: &global.ConnPoolConfig{MaxConnections: -1}},
+		{ConnPool: &global.ConnPoolConfig{OpenThreshold: 1.5}},
+		{ConnPool: &global.ConnPoolConfig{IdleTimeout: "1"}},
+	}
+	for _, conf := range invalids {
+		_, err = NewTransportSettings(conf)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestTransportSettings_ConfigureHTTP2Server(t *testing.T) {
+	ts := &TransportSettings{
+		MaxConcurrentStreams:    128,
+		InitialStreamWindowSize: 1 << 20,
+		InitialConnWindowSize:   4 << 20,
+		MaxHeaderListSize:       16 << 10,
+		IdleTimeout:             time.Minute,
+	}
+	httpSrv := &http.Server{}
+	h2Srv := &http2.Server{}
+	ts.ConfigureHTTP2Server(httpSrv, h2Srv)
+	assert.Equal(t, uint32(128), h2Srv.MaxConcurrentStreams)
+	assert.Equal(t, int32(1<<20), h2Srv.MaxUploadBufferPerStream)
+	assert.Equal(t, int32(4<<20), h2Srv.MaxUploadBufferPerConnection)
+	assert.Equal(t, time.Minute, h2Srv.IdleTimeout)
+	assert.Equal(t, 16<<10, httpSrv.MaxHeaderBytes)
+	assert.Equal(t, time.Minute, httpSrv.IdleTimeout)
+}
+
+func TestTransportSettings_ConfigureHTTP2Transport(t *testing.T) {
+	ts := &TransportSettings{
+		MaxConcurrentStreams: 128,
+		MaxHeaderListSize:    16 << 10,
+		IdleTimeout:          time.Minute,
+		DialTimeout:          time.Second,
+	}
+	transport := &http2.Transport{}
+	ts.ConfigureHTTP2Transport(transport)
+	assert.True(t, transport.StrictMaxConcurrentStreams)
+	assert.Equal(t, uint32(16<<10), transport.MaxHeaderListSize)
+	assert.Equal(t, time.Minute, transport.IdleConnTimeout)
+	assert.NotNil(t, transport.DialTLSContext)
+
+	// the default TLS dialing of the transport is kept without a dial timeout
+	transport = &http2.Transport{}
+	(&TransportSettings{}).ConfigureHTTP2Transport(transport)
+	assert.Nil(t, transport.DialTLSContext)
+}
+
+func TestTransportSettings_H2CDialContext(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	transport := &http2.Transport{AllowHTTP: true}
+	(&TransportSettings{DialTimeout: time.Second}).ConfigureHTTP2Transport(transport)
+
+	conn, err := transport.DialTLSContext(context.Background(), "tcp", ln.Addr().String(), nil)
+	assert.Nil(t, err)
+	_ = conn.Close()
+
+	// the h2c dialing honors the context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = transport.DialTLSContext(ctx, "tcp", ln.Addr().String(), nil)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestTransportSettings_ClientTLSConfig(t *testing.T) {
+	tlsConf := &tls.Config{ServerName: "provider"}
+	assert.Same(t, tlsConf, (&TransportSettings{}).ClientTLSConfig(tlsConf))
+
+	// 0-RTT resumes the sessions through a cache on a copy of the config
+	resumed := (&TransportSettings{Allow0RTT: true}).ClientTLSConfig(tlsConf)
+	assert.NotNil(t, resumed.ClientSessionCache)
+	assert.Equal(t, "provider", resumed.ServerName)
+	assert.Nil(t, tlsConf.ClientSessionCache)
+
+	// a cache of the user is kept
+	cache := tls.NewLRUClientSessionCache(1)
+	tlsConf.ClientSessionCache = cache
+	assert.Same(t, tlsConf, (&TransportSettings{Allow0RTT: true}).ClientTLSConfig(tlsConf))
+}
+
+func TestTransportSettings_QUICConfig(t *testing.T) {
+	conf := (&TransportSettings{}).QUICConfig(10*time.Second, 20*time.Second)
+	assert.Equal(t, 10*time.Second, conf.KeepAlivePeriod)
+	assert.Equal(t, 20*time.Second, conf.MaxIdleTimeout)
+	assert.Zero(t, conf.MaxIncomingStreams)
+	assert.False(t, conf.Allow0RTT)
+
+	conf = (&TransportSettings{
+		MaxConcurrentStreams:    128,
+		InitialStreamWindowSize: 8 << 20,
+		InitialConnWindowSize:   1 << 20,
+		IdleTimeout:             time.Minute,
+		DialTimeout:             3 * time.Second,
+		Allow0RTT:               true,
+		EnableDatagrams:         true,
+		DisablePathMTUDiscovery: true,
+		InitialPacketSize:       1200,
+	}).QUICConfig(10*time.Second, 20*time.Second)
+	assert.Equal(t, time.Minute, conf.MaxIdleTimeout)
+	assert.Equal(t, 3*time.Second, conf.HandshakeIdleTimeout)
+	assert.Equal(t, int64(128), conf.MaxIncomingStreams)
+	assert.Equal(t, uint64(8<<20), conf.InitialStreamReceiveWindow)
+	// the max window grows with an initial window beyond the default of quic-go
+	assert.Equal(t, uint64(8<<20), conf.MaxStreamReceiveWindow)
+	assert.Equal(t, uint64(1<<20), conf.InitialConnectionReceiveWindow)
+	assert.Zero(t, conf.MaxConnectionReceiveWindow)
+	assert.True(t, conf.Allow0RTT)
+	assert.True(t, conf.EnableDatagrams)
+	assert.True(t, conf.DisablePathMTUDiscovery)
+	assert.Equal(t, uint16(1200), conf.InitialPacketSize)
+}
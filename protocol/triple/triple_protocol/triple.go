@@ -297,7 +297,6 @@ type Spec struct {
 //
 // On both the client and the server, Protocol is the RPC protocol in use.
 // Currently, it's either [ProtocolTriple], [ProtocolGRPC], or
-// todo: Should we support ProtocolGRPCWeb?
 // [ProtocolGRPCWeb], but additional protocols may be added in the future.
 //
 // Query contains the query parameters for the request. For the server, this
@@ -18,20 +18,26 @@ import (
 	"compress/flate"
 	"compress/gzip"
 	"context"
+	"crypto/tls"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
 	"math/rand"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
 
 import (
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
 	"google.golang.org/protobuf/proto"
 
 	"google.golang.org/protobuf/reflect/protoregistry"
@@ -454,6 +460,12 @@ func TestServer(t *testing.T) {
 			t.Run("proto_gzip", func(t *testing.T) {
 				run(t, false, triple.WithTriple(), triple.WithSendGzip())
 			})
+			t.Run("proto_zstd", func(t *testing.T) {
+				run(t, false, triple.WithTriple(), triple.WithSendZstd())
+			})
+			t.Run("proto_snappy", func(t *testing.T) {
+				run(t, false, triple.WithTriple(), triple.WithSendSnappy())
+			})
 			t.Run("json_gzip", func(t *testing.T) {
 				run(
 					t,
@@ -471,10 +483,33 @@ func TestServer(t *testing.T) {
 			t.Run("proto_gzip", func(t *testing.T) {
 				run(t, true, triple.WithSendGzip())
 			})
+			t.Run("proto_zstd", func(t *testing.T) {
+				run(t, true, triple.WithSendZstd())
+			})
+			t.Run("proto_snappy", func(t *testing.T) {
+				run(t, true, triple.WithSendSnappy())
+			})
+			t.Run("json_gzip", func(t *testing.T) {
+				run(
+					t,
+					true,
+					triple.WithProtoJSON(),
+					triple.WithSendGzip(),
+				)
+			})
+		})
+		t.Run("grpcweb", func(t *testing.T) {
+			t.Run("proto", func(t *testing.T) {
+				run(t, true, triple.WithGRPCWeb())
+			})
+			t.Run("proto_gzip", func(t *testing.T) {
+				run(t, true, triple.WithGRPCWeb(), triple.WithSendGzip())
+			})
 			t.Run("json_gzip", func(t *testing.T) {
 				run(
 					t,
 					true,
+					triple.WithGRPCWeb(),
 					triple.WithProtoJSON(),
 					triple.WithSendGzip(),
 				)
@@ -629,6 +664,63 @@ func TestTimeoutParsing(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestCancelPropagatesToHandler(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		desc    string
+		cliOpts []triple.ClientOption
+	}{
+		{desc: "Triple protocol", cliOpts: []triple.ClientOption{triple.WithTriple()}},
+		{desc: "gRPC protocol"},
+	} {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			started := make(chan struct{})
+			canceled := make(chan struct{})
+			pingServer := &pluggablePingServer{
+				ping: func(ctx context.Context, request *triple.Request) (*triple.Response, error) {
+					close(started)
+					select {
+					case <-ctx.Done():
+						close(canceled)
+					case <-time.After(5 * time.Second):
+					}
+					return triple.NewResponse(&pingv1.PingResponse{}), nil
+				},
+			}
+			mux := http.NewServeMux()
+			mux.Handle(pingv1connect.NewPingServiceHandler(pingServer))
+			// serves h2c by golang.org/x/net/http2 like triple.Server, which cancels the context
+			// of the request whose stream is reset by the client
+			server := httptest.NewServer(h2c.NewHandler(mux, &http2.Server{}))
+			defer server.Close()
+
+			httpClient := &http.Client{Transport: &http2.Transport{
+				AllowHTTP: true,
+				DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+					var dialer net.Dialer
+					return dialer.DialContext(ctx, network, addr)
+				},
+			}}
+			client := pingv1connect.NewPingServiceClient(httpClient, server.URL, test.cliOpts...)
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				<-started
+				cancel()
+			}()
+			err := client.Ping(ctx, triple.NewRequest(&pingv1.PingRequest{}), triple.NewResponse(&pingv1.PingResponse{}))
+			assert.Equal(t, triple.CodeCanceled, triple.CodeOf(err))
+			// the client resets the stream by RST_STREAM, which cancels the context of the handler
+			select {
+			case <-canceled:
+			case <-time.After(3 * time.Second):
+				t.Fatal("the context of the handler is not canceled")
+			}
+		})
+	}
+}
+
 func TestFailCodec(t *testing.T) {
 	t.Parallel()
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
@@ -1374,6 +1466,49 @@ func TestHandlerWithSendMaxBytes(t *testing.T) {
 	})
 }
 
+func TestWithMaxBytesObserver(t *testing.T) {
+	t.Parallel()
+	var handlerObserved, clientObserved atomic.Int32
+	mux := http.NewServeMux()
+	mux.Handle(pingv1connect.NewPingServiceHandler(
+		pingServer{},
+		triple.WithReadMaxBytes(1024),
+		triple.WithMaxBytesObserver(func(send bool) {
+			assert.False(t, send)
+			handlerObserved.Add(1)
+		}),
+	))
+	server := httptest.NewUnstartedServer(mux)
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	pingRequest := &pingv1.PingRequest{Text: strings.Repeat("a", 1022)}
+
+	// the message rejected by the send max bytes of the client is observed by the client
+	client := pingv1connect.NewPingServiceClient(server.Client(), server.URL,
+		triple.WithSendMaxBytes(1024),
+		triple.WithMaxBytesObserver(func(send bool) {
+			assert.True(t, send)
+			clientObserved.Add(1)
+		}),
+	)
+	err := client.Ping(context.Background(), triple.NewRequest(pingRequest), triple.NewResponse(&pingv1.PingResponse{}))
+	assert.Equal(t, triple.CodeResourceExhausted, triple.CodeOf(err))
+	assert.Equal(t, int32(1), clientObserved.Load())
+	assert.Equal(t, int32(0), handlerObserved.Load())
+
+	// the message rejected by the handler is only observed by the handler
+	client = pingv1connect.NewPingServiceClient(server.Client(), server.URL,
+		triple.WithMaxBytesObserver(func(bool) {
+			clientObserved.Add(1)
+		}),
+	)
+	err = client.Ping(context.Background(), triple.NewRequest(pingRequest), triple.NewResponse(&pingv1.PingResponse{}))
+	assert.Equal(t, triple.CodeResourceExhausted, triple.CodeOf(err))
+	assert.Equal(t, int32(1), clientObserved.Load())
+	assert.Equal(t, int32(1), handlerObserved.Load())
+}
+
 func TestClientWithSendMaxBytes(t *testing.T) {
 	t.Parallel()
 	mux := http.NewServeMux()
@@ -0,0 +1,531 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package triple_protocol
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/gorilla/websocket"
+)
+
+// A call over WebSocket tunnels the HTTP request and response of the call in a WebSocket
+// connection, so the protocols, the envelopes and the stream types are the same as over HTTP/2:
+//   - the text messages are the control frames in JSON, carrying the headers of the request and
+//     the response, the trailers of the response and the end of the request body.
+//   - the binary messages carry the enveloped messages of the request and response bodies.
+//
+// Both peers ping each other and close the connection if nothing is received in time.
+const (
+	webSocketSubprotocol = "triple"
+
+	webSocketFrameHeaders  = "headers"
+	webSocketFrameTrailers = "trailers"
+	webSocketFrameEnd      = "end"
+
+	defaultWebSocketPingInterval = 30 * time.Second
+	defaultWebSocketPongTimeout  = 10 * time.Second
+	// webSocketControlTimeout bounds sending a control message and the closing handshake
+	webSocketControlTimeout = 5 * time.Second
+	// the max size of a binary message of the request body
+	webSocketMaxMessageSize = 32 << 10
+)
+
+// webSocketControlFrame is a text message of a call over WebSocket.
+type webSocketControlFrame struct {
+	Type   string      `json:"type"`
+	Status int         `json:"status,omitempty"` // the status code of the response headers
+	Header http.Header `json:"header,omitempty"`
+}
+
+// webSocketConn is a WebSocket connection carrying a call, which keeps the connection alive
+// and reads the messages in the background so the pings are answered at any time.
+type webSocketConn struct {
+	conn            *websocket.Conn
+	pingInterval    time.Duration
+	pongTimeout     time.Duration
+	maxControlBytes int64
+
+	writeMu   sync.Mutex
+	closeOnce sync.Once
+	done      chan struct{} // closed on closing the connection
+	readDone  chan struct{} // closed when readLoop returns
+}
+
+func newWebSocketConn(conn *websocket.Conn, settings *TransportSettings) *webSocketConn {
+	c := &webSocketConn{
+		conn:            conn,
+		pingInterval:    defaultWebSocketPingInterval,
+		pongTimeout:     defaultWebSocketPongTimeout,
+		maxControlBytes: http.DefaultMaxHeaderBytes,
+		done:            make(chan struct{}),
+		readDone:        make(chan struct{}),
+	}
+	if settings.WebSocketPingInterval > 0 {
+		c.pingInterval = settings.WebSocketPingInterval
+	}
+	if settings.WebSocketPongTimeout > 0 {
+		c.pongTimeout = settings.WebSocketPongTimeout
+	}
+	if settings.MaxHeaderListSize > 0 {
+		c.maxControlBytes = int64(settings.MaxHeaderListSize)
+	}
+	// the pings of the peer prove it's alive as well as the pongs
+	conn.SetPingHandler(func(data string) error {
+		c.extendReadDeadline()
+		err := conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(webSocketControlTimeout))
+		if errors.Is(err, websocket.ErrCloseSent) {
+			return nil
+		}
+		return err
+	})
+	conn.SetPongHandler(func(string) error {
+		c.extendReadDeadline()
+		return nil
+	})
+	go c.keepAlive()
+	return c
+}
+
+func (c *webSocketConn) extendReadDeadline() {
+	_ = c.conn.SetReadDeadline(time.Now().Add(c.pingInterval + c.pongTimeout))
+}
+
+func (c *webSocketConn) keepAlive() {
+	ticker := time.NewTicker(c.pingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(webSocketControlTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (c *webSocketConn) writeMessage(messageType int, data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.conn.WriteMessage(messageType, data)
+}
+
+func (c *webSocketConn) writeControl(frame *webSocketControlFrame) error {
+	data, err := json.Marshal(frame)
+	if err != nil {
+		return err
+	}
+	return c.writeMessage(websocket.TextMessage, data)
+}
+
+// readControl reads the next message, which must be a control frame of typ.
+func (c *webSocketConn) readControl(typ string) (*webSocketControlFrame, error) {
+	c.extendReadDeadline()
+	messageType, reader, err := c.conn.NextReader()
+	if err != nil {
+		return nil, err
+	}
+	if messageType != websocket.TextMessage {
+		return nil, fmt.Errorf("websocket: unexpected binary message, want the %s frame", typ)
+	}
+	frame, err := c.decodeControl(reader)
+	if err != nil {
+		return nil, err
+	}
+	if frame.Type != typ {
+		return nil, fmt.Errorf("websocket: unexpected %s frame, want the %s frame", frame.Type, typ)
+	}
+	return frame, nil
+}
+
+func (c *webSocketConn) decodeControl(reader io.Reader) (*webSocketControlFrame, error) {
+	frame := &webSocketControlFrame{}
+	if err := json.NewDecoder(io.LimitReader(reader, c.maxControlBytes)).Decode(frame); err != nil {
+		return nil, fmt.Errorf("websocket: invalid control frame: %w", err)
+	}
+	if frame.Header == nil {
+		frame.Header = make(http.Header)
+	}
+	return frame, nil
+}
+
+// readLoop reads the messages until the connection fails or is closed. The binary messages are
+// written to body, which drops them once its reader is closed, and the control frames are passed
+// to onControl.
+func (c *webSocketConn) readLoop(body *io.PipeWriter, onControl func(*webSocketControlFrame) error) error {
+	defer close(c.readDone)
+	for {
+		c.extendReadDeadline()
+		messageType, reader, err := c.conn.NextReader()
+		if err != nil {
+			return err
+		}
+		switch messageType {
+		case websocket.BinaryMessage:
+			if _, err = io.Copy(body, reader); err != nil && !errors.Is(err, io.ErrClosedPipe) {
+				return err
+			}
+		case websocket.TextMessage:
+			frame, err := c.decodeControl(reader)
+			if err != nil {
+				return err
+			}
+			if err = onControl(frame); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Close closes the connection with the closing handshake, readLoop must be running.
+func (c *webSocketConn) Close(code int) {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		_ = c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, ""),
+			time.Now().Add(webSocketControlTimeout))
+		// wait for the peer to echo the close message, so the unread data doesn't reset the connection
+		timer := time.NewTimer(webSocketControlTimeout)
+		defer timer.Stop()
+		select {
+		case <-c.readDone:
+		case <-timer.C:
+		}
+		_ = c.conn.Close()
+	})
+}
+
+// abort closes the connection immediately.
+func (c *webSocketConn) abort() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		_ = c.conn.Close()
+	})
+}
+
+// webSocketHandler serves the calls over WebSocket by handler, the other requests are passed
+// to handler as is.
+type webSocketHandler struct {
+	handler  http.Handler
+	settings *TransportSettings
+	conns    sync.Map // the serving *webSocketConn
+}
+
+func newWebSocketHandler(handler http.Handler, settings *TransportSettings) *webSocketHandler {
+	return &webSocketHandler{
+		handler:  handler,
+		settings: settings,
+	}
+}
+
+func (h *webSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !websocket.IsWebSocketUpgrade(r) {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	// the origins allowed by the CORS config may call across origins
+	corsAllowed := w.Header().Get("Access-Control-Allow-Origin") != ""
+	upgrader := &websocket.Upgrader{
+		HandshakeTimeout: h.settings.DialTimeout,
+		Subprotocols:     []string{webSocketSubprotocol},
+		CheckOrigin: func(r *http.Request) bool {
+			return corsAllowed || isSameOrigin(r)
+		},
+	}
+	wsConn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader has replied with the error
+		return
+	}
+	conn := newWebSocketConn(wsConn, h.settings)
+	h.conns.Store(conn, struct{}{})
+	defer h.conns.Delete(conn)
+
+	frame, err := conn.readControl(webSocketFrameHeaders)
+	if err != nil {
+		conn.abort()
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	bodyReader, bodyWriter := io.Pipe()
+	go func() {
+		err := conn.readLoop(bodyWriter, func(frame *webSocketControlFrame) error {
+			if frame.Type != webSocketFrameEnd {
+				return fmt.Errorf("websocket: unexpected %s frame of the request", frame.Type)
+			}
+			return bodyWriter.Close()
+		})
+		// the client has gone or broken the protocol
+		_ = bodyWriter.CloseWithError(err)
+		cancel()
+	}()
+
+	request := r.Clone(ctx)
+	request.Method = http.MethodPost
+	request.Header = frame.Header
+	// the tunnel is full-duplex like HTTP/2, which the bidi streams require
+	request.Proto, request.ProtoMajor, request.ProtoMinor = "HTTP/2.0", 2, 0
+	request.Body = bodyReader
+	request.ContentLength = -1
+	request.TransferEncoding = nil
+	request.Trailer = nil
+
+	writer := &webSocketResponseWriter{conn: conn, header: make(http.Header)}
+	h.handler.ServeHTTP(writer, request)
+	writer.finish()
+	_ = bodyReader.Close()
+	conn.Close(websocket.CloseNormalClosure)
+}
+
+// Close closes the serving connections, whose calls are canceled.
+func (h *webSocketHandler) Close() {
+	h.conns.Range(func(key, _ any) bool {
+		go key.(*webSocketConn).Close(websocket.CloseGoingAway)
+		return true
+	})
+}
+
+func isSameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// webSocketResponseWriter writes the response of a call over WebSocket, the headers and the
+// trailers are collected like net/http does.
+type webSocketResponseWriter struct {
+	conn        *webSocketConn
+	header      http.Header
+	wroteHeader bool
+	err         error
+}
+
+func (w *webSocketResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *webSocketResponseWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	header := w.header.Clone()
+	delete(header, headerTrailer)
+	w.err = w.conn.writeControl(&webSocketControlFrame{
+		Type:   webSocketFrameHeaders,
+		Status: statusCode,
+		Header: header,
+	})
+}
+
+func (w *webSocketResponseWriter) Write(data []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	if w.err != nil {
+		return 0, w.err
+	}
+	if w.err = w.conn.writeMessage(websocket.BinaryMessage, data); w.err != nil {
+		return 0, w.err
+	}
+	return len(data), nil
+}
+
+// Flush sends the headers if they are not sent, the data is sent as soon as it's written.
+func (w *webSocketResponseWriter) Flush() {
+	w.WriteHeader(http.StatusOK)
+}
+
+// finish sends the trailers declared by the Trailer header or prefixed by http.TrailerPrefix.
+func (w *webSocketResponseWriter) finish() {
+	w.WriteHeader(http.StatusOK)
+	if w.err != nil {
+		return
+	}
+	trailer := make(http.Header)
+	for _, declared := range w.header.Values(headerTrailer) {
+		for _, key := range strings.Split(declared, ",") {
+			key = http.CanonicalHeaderKey(strings.TrimSpace(key))
+			if values, ok := w.header[key]; ok {
+				trailer[key] = values
+			}
+		}
+	}
+	for key, values := range w.header {
+		if strings.HasPrefix(key, http.TrailerPrefix) {
+			trailer[http.CanonicalHeaderKey(strings.TrimPrefix(key, http.TrailerPrefix))] = values
+		}
+	}
+	w.err = w.conn.writeControl(&webSocketControlFrame{
+		Type:   webSocketFrameTrailers,
+		Header: trailer,
+	})
+}
+
+// webSocketClient is the HTTPClient carrying each call in a WebSocket connection.
+type webSocketClient struct {
+	dialer   *websocket.Dialer
+	settings *TransportSettings
+}
+
+// NewWebSocketClient returns the HTTPClient carrying each call in a WebSocket connection, which
+// is dialed by DialContext of the settings and secured by tlsConf if it's not nil. The streaming
+// clients use it by WithStreamHTTPClient.
+func (ts *TransportSettings) NewWebSocketClient(tlsConf *tls.Config) HTTPClient {
+	dialer := &websocket.Dialer{
+		NetDialContext:   ts.DialContext,
+		HandshakeTimeout: ts.DialTimeout,
+		Subprotocols:     []string{webSocketSubprotocol},
+	}
+	if ts.DialContext == nil {
+		// WebSocket is meant to pass the proxies
+		dialer.Proxy = http.ProxyFromEnvironment
+	}
+	if tlsConf != nil {
+		tlsConf = tlsConf.Clone()
+		// the upgrade runs over HTTP/1.1
+		tlsConf.NextProtos = []string{"http/1.1"}
+		dialer.TLSClientConfig = tlsConf
+	}
+	return &webSocketClient{
+		dialer:   dialer,
+		settings: ts,
+	}
+}
+
+func (c *webSocketClient) Do(request *http.Request) (*http.Response, error) {
+	wsURL := cloneURL(request.URL)
+	if wsURL.Scheme == "https" {
+		wsURL.Scheme = "wss"
+	} else {
+		wsURL.Scheme = "ws"
+	}
+	wsConn, handshake, err := c.dialer.DialContext(request.Context(), wsURL.String(), nil)
+	if err != nil {
+		if handshake != nil {
+			return nil, fmt.Errorf("websocket handshake with %s failed with %s: %w", wsURL, handshake.Status, err)
+		}
+		return nil, err
+	}
+	conn := newWebSocketConn(wsConn, c.settings)
+	if err = conn.writeControl(&webSocketControlFrame{Type: webSocketFrameHeaders, Header: request.Header}); err != nil {
+		conn.abort()
+		return nil, err
+	}
+	go sendWebSocketBody(conn, request.Body)
+
+	// the connection is closed once the call is canceled
+	stop := context.AfterFunc(request.Context(), conn.abort)
+	frame, err := conn.readControl(webSocketFrameHeaders)
+	if err != nil {
+		stop()
+		conn.abort()
+		if ctxErr := request.Context().Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+
+	bodyReader, bodyWriter := io.Pipe()
+	response := &http.Response{
+		Status:     fmt.Sprintf("%d %s", frame.Status, http.StatusText(frame.Status)),
+		StatusCode: frame.Status,
+		// the tunnel is full-duplex like HTTP/2, which the bidi streams require
+		Proto:         "HTTP/2.0",
+		ProtoMajor:    2,
+		Header:        frame.Header,
+		Trailer:       make(http.Header),
+		ContentLength: -1,
+		Request:       request,
+	}
+	response.Body = &webSocketResponseBody{
+		PipeReader: bodyReader,
+		close: func() {
+			stop()
+			conn.Close(websocket.CloseNormalClosure)
+		},
+	}
+	go func() {
+		err := conn.readLoop(bodyWriter, func(frame *webSocketControlFrame) error {
+			if frame.Type != webSocketFrameTrailers {
+				return fmt.Errorf("websocket: unexpected %s frame of the response", frame.Type)
+			}
+			mergeHeaders(response.Trailer, frame.Header)
+			return bodyWriter.Close()
+		})
+		if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+			// the server has closed the connection before the trailers
+			err = io.ErrUnexpectedEOF
+		}
+		_ = bodyWriter.CloseWithError(err)
+	}()
+	return response, nil
+}
+
+// sendWebSocketBody sends the request body in binary messages, and the end frame once it's read up.
+func sendWebSocketBody(conn *webSocketConn, body io.ReadCloser) {
+	if body == nil {
+		_ = conn.writeControl(&webSocketControlFrame{Type: webSocketFrameEnd})
+		return
+	}
+	defer body.Close()
+	buf := make([]byte, webSocketMaxMessageSize)
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			if writeErr := conn.writeMessage(websocket.BinaryMessage, buf[:n]); writeErr != nil {
+				return
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			_ = conn.writeControl(&webSocketControlFrame{Type: webSocketFrameEnd})
+			return
+		}
+		if err != nil {
+			// the call has failed on the client side
+			conn.abort()
+			return
+		}
+	}
+}
+
+type webSocketResponseBody struct {
+	*io.PipeReader
+	close func()
+}
+
+func (b *webSocketResponseBody) Close() error {
+	err := b.PipeReader.Close()
+	b.close()
+	return err
+}
@@ -0,0 +1,124 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package triple_protocol
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"golang.org/x/net/http2"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestWebSocketBidiStream(t *testing.T) {
+	srv := NewServer("", nil)
+	err := srv.RegisterBidiStreamHandler("/greet.GreetService/GreetStream",
+		func(ctx context.Context, stream *BidiStream) error {
+			stream.ResponseHeader().Set("X-Stream", "header")
+			for {
+				msg := &wrapperspb.StringValue{}
+				if err := stream.Receive(msg); err != nil {
+					if errors.Is(err, io.EOF) {
+						stream.ResponseTrailer().Set("X-Stream", "trailer")
+						return nil
+					}
+					return err
+				}
+				if msg.GetValue() == "fail" {
+					return NewError(CodeInvalidArgument, errors.New("fail on purpose"))
+				}
+				if err := stream.Send(wrapperspb.String("hello " + msg.GetValue())); err != nil {
+					return err
+				}
+			}
+		})
+	assert.Nil(t, err)
+	err = srv.RegisterUnaryHandler("/greet.GreetService/Greet", func() any { return &wrapperspb.StringValue{} },
+		func(ctx context.Context, req *Request) (*Response, error) {
+			name := req.Msg.(*wrapperspb.StringValue).GetValue()
+			return NewResponse(wrapperspb.String("hello " + name)), nil
+		})
+	assert.Nil(t, err)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	go func() {
+		_ = srv.Serve(lis, nil)
+	}()
+	defer srv.Stop()
+
+	// the pings keep the idle streams alive
+	settings := &TransportSettings{
+		WebSocketPingInterval: 20 * time.Millisecond,
+		WebSocketPongTimeout:  20 * time.Millisecond,
+	}
+	baseURL := "http://" + lis.Addr().String() + "/greet.GreetService/"
+	transport := &http2.Transport{AllowHTTP: true}
+	settings.ConfigureHTTP2Transport(transport)
+	newClient := func(procedure string) *Client {
+		return NewClient(&http.Client{Transport: transport}, baseURL+procedure,
+			WithStreamHTTPClient(settings.NewWebSocketClient(nil)))
+	}
+
+	stream, err := newClient("GreetStream").CallBidiStream(context.Background())
+	assert.Nil(t, err)
+	for _, name := range []string{"dubbo", "triple"} {
+		assert.Nil(t, stream.Send(wrapperspb.String(name)))
+		time.Sleep(100 * time.Millisecond)
+		msg := &wrapperspb.StringValue{}
+		assert.Nil(t, stream.Receive(msg))
+		assert.Equal(t, "hello "+name, msg.GetValue())
+	}
+	assert.Nil(t, stream.CloseRequest())
+	assert.True(t, errors.Is(stream.Receive(&wrapperspb.StringValue{}), io.EOF))
+	assert.Equal(t, "header", stream.ResponseHeader().Get("X-Stream"))
+	assert.Equal(t, "trailer", stream.ResponseTrailer().Get("X-Stream"))
+	assert.Nil(t, stream.CloseResponse())
+
+	// the errors of the handlers are carried by the trailers
+	stream, err = newClient("GreetStream").CallBidiStream(context.Background())
+	assert.Nil(t, err)
+	assert.Nil(t, stream.Send(wrapperspb.String("fail")))
+	err = stream.Receive(&wrapperspb.StringValue{})
+	assert.Equal(t, CodeInvalidArgument, CodeOf(err))
+	assert.Nil(t, stream.CloseRequest())
+	assert.Nil(t, stream.CloseResponse())
+
+	// the unary calls keep using HTTP/2
+	resp := NewResponse(&wrapperspb.StringValue{})
+	err = newClient("Greet").CallUnary(context.Background(), NewRequest(wrapperspb.String("dubbo")), resp)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello dubbo", resp.Msg.(*wrapperspb.StringValue).GetValue())
+}
+
+func TestWebSocketCrossOrigin(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodGet, "http://example.com/greet.GreetService/GreetStream", nil)
+	assert.True(t, isSameOrigin(r))
+	r.Header.Set("Origin", "https://EXAMPLE.com")
+	assert.True(t, isSameOrigin(r))
+	r.Header.Set("Origin", "https://evil.com")
+	assert.False(t, isSameOrigin(r))
+}
@@ -110,6 +110,12 @@ func getUrlToRegistry(providerUrl *common.URL, registryUrl *common.URL) *common.
 	}
 }
 
+// isServedLocally checks whether the provider is served on a unix domain socket or an in-memory
+// listener instead of its TCP address.
+func isServedLocally(providerUrl *common.URL) bool {
+	return providerUrl.GetParam(constant.UnixSocketKey, "") != "" || providerUrl.GetParam(constant.InMemoryKey, "") != ""
+}
+
 // filterHideKey filter the parameters that do not need to be output in url(Starting with .)
 func filterHideKey(url *common.URL) *common.URL {
 	// be careful params maps in url is map type
@@ -206,7 +212,11 @@ func (proto *registryProtocol) Export(originInvoker base.Invoker) base.Exporter
 	// update health status
 	//health.SetServingStatusServing(registryUrl.Service())
 
-	if len(registryUrl.Protocol) > 0 {
+	if len(registryUrl.Protocol) > 0 && isServedLocally(providerUrl) {
+		logger.Warnf("provider service %v is served on a unix domain socket or an in-memory listener, "+
+			"which the remote consumers can't reach, so it is not registered to registry %v",
+			providerUrl.Key(), registryUrl.Key())
+	} else if len(registryUrl.Protocol) > 0 {
 		// url to registry
 		reg := proto.getRegistry(registryUrl)
 		registeredProviderUrl := getUrlToRegistry(providerUrl, registryUrl)
@@ -415,9 +425,11 @@ func (proto *registryProtocol) Destroy() {
 		// protocol holds the exporters actually, instead, registry holds them in order to avoid export repeatedly, so
 		// the work for unexport should be finished in protocol.UnExport(), see also config.destroyProviderProtocols().
 		exporter := value.(*exporterChangeableWrapper)
-		reg := proto.getRegistry(getRegistryUrl(exporter.originInvoker))
-		if err := reg.UnRegister(exporter.registerUrl); err != nil {
-			panic(err)
+		if exporter.registerUrl != nil {
+			reg := proto.getRegistry(getRegistryUrl(exporter.originInvoker))
+			if err := reg.UnRegister(exporter.registerUrl); err != nil {
+				panic(err)
+			}
 		}
 		// TODO unsubscribeUrl
 
@@ -298,3 +298,42 @@ func TestGetProviderUrlWithHideKey(t *testing.T) {
 	assert.NotContains(t, providerUrl.GetParams(), ".d")
 	assert.Contains(t, providerUrl.GetParams(), "a")
 }
+
+type recordingRegistry struct {
+	registry.Registry
+	registered []*common.URL
+}
+
+func (r *recordingRegistry) Register(url *common.URL) error {
+	r.registered = append(r.registered, url)
+	return nil
+}
+
+func TestExportServedLocally(t *testing.T) {
+	mockRegistry, _ := registry.NewMockRegistry(nil)
+	reg := &recordingRegistry{Registry: mockRegistry}
+	extension.SetRegistry("recording", func(*common.URL) (registry.Registry, error) {
+		return reg, nil
+	})
+	extension.SetProtocol(protocolwrapper.FILTER, protocolwrapper.NewMockProtocolFilter)
+	regProtocol := newRegistryProtocol()
+
+	export := func(provider string) base.Exporter {
+		url, _ := common.NewURL("recording://127.0.0.1:1111")
+		url.SubURL, _ = common.NewURL(provider)
+		return regProtocol.Export(base.NewBaseInvoker(url))
+	}
+
+	// the providers on the unix domain sockets and the in-memory listeners are exported but not registered
+	exporter := export("tri://127.0.0.1:20000/org.apache.dubbo-go.unixService?unix-socket=/tmp/dubbo.sock")
+	assert.Equal(t, "/tmp/dubbo.sock", exporter.GetInvoker().GetURL().GetParam(constant.UnixSocketKey, ""))
+	export("tri://127.0.0.1:20000/org.apache.dubbo-go.memoryService?in-memory=provider")
+	assert.Empty(t, reg.registered)
+
+	export("tri://127.0.0.1:20000/org.apache.dubbo-go.tcpService")
+	assert.Len(t, reg.registered, 1)
+	assert.Equal(t, "/org.apache.dubbo-go.tcpService", reg.registered[0].Path)
+
+	// the unregistered exporters are skipped as the protocol is destroyed
+	regProtocol.Destroy()
+}
@@ -31,6 +31,8 @@ import (
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	"dubbo.apache.org/dubbo-go/v3/protocol/sizelimit"
 )
 
 var (
@@ -59,6 +61,12 @@ type Request struct {
 	Data     any
 	TwoWay   bool
 	Event    bool
+	// Compression is the algorithm accepted for the response, which also compresses the request if CompressBody
+	Compression      string
+	CompressBody     bool
+	CompressMinBytes int
+	// BodyLimit bounds the length of the encoded body, nil if unlimited
+	BodyLimit *sizelimit.Limit
 }
 
 // NewRequest aims to create Request. The ID is auto increase.
@@ -78,6 +86,13 @@ type Response struct {
 	Event    bool
 	Error    error
 	Result   any
+	// Compression is the algorithm compressing the response
+	Compression      string
+	CompressMinBytes int
+	// BodyLimit bounds the length of the encoded body, nil if unlimited
+	BodyLimit *sizelimit.Limit
+	// BodyLen is the length of the decoded body
+	BodyLen int
 }
 
 // NewResponse create to a new Response.
@@ -100,6 +115,13 @@ func (response *Response) Handle() {
 	}
 
 	pendingResponse.response = response
+	if err := pendingResponse.BodyLimit.Check(response.BodyLen); err != nil {
+		response.Error = err
+		if rpcResult, ok := response.Result.(*result.RPCResult); ok {
+			rpcResult.Err = err
+			rpcResult.Rest = nil
+		}
+	}
 
 	if pendingResponse.Callback == nil {
 		pendingResponse.Err = pendingResponse.response.Error
@@ -139,6 +161,8 @@ type PendingResponse struct {
 	response  *Response
 	Reply     any
 	Done      chan struct{}
+	// BodyLimit bounds the length of the body of the response, nil if unlimited
+	BodyLimit *sizelimit.Limit
 }
 
 // NewPendingResponse aims to create PendingResponse.
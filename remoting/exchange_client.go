@@ -19,6 +19,7 @@ package remoting
 
 import (
 	"errors"
+	"strings"
 	"time"
 )
 
@@ -30,8 +31,10 @@ import (
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/protocol/base"
 	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	"dubbo.apache.org/dubbo-go/v3/protocol/sizelimit"
 )
 
 // Client is the interface that wraps SetExchangeClient、 Connect、Close、Request and
@@ -123,9 +126,11 @@ func (client *ExchangeClient) Request(invocation *base.Invocation, url *common.U
 	request.Data = invocation
 	request.Event = false
 	request.TwoWay = true
+	setRequestCompression(request, url)
 
 	rsp := NewPendingResponse(request.ID)
 	rsp.response = NewResponse(request.ID, "2.0.2")
+	setSizeLimit(request, rsp, (*invocation).MethodName(), url)
 	rsp.Reply = (*invocation).Reply()
 	AddPendingResponse(rsp)
 
@@ -156,9 +161,11 @@ func (client *ExchangeClient) AsyncRequest(invocation *base.Invocation, url *com
 	request.Data = invocation
 	request.Event = false
 	request.TwoWay = true
+	setRequestCompression(request, url)
 
 	rsp := NewPendingResponse(request.ID)
 	rsp.response = NewResponse(request.ID, "2.0.2")
+	setSizeLimit(request, rsp, (*invocation).MethodName(), url)
 	rsp.Callback = callback
 	rsp.Reply = (*invocation).Reply()
 	AddPendingResponse(rsp)
@@ -181,9 +188,11 @@ func (client *ExchangeClient) Send(invocation *base.Invocation, url *common.URL,
 	request.Data = invocation
 	request.Event = false
 	request.TwoWay = false
+	setRequestCompression(request, url)
 
 	rsp := NewPendingResponse(request.ID)
 	rsp.response = NewResponse(request.ID, "2.0.2")
+	setSizeLimit(request, rsp, (*invocation).MethodName(), url)
 
 	err := client.client.Request(request, timeout, rsp)
 	if err != nil {
@@ -192,6 +201,27 @@ func (client *ExchangeClient) Send(invocation *base.Invocation, url *common.URL,
 	return nil
 }
 
+// setSizeLimit applies the size limits of method configured on the reference url to request and its response.
+func setSizeLimit(request *Request, rsp *PendingResponse, method string, url *common.URL) {
+	request.BodyLimit = sizelimit.RequestLimit(url, constant.SideConsumer, method)
+	rsp.BodyLimit = sizelimit.ResponseLimit(url, constant.SideConsumer, method)
+}
+
+// setRequestCompression applies the compression configured on the reference url to request. The body
+// is only compressed for dubbo-go providers, the others fail to decode the marker of compressed bodies.
+func setRequestCompression(request *Request, url *common.URL) {
+	request.Compression = url.GetParam(constant.CompressionKey, "")
+	request.CompressBody = request.Compression != "" && isGoProvider(url)
+	request.CompressMinBytes = int(url.GetParamInt(constant.CompressMinBytesKey, 0))
+}
+
+// isGoProvider reports whether url is registered by a dubbo-go provider. The release of a consumer
+// merged into a provider url without one, as for direct connections, isn't taken for the provider's.
+func isGoProvider(url *common.URL) bool {
+	return url.GetParam(constant.SideKey, "") == common.RoleType(common.PROVIDER).Role() &&
+		strings.HasPrefix(url.GetParam(constant.ReleaseKey, ""), "dubbo-golang-")
+}
+
 // Close close the client.
 func (client *ExchangeClient) Close() {
 	client.client.Close()
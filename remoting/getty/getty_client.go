@@ -137,6 +137,11 @@ func SetClientConf(c ClientConfig) {
 	setClientGrPool()
 }
 
+// GetClientConf get getty client config.
+func GetClientConf() ClientConfig {
+	return *clientConf
+}
+
 func setClientGrPool() {
 	clientGrPool = gxsync.NewTaskPoolSimple(clientConf.GrPoolSize)
 }
@@ -37,6 +37,7 @@ import (
 import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/protocol/sizelimit"
 	"dubbo.apache.org/dubbo-go/v3/remoting"
 )
 
@@ -277,10 +278,28 @@ func (h *RpcServerHandler) OnMessage(session getty.Session, pkg any) {
 		return
 	}
 	resp.Result = result
+	setResponseCompression(resp, req.Compression, invoc)
+	resp.BodyLimit, _ = invoc.GetAttributeWithDefaultValue(constant.MaxResponseSizeKey, nil).(*sizelimit.Limit)
 
 	reply(session, resp)
 }
 
+// setResponseCompression compresses the response with the algorithm the consumer accepts.
+// Consumers advertise a single algorithm, so the service can only turn the compression off
+// with identity, or set the threshold.
+func setResponseCompression(resp *remoting.Response, accepted string, invoc *invocation.RPCInvocation) {
+	if accepted == "" {
+		return
+	}
+	if compression, _ := invoc.GetAttributeWithDefaultValue(constant.CompressionKey, "").(string); compression == constant.CompressionIdentity {
+		return
+	}
+	resp.Compression = accepted
+	if minBytes, ok := invoc.GetAttributeWithDefaultValue(constant.CompressMinBytesKey, 0).(int); ok {
+		resp.CompressMinBytes = minBytes
+	}
+}
+
 // OnCron check the session health periodic. if the session's sessionTimeout has reached, just close the session
 func (h *RpcServerHandler) OnCron(session getty.Session) {
 	var (
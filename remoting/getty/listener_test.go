@@ -32,6 +32,7 @@ import (
 import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
 )
 
 // test rebuild the ctx
@@ -74,3 +75,29 @@ func rebuildCtx(inv *invocation.RPCInvocation) context.Context {
 	}
 	return ctx
 }
+
+func TestSetResponseCompression(t *testing.T) {
+	inv := invocation.NewRPCInvocation("MethodName", nil, nil)
+
+	// consumers not accepting compression get uncompressed responses
+	resp := remoting.NewResponse(1, "2.0.2")
+	setResponseCompression(resp, "", inv)
+	assert.Empty(t, resp.Compression)
+
+	setResponseCompression(resp, constant.CompressionSnappy, inv)
+	assert.Equal(t, constant.CompressionSnappy, resp.Compression)
+	assert.Equal(t, 0, resp.CompressMinBytes)
+
+	// the algorithm of the service never replaces the one the consumer accepts
+	inv.SetAttribute(constant.CompressionKey, constant.CompressionZstd)
+	inv.SetAttribute(constant.CompressMinBytesKey, 1024)
+	setResponseCompression(resp, constant.CompressionSnappy, inv)
+	assert.Equal(t, constant.CompressionSnappy, resp.Compression)
+	assert.Equal(t, 1024, resp.CompressMinBytes)
+
+	// identity turns the compression off
+	inv.SetAttribute(constant.CompressionKey, constant.CompressionIdentity)
+	resp = remoting.NewResponse(1, "2.0.2")
+	setResponseCompression(resp, constant.CompressionSnappy, inv)
+	assert.Empty(t, resp.Compression)
+}
@@ -230,6 +230,17 @@ func (svcOpts *ServiceOptions) export(info *common.ServiceInfo) error {
 			ivkURL.AddParam(constant.Tagkey, svcConf.Tag)
 		}
 
+		// serve on a unix domain socket or an in-memory listener instead of the TCP address
+		if len(protocolConf.UnixSocket) > 0 {
+			ivkURL.AddParam(constant.UnixSocketKey, protocolConf.UnixSocket)
+		}
+		if len(protocolConf.UnixSocketMode) > 0 {
+			ivkURL.AddParam(constant.UnixSocketModeKey, protocolConf.UnixSocketMode)
+		}
+		if len(protocolConf.InMemory) > 0 {
+			ivkURL.AddParam(constant.InMemoryKey, protocolConf.InMemory)
+		}
+
 		// post process the URL to be exported
 		svcOpts.postProcessConfig(ivkURL)
 		// config post processor may set "needExport" to false
@@ -278,7 +289,15 @@ func (svcOpts *ServiceOptions) generatorInvoker(url *common.URL, info *common.Se
 
 	url.SetAttribute(constant.RpcServiceKey, svcOpts.rpcService)
 
-	return proxyFactory.GetInvoker(url)
+	invoker := proxyFactory.GetInvoker(url)
+	var interceptors []Interceptor
+	if svcOpts.srvOpts != nil {
+		interceptors = append(interceptors, svcOpts.srvOpts.interceptors...)
+	}
+	if interceptors = append(interceptors, svcOpts.interceptors...); len(interceptors) > 0 {
+		invoker = newInterceptedInvoker(invoker, interceptors, info != nil)
+	}
+	return invoker
 }
 
 // setRegistrySubURL set registry sub url is ivkURl
@@ -356,6 +375,12 @@ func (svcOpts *ServiceOptions) getUrlMap() url.Values {
 	urlMap.Set(constant.SideKey, (common.RoleType(common.PROVIDER)).Role())
 	// todo: move
 	urlMap.Set(constant.SerializationKey, svcConf.Serialization)
+	if svcConf.Compression != "" {
+		urlMap.Set(constant.CompressionKey, svcConf.Compression)
+	}
+	if svcConf.CompressMinBytes > 0 {
+		urlMap.Set(constant.CompressMinBytesKey, strconv.Itoa(svcConf.CompressMinBytes))
+	}
 	// application config info
 	urlMap.Set(constant.ApplicationKey, app.Name)
 	urlMap.Set(constant.OrganizationKey, app.Organization)
@@ -393,12 +418,18 @@ func (svcOpts *ServiceOptions) getUrlMap() url.Values {
 	urlMap.Set(constant.TPSLimitRateKey, svcConf.TpsLimitRate)
 	urlMap.Set(constant.TPSLimiterKey, svcConf.TpsLimiter)
 	urlMap.Set(constant.TPSRejectedExecutionHandlerKey, svcConf.TpsLimitRejectedHandler)
+	urlMap.Set(constant.TPSLimitBurstKey, svcConf.TpsLimitBurst)
+	urlMap.Set(constant.TPSLimitMaxWaitKey, svcConf.TpsLimitMaxWait)
+	urlMap.Set(constant.TPSLimitByKey, svcConf.TpsLimitBy)
 	urlMap.Set(constant.TracingConfigKey, svcConf.TracingKey)
 
 	// execute limit filter
 	urlMap.Set(constant.ExecuteLimitKey, svcConf.ExecuteLimit)
 	urlMap.Set(constant.ExecuteRejectedExecutionHandlerKey, svcConf.ExecuteLimitRejectedHandler)
 
+	// validation filter
+	urlMap.Set(constant.ValidationKey, svcConf.Validation)
+
 	// auth filter
 	urlMap.Set(constant.ServiceAuthKey, svcConf.Auth)
 	urlMap.Set(constant.ParameterSignatureEnableKey, svcConf.ParamSign)
@@ -411,15 +442,39 @@ func (svcOpts *ServiceOptions) getUrlMap() url.Values {
 		prefix := "methods." + v.Name + "."
 		urlMap.Set(prefix+constant.LoadbalanceKey, v.LoadBalance)
 		urlMap.Set(prefix+constant.RetriesKey, v.Retries)
+		// the timeout of the method on the provider, which is not taken by the consumers as theirs
+		urlMap.Set(prefix+constant.ServerTimeoutKey, v.RequestTimeout)
 		urlMap.Set(prefix+constant.WeightKey, strconv.FormatInt(v.Weight, 10))
 
 		urlMap.Set(prefix+constant.TPSLimitStrategyKey, v.TpsLimitStrategy)
 		urlMap.Set(prefix+constant.TPSLimitIntervalKey, v.TpsLimitInterval)
 		urlMap.Set(prefix+constant.TPSLimitRateKey, v.TpsLimitRate)
+		urlMap.Set(prefix+constant.TPSLimitBurstKey, v.TpsLimitBurst)
+		urlMap.Set(prefix+constant.TPSLimitMaxWaitKey, v.TpsLimitMaxWait)
+		urlMap.Set(prefix+constant.TPSLimitByKey, v.TpsLimitBy)
 
 		urlMap.Set(constant.ExecuteLimitKey, v.ExecuteLimit)
 		urlMap.Set(constant.ExecuteRejectedExecutionHandlerKey, v.ExecuteLimitRejectedHandler)
+
+		urlMap.Set(prefix+constant.ValidationKey, v.Validation)
+		urlMap.Set(prefix+constant.IdempotentKey, strconv.FormatBool(v.Idempotent))
+		urlMap.Set(prefix+constant.MaxRequestSizeKey, v.MaxRequestSize)
+		urlMap.Set(prefix+constant.MaxResponseSizeKey, v.MaxResponseSize)
+		if v.Deprecated || v.Sunset != "" {
+			urlMap.Set(prefix+constant.DeprecatedKey, "true")
+			urlMap.Set(prefix+constant.SunsetKey, v.Sunset)
+			urlMap.Set(prefix+constant.ReplacementKey, v.Replacement)
+		}
+		if v.Audit {
+			urlMap.Set(prefix+constant.AuditKey, "true")
+			urlMap.Set(prefix+constant.AuditFieldsKey, v.AuditFields)
+		}
+		if v.CacheControl != "" {
+			urlMap.Set(prefix+constant.CacheControlKey, v.CacheControl)
+		}
 	}
+	// the filters enabled by the params of the service or its methods
+	urlMap.Set(constant.ServiceFilterKey, common.AppendOptionalServiceFilters(urlMap.Get(constant.ServiceFilterKey), urlMap))
 
 	return urlMap
 }
@@ -0,0 +1,205 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"context"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	triple_protocol "dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+)
+
+// Request is a request handled by the service, seen by the interceptors
+type Request struct {
+	// Method is the name of the method called
+	Method string
+	// CallType is the type of the call, like constant.CallUnary and constant.CallBidiStream.
+	// The calls of the protocols without streaming, like dubbo and jsonrpc, are unary.
+	CallType string
+	// Messages are the request messages, which are the arguments of a unary call or the request of
+	// a server streaming call. An element replaced is passed to the service instead.
+	Messages []any
+	// Stream is the stream of a streaming call, e.g. *triple_protocol.BidiStream, nil for a unary call
+	Stream any
+	// Header is the attachments sent by the consumer, whose values are string or []string
+	Header map[string]any
+}
+
+// Response is the result of a unary call seen by the interceptors
+type Response struct {
+	// Message is the response message returned by the service
+	Message any
+	// Header is the attachments returned to the consumer
+	Header map[string]any
+}
+
+// UnaryFunc is the signature of a unary call handled by the service
+type UnaryFunc func(ctx context.Context, req *Request) (*Response, error)
+
+// StreamFunc is the signature of a streaming call handled by the service
+type StreamFunc func(ctx context.Context, req *Request) error
+
+// Interceptor intercepts the requests to a service, like a provider filter, but configured directly by
+// WithInterceptors or WithServerInterceptors, without a registered name. Interceptors are invoked after
+// the filters of the service, right before the service, so they see the decoded messages of any protocol.
+//
+// The returned functions must be safe to call concurrently.
+type Interceptor interface {
+	WrapUnary(UnaryFunc) UnaryFunc
+	WrapStream(StreamFunc) StreamFunc
+}
+
+// UnaryInterceptorFunc is an Interceptor wrapping the unary calls only
+type UnaryInterceptorFunc func(UnaryFunc) UnaryFunc
+
+// WrapUnary implements Interceptor by applying the interceptor function.
+func (f UnaryInterceptorFunc) WrapUnary(next UnaryFunc) UnaryFunc {
+	return f(next)
+}
+
+// WrapStream implements Interceptor with a no-op.
+func (f UnaryInterceptorFunc) WrapStream(next StreamFunc) StreamFunc {
+	return next
+}
+
+// StreamInterceptorFunc is an Interceptor wrapping the streaming calls only
+type StreamInterceptorFunc func(StreamFunc) StreamFunc
+
+// WrapUnary implements Interceptor with a no-op.
+func (f StreamInterceptorFunc) WrapUnary(next UnaryFunc) UnaryFunc {
+	return next
+}
+
+// WrapStream implements Interceptor by applying the interceptor function.
+func (f StreamInterceptorFunc) WrapStream(next StreamFunc) StreamFunc {
+	return f(next)
+}
+
+// interceptedInvoker runs the interceptors before invoking the service
+type interceptedInvoker struct {
+	base.Invoker
+	interceptors []Interceptor
+	// withInfo tells whether the service is invoked by its ServiceInfo, whose unary results are *triple_protocol.Response
+	withInfo bool
+}
+
+func newInterceptedInvoker(invoker base.Invoker, interceptors []Interceptor, withInfo bool) base.Invoker {
+	return &interceptedInvoker{
+		Invoker:      invoker,
+		interceptors: interceptors,
+		withInfo:     withInfo,
+	}
+}
+
+func (ii *interceptedInvoker) Invoke(ctx context.Context, invocation base.Invocation) result.Result {
+	callType := constant.CallUnary
+	if typ, ok := invocation.GetAttribute(constant.CallTypeKey); ok {
+		if s, isString := typ.(string); isString && s != "" {
+			callType = s
+		}
+	}
+	req := &Request{
+		Method:   invocation.MethodName(),
+		CallType: callType,
+		Messages: invocation.Arguments(),
+		Header:   invocation.Attachments(),
+	}
+	if req.Header == nil {
+		req.Header = make(map[string]any)
+	}
+
+	var res result.Result
+	switch callType {
+	case constant.CallClientStream, constant.CallServerStream, constant.CallBidiStream:
+		// the stream is the last argument
+		if n := len(req.Messages); n > 0 {
+			req.Messages, req.Stream = req.Messages[:n-1:n-1], req.Messages[n-1]
+		}
+		handle := func(ctx context.Context, req *Request) error {
+			res = ii.Invoker.Invoke(ctx, ii.invocation(invocation, req))
+			return res.Error()
+		}
+		err := ii.wrapStream(handle)(ctx, req)
+		if res == nil {
+			res = &result.RPCResult{}
+		}
+		res.SetError(err)
+		return res
+	}
+
+	handle := func(ctx context.Context, req *Request) (*Response, error) {
+		res = ii.Invoker.Invoke(ctx, ii.invocation(invocation, req))
+		msg := res.Result()
+		if triResp, ok := msg.(*triple_protocol.Response); ok {
+			msg = triResp.Msg
+		}
+		return &Response{Message: msg, Header: res.Attachments()}, res.Error()
+	}
+	resp, err := ii.wrapUnary(handle)(ctx, req)
+	if res == nil {
+		// the call is answered by an interceptor
+		res = &result.RPCResult{}
+		if resp != nil && ii.withInfo {
+			res.SetResult(triple_protocol.NewResponse(resp.Message))
+		}
+	}
+	if resp != nil {
+		if triResp, ok := res.Result().(*triple_protocol.Response); ok {
+			triResp.Msg = resp.Message
+		} else {
+			res.SetResult(resp.Message)
+		}
+		res.SetAttachments(resp.Header)
+	}
+	res.SetError(err)
+	return res
+}
+
+// invocation returns the invocation of the request, the arguments replaced by the interceptors are
+// written back, as the invocation shares them with the request.
+func (ii *interceptedInvoker) invocation(invocation base.Invocation, req *Request) base.Invocation {
+	arguments := invocation.Arguments()
+	for i := 0; i < len(req.Messages) && i < len(arguments); i++ {
+		arguments[i] = req.Messages[i]
+	}
+	return invocation
+}
+
+// wrapUnary composes the interceptors, the first of which is invoked first
+func (ii *interceptedInvoker) wrapUnary(next UnaryFunc) UnaryFunc {
+	for i := len(ii.interceptors) - 1; i >= 0; i-- {
+		if ii.interceptors[i] != nil {
+			next = ii.interceptors[i].WrapUnary(next)
+		}
+	}
+	return next
+}
+
+// wrapStream composes the interceptors, the first of which is invoked first
+func (ii *interceptedInvoker) wrapStream(next StreamFunc) StreamFunc {
+	for i := len(ii.interceptors) - 1; i >= 0; i-- {
+		if ii.interceptors[i] != nil {
+			next = ii.interceptors[i].WrapStream(next)
+		}
+	}
+	return next
+}
@@ -0,0 +1,139 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/base"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/protocol/result"
+	triple_protocol "dubbo.apache.org/dubbo-go/v3/protocol/triple/triple_protocol"
+)
+
+// echoInvoker echoes the first argument, wrapped in a *triple_protocol.Response like the services with info
+type echoInvoker struct {
+	base.BaseInvoker
+	invoked bool
+}
+
+func (ei *echoInvoker) Invoke(_ context.Context, invocation base.Invocation) result.Result {
+	ei.invoked = true
+	res := &result.RPCResult{}
+	if len(invocation.Arguments()) > 0 {
+		res.SetResult(triple_protocol.NewResponse(invocation.Arguments()[0]))
+	}
+	res.SetAttachments(invocation.Attachments())
+	return res
+}
+
+func newEchoInvoker() *echoInvoker {
+	url, _ := common.NewURL("tri://127.0.0.1:20000/EchoService")
+	return &echoInvoker{BaseInvoker: *base.NewBaseInvoker(url)}
+}
+
+func TestInterceptedInvokerUnary(t *testing.T) {
+	var order []string
+	record := func(name string) Interceptor {
+		return UnaryInterceptorFunc(func(next UnaryFunc) UnaryFunc {
+			return func(ctx context.Context, req *Request) (*Response, error) {
+				order = append(order, name)
+				return next(ctx, req)
+			}
+		})
+	}
+	rewrite := UnaryInterceptorFunc(func(next UnaryFunc) UnaryFunc {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			assert.Equal(t, "Echo", req.Method)
+			assert.Equal(t, constant.CallUnary, req.CallType)
+			assert.Equal(t, "alice", req.Header["user"])
+			req.Messages[0] = "hello " + req.Messages[0].(string)
+			resp, err := next(ctx, req)
+			assert.Equal(t, "hello dubbo", resp.Message)
+			resp.Message = resp.Message.(string) + "!"
+			resp.Header["handled-by"] = "interceptor"
+			return resp, err
+		}
+	})
+	echo := newEchoInvoker()
+	invoker := newInterceptedInvoker(echo, []Interceptor{record("first"), rewrite, record("last")}, true)
+
+	inv := invocation.NewRPCInvocation("Echo", []any{"dubbo"}, map[string]any{"user": "alice"})
+	res := invoker.Invoke(context.Background(), inv)
+	assert.NoError(t, res.Error())
+	assert.Equal(t, "hello dubbo!", res.Result().(*triple_protocol.Response).Msg)
+	assert.Equal(t, "interceptor", res.Attachments()["handled-by"])
+	assert.Equal(t, []string{"first", "last"}, order)
+}
+
+func TestInterceptedInvokerShortCircuit(t *testing.T) {
+	echo := newEchoInvoker()
+	cached := UnaryInterceptorFunc(func(next UnaryFunc) UnaryFunc {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			return &Response{Message: "cached"}, nil
+		}
+	})
+	res := newInterceptedInvoker(echo, []Interceptor{cached}, true).
+		Invoke(context.Background(), invocation.NewRPCInvocation("Echo", []any{"dubbo"}, nil))
+	assert.False(t, echo.invoked)
+	assert.Equal(t, "cached", res.Result().(*triple_protocol.Response).Msg)
+
+	res = newInterceptedInvoker(echo, []Interceptor{cached}, false).
+		Invoke(context.Background(), invocation.NewRPCInvocation("Echo", []any{"dubbo"}, nil))
+	assert.Equal(t, "cached", res.Result())
+
+	denied := errors.New("denied")
+	res = newInterceptedInvoker(echo, []Interceptor{UnaryInterceptorFunc(func(next UnaryFunc) UnaryFunc {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			return nil, denied
+		}
+	})}, true).Invoke(context.Background(), invocation.NewRPCInvocation("Echo", []any{"dubbo"}, nil))
+	assert.Equal(t, denied, res.Error())
+	assert.False(t, echo.invoked)
+}
+
+func TestInterceptedInvokerStream(t *testing.T) {
+	stream := &triple_protocol.BidiStream{}
+	var seen *Request
+	echo := newEchoInvoker()
+	invoker := newInterceptedInvoker(echo, []Interceptor{StreamInterceptorFunc(func(next StreamFunc) StreamFunc {
+		return func(ctx context.Context, req *Request) error {
+			seen = req
+			return next(ctx, req)
+		}
+	})}, true)
+
+	inv := invocation.NewRPCInvocation("Chat", []any{stream}, nil)
+	inv.SetAttribute(constant.CallTypeKey, constant.CallBidiStream)
+	res := invoker.Invoke(context.Background(), inv)
+	assert.NoError(t, res.Error())
+	assert.True(t, echo.invoked)
+	assert.Equal(t, constant.CallBidiStream, seen.CallType)
+	assert.Empty(t, seen.Messages)
+	assert.Same(t, stream, seen.Stream)
+}
@@ -20,6 +20,7 @@ package server
 import (
 	"reflect"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -58,6 +59,8 @@ type ServerOptions struct {
 	Metrics     *global.MetricsConfig
 	Otel        *global.OtelConfig
 	TLS         *global.TLSConfig
+
+	interceptors []Interceptor
 }
 
 func defaultServerOptions() *ServerOptions {
@@ -314,6 +317,14 @@ func WithServerExecuteLimitRejectedHandler(exeRejHandler string) ServerOption {
 	}
 }
 
+// WithServerValidation validates the arguments of every service of this server
+// with the validation filter before they reach the implementation.
+func WithServerValidation() ServerOption {
+	return func(opts *ServerOptions) {
+		opts.Provider.Validation = "true"
+	}
+}
+
 func WithServerAuth(auth string) ServerOption {
 	return func(opts *ServerOptions) {
 		opts.Provider.Auth = auth
@@ -348,6 +359,14 @@ func WithServerFilter(filter string) ServerOption {
 	}
 }
 
+// WithServerInterceptors appends the interceptors of the requests to every service of this server,
+// which are invoked in order.
+func WithServerInterceptors(interceptors ...Interceptor) ServerOption {
+	return func(opts *ServerOptions) {
+		opts.interceptors = append(opts.interceptors, interceptors...)
+	}
+}
+
 // todo(DMwangnima): think about a more ideal configuration style
 func WithServerRegistryIDs(registryIDs []string) ServerOption {
 	return func(opts *ServerOptions) {
@@ -494,6 +513,7 @@ type ServiceOptions struct {
 	exportersLock   sync.Mutex
 	exporters       []base.Exporter
 	adaptiveService bool
+	interceptors    []Interceptor
 
 	// for triple non-IDL mode
 	// consider put here or global.ServiceConfig
@@ -640,6 +660,14 @@ func WithFilter(filter string) ServiceOption {
 	}
 }
 
+// WithInterceptors appends the interceptors of the requests to this service, which are invoked
+// in order, after the ones set by WithServerInterceptors.
+func WithInterceptors(interceptors ...Interceptor) ServiceOption {
+	return func(cfg *ServiceOptions) {
+		cfg.interceptors = append(cfg.interceptors, interceptors...)
+	}
+}
+
 // todo(DMwangnima): think about a more ideal configuration style
 func WithProtocolIDs(protocolIDs []string) ServiceOption {
 	return func(cfg *ServiceOptions) {
@@ -806,12 +834,40 @@ func WithSerialization(ser string) ServiceOption {
 	}
 }
 
+// WithCompression sets the algorithm used to compress responses, such as
+// constant.CompressionGzip, constant.CompressionZstd or constant.CompressionSnappy.
+// Over the dubbo protocol the responses use the algorithm the consumer accepts,
+// constant.CompressionIdentity turns their compression off.
+func WithCompression(compression string) ServiceOption {
+	return func(opts *ServiceOptions) {
+		opts.Service.Compression = compression
+	}
+}
+
+// WithCompressMinBytes sets the size below which responses are sent uncompressed.
+func WithCompressMinBytes(min int) ServiceOption {
+	return func(opts *ServiceOptions) {
+		opts.Service.CompressMinBytes = min
+	}
+}
+
 func WithAccesslog(accesslog string) ServiceOption {
 	return func(opts *ServiceOptions) {
 		opts.Service.AccessLog = accesslog
 	}
 }
 
+// WithAccesslogFormat sets the format of the access log, which is one of text, json and logfmt.
+// The other options of the access log, such as constant.AccessLogFieldsKey, are configured by WithParam.
+func WithAccesslogFormat(format string) ServiceOption {
+	return WithParam(constant.AccessLogFormatKey, format)
+}
+
+// WithAccesslogRedact sets the names of the struct fields and map keys redacted in the access log
+func WithAccesslogRedact(names ...string) ServiceOption {
+	return WithParam(constant.AccessLogRedactKey, strings.Join(names, ","))
+}
+
 func WithTpsLimiter(limiter string) ServiceOption {
 	return func(opts *ServiceOptions) {
 		opts.Service.TpsLimiter = limiter
@@ -836,6 +892,27 @@ func WithTpsLimitRejectedHandler(rejHandler string) ServiceOption {
 	}
 }
 
+// WithTpsLimitBurst sets the max number of requests the tokenBucket strategy allows at once
+func WithTpsLimitBurst(burst int) ServiceOption {
+	return func(opts *ServiceOptions) {
+		opts.Service.TpsLimitBurst = strconv.Itoa(burst)
+	}
+}
+
+// WithTpsLimitMaxWait sets how long the leakyBucket strategy lets a request wait for its turn
+func WithTpsLimitMaxWait(maxWait time.Duration) ServiceOption {
+	return func(opts *ServiceOptions) {
+		opts.Service.TpsLimitMaxWait = strconv.FormatInt(maxWait.Milliseconds(), 10)
+	}
+}
+
+// WithTpsLimitBy limits the requests of each caller separately, by is "application" or "attachment:{key}"
+func WithTpsLimitBy(by string) ServiceOption {
+	return func(opts *ServiceOptions) {
+		opts.Service.TpsLimitBy = by
+	}
+}
+
 func WithExecuteLimit(exeLimit string) ServiceOption {
 	return func(opts *ServiceOptions) {
 		opts.Service.ExecuteLimit = exeLimit
@@ -848,6 +925,14 @@ func WithExecuteLimitRejectedHandler(exeRejHandler string) ServiceOption {
 	}
 }
 
+// WithValidation validates the arguments of this service with the validation filter
+// before they reach the implementation. Use config.WithValidation to override it per method.
+func WithValidation() ServiceOption {
+	return func(opts *ServiceOptions) {
+		opts.Service.Validation = "true"
+	}
+}
+
 func WithAuth(auth string) ServiceOption {
 	return func(opts *ServiceOptions) {
 		opts.Service.Auth = auth
@@ -908,6 +993,65 @@ func WithParam(k, v string) ServiceOption {
 	}
 }
 
+// WithAdaptiveServiceLimiter selects the limiter of the adaptive service for this service,
+// which is one of hill-climbing, gradient2 and vegas. The limiter is configured by
+// WithParam with the keys like constant.AdaptiveServiceLimiterMaxLimitKey.
+func WithAdaptiveServiceLimiter(name string) ServiceOption {
+	return WithParam(constant.AdaptiveServiceLimiterKey, name)
+}
+
+// WithMaxRequestSize limits the encoded size of the requests of every method of this service,
+// e.g. "512kib". Use config.WithMaxRequestSize to override it per method.
+func WithMaxRequestSize(size string) ServiceOption {
+	return WithParam(constant.MaxRequestSizeKey, size)
+}
+
+// WithMaxResponseSize limits the encoded size of the responses of every method of this service,
+// e.g. "4mib". Use config.WithMaxResponseSize to override it per method.
+func WithMaxResponseSize(size string) ServiceOption {
+	return WithParam(constant.MaxResponseSizeKey, size)
+}
+
+// WithCriticalityShedding makes the execute limit and the adaptive service filters of this service
+// reject the less critical requests first as their limitations are approached, see common/criticality.
+func WithCriticalityShedding() ServiceOption {
+	return WithParam(constant.CriticalitySheddingKey, "true")
+}
+
+// WithDeprecated marks every method of this service as deprecated, the consumers calling them are warned.
+// Use config.WithDeprecated to mark a single method.
+func WithDeprecated() ServiceOption {
+	return WithParam(constant.DeprecatedKey, "true")
+}
+
+// WithSunset marks this service as deprecated, and to be removed at sunset.
+// Use config.WithSunset to set the sunset of a single method.
+func WithSunset(sunset time.Time) ServiceOption {
+	return WithParam(constant.SunsetKey, sunset.UTC().Format(time.RFC3339))
+}
+
+// WithReplacement tells the consumers of this deprecated service what to call instead.
+func WithReplacement(replacement string) ServiceOption {
+	return WithParam(constant.ReplacementKey, replacement)
+}
+
+// WithRejectAfterSunset rejects the calls to the methods of this service whose sunset has passed,
+// otherwise they are only warned about.
+func WithRejectAfterSunset() ServiceOption {
+	return WithParam(constant.SunsetRejectKey, "true")
+}
+
+// WithAudit records every invocation of the methods of this service by the audit filter,
+// use config.WithAudit to audit a single method.
+func WithAudit() ServiceOption {
+	return WithParam(constant.AuditKey, "true")
+}
+
+// WithAuditFile sets the file the audit records of this service are appended to.
+func WithAuditFile(path string) ServiceOption {
+	return WithParam(constant.AuditFileKey, path)
+}
+
 // TODO: remove when config package is removed
 func WithIDLMode(IDLMode string) ServiceOption {
 	return func(opts *ServiceOptions) {
@@ -0,0 +1,71 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/client"
+	_ "dubbo.apache.org/dubbo-go/v3/imports"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/server"
+)
+
+type LocalGreetProvider struct{}
+
+func (*LocalGreetProvider) Greet(_ context.Context, name string) (string, error) {
+	return "hello " + name, nil
+}
+
+func TestServeUnixSocket(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "dubbo.sock")
+	srv, err := server.NewServer(
+		server.WithServerProtocol(
+			protocol.WithTriple(),
+			protocol.WithUnixSocket(sock),
+			protocol.WithUnixSocketMode(0600),
+		),
+	)
+	require.NoError(t, err)
+	err = srv.RegisterService(&LocalGreetProvider{}, server.WithInterface("GreetProvider"), server.WithNotRegister())
+	require.NoError(t, err)
+	go func() {
+		_ = srv.Serve()
+	}()
+
+	cli, err := client.NewClient(client.WithClientURL("unix://" + sock))
+	require.NoError(t, err)
+	conn, err := cli.NewService(&LocalGreetProvider{}, client.WithInterface("GreetProvider"))
+	require.NoError(t, err)
+	// the service is exported in the background
+	assert.Eventually(t, func() bool {
+		var greeting string
+		err := conn.CallUnary(context.Background(), []any{"dubbo"}, &greeting, "Greet")
+		return err == nil && greeting == "hello dubbo"
+	}, 5*time.Second, 10*time.Millisecond)
+}